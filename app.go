@@ -4,27 +4,139 @@
 package cce
 
 import (
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"net/url"
+	"path"
+	"regexp"
 	"strings"
 
 	"github.com/open-ness/edgecontroller/uuid"
 )
 
-// App is an application.
+// App is an application. Each version of an application is a separate app
+// that is linked to the first version through VersionOf. Apps of a Tenant
+// are isolated from other tenants' apps in Kubernetes mode. The Source is
+// either a URL the node downloads the app's image from, or a reference to an
+// image in a registry, e.g. docker://registry.example.com/app:1.0. The image
+// can be pinned by its SHA256 digest, which the node checks before running it,
+// and signed with a base64-encoded Signature of the digest, which the
+// controller verifies with its trust store.
 type App struct {
 	ID          string       `json:"id"`
 	Type        string       `json:"type"`
 	Name        string       `json:"name"`
 	Version     string       `json:"version"`
+	VersionOf   string       `json:"version_of,omitempty"`
+	Tenant      string       `json:"tenant,omitempty"`
 	Vendor      string       `json:"vendor"`
 	Description string       `json:"description"`
 	Cores       int          `json:"cores"`
-	Memory      int          `json:"memory"` // in MB
+	Memory      int          `json:"memory"`              // in MB
+	Hugepages   int          `json:"hugepages,omitempty"` // in MB
 	Ports       []PortProto  `json:"ports,omitempty"`
 	Source      string       `json:"source"`
+	SHA256      string       `json:"sha256,omitempty"`    // hex digest of the image
+	Signature   string       `json:"signature,omitempty"` // base64-encoded
 	EPAFeatures []EPAFeature `json:"epafeatures,omitempty"`
+	Env         []EnvVar     `json:"env,omitempty"`
+	Command     []string     `json:"command,omitempty"`
+	Args        []string     `json:"args,omitempty"`
+	Volumes     []Volume     `json:"volumes,omitempty"`
+	ConfigFiles []ConfigFile `json:"config_files,omitempty"`
+	Networks    []string     `json:"networks,omitempty"` // IDs of the networks
+
+	LivenessProbe  *Probe `json:"liveness_probe,omitempty"`
+	ReadinessProbe *Probe `json:"readiness_probe,omitempty"`
+	RestartPolicy  string `json:"restart_policy,omitempty"`
+	PullPolicy     string `json:"pull_policy,omitempty"`
+}
+
+// Restart policies of an application. An empty policy means always.
+const (
+	RestartPolicyAlways    = "always"
+	RestartPolicyOnFailure = "on-failure"
+	RestartPolicyNever     = "never"
+)
+
+// Schemes of sources referencing an image in a registry. The image is pulled
+// by Kubernetes instead of being downloaded by the node.
+const (
+	SourceSchemeDocker = "docker"
+	SourceSchemeOCI    = "oci"
+)
+
+// Pull policies of the image of an application in Kubernetes mode. An empty
+// policy means if-not-present for registry sources and never otherwise, as
+// the node loads the image of other sources.
+const (
+	PullPolicyAlways       = "always"
+	PullPolicyIfNotPresent = "if-not-present"
+	PullPolicyNever        = "never"
+)
+
+// Types of health probes.
+const (
+	ProbeTypeHTTP = "http"
+	ProbeTypeTCP  = "tcp"
+	ProbeTypeExec = "exec"
+)
+
+// Probe checks the health of an application. An HTTP probe succeeds if a GET
+// of the path returns a 2xx or 3xx status, a TCP probe if the port accepts a
+// connection and an exec probe if the command exits with 0. The zero value
+// of the timings leaves the defaults of the node.
+type Probe struct {
+	Type                string   `json:"type"`
+	Path                string   `json:"path,omitempty"`
+	Port                uint32   `json:"port,omitempty"`
+	Command             []string `json:"command,omitempty"`
+	InitialDelaySeconds int      `json:"initial_delay_seconds,omitempty"`
+	PeriodSeconds       int      `json:"period_seconds,omitempty"`
+	TimeoutSeconds      int      `json:"timeout_seconds,omitempty"`
+	FailureThreshold    int      `json:"failure_threshold,omitempty"`
+}
+
+func (p *Probe) String() string {
+	switch p.Type {
+	case ProbeTypeHTTP:
+		return fmt.Sprintf("http-get :%d%s", p.Port, p.Path)
+	case ProbeTypeTCP:
+		return fmt.Sprintf("tcp-socket :%d", p.Port)
+	default:
+		return fmt.Sprintf("exec %s", p.Command)
+	}
+}
+
+func (p *Probe) validate(field string) error {
+	switch p.Type {
+	case ProbeTypeHTTP:
+		if !path.IsAbs(p.Path) {
+			return fmt.Errorf("%s path %q must be an absolute path", field, p.Path)
+		}
+		fallthrough
+	case ProbeTypeTCP:
+		if p.Port < 1 || p.Port > MaxPort {
+			return fmt.Errorf("%s port must be in [1..%d]", field, MaxPort)
+		}
+	case ProbeTypeExec:
+		if len(p.Command) == 0 {
+			return fmt.Errorf("%s command cannot be empty", field)
+		}
+	default:
+		return fmt.Errorf(`%s type must be "%s", "%s" or "%s"`, field, ProbeTypeHTTP, ProbeTypeTCP, ProbeTypeExec)
+	}
+	for _, seconds := range []int{p.InitialDelaySeconds, p.PeriodSeconds, p.TimeoutSeconds} {
+		if seconds < 0 || seconds > MaxProbeSeconds {
+			return fmt.Errorf("%s delay, period and timeout must be in [0..%d] seconds", field, MaxProbeSeconds)
+		}
+	}
+	if p.FailureThreshold < 0 {
+		return fmt.Errorf("%s failure_threshold cannot be negative", field)
+	}
+
+	return nil
 }
 
 // PortProto is a port and protocol combination. It is typically used to represent the ports and protocols that an
@@ -45,6 +157,71 @@ type EPAFeature struct {
 	Value string `json:"value,omitempty"`
 }
 
+// EnvVar is an environment variable set in the application.
+type EnvVar struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+func (e EnvVar) String() string {
+	return e.Name + "=" + e.Value
+}
+
+// Volume is a directory of the node mounted in the application. The data in
+// it outlives the application.
+type Volume struct {
+	Name      string `json:"name"`
+	MountPath string `json:"mount_path"`
+	HostPath  string `json:"host_path"`
+	ReadOnly  bool   `json:"read_only,omitempty"`
+}
+
+func (v Volume) String() string {
+	if v.ReadOnly {
+		return fmt.Sprintf("%s:%s:ro", v.HostPath, v.MountPath)
+	}
+	return fmt.Sprintf("%s:%s", v.HostPath, v.MountPath)
+}
+
+// ConfigFile is a file written in the application before it starts.
+type ConfigFile struct {
+	Path    string `json:"path"`
+	Content string `json:"content"`
+}
+
+func (f ConfigFile) String() string {
+	return fmt.Sprintf("%s (%d bytes)", f.Path, len(f.Content))
+}
+
+var (
+	envVarNameRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	dnsLabelRegexp   = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+	sha256Regexp     = regexp.MustCompile(`^[a-f0-9]{64}$`)
+
+	// host[:port] of an image registry
+	registryHostRegexp = regexp.MustCompile(`^` + registryHostPattern + `$`)
+	// [host[:port]/]name[/name...][:tag][@digest], with the tag and the
+	// digest as the last two submatches
+	imageRefRegexp = regexp.MustCompile(`^(` + registryHostPattern + `/)?` +
+		`(` + imageNamePattern + `(/` + imageNamePattern + `)*)` +
+		`(:[\w][\w.-]{0,127})?(@sha256:[a-f0-9]{64})?$`)
+)
+
+const (
+	registryHostPattern = `[a-zA-Z0-9]([-a-zA-Z0-9]*[a-zA-Z0-9])?(\.[a-zA-Z0-9]([-a-zA-Z0-9]*[a-zA-Z0-9])?)*(:[0-9]+)?`
+	imageNamePattern    = `[a-z0-9]+((\.|_|__|-+)[a-z0-9]+)*`
+
+	// configFilesVolumeName is the name of the volume the config files are
+	// mounted from on Kubernetes, see the k8s package
+	configFilesVolumeName = "config-files"
+)
+
+// ValidTenant returns true if the tenant is empty or a lowercase DNS label of
+// at most MaxTenantLength characters.
+func ValidTenant(tenant string) bool {
+	return tenant == "" || (dnsLabelRegexp.MatchString(tenant) && len(tenant) <= MaxTenantLength)
+}
+
 // GetTableName returns the name of the persistence table.
 func (*App) GetTableName() string {
 	return "apps"
@@ -77,12 +254,24 @@ func (app *App) Validate() error { // nolint: gocyclo
 	if app.Version == "" {
 		return errors.New("version cannot be empty")
 	}
+	if app.VersionOf != "" && !uuid.IsValid(app.VersionOf) {
+		return errors.New("version_of not a valid uuid")
+	}
+	if app.VersionOf == app.ID {
+		return errors.New("version_of cannot be the app's own id")
+	}
+	if !ValidTenant(app.Tenant) {
+		return fmt.Errorf("tenant must be a lowercase DNS label of at most %d characters", MaxTenantLength)
+	}
 	if app.Cores < 1 || app.Cores > MaxCores {
 		return fmt.Errorf("cores must be in [1..%d]", MaxCores)
 	}
 	if app.Memory < 1 || app.Memory > MaxMemory {
 		return fmt.Errorf("memory must be in [1..%d]", MaxMemory)
 	}
+	if app.Hugepages < 0 || app.Hugepages > MaxMemory {
+		return fmt.Errorf("hugepages must be in [0..%d]", MaxMemory)
+	}
 	for _, pp := range app.Ports {
 		switch pp.Protocol {
 		case "tcp", "udp", "icmp", "sctp", "all":
@@ -97,16 +286,222 @@ func (app *App) Validate() error { // nolint: gocyclo
 			return fmt.Errorf("port must be in [1..%d]", MaxPort)
 		}
 	}
+	seenNetworks := make(map[string]bool)
+	for i, id := range app.Networks {
+		if !uuid.IsValid(id) {
+			return fmt.Errorf("networks[%d] not a valid uuid", i)
+		}
+		if seenNetworks[id] {
+			return fmt.Errorf("network %s is referenced more than once", id)
+		}
+		seenNetworks[id] = true
+	}
 	if app.Source == "" {
 		return errors.New("source cannot be empty")
 	}
-	if _, err := url.ParseRequestURI(app.Source); err != nil {
+	if ref, ok := imageRef(app.Source); ok {
+		if !validImageRef(ref) {
+			return fmt.Errorf("source %q must be an image reference with a tag or digest", app.Source)
+		}
+	} else if _, err := url.ParseRequestURI(app.Source); err != nil {
 		return errors.New("source cannot be parsed as a URI")
 	}
+	if err := app.validateIntegrity(); err != nil {
+		return err
+	}
+	switch app.PullPolicy {
+	case "", PullPolicyAlways, PullPolicyIfNotPresent, PullPolicyNever:
+	default:
+		return fmt.Errorf(`pull_policy must be "%s", "%s" or "%s"`,
+			PullPolicyAlways, PullPolicyIfNotPresent, PullPolicyNever)
+	}
+	if err := app.validateEnv(); err != nil {
+		return err
+	}
+	if err := app.validateMounts(); err != nil {
+		return err
+	}
+
+	return app.validateHealth()
+}
+
+func (app *App) validateIntegrity() error {
+	if app.SHA256 != "" && !sha256Regexp.MatchString(app.SHA256) {
+		return errors.New("sha256 must be 64 lowercase hex characters")
+	}
+	if digest := app.sourceDigest(); digest != "" && app.SHA256 != "" && digest != app.SHA256 {
+		return errors.New("sha256 does not match the digest of source")
+	}
+	if app.Signature != "" {
+		if _, err := base64.StdEncoding.DecodeString(app.Signature); err != nil {
+			return errors.New("signature must be base64-encoded")
+		}
+		if app.ImageDigest() == "" {
+			return errors.New("signature cannot be set without sha256")
+		}
+	}
+
+	return nil
+}
+
+func (app *App) validateEnv() error {
+	names := make(map[string]bool)
+	for _, env := range app.Env {
+		if !envVarNameRegexp.MatchString(env.Name) {
+			return fmt.Errorf("env name %q is not a valid environment variable name", env.Name)
+		}
+		if names[env.Name] {
+			return fmt.Errorf("env name %s is duplicated", env.Name)
+		}
+		names[env.Name] = true
+	}
+	if len(app.Args) != 0 && len(app.Command) == 0 {
+		return errors.New("args cannot be set without command")
+	}
 
 	return nil
 }
 
+func (app *App) validateHealth() error {
+	if app.LivenessProbe != nil {
+		if err := app.LivenessProbe.validate("liveness_probe"); err != nil {
+			return err
+		}
+	}
+	if app.ReadinessProbe != nil {
+		if err := app.ReadinessProbe.validate("readiness_probe"); err != nil {
+			return err
+		}
+	}
+	switch app.RestartPolicy {
+	case "", RestartPolicyAlways, RestartPolicyOnFailure, RestartPolicyNever:
+	default:
+		return fmt.Errorf(`restart_policy must be "%s", "%s" or "%s"`,
+			RestartPolicyAlways, RestartPolicyOnFailure, RestartPolicyNever)
+	}
+
+	return nil
+}
+
+// validateMounts validates the volumes and config files, which share the
+// paths of the application's file system.
+func (app *App) validateMounts() error {
+	names := make(map[string]bool)
+	paths := make(map[string]bool)
+	checkPath := func(field, p string) error {
+		if !path.IsAbs(p) {
+			return fmt.Errorf("%s %q must be an absolute path", field, p)
+		}
+		if paths[path.Clean(p)] {
+			return fmt.Errorf("%s %s is mounted more than once", field, p)
+		}
+		paths[path.Clean(p)] = true
+		return nil
+	}
+
+	for _, v := range app.Volumes {
+		if !dnsLabelRegexp.MatchString(v.Name) || len(v.Name) > 63 {
+			return fmt.Errorf("volume name %q must be a lowercase DNS label", v.Name)
+		}
+		if v.Name == configFilesVolumeName {
+			return fmt.Errorf("volume name %s is reserved", v.Name)
+		}
+		if names[v.Name] {
+			return fmt.Errorf("volume name %s is duplicated", v.Name)
+		}
+		names[v.Name] = true
+		if !path.IsAbs(v.HostPath) {
+			return fmt.Errorf("host_path %q must be an absolute path", v.HostPath)
+		}
+		if err := checkPath("mount_path", v.MountPath); err != nil {
+			return err
+		}
+	}
+	for _, f := range app.ConfigFiles {
+		if err := checkPath("config file path", f.Path); err != nil {
+			return err
+		}
+		if strings.HasSuffix(f.Path, "/") {
+			return fmt.Errorf("config file path %s must be a file", f.Path)
+		}
+	}
+
+	return nil
+}
+
+// ImageRef returns the reference of the app's image in a registry, e.g.
+// registry.example.com/app:1.0, or an empty string if the source is not a
+// registry.
+func (app *App) ImageRef() string {
+	ref, _ := imageRef(app.Source)
+	return ref
+}
+
+// ImageRegistry returns the host of the registry of the app's image, or an
+// empty string if the source is not a registry. References without a host
+// are images of Docker Hub.
+func (app *App) ImageRegistry() string {
+	ref := app.ImageRef()
+	if ref == "" {
+		return ""
+	}
+	i := strings.Index(ref, "/")
+	if i < 0 {
+		return "docker.io"
+	}
+	if host := ref[:i]; host == "localhost" || strings.ContainsAny(host, ".:") {
+		return host
+	}
+	return "docker.io"
+}
+
+// ImageDigest returns the hex SHA256 digest of the app's image, either set in
+// SHA256 or pinned by the registry source, or an empty string if the image
+// is not pinned. The digest of a registry image is the digest of its
+// manifest.
+func (app *App) ImageDigest() string {
+	if app.SHA256 != "" {
+		return app.SHA256
+	}
+	return app.sourceDigest()
+}
+
+// sourceDigest returns the hex digest pinned by a registry source, if any.
+func (app *App) sourceDigest() string {
+	m := imageRefRegexp.FindStringSubmatch(app.ImageRef())
+	if m == nil {
+		return ""
+	}
+	return strings.TrimPrefix(m[len(m)-1], "@sha256:")
+}
+
+// imageRef returns the image reference of a registry source and true, or
+// false if the source is not a registry.
+func imageRef(source string) (string, bool) {
+	for _, scheme := range []string{SourceSchemeDocker, SourceSchemeOCI} {
+		if strings.HasPrefix(source, scheme+"://") {
+			return strings.TrimPrefix(source, scheme+"://"), true
+		}
+	}
+	return "", false
+}
+
+// validImageRef returns true if the reference is well-formed and pins the
+// image with a tag or a digest.
+func validImageRef(ref string) bool {
+	m := imageRefRegexp.FindStringSubmatch(ref)
+	return m != nil && (m[len(m)-2] != "" || m[len(m)-1] != "")
+}
+
+// Identity returns the ID shared by all versions of the app, which is the ID
+// of its first version.
+func (app *App) Identity() string {
+	if app.VersionOf != "" {
+		return app.VersionOf
+	}
+	return app.ID
+}
+
 // FilterFields returns the filterable fields for this model.
 func (*App) FilterFields() []string {
 	return []string{}
@@ -118,24 +513,54 @@ App[
     ID: %s
     Name: %s
     Version: %s
+    VersionOf: %s
+    Tenant: %s
     Vendor: %s
     Description: %s
     Cores: %d
     Memory: %d
+    Hugepages: %d
     Ports: %s
     Source: %s
+    SHA256: %s
+    Signature: %s
     EPAFeatures: %s
+    Env: %s
+    Command: %s
+    Args: %s
+    Volumes: %s
+    ConfigFiles: %s
+    Networks: %s
+    LivenessProbe: %v
+    ReadinessProbe: %v
+    RestartPolicy: %s
+    PullPolicy: %s
 ]`),
 		app.ID,
 		app.Name,
 		app.Version,
+		app.VersionOf,
+		app.Tenant,
 		app.Vendor,
 		app.Description,
 		app.Cores,
 		app.Memory,
+		app.Hugepages,
 		app.Ports,
 		app.Source,
-		app.EPAFeatures)
+		app.SHA256,
+		app.Signature,
+		app.EPAFeatures,
+		app.Env,
+		app.Command,
+		app.Args,
+		app.Volumes,
+		app.ConfigFiles,
+		app.Networks,
+		app.LivenessProbe,
+		app.ReadinessProbe,
+		app.RestartPolicy,
+		app.PullPolicy)
 }
 
 // EPAValidate returns error if provided nodeFeatures do not fulfill app.EPAFeatures
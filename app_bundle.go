@@ -0,0 +1,159 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package cce
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/open-ness/edgecontroller/uuid"
+)
+
+// AppBundle is a set of apps that are deployed to a node together. A member
+// depends on the members listed in DependsOn, which are deployed and started
+// before it and stopped after it.
+type AppBundle struct {
+	ID          string            `json:"id"`
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	Members     []AppBundleMember `json:"members"`
+}
+
+// AppBundleMember is an app of a bundle and the apps of the bundle it
+// depends on.
+type AppBundleMember struct {
+	AppID     string   `json:"app_id"`
+	DependsOn []string `json:"depends_on,omitempty"`
+}
+
+// GetTableName returns the name of the persistence table.
+func (*AppBundle) GetTableName() string {
+	return "app_bundles"
+}
+
+// GetID gets the ID.
+func (b *AppBundle) GetID() string {
+	return b.ID
+}
+
+// SetID sets the ID.
+func (b *AppBundle) SetID(id string) {
+	b.ID = id
+}
+
+// Validate validates the model.
+func (b *AppBundle) Validate() error {
+	if !uuid.IsValid(b.ID) {
+		return errors.New("id not a valid uuid")
+	}
+	if len(strings.TrimSpace(b.Name)) == 0 {
+		return errors.New("name cannot be empty")
+	}
+	if len(b.Members) == 0 {
+		return errors.New("members cannot be empty")
+	}
+
+	members := make(map[string]bool)
+	for _, m := range b.Members {
+		if !uuid.IsValid(m.AppID) {
+			return errors.New("members.app_id not a valid uuid")
+		}
+		if members[m.AppID] {
+			return fmt.Errorf("members.app_id %s is not unique", m.AppID)
+		}
+		members[m.AppID] = true
+	}
+	for _, m := range b.Members {
+		for _, dep := range m.DependsOn {
+			if dep == m.AppID {
+				return fmt.Errorf("members.depends_on of app_id %s contains itself", m.AppID)
+			}
+			if !members[dep] {
+				return fmt.Errorf("members.depends_on of app_id %s contains %s which is not a member",
+					m.AppID, dep)
+			}
+		}
+	}
+
+	if _, err := b.Order(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Order returns the app IDs of the members in the order they are deployed
+// and started, i.e. each app after the apps it depends on. Members that do
+// not depend on each other keep the order they are listed in. Order returns
+// an error if the dependencies form a cycle.
+func (b *AppBundle) Order() ([]string, error) {
+	var (
+		order  []string
+		placed = make(map[string]bool)
+	)
+
+	for len(order) < len(b.Members) {
+		progress := false
+		for _, m := range b.Members {
+			if placed[m.AppID] || !dependenciesPlaced(m, placed) {
+				continue
+			}
+			order = append(order, m.AppID)
+			placed[m.AppID] = true
+			progress = true
+		}
+
+		if !progress {
+			var cycle []string
+			for _, m := range b.Members {
+				if !placed[m.AppID] {
+					cycle = append(cycle, m.AppID)
+				}
+			}
+			return nil, fmt.Errorf("members.depends_on forms a cycle between app_ids %s",
+				strings.Join(cycle, ", "))
+		}
+	}
+
+	return order, nil
+}
+
+func dependenciesPlaced(m AppBundleMember, placed map[string]bool) bool {
+	for _, dep := range m.DependsOn {
+		if !placed[dep] {
+			return false
+		}
+	}
+	return true
+}
+
+// HasMember returns true if the app is a member of the bundle.
+func (b *AppBundle) HasMember(appID string) bool {
+	for _, m := range b.Members {
+		if m.AppID == appID {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *AppBundle) String() string {
+	var members []string
+	for _, m := range b.Members {
+		members = append(members, fmt.Sprintf("%s%v", m.AppID, m.DependsOn))
+	}
+
+	return fmt.Sprintf(strings.TrimSpace(`
+AppBundle[
+    ID: %s
+    Name: %s
+    Description: %s
+    Members: %s
+]`),
+		b.ID,
+		b.Name,
+		b.Description,
+		strings.Join(members, ", "))
+}
@@ -0,0 +1,149 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package cce_test
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	cce "github.com/open-ness/edgecontroller"
+)
+
+var _ = Describe("Entities: AppBundle", func() {
+	const (
+		dbID    = "1a2b3c4d-5e6f-4a7b-8c9d-0e1f2a3b4c5d"
+		cacheID = "2b3c4d5e-6f7a-4b8c-9d0e-1f2a3b4c5d6e"
+		webID   = "3c4d5e6f-7a8b-4c9d-8e1f-2a3b4c5d6e7f"
+	)
+
+	var (
+		bundle *cce.AppBundle
+	)
+
+	BeforeEach(func() {
+		bundle = &cce.AppBundle{
+			ID:          "9f8e7d6c-5b4a-4392-8170-6f5e4d3c2b1a",
+			Name:        "shop",
+			Description: "web shop",
+			Members: []cce.AppBundleMember{
+				{AppID: webID, DependsOn: []string{dbID, cacheID}},
+				{AppID: cacheID},
+				{AppID: dbID},
+			},
+		}
+	})
+
+	Describe("GetTableName", func() {
+		It(`Should return "app_bundles"`, func() {
+			Expect(bundle.GetTableName()).To(Equal("app_bundles"))
+		})
+	})
+
+	Describe("GetID", func() {
+		It("Should return the ID", func() {
+			Expect(bundle.GetID()).To(Equal("9f8e7d6c-5b4a-4392-8170-6f5e4d3c2b1a"))
+		})
+	})
+
+	Describe("SetID", func() {
+		It("Should set and return the updated ID", func() {
+			By("Setting the ID")
+			bundle.SetID("456")
+
+			By("Getting the updated ID")
+			Expect(bundle.ID).To(Equal("456"))
+		})
+	})
+
+	Describe("Validate", func() {
+		It("Should not return an error for a valid bundle", func() {
+			Expect(bundle.Validate()).To(Succeed())
+		})
+
+		It("Should return an error if ID is not a UUID", func() {
+			bundle.ID = "123"
+			Expect(bundle.Validate()).To(MatchError("id not a valid uuid"))
+		})
+
+		It("Should return an error if Name is empty", func() {
+			bundle.Name = " "
+			Expect(bundle.Validate()).To(MatchError("name cannot be empty"))
+		})
+
+		It("Should return an error if Members is empty", func() {
+			bundle.Members = nil
+			Expect(bundle.Validate()).To(MatchError("members cannot be empty"))
+		})
+
+		It("Should return an error if an AppID is not a UUID", func() {
+			bundle.Members[1].AppID = "123"
+			Expect(bundle.Validate()).To(MatchError("members.app_id not a valid uuid"))
+		})
+
+		It("Should return an error if an AppID is repeated", func() {
+			bundle.Members[1].AppID = dbID
+			Expect(bundle.Validate()).To(MatchError("members.app_id " + dbID + " is not unique"))
+		})
+
+		It("Should return an error if a member depends on itself", func() {
+			bundle.Members[2].DependsOn = []string{dbID}
+			Expect(bundle.Validate()).To(MatchError(
+				"members.depends_on of app_id " + dbID + " contains itself"))
+		})
+
+		It("Should return an error if a member depends on a non-member", func() {
+			bundle.Members[2].DependsOn = []string{"4d5e6f7a-8b9c-4d0e-9f1a-2b3c4d5e6f7a"}
+			Expect(bundle.Validate()).To(MatchError(
+				"members.depends_on of app_id " + dbID +
+					" contains 4d5e6f7a-8b9c-4d0e-9f1a-2b3c4d5e6f7a which is not a member"))
+		})
+
+		It("Should return an error if the dependencies form a cycle", func() {
+			bundle.Members[2].DependsOn = []string{webID}
+			Expect(bundle.Validate()).To(MatchError(
+				"members.depends_on forms a cycle between app_ids " + webID + ", " + dbID))
+		})
+	})
+
+	Describe("Order", func() {
+		It("Should order each member after its dependencies", func() {
+			Expect(bundle.Order()).To(Equal([]string{cacheID, dbID, webID}))
+		})
+
+		It("Should keep the listed order of independent members", func() {
+			bundle.Members[0].DependsOn = nil
+			Expect(bundle.Order()).To(Equal([]string{webID, cacheID, dbID}))
+		})
+
+		It("Should order a chain of dependencies", func() {
+			bundle.Members[1].DependsOn = []string{dbID}
+			bundle.Members[0].DependsOn = []string{cacheID}
+			Expect(bundle.Order()).To(Equal([]string{dbID, cacheID, webID}))
+		})
+	})
+
+	Describe("HasMember", func() {
+		It("Should return true for a member", func() {
+			Expect(bundle.HasMember(cacheID)).To(BeTrue())
+		})
+
+		It("Should return false for a non-member", func() {
+			Expect(bundle.HasMember("4d5e6f7a-8b9c-4d0e-9f1a-2b3c4d5e6f7a")).To(BeFalse())
+		})
+	})
+
+	Describe("String", func() {
+		It("Should return the string value", func() {
+			Expect(bundle.String()).To(Equal(strings.TrimSpace(`
+AppBundle[
+    ID: 9f8e7d6c-5b4a-4392-8170-6f5e4d3c2b1a
+    Name: shop
+    Description: web shop
+    Members: ` + webID + `[` + dbID + ` ` + cacheID + `], ` + cacheID + `[], ` + dbID + `[]
+]`,
+			)))
+		})
+	})
+})
@@ -0,0 +1,323 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package cce
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/open-ness/edgecontroller/uuid"
+)
+
+// Types of the parameters of an app template.
+const (
+	TemplateParamString = "string"
+	TemplateParamInt    = "int"
+	TemplateParamBool   = "bool"
+)
+
+// templateParamName matches the names of template parameters.
+var templateParamName = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// templatePlaceholder matches a reference to a template parameter in a spec.
+var templatePlaceholder = regexp.MustCompile(`\{\{([a-z][a-z0-9_]*)\}\}`)
+
+// AppTemplate is an app whose fields are filled in from parameters when it is
+// instantiated, e.g. to deploy the same app with different ports or memory on
+// each node. Spec is the JSON of the app: a string "{{name}}" is replaced by
+// the value of the parameter name, keeping its type, and "{{name}}" within a
+// longer string by the value's text. Parameters without a default must be
+// given a value. Version is incremented each time the template is updated.
+type AppTemplate struct {
+	ID          string                 `json:"id"`
+	Name        string                 `json:"name"`
+	Description string                 `json:"description,omitempty"`
+	Version     int                    `json:"version"`
+	Parameters  []AppTemplateParameter `json:"parameters,omitempty"`
+	Spec        json.RawMessage        `json:"spec"`
+}
+
+// AppTemplateParameter is a typed parameter of an app template.
+type AppTemplateParameter struct {
+	Name        string          `json:"name"`
+	Type        string          `json:"type"`
+	Default     json.RawMessage `json:"default,omitempty"`
+	Description string          `json:"description,omitempty"`
+}
+
+// GetTableName returns the name of the persistence table.
+func (*AppTemplate) GetTableName() string {
+	return "app_templates"
+}
+
+// GetID gets the ID.
+func (t *AppTemplate) GetID() string {
+	return t.ID
+}
+
+// SetID sets the ID.
+func (t *AppTemplate) SetID(id string) {
+	t.ID = id
+}
+
+// Validate validates the model. The spec must only reference declared
+// parameters and, with each parameter set to the zero value of its type,
+// decode to an app.
+func (t *AppTemplate) Validate() error {
+	if !uuid.IsValid(t.ID) {
+		return errors.New("id not a valid uuid")
+	}
+	if len(strings.TrimSpace(t.Name)) == 0 {
+		return errors.New("name cannot be empty")
+	}
+	if t.Version < 1 {
+		return errors.New("version must be positive")
+	}
+
+	zeros := make(map[string]interface{})
+	for _, p := range t.Parameters {
+		if !templateParamName.MatchString(p.Name) {
+			return fmt.Errorf(
+				"parameters.name %q must start with a lowercase letter followed by lowercase letters, "+
+					"digits or underscores", p.Name)
+		}
+		if _, ok := zeros[p.Name]; ok {
+			return fmt.Errorf("parameters.name %s is not unique", p.Name)
+		}
+		switch p.Type {
+		case TemplateParamString:
+			zeros[p.Name] = ""
+		case TemplateParamInt:
+			zeros[p.Name] = json.Number("0")
+		case TemplateParamBool:
+			zeros[p.Name] = false
+		default:
+			return fmt.Errorf(`parameters.type of %s must be "%s", "%s" or "%s"`,
+				p.Name, TemplateParamString, TemplateParamInt, TemplateParamBool)
+		}
+		if p.Default != nil {
+			if _, err := p.decode(p.Default); err != nil {
+				return fmt.Errorf("parameters.default of %s must be of type %s", p.Name, p.Type)
+			}
+		}
+	}
+
+	spec, err := decodeSpec(t.Spec)
+	if err != nil {
+		return err
+	}
+	if _, ok := spec.(map[string]interface{}); !ok {
+		return errors.New("spec must be a JSON object")
+	}
+	for _, name := range specParams(spec) {
+		if _, ok := zeros[name]; !ok {
+			return fmt.Errorf("spec references undeclared parameter %s", name)
+		}
+	}
+	if _, err = specApp(substitute(spec, zeros)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Instantiate fills in the spec of the template with the values of its
+// parameters and returns the resulting app with a new ID, along with the
+// values used, including defaults. The app is validated like any other app.
+func (t *AppTemplate) Instantiate(values map[string]json.RawMessage) (*App, map[string]json.RawMessage, error) {
+	params := make(map[string]AppTemplateParameter)
+	for _, p := range t.Parameters {
+		params[p.Name] = p
+	}
+	for name := range values {
+		if _, ok := params[name]; !ok {
+			return nil, nil, fmt.Errorf("parameter %s is not defined", name)
+		}
+	}
+
+	var (
+		used     = make(map[string]json.RawMessage)
+		resolved = make(map[string]interface{})
+	)
+	for _, p := range t.Parameters {
+		value, ok := values[p.Name]
+		if !ok {
+			value = p.Default
+		}
+		if value == nil {
+			return nil, nil, fmt.Errorf("parameter %s is required", p.Name)
+		}
+
+		v, err := p.decode(value)
+		if err != nil {
+			return nil, nil, fmt.Errorf("parameter %s must be of type %s", p.Name, p.Type)
+		}
+		used[p.Name] = value
+		resolved[p.Name] = v
+	}
+
+	spec, err := decodeSpec(t.Spec)
+	if err != nil {
+		return nil, nil, err
+	}
+	app, err := specApp(substitute(spec, resolved))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	app.ID = uuid.New()
+	if err = app.Validate(); err != nil {
+		return nil, nil, fmt.Errorf("instantiated app is invalid: %v", err)
+	}
+
+	return app, used, nil
+}
+
+// decode returns the value of the parameter in a JSON value, or an error if
+// the value is not of the parameter's type.
+func (p AppTemplateParameter) decode(value json.RawMessage) (interface{}, error) {
+	d := json.NewDecoder(bytes.NewReader(value))
+	d.UseNumber()
+
+	var v interface{}
+	if err := d.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	switch v := v.(type) {
+	case string:
+		if p.Type == TemplateParamString {
+			return v, nil
+		}
+	case json.Number:
+		if _, err := strconv.Atoi(v.String()); err == nil && p.Type == TemplateParamInt {
+			return v, nil
+		}
+	case bool:
+		if p.Type == TemplateParamBool {
+			return v, nil
+		}
+	}
+	return nil, fmt.Errorf("%s is not of type %s", value, p.Type)
+}
+
+func decodeSpec(spec json.RawMessage) (interface{}, error) {
+	d := json.NewDecoder(bytes.NewReader(spec))
+	d.UseNumber()
+
+	var v interface{}
+	if err := d.Decode(&v); err != nil {
+		return nil, errors.New("spec must be a JSON object")
+	}
+	return v, nil
+}
+
+// specParams returns the names of the parameters referenced by a spec.
+func specParams(spec interface{}) []string {
+	seen := make(map[string]bool)
+
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		switch v := v.(type) {
+		case string:
+			for _, m := range templatePlaceholder.FindAllStringSubmatch(v, -1) {
+				seen[m[1]] = true
+			}
+		case []interface{}:
+			for _, e := range v {
+				walk(e)
+			}
+		case map[string]interface{}:
+			for _, e := range v {
+				walk(e)
+			}
+		}
+	}
+	walk(spec)
+
+	var names []string
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// substitute replaces the references to parameters in a spec with their
+// values.
+func substitute(spec interface{}, values map[string]interface{}) interface{} {
+	switch v := spec.(type) {
+	case string:
+		if m := templatePlaceholder.FindStringSubmatch(v); m != nil && m[0] == v {
+			return values[m[1]]
+		}
+		return templatePlaceholder.ReplaceAllStringFunc(v, func(ref string) string {
+			return fmt.Sprint(values[templatePlaceholder.FindStringSubmatch(ref)[1]])
+		})
+	case []interface{}:
+		substituted := make([]interface{}, len(v))
+		for i, e := range v {
+			substituted[i] = substitute(e, values)
+		}
+		return substituted
+	case map[string]interface{}:
+		substituted := make(map[string]interface{})
+		for k, e := range v {
+			substituted[k] = substitute(e, values)
+		}
+		return substituted
+	default:
+		return v
+	}
+}
+
+// specApp decodes a spec whose parameters are substituted into an app.
+func specApp(spec interface{}) (*App, error) {
+	b, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.DisallowUnknownFields()
+
+	var app App
+	if err = d.Decode(&app); err != nil {
+		return nil, fmt.Errorf("spec does not describe an app: %v", err)
+	}
+	return &app, nil
+}
+
+func (t *AppTemplate) String() string {
+	var params []string
+	for _, p := range t.Parameters {
+		if p.Default != nil {
+			params = append(params, fmt.Sprintf("%s %s=%s", p.Name, p.Type, p.Default))
+		} else {
+			params = append(params, fmt.Sprintf("%s %s", p.Name, p.Type))
+		}
+	}
+
+	return fmt.Sprintf(strings.TrimSpace(`
+AppTemplate[
+    ID: %s
+    Name: %s
+    Description: %s
+    Version: %d
+    Parameters: %s
+    Spec: %s
+]`),
+		t.ID,
+		t.Name,
+		t.Description,
+		t.Version,
+		strings.Join(params, ", "),
+		t.Spec)
+}
@@ -0,0 +1,202 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package cce_test
+
+import (
+	"encoding/json"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	cce "github.com/open-ness/edgecontroller"
+)
+
+var _ = Describe("Entities: AppTemplate", func() {
+	var (
+		template *cce.AppTemplate
+	)
+
+	BeforeEach(func() {
+		template = &cce.AppTemplate{
+			ID:      "3e6f1c2a-8b4d-4f7e-9a0c-5d2b1e4f6a8c",
+			Name:    "video-analytics",
+			Version: 1,
+			Parameters: []cce.AppTemplateParameter{
+				{Name: "site", Type: cce.TemplateParamString},
+				{Name: "port", Type: cce.TemplateParamInt, Default: json.RawMessage(`8080`)},
+				{Name: "memory", Type: cce.TemplateParamInt, Default: json.RawMessage(`512`)},
+			},
+			Spec: json.RawMessage(`{
+				"type": "container",
+				"name": "va-{{site}}",
+				"version": "1.0",
+				"vendor": "acme",
+				"cores": 1,
+				"memory": "{{memory}}",
+				"ports": [{"port": "{{port}}", "protocol": "tcp"}],
+				"source": "docker://registry.example.com/va:1.0",
+				"env": [{"name": "LISTEN", "value": ":{{port}}"}]
+			}`),
+		}
+	})
+
+	Describe("GetTableName", func() {
+		It(`Should return "app_templates"`, func() {
+			Expect(template.GetTableName()).To(Equal("app_templates"))
+		})
+	})
+
+	Describe("GetID", func() {
+		It("Should return the ID", func() {
+			Expect(template.GetID()).To(Equal("3e6f1c2a-8b4d-4f7e-9a0c-5d2b1e4f6a8c"))
+		})
+	})
+
+	Describe("SetID", func() {
+		It("Should set and return the updated ID", func() {
+			By("Setting the ID")
+			template.SetID("456")
+
+			By("Getting the updated ID")
+			Expect(template.ID).To(Equal("456"))
+		})
+	})
+
+	Describe("Validate", func() {
+		It("Should not return an error for a valid template", func() {
+			Expect(template.Validate()).To(Succeed())
+		})
+
+		It("Should return an error if ID is not a UUID", func() {
+			template.ID = "123"
+			Expect(template.Validate()).To(MatchError("id not a valid uuid"))
+		})
+
+		It("Should return an error if Name is empty", func() {
+			template.Name = ""
+			Expect(template.Validate()).To(MatchError("name cannot be empty"))
+		})
+
+		It("Should return an error if Version is not positive", func() {
+			template.Version = 0
+			Expect(template.Validate()).To(MatchError("version must be positive"))
+		})
+
+		It("Should return an error if a parameter name is invalid", func() {
+			template.Parameters[0].Name = "Site"
+			Expect(template.Validate()).To(MatchError(`parameters.name "Site" must start with a ` +
+				`lowercase letter followed by lowercase letters, digits or underscores`))
+		})
+
+		It("Should return an error if a parameter name is repeated", func() {
+			template.Parameters[2].Name = "port"
+			Expect(template.Validate()).To(MatchError("parameters.name port is not unique"))
+		})
+
+		It("Should return an error if a parameter type is invalid", func() {
+			template.Parameters[0].Type = "float"
+			Expect(template.Validate()).To(MatchError(
+				`parameters.type of site must be "string", "int" or "bool"`))
+		})
+
+		It("Should return an error if a default does not match its type", func() {
+			template.Parameters[1].Default = json.RawMessage(`"8080"`)
+			Expect(template.Validate()).To(MatchError("parameters.default of port must be of type int"))
+		})
+
+		It("Should return an error if the spec is not an object", func() {
+			template.Spec = json.RawMessage(`[]`)
+			Expect(template.Validate()).To(MatchError("spec must be a JSON object"))
+		})
+
+		It("Should return an error if the spec references an undeclared parameter", func() {
+			template.Parameters = template.Parameters[:2]
+			Expect(template.Validate()).To(MatchError("spec references undeclared parameter memory"))
+		})
+
+		It("Should return an error if the spec has an unknown field", func() {
+			template.Spec = json.RawMessage(`{"name": "va", "memroy": 512}`)
+			Expect(template.Validate()).To(MatchError(
+				`spec does not describe an app: json: unknown field "memroy"`))
+		})
+
+		It("Should return an error if a parameter does not fit its field", func() {
+			template.Parameters[2].Type = cce.TemplateParamString
+			template.Parameters[2].Default = nil
+			Expect(template.Validate()).To(MatchError(
+				"spec does not describe an app: json: cannot unmarshal string into Go struct field " +
+					"App.memory of type int"))
+		})
+	})
+
+	Describe("Instantiate", func() {
+		It("Should fill in the spec with the values and defaults", func() {
+			app, used, err := template.Instantiate(map[string]json.RawMessage{
+				"site":   json.RawMessage(`"dublin"`),
+				"memory": json.RawMessage(`1024`),
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			By("Verifying the app")
+			Expect(app.ID).ToNot(BeEmpty())
+			Expect(app.Name).To(Equal("va-dublin"))
+			Expect(app.Memory).To(Equal(1024))
+			Expect(app.Ports).To(Equal([]cce.PortProto{{Port: 8080, Protocol: "tcp"}}))
+			Expect(app.Env).To(Equal([]cce.EnvVar{{Name: "LISTEN", Value: ":8080"}}))
+
+			By("Verifying the values used")
+			Expect(used).To(Equal(map[string]json.RawMessage{
+				"site":   json.RawMessage(`"dublin"`),
+				"port":   json.RawMessage(`8080`),
+				"memory": json.RawMessage(`1024`),
+			}))
+		})
+
+		It("Should return an error if a parameter is not defined", func() {
+			_, _, err := template.Instantiate(map[string]json.RawMessage{
+				"site":  json.RawMessage(`"dublin"`),
+				"cores": json.RawMessage(`2`),
+			})
+			Expect(err).To(MatchError("parameter cores is not defined"))
+		})
+
+		It("Should return an error if a required parameter is missing", func() {
+			_, _, err := template.Instantiate(nil)
+			Expect(err).To(MatchError("parameter site is required"))
+		})
+
+		It("Should return an error if a value does not match its type", func() {
+			_, _, err := template.Instantiate(map[string]json.RawMessage{
+				"site": json.RawMessage(`"dublin"`),
+				"port": json.RawMessage(`80.5`),
+			})
+			Expect(err).To(MatchError("parameter port must be of type int"))
+		})
+
+		It("Should return an error if the app is invalid", func() {
+			_, _, err := template.Instantiate(map[string]json.RawMessage{
+				"site":   json.RawMessage(`"dublin"`),
+				"memory": json.RawMessage(`0`),
+			})
+			Expect(err).To(MatchError(HavePrefix("instantiated app is invalid: memory")))
+		})
+	})
+
+	Describe("String", func() {
+		It("Should return the string value", func() {
+			template.Spec = json.RawMessage(`{"name":"va-{{site}}"}`)
+			template.Parameters = template.Parameters[:2]
+			Expect(template.String()).To(Equal(strings.TrimSpace(`
+AppTemplate[
+    ID: 3e6f1c2a-8b4d-4f7e-9a0c-5d2b1e4f6a8c
+    Name: video-analytics
+    Description: 
+    Version: 1
+    Parameters: site string, port int=8080
+    Spec: {"name":"va-{{site}}"}
+]`,
+			)))
+		})
+	})
+})
@@ -31,6 +31,26 @@ var _ = Describe("Entities: App", func() {
 				{Port: 443, Protocol: "tcp"},
 			},
 			Source: "https://path/to/file.zip",
+			SHA256: "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+			Env: []cce.EnvVar{
+				{Name: "LOG_LEVEL", Value: "debug"},
+			},
+			Command: []string{"/bin/app"},
+			Args:    []string{"-config", "/etc/app/app.conf"},
+			Volumes: []cce.Volume{
+				{Name: "data", MountPath: "/var/lib/app", HostPath: "/var/lib/edge/app"},
+			},
+			ConfigFiles: []cce.ConfigFile{
+				{Path: "/etc/app/app.conf", Content: "port=8080"},
+			},
+			Networks: []string{"0d4f6a2c-1b3e-4c5d-9e8f-7a6b5c4d3e2f"},
+			LivenessProbe: &cce.Probe{
+				Type:          cce.ProbeTypeHTTP,
+				Path:          "/healthz",
+				Port:          80,
+				PeriodSeconds: 10,
+			},
+			RestartPolicy: cce.RestartPolicyOnFailure,
 		}
 	})
 
@@ -79,6 +99,26 @@ var _ = Describe("Entities: App", func() {
 			Expect(app.Validate()).To(MatchError("version cannot be empty"))
 		})
 
+		It("Should return an error if VersionOf is not a UUID", func() {
+			app.VersionOf = "123"
+			Expect(app.Validate()).To(MatchError("version_of not a valid uuid"))
+		})
+
+		It("Should return an error if VersionOf is the app's own ID", func() {
+			app.VersionOf = app.ID
+			Expect(app.Validate()).To(MatchError("version_of cannot be the app's own id"))
+		})
+
+		It("Should return an error if Tenant is not a DNS label", func() {
+			app.Tenant = "Operator_A"
+			Expect(app.Validate()).To(MatchError(
+				"tenant must be a lowercase DNS label of at most 56 characters"))
+
+			app.Tenant = strings.Repeat("a", 57)
+			Expect(app.Validate()).To(MatchError(
+				"tenant must be a lowercase DNS label of at most 56 characters"))
+		})
+
 		It("Should return an error if Vendor is empty", func() {
 			app.Vendor = ""
 			Expect(app.Validate()).To(MatchError("vendor cannot be empty"))
@@ -106,6 +146,12 @@ var _ = Describe("Entities: App", func() {
 				"memory must be in [1..16384]"))
 		})
 
+		It("Should return an error if Hugepages is < 0", func() {
+			app.Hugepages = -1
+			Expect(app.Validate()).To(MatchError(
+				"hugepages must be in [0..16384]"))
+		})
+
 		It("Should return an error if Ports (port) is invalid", func() {
 			app.Ports[0].Port = 99999
 			Expect(app.Validate()).To(MatchError(
@@ -118,6 +164,17 @@ var _ = Describe("Entities: App", func() {
 				"protocol must be tcp, udp, sctp, icmp or all"))
 		})
 
+		It("Should return an error if Networks (id) is invalid", func() {
+			app.Networks[0] = "dataplane"
+			Expect(app.Validate()).To(MatchError("networks[0] not a valid uuid"))
+		})
+
+		It("Should return an error if Networks (id) is duplicated", func() {
+			app.Networks = append(app.Networks, app.Networks[0])
+			Expect(app.Validate()).To(MatchError(
+				"network 0d4f6a2c-1b3e-4c5d-9e8f-7a6b5c4d3e2f is referenced more than once"))
+		})
+
 		It("Should return an error if Source is empty", func() {
 			app.Source = ""
 			Expect(app.Validate()).To(MatchError("source cannot be empty"))
@@ -127,6 +184,179 @@ var _ = Describe("Entities: App", func() {
 			app.Source = "invalid.url"
 			Expect(app.Validate()).To(MatchError("source cannot be parsed as a URI"))
 		})
+
+		It("Should not return an error if Source is an image reference", func() {
+			app.SHA256 = ""
+			for _, source := range []string{
+				"docker://nginx:1.19",
+				"docker://registry.example.com:5000/edge/app:v1.0",
+				"oci://localhost/app@sha256:" + strings.Repeat("ab", 32),
+			} {
+				app.Source = source
+				Expect(app.Validate()).To(Succeed())
+			}
+		})
+
+		It("Should return an error if Source is an image reference without tag or digest", func() {
+			app.Source = "docker://registry.example.com/app"
+			Expect(app.Validate()).To(MatchError(
+				`source "docker://registry.example.com/app" must be an image reference with a tag or digest`))
+		})
+
+		It("Should return an error if SHA256 is not a hex digest", func() {
+			app.SHA256 = "9F86D081884C7D659A2FEAA0C55AD015A3BF4F1B2B0B822CD15D6C15B0F00A08"
+			Expect(app.Validate()).To(MatchError("sha256 must be 64 lowercase hex characters"))
+		})
+
+		It("Should return an error if SHA256 does not match the digest of Source", func() {
+			app.Source = "oci://localhost/app@sha256:" + strings.Repeat("ab", 32)
+			Expect(app.Validate()).To(MatchError("sha256 does not match the digest of source"))
+		})
+
+		It("Should return an error if Signature is not base64-encoded", func() {
+			app.Signature = "not base64!"
+			Expect(app.Validate()).To(MatchError("signature must be base64-encoded"))
+		})
+
+		It("Should return an error if Signature is set without a digest", func() {
+			app.SHA256 = ""
+			app.Signature = "c2lnbmF0dXJl"
+			Expect(app.Validate()).To(MatchError("signature cannot be set without sha256"))
+
+			app.Source = "oci://localhost/app@sha256:" + strings.Repeat("ab", 32)
+			Expect(app.Validate()).To(Succeed())
+		})
+
+		It("Should return an error if PullPolicy is invalid", func() {
+			app.PullPolicy = "sometimes"
+			Expect(app.Validate()).To(MatchError(
+				`pull_policy must be "always", "if-not-present" or "never"`))
+		})
+
+		It("Should return an error if Env (name) is invalid", func() {
+			app.Env[0].Name = "LOG-LEVEL"
+			Expect(app.Validate()).To(MatchError(
+				`env name "LOG-LEVEL" is not a valid environment variable name`))
+		})
+
+		It("Should return an error if Env (name) is duplicated", func() {
+			app.Env = append(app.Env, cce.EnvVar{Name: "LOG_LEVEL", Value: "info"})
+			Expect(app.Validate()).To(MatchError("env name LOG_LEVEL is duplicated"))
+		})
+
+		It("Should return an error if Args are set without Command", func() {
+			app.Command = nil
+			Expect(app.Validate()).To(MatchError("args cannot be set without command"))
+		})
+
+		It("Should return an error if Volumes (name) is invalid", func() {
+			app.Volumes[0].Name = "Data"
+			Expect(app.Validate()).To(MatchError(
+				`volume name "Data" must be a lowercase DNS label`))
+		})
+
+		It("Should return an error if Volumes (name) is reserved", func() {
+			app.Volumes[0].Name = "config-files"
+			Expect(app.Validate()).To(MatchError(
+				"volume name config-files is reserved"))
+		})
+
+		It("Should return an error if Volumes (host_path) is relative", func() {
+			app.Volumes[0].HostPath = "edge/app"
+			Expect(app.Validate()).To(MatchError(
+				`host_path "edge/app" must be an absolute path`))
+		})
+
+		It("Should return an error if Volumes (mount_path) is relative", func() {
+			app.Volumes[0].MountPath = "app"
+			Expect(app.Validate()).To(MatchError(
+				`mount_path "app" must be an absolute path`))
+		})
+
+		It("Should return an error if ConfigFiles (path) is relative", func() {
+			app.ConfigFiles[0].Path = "app.conf"
+			Expect(app.Validate()).To(MatchError(
+				`config file path "app.conf" must be an absolute path`))
+		})
+
+		It("Should return an error if ConfigFiles (path) is a volume's mount_path", func() {
+			app.ConfigFiles[0].Path = "/var/lib/app/"
+			Expect(app.Validate()).To(MatchError(
+				"config file path /var/lib/app/ is mounted more than once"))
+		})
+
+		It("Should return an error if LivenessProbe (type) is invalid", func() {
+			app.LivenessProbe.Type = "grpc"
+			Expect(app.Validate()).To(MatchError(
+				`liveness_probe type must be "http", "tcp" or "exec"`))
+		})
+
+		It("Should return an error if LivenessProbe (path) is relative", func() {
+			app.LivenessProbe.Path = "healthz"
+			Expect(app.Validate()).To(MatchError(
+				`liveness_probe path "healthz" must be an absolute path`))
+		})
+
+		It("Should return an error if ReadinessProbe (port) is invalid", func() {
+			app.ReadinessProbe = &cce.Probe{Type: cce.ProbeTypeTCP}
+			Expect(app.Validate()).To(MatchError(
+				"readiness_probe port must be in [1..65535]"))
+		})
+
+		It("Should return an error if ReadinessProbe (command) is empty", func() {
+			app.ReadinessProbe = &cce.Probe{Type: cce.ProbeTypeExec}
+			Expect(app.Validate()).To(MatchError(
+				"readiness_probe command cannot be empty"))
+		})
+
+		It("Should return an error if LivenessProbe (period) is invalid", func() {
+			app.LivenessProbe.PeriodSeconds = -1
+			Expect(app.Validate()).To(MatchError(
+				"liveness_probe delay, period and timeout must be in [0..3600] seconds"))
+		})
+
+		It("Should return an error if RestartPolicy is invalid", func() {
+			app.RestartPolicy = "sometimes"
+			Expect(app.Validate()).To(MatchError(
+				`restart_policy must be "always", "on-failure" or "never"`))
+		})
+	})
+
+	Describe("ImageRef", func() {
+		It("Should return the image reference of a registry source", func() {
+			Expect(app.ImageRef()).To(BeEmpty())
+			Expect(app.ImageRegistry()).To(BeEmpty())
+
+			app.Source = "docker://registry.example.com:5000/edge/app:v1.0"
+			Expect(app.ImageRef()).To(Equal("registry.example.com:5000/edge/app:v1.0"))
+			Expect(app.ImageRegistry()).To(Equal("registry.example.com:5000"))
+
+			app.Source = "oci://edge/app:v1.0"
+			Expect(app.ImageRef()).To(Equal("edge/app:v1.0"))
+			Expect(app.ImageRegistry()).To(Equal("docker.io"))
+		})
+	})
+
+	Describe("ImageDigest", func() {
+		It("Should return SHA256 or the digest of Source", func() {
+			Expect(app.ImageDigest()).To(Equal(
+				"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"))
+
+			app.SHA256 = ""
+			Expect(app.ImageDigest()).To(BeEmpty())
+
+			app.Source = "docker://registry.example.com/app:v1.0@sha256:" + strings.Repeat("ab", 32)
+			Expect(app.ImageDigest()).To(Equal(strings.Repeat("ab", 32)))
+		})
+	})
+
+	Describe("Identity", func() {
+		It("Should return the ID of the first version", func() {
+			Expect(app.Identity()).To(Equal("efcece3c-6b58-4993-8d45-bde6239d4baa"))
+
+			app.VersionOf = "9d740ea1-6b5c-4d0b-87e4-a8e0a0d7b7a0"
+			Expect(app.Identity()).To(Equal("9d740ea1-6b5c-4d0b-87e4-a8e0a0d7b7a0"))
+		})
 	})
 
 	Describe("String", func() {
@@ -136,13 +366,28 @@ App[
     ID: efcece3c-6b58-4993-8d45-bde6239d4baa
     Name: test-container-app
     Version: latest
+    VersionOf: 
+    Tenant: 
     Vendor: test-vendor
     Description: test-description
     Cores: 4
     Memory: 1024
+    Hugepages: 0
     Ports: [80/tcp 443/tcp]
     Source: https://path/to/file.zip
+    SHA256: 9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08
+    Signature: 
     EPAFeatures: []
+    Env: [LOG_LEVEL=debug]
+    Command: [/bin/app]
+    Args: [-config /etc/app/app.conf]
+    Volumes: [/var/lib/edge/app:/var/lib/app]
+    ConfigFiles: [/etc/app/app.conf (9 bytes)]
+    Networks: [0d4f6a2c-1b3e-4c5d-9e8f-7a6b5c4d3e2f]
+    LivenessProbe: http-get :80/healthz
+    ReadinessProbe: <nil>
+    RestartPolicy: on-failure
+    PullPolicy: 
 ]`,
 			)))
 		})
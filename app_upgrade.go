@@ -0,0 +1,159 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package cce
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/open-ness/edgecontroller/uuid"
+)
+
+const (
+	// UpgradeStateRunning is upgrading the nodes batch by batch
+	UpgradeStateRunning = "running"
+	// UpgradeStateSucceeded has upgraded all nodes
+	UpgradeStateSucceeded = "succeeded"
+	// UpgradeStateRollingBack is returning the upgraded nodes to their
+	// previous version after a failure
+	UpgradeStateRollingBack = "rolling_back"
+	// UpgradeStateRolledBack has returned all upgraded nodes to their previous
+	// version
+	UpgradeStateRolledBack = "rolled_back"
+	// UpgradeStateFailed could not return all upgraded nodes to their
+	// previous version
+	UpgradeStateFailed = "failed"
+)
+
+const (
+	// UpgradeNodeStatePending is waiting for its batch
+	UpgradeNodeStatePending = "pending"
+	// UpgradeNodeStateUpgrading is being upgraded
+	UpgradeNodeStateUpgrading = "upgrading"
+	// UpgradeNodeStateUpgraded runs the target version
+	UpgradeNodeStateUpgraded = "upgraded"
+	// UpgradeNodeStateRollingBack is returning to its previous version
+	UpgradeNodeStateRollingBack = "rolling_back"
+	// UpgradeNodeStateRolledBack runs its previous version again
+	UpgradeNodeStateRolledBack = "rolled_back"
+	// UpgradeNodeStateFailed could not be upgraded or rolled back
+	UpgradeNodeStateFailed = "failed"
+)
+
+// AppUpgrade is a rolling upgrade of the nodes running an app to one of the
+// app's versions. Nodes are upgraded in batches and their health is checked
+// after each batch. If a node fails, all nodes touched by the upgrade are
+// returned to the version they ran before.
+type AppUpgrade struct {
+	ID          string            `json:"id"`
+	AppID       string            `json:"app_id"`
+	TargetAppID string            `json:"target_app_id"`
+	BatchSize   int               `json:"batch_size"`
+	State       string            `json:"state"`
+	Error       string            `json:"error,omitempty"`
+	Nodes       []*AppUpgradeNode `json:"nodes"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
+
+// AppUpgradeNode is the progress of an upgrade on a single node. AppID is the
+// app deployed to the node and FromAppID the version it ran before.
+type AppUpgradeNode struct {
+	NodeID      string `json:"node_id"`
+	AppID       string `json:"app_id"`
+	FromAppID   string `json:"from_app_id"`
+	State       string `json:"state"`
+	OperationID string `json:"operation_id,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// GetTableName returns the name of the persistence table.
+func (*AppUpgrade) GetTableName() string {
+	return "apps_upgrades"
+}
+
+// GetID gets the ID.
+func (u *AppUpgrade) GetID() string {
+	return u.ID
+}
+
+// SetID sets the ID.
+func (u *AppUpgrade) SetID(id string) {
+	u.ID = id
+}
+
+// Validate validates the model.
+func (u *AppUpgrade) Validate() error {
+	if !uuid.IsValid(u.ID) {
+		return errors.New("id not a valid uuid")
+	}
+	if !uuid.IsValid(u.AppID) {
+		return errors.New("app_id not a valid uuid")
+	}
+	if !uuid.IsValid(u.TargetAppID) {
+		return errors.New("target_app_id not a valid uuid")
+	}
+	if u.BatchSize < 1 {
+		return errors.New("batch_size must be at least 1")
+	}
+	switch u.State {
+	case UpgradeStateRunning, UpgradeStateSucceeded,
+		UpgradeStateRollingBack, UpgradeStateRolledBack,
+		UpgradeStateFailed:
+	default:
+		return fmt.Errorf(`state "%s" is invalid`, u.State)
+	}
+	for _, n := range u.Nodes {
+		if !uuid.IsValid(n.NodeID) {
+			return errors.New("nodes node_id not a valid uuid")
+		}
+		if !uuid.IsValid(n.AppID) {
+			return errors.New("nodes app_id not a valid uuid")
+		}
+		if !uuid.IsValid(n.FromAppID) {
+			return errors.New("nodes from_app_id not a valid uuid")
+		}
+	}
+
+	return nil
+}
+
+// FilterFields returns the filterable fields for this model.
+func (*AppUpgrade) FilterFields() []string {
+	return []string{
+		"app_id",
+		"state",
+	}
+}
+
+// Done returns true if the upgrade reached a terminal state.
+func (u *AppUpgrade) Done() bool {
+	switch u.State {
+	case UpgradeStateSucceeded, UpgradeStateRolledBack, UpgradeStateFailed:
+		return true
+	}
+	return false
+}
+
+func (u *AppUpgrade) String() string {
+	return fmt.Sprintf(strings.TrimSpace(`
+AppUpgrade[
+    ID: %s
+    AppID: %s
+    TargetAppID: %s
+    BatchSize: %d
+    State: %s
+    Nodes: %d
+    Error: %s
+]`),
+		u.ID,
+		u.AppID,
+		u.TargetAppID,
+		u.BatchSize,
+		u.State,
+		len(u.Nodes),
+		u.Error)
+}
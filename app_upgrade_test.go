@@ -0,0 +1,143 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package cce_test
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	cce "github.com/open-ness/edgecontroller"
+)
+
+var _ = Describe("Entities: AppUpgrade", func() {
+	var (
+		upgrade *cce.AppUpgrade
+	)
+
+	BeforeEach(func() {
+		upgrade = &cce.AppUpgrade{
+			ID:          "3b2f5e8a-9c1d-4f7e-8a6b-5d4c3b2a1f0e",
+			AppID:       "efcece3c-6b58-4993-8d45-bde6239d4baa",
+			TargetAppID: "9d740ea1-6b5c-4d0b-87e4-a8e0a0d7b7a0",
+			BatchSize:   2,
+			State:       cce.UpgradeStateRunning,
+			Nodes: []*cce.AppUpgradeNode{
+				{
+					NodeID:    "48606c73-3905-47e0-864f-14bc7466f5bb",
+					AppID:     "efcece3c-6b58-4993-8d45-bde6239d4baa",
+					FromAppID: "efcece3c-6b58-4993-8d45-bde6239d4baa",
+					State:     cce.UpgradeNodeStatePending,
+				},
+			},
+		}
+	})
+
+	Describe("GetTableName", func() {
+		It(`Should return "apps_upgrades"`, func() {
+			Expect(upgrade.GetTableName()).To(Equal("apps_upgrades"))
+		})
+	})
+
+	Describe("GetID", func() {
+		It("Should return the ID", func() {
+			Expect(upgrade.GetID()).To(Equal(
+				"3b2f5e8a-9c1d-4f7e-8a6b-5d4c3b2a1f0e"))
+		})
+	})
+
+	Describe("SetID", func() {
+		It("Should set and return the updated ID", func() {
+			By("Setting the ID")
+			upgrade.SetID("456")
+
+			By("Getting the updated ID")
+			Expect(upgrade.ID).To(Equal("456"))
+		})
+	})
+
+	Describe("Validate", func() {
+		It("Should not return an error for a valid upgrade", func() {
+			Expect(upgrade.Validate()).To(Succeed())
+		})
+
+		It("Should return an error if ID is not a UUID", func() {
+			upgrade.ID = "123"
+			Expect(upgrade.Validate()).To(MatchError("id not a valid uuid"))
+		})
+
+		It("Should return an error if AppID is not a UUID", func() {
+			upgrade.AppID = "123"
+			Expect(upgrade.Validate()).To(MatchError("app_id not a valid uuid"))
+		})
+
+		It("Should return an error if TargetAppID is not a UUID", func() {
+			upgrade.TargetAppID = "123"
+			Expect(upgrade.Validate()).To(MatchError("target_app_id not a valid uuid"))
+		})
+
+		It("Should return an error if BatchSize is < 1", func() {
+			upgrade.BatchSize = 0
+			Expect(upgrade.Validate()).To(MatchError("batch_size must be at least 1"))
+		})
+
+		It("Should return an error if State is invalid", func() {
+			upgrade.State = "sleeping"
+			Expect(upgrade.Validate()).To(MatchError(`state "sleeping" is invalid`))
+		})
+
+		It("Should return an error if Nodes (node_id) is not a UUID", func() {
+			upgrade.Nodes[0].NodeID = "123"
+			Expect(upgrade.Validate()).To(MatchError("nodes node_id not a valid uuid"))
+		})
+
+		It("Should return an error if Nodes (app_id) is not a UUID", func() {
+			upgrade.Nodes[0].AppID = "123"
+			Expect(upgrade.Validate()).To(MatchError("nodes app_id not a valid uuid"))
+		})
+
+		It("Should return an error if Nodes (from_app_id) is not a UUID", func() {
+			upgrade.Nodes[0].FromAppID = "123"
+			Expect(upgrade.Validate()).To(MatchError("nodes from_app_id not a valid uuid"))
+		})
+	})
+
+	Describe("FilterFields", func() {
+		It("Should return the filterable fields", func() {
+			Expect(upgrade.FilterFields()).To(Equal([]string{
+				"app_id",
+				"state",
+			}))
+		})
+	})
+
+	Describe("Done", func() {
+		It("Should report only terminal states as done", func() {
+			Expect(upgrade.Done()).To(BeFalse())
+
+			upgrade.State = cce.UpgradeStateRollingBack
+			Expect(upgrade.Done()).To(BeFalse())
+
+			upgrade.State = cce.UpgradeStateRolledBack
+			Expect(upgrade.Done()).To(BeTrue())
+		})
+	})
+
+	Describe("String", func() {
+		It("Should return the string value", func() {
+			upgrade.Error = "boom"
+			Expect(upgrade.String()).To(Equal(strings.TrimSpace(`
+AppUpgrade[
+    ID: 3b2f5e8a-9c1d-4f7e-8a6b-5d4c3b2a1f0e
+    AppID: efcece3c-6b58-4993-8d45-bde6239d4baa
+    TargetAppID: 9d740ea1-6b5c-4d0b-87e4-a8e0a0d7b7a0
+    BatchSize: 2
+    State: running
+    Nodes: 1
+    Error: boom
+]`,
+			)))
+		})
+	})
+})
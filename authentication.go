@@ -3,8 +3,18 @@
 
 package cce
 
-// AuthCreds contains the username and password for a user.
+// Roles of the users of the HTTP API.
+const (
+	// RoleAdmin is allowed every request
+	RoleAdmin = "admin"
+	// RoleLogReader is only allowed to read the logs of apps
+	RoleLogReader = "log-reader"
+)
+
+// AuthCreds contains the username and password for a user and the role it is
+// granted once authenticated.
 type AuthCreds struct {
 	Username string
 	Password string
+	Role     string `json:"-"`
 }
@@ -7,6 +7,8 @@ import (
 	"context"
 	"crypto/tls"
 	"fmt"
+	"net"
+	"sync"
 
 	"github.com/open-ness/common/proxy/progutil"
 	"github.com/open-ness/edgecontroller/jose"
@@ -33,6 +35,27 @@ const (
 	OrchestrationModeKubernetesOVN
 )
 
+const (
+	// OvercommitReject rejects deployments that request more resources than
+	// a node has left
+	OvercommitReject = "reject"
+	// OvercommitWarn logs a warning for deployments that request more
+	// resources than a node has left and deploys them anyway
+	OvercommitWarn = "warn"
+)
+
+const (
+	// OrphanPolicyReport reports the Kubernetes objects of apps that are not
+	// in persistence and the apps in persistence whose objects are missing
+	OrphanPolicyReport = "report"
+	// OrphanPolicyAdopt adds the orphaned apps to persistence and recreates
+	// the missing objects
+	OrphanPolicyAdopt = "adopt"
+	// OrphanPolicyGC deletes the orphaned objects and recreates the missing
+	// objects
+	OrphanPolicyGC = "gc"
+)
+
 // Controller aggregates controller services.
 type Controller struct {
 	OrchestrationMode OrchestrationMode
@@ -44,6 +67,12 @@ type Controller struct {
 	TokenService       *jose.JWSTokenIssuer
 	AdminCreds         *AuthCreds
 
+	// LogReaderCreds are the credentials of the user allowed to read the logs
+	// of apps and nothing else.
+	//
+	// If LogReaderCreds is nil only the admin can log in.
+	LogReaderCreds *AuthCreds
+
 	// The edge node's port that it listens on for gRPC connections from the
 	// Controller and serves Mm5-related endpoints for application and network
 	// policy configuration.
@@ -61,6 +90,44 @@ type Controller struct {
 	// EdgeNodeCreds are the transport credentials for connecting to an edge
 	// node. The server name will be overridden.
 	EdgeNodeCreds *tls.Config
+
+	// QueueOfflineOperations queues operations for nodes that cannot be
+	// reached instead of failing them. Queued operations are replayed in
+	// order once the node is seen again.
+	QueueOfflineOperations bool
+
+	// Overcommit is the handling of deployments that request more resources
+	// than a node has left, either OvercommitReject or OvercommitWarn.
+	//
+	// If Overcommit is empty deployments are rejected.
+	Overcommit string
+
+	// AppDNSDomain registers the Service of each app deployed in Kubernetes
+	// mode as an A record <app_id>.<AppDNSDomain> in the DNS of its node.
+	//
+	// If AppDNSDomain is empty no records are registered.
+	AppDNSDomain string
+
+	// AppTrustStore verifies the signatures of apps when they are created,
+	// updated, deployed or upgraded.
+	//
+	// If AppTrustStore is nil signed apps are rejected and unsigned apps
+	// accepted. Otherwise apps must be signed with one of its keys.
+	AppTrustStore *TrustStore
+
+	// OrphanPolicy is the handling of differences between the Kubernetes
+	// objects of apps and nodes_apps and nodes_apps_traffic_policies, either
+	// OrphanPolicyReport, OrphanPolicyAdopt or OrphanPolicyGC.
+	//
+	// If OrphanPolicy is empty differences are reported.
+	OrphanPolicy string
+
+	// Revocations caches whether the credentials of nodes were revoked when
+	// they were decommissioned.
+	//
+	// If Revocations is nil the credentials are read on every gRPC request of
+	// a node.
+	Revocations *RevocationCache
 }
 
 // PersistenceService manages entity persistence. The methods with zv parameters take a zero-value Persistable for
@@ -132,6 +199,44 @@ func getIP(ctx context.Context, ps PersistenceService, nodeID string) (string, e
 	return "", fmt.Errorf("IP for %v not found", nodeID)
 }
 
+// ProxyListener wraps the listener of the proxy and reports the address of
+// every node that opens an ELA or EVA connection to the controller.
+type ProxyListener struct {
+	net.Listener
+
+	// OnConnect is called in a new goroutine with the node's address.
+	OnConnect func(addr string)
+}
+
+// Accept waits for and returns the next connection to the listener.
+func (l *ProxyListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return &prefaceConn{Conn: conn, onConnect: l.OnConnect}, nil
+}
+
+// prefaceConn inspects the first packet read from a connection, which is the
+// preface sent by the node's agents when they connect through the proxy.
+type prefaceConn struct {
+	net.Conn
+
+	onConnect func(addr string)
+	once      sync.Once
+}
+
+func (c *prefaceConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	c.once.Do(func() {
+		if p := string(b[:n]); c.onConnect != nil && (p == "ELA" || p == "EVA") {
+			addr, _, _ := net.SplitHostPort(c.RemoteAddr().String())
+			go c.onConnect(addr)
+		}
+	})
+	return n, err
+}
+
 // Inform the proxy we're serving this host
 func RegisterToProxy(ctx context.Context, ps PersistenceService, nodeID string) {
 	ip, err := getIP(ctx, ps, nodeID)
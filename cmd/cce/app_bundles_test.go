@@ -0,0 +1,377 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package main_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/open-ness/edgecontroller/swagger"
+	"github.com/open-ness/edgecontroller/uuid"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+func postAppBundles(dbID, webID string) (id string) {
+	By("Sending a POST /app_bundles request")
+	resp, err := apiCli.Post(
+		"http://127.0.0.1:8080/app_bundles",
+		"application/json",
+		strings.NewReader(fmt.Sprintf(`
+			{
+				"name": "shop",
+				"members": [
+					{"app_id": "%s", "depends_on": ["%s"]},
+					{"app_id": "%s"}
+				]
+			}`, webID, dbID, dbID)))
+	Expect(err).ToNot(HaveOccurred())
+	defer resp.Body.Close()
+
+	By("Verifying a 201 Created response")
+	Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+
+	var rb respBody
+
+	By("Unmarshaling the response")
+	Expect(json.NewDecoder(resp.Body).Decode(&rb)).To(Succeed())
+
+	return rb.ID
+}
+
+func postNodeAppBundles(nodeID, bundleID string) *http.Response {
+	By("Sending a POST /nodes/{node_id}/app_bundles request")
+	resp, err := apiCli.Post(
+		fmt.Sprintf("http://127.0.0.1:8080/nodes/%s/app_bundles", nodeID),
+		"application/json",
+		strings.NewReader(fmt.Sprintf(`{"id": "%s"}`, bundleID)))
+	Expect(err).ToNot(HaveOccurred())
+	return resp
+}
+
+func getNodeAppBundle(nodeID, bundleID string) *swagger.NodeAppBundleDetail {
+	By("Sending a GET /nodes/{node_id}/app_bundles/{bundle_id} request")
+	resp, err := apiCli.Get(
+		fmt.Sprintf("http://127.0.0.1:8080/nodes/%s/app_bundles/%s", nodeID, bundleID))
+	Expect(err).ToNot(HaveOccurred())
+	defer resp.Body.Close()
+
+	By("Verifying a 200 OK response")
+	Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+	var bundle *swagger.NodeAppBundleDetail
+
+	By("Unmarshaling the response")
+	Expect(json.NewDecoder(resp.Body).Decode(&bundle)).To(Succeed())
+
+	return bundle
+}
+
+var _ = Describe("/app_bundles", func() {
+	var (
+		dbID  string
+		webID string
+	)
+
+	BeforeEach(func() {
+		dbID = postApps("container")
+		webID = postApps("container")
+	})
+
+	Describe("POST /app_bundles", func() {
+		It("Should create a bundle", func() {
+			id := postAppBundles(dbID, webID)
+
+			By("Sending a GET /app_bundles/{bundle_id} request")
+			resp, err := apiCli.Get(fmt.Sprintf("http://127.0.0.1:8080/app_bundles/%s", id))
+			Expect(err).ToNot(HaveOccurred())
+			defer resp.Body.Close()
+
+			By("Verifying a 200 OK response")
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+			var bundle swagger.AppBundleDetail
+
+			By("Unmarshaling the response")
+			Expect(json.NewDecoder(resp.Body).Decode(&bundle)).To(Succeed())
+
+			By("Verifying the members")
+			Expect(bundle.Name).To(Equal("shop"))
+			Expect(bundle.Members).To(Equal([]swagger.AppBundleMember{
+				{AppID: webID, DependsOn: []string{dbID}},
+				{AppID: dbID},
+			}))
+		})
+
+		DescribeTable("400 Bad Request",
+			func(members func() string, expectedResp func() string) {
+				By("Sending a POST /app_bundles request")
+				resp, err := apiCli.Post(
+					"http://127.0.0.1:8080/app_bundles",
+					"application/json",
+					strings.NewReader(fmt.Sprintf(`{"name": "shop", "members": %s}`, members())))
+				Expect(err).ToNot(HaveOccurred())
+				defer resp.Body.Close()
+
+				By("Verifying a 400 Bad Request response")
+				Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+
+				By("Reading the response body")
+				body, err := ioutil.ReadAll(resp.Body)
+				Expect(err).ToNot(HaveOccurred())
+
+				By("Verifying the response body")
+				Expect(string(body)).To(Equal(expectedResp()))
+			},
+			Entry("POST /app_bundles without members",
+				func() string { return "[]" },
+				func() string { return "Validation failed: members cannot be empty" }),
+			Entry("POST /app_bundles with a dependency cycle",
+				func() string {
+					return fmt.Sprintf(`[
+						{"app_id": "%s", "depends_on": ["%s"]},
+						{"app_id": "%s", "depends_on": ["%s"]}
+					]`, webID, dbID, dbID, webID)
+				},
+				func() string {
+					return fmt.Sprintf(
+						"Validation failed: members.depends_on forms a cycle between app_ids %s, %s", webID, dbID)
+				}),
+		)
+
+		It("Should not create a bundle with a nonexistent app", func() {
+			missingID := uuid.New()
+
+			By("Sending a POST /app_bundles request")
+			resp, err := apiCli.Post(
+				"http://127.0.0.1:8080/app_bundles",
+				"application/json",
+				strings.NewReader(fmt.Sprintf(`{"name": "shop", "members": [{"app_id": "%s"}]}`, missingID)))
+			Expect(err).ToNot(HaveOccurred())
+			defer resp.Body.Close()
+
+			By("Verifying a 422 Unprocessable Entity response")
+			Expect(resp.StatusCode).To(Equal(http.StatusUnprocessableEntity))
+
+			By("Reading the response body")
+			body, err := ioutil.ReadAll(resp.Body)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(body)).To(Equal(fmt.Sprintf("members.app_id %s not found", missingID)))
+		})
+	})
+
+	Describe("/nodes/{node_id}/app_bundles", func() {
+		var (
+			nodeCfg  *nodeConfig
+			bundleID string
+		)
+
+		BeforeEach(func() {
+			nodeCfg = createAndRegisterNode()
+			bundleID = postAppBundles(dbID, webID)
+		})
+
+		It("Should deploy, stop and undeploy the bundle in dependency order", func() {
+			resp := postNodeAppBundles(nodeCfg.nodeID, bundleID)
+			defer resp.Body.Close()
+
+			By("Verifying a 202 Accepted response")
+			Expect(resp.StatusCode).To(Equal(http.StatusAccepted))
+			waitForOperation(resp)
+
+			By("Verifying the bundle is running with its members in order")
+			Expect(getNodeAppBundle(nodeCfg.nodeID, bundleID)).To(Equal(&swagger.NodeAppBundleDetail{
+				NodeAppBundleSummary: swagger.NodeAppBundleSummary{
+					ID:     bundleID,
+					Status: "running",
+				},
+				Members: []swagger.NodeAppBundleMember{
+					{AppID: dbID, Status: "running"},
+					{AppID: webID, Status: "running"},
+				},
+			}))
+
+			By("Sending a PATCH /nodes/{node_id}/app_bundles/{bundle_id} request")
+			resp2, err := apiCli.Patch(
+				fmt.Sprintf("http://127.0.0.1:8080/nodes/%s/app_bundles/%s", nodeCfg.nodeID, bundleID),
+				"application/json",
+				strings.NewReader(`{"command": "stop"}`))
+			Expect(err).ToNot(HaveOccurred())
+			defer resp2.Body.Close()
+
+			By("Verifying a 202 Accepted response")
+			Expect(resp2.StatusCode).To(Equal(http.StatusAccepted))
+			waitForOperation(resp2)
+			Expect(getNodeAppBundle(nodeCfg.nodeID, bundleID).Status).To(Equal("stopped"))
+
+			By("Verifying a member of the bundle cannot be deleted")
+			resp3, err := apiCli.Delete(fmt.Sprintf("http://127.0.0.1:8080/apps/%s", dbID))
+			Expect(err).ToNot(HaveOccurred())
+			defer resp3.Body.Close()
+			Expect(resp3.StatusCode).To(Equal(http.StatusUnprocessableEntity))
+
+			By("Sending a DELETE /nodes/{node_id}/app_bundles/{bundle_id} request")
+			resp4, err := apiCli.Delete(
+				fmt.Sprintf("http://127.0.0.1:8080/nodes/%s/app_bundles/%s", nodeCfg.nodeID, bundleID))
+			Expect(err).ToNot(HaveOccurred())
+			defer resp4.Body.Close()
+
+			By("Verifying a 202 Accepted response")
+			Expect(resp4.StatusCode).To(Equal(http.StatusAccepted))
+			waitForOperation(resp4)
+
+			By("Verifying the members are undeployed")
+			Expect(getNodeApps(nodeCfg.nodeID).NodeApps).To(BeEmpty())
+
+			By("Sending a GET /nodes/{node_id}/app_bundles request")
+			resp5, err := apiCli.Get(
+				fmt.Sprintf("http://127.0.0.1:8080/nodes/%s/app_bundles", nodeCfg.nodeID))
+			Expect(err).ToNot(HaveOccurred())
+			defer resp5.Body.Close()
+
+			var bundles swagger.NodeAppBundleList
+
+			By("Verifying the bundle is removed from the node")
+			Expect(json.NewDecoder(resp5.Body).Decode(&bundles)).To(Succeed())
+			Expect(bundles.NodeAppBundles).To(BeEmpty())
+		})
+
+		It("Should not deploy a bundle with a member already deployed", func() {
+			postNodeApps(nodeCfg.nodeID, dbID)
+
+			resp := postNodeAppBundles(nodeCfg.nodeID, bundleID)
+			defer resp.Body.Close()
+
+			By("Verifying a 422 Unprocessable Entity response")
+			Expect(resp.StatusCode).To(Equal(http.StatusUnprocessableEntity))
+
+			By("Reading the response body")
+			body, err := ioutil.ReadAll(resp.Body)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(body)).To(Equal(fmt.Sprintf(
+				"duplicate record in nodes_apps detected for node_id %s and app_id %s",
+				nodeCfg.nodeID, dbID)))
+		})
+
+		It("Should not deploy a bundle whose members together over-commit the node", func() {
+			By("Sending a PATCH /nodes/{node_id}/capacity request")
+			resp, err := apiCli.Patch(
+				fmt.Sprintf("http://127.0.0.1:8080/nodes/%s/capacity", nodeCfg.nodeID),
+				"application/json",
+				strings.NewReader(`{"cores": 6, "memory": 4096}`))
+			Expect(err).ToNot(HaveOccurred())
+			resp.Body.Close()
+			Expect(resp.StatusCode).To(Equal(http.StatusNoContent))
+
+			resp2 := postNodeAppBundles(nodeCfg.nodeID, bundleID)
+			defer resp2.Body.Close()
+
+			By("Verifying a 422 Unprocessable Entity response")
+			Expect(resp2.StatusCode).To(Equal(http.StatusUnprocessableEntity))
+
+			By("Reading the response body")
+			body, err := ioutil.ReadAll(resp2.Body)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(body)).To(Equal(fmt.Sprintf(
+				"bundle %s over-commits node %s: [cores: 8 requested, 0 of 6 allocated]",
+				bundleID, nodeCfg.nodeID)))
+		})
+
+		It("Should not update a bundle while it is being deployed", func() {
+			By("Deferring an operation on the node")
+			appID := postApps("container")
+			postNodeApps(nodeCfg.nodeID, appID)
+			// the window opens once a year for a minute
+			resp, err := apiCli.Post(
+				fmt.Sprintf("http://127.0.0.1:8080/nodes/%s/maintenance_windows", nodeCfg.nodeID),
+				"application/json",
+				strings.NewReader(`{"cron": "0 0 1 1 *", "time_zone": "UTC", "duration": 1}`))
+			Expect(err).ToNot(HaveOccurred())
+			resp.Body.Close()
+			Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+			resp, err = apiCli.Patch(
+				fmt.Sprintf("http://127.0.0.1:8080/nodes/%s/apps/%s", nodeCfg.nodeID, appID),
+				"application/json",
+				strings.NewReader(`{"command": "restart"}`))
+			Expect(err).ToNot(HaveOccurred())
+			resp.Body.Close()
+			Expect(resp.StatusCode).To(Equal(http.StatusAccepted))
+
+			resp2 := postNodeAppBundles(nodeCfg.nodeID, bundleID)
+			defer resp2.Body.Close()
+			Expect(resp2.StatusCode).To(Equal(http.StatusAccepted))
+			body, err := ioutil.ReadAll(resp2.Body)
+			Expect(err).ToNot(HaveOccurred())
+			var op swagger.OperationSummary
+			Expect(json.Unmarshal(body, &op)).To(Succeed())
+			Expect(op.State).To(Equal("deferred"))
+
+			By("Verifying the members of the bundle being deployed are requested")
+			Expect(getNode(nodeCfg.nodeID).Utilization.Requested).To(Equal(
+				swagger.NodeResources{
+					Cores:  12,
+					Memory: 3072,
+				}))
+
+			By("Sending a PATCH /app_bundles/{bundle_id} request")
+			resp3, err := apiCli.Patch(
+				fmt.Sprintf("http://127.0.0.1:8080/app_bundles/%s", bundleID),
+				"application/json",
+				strings.NewReader(fmt.Sprintf(`{"name": "shop", "members": [{"app_id": "%s"}]}`, dbID)))
+			Expect(err).ToNot(HaveOccurred())
+			defer resp3.Body.Close()
+
+			By("Verifying a 422 Unprocessable Entity response")
+			Expect(resp3.StatusCode).To(Equal(http.StatusUnprocessableEntity))
+
+			By("Reading the response body")
+			body, err = ioutil.ReadAll(resp3.Body)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(body)).To(Equal(fmt.Sprintf(
+				"operation %s is deferred for node_id %s and bundle_id %s",
+				op.ID, nodeCfg.nodeID, bundleID)))
+		})
+
+		It("Should not delete a deployed bundle", func() {
+			resp := postNodeAppBundles(nodeCfg.nodeID, bundleID)
+			defer resp.Body.Close()
+			waitForOperation(resp)
+
+			By("Sending a DELETE /app_bundles/{bundle_id} request")
+			resp2, err := apiCli.Delete(fmt.Sprintf("http://127.0.0.1:8080/app_bundles/%s", bundleID))
+			Expect(err).ToNot(HaveOccurred())
+			defer resp2.Body.Close()
+
+			By("Verifying a 422 Unprocessable Entity response")
+			Expect(resp2.StatusCode).To(Equal(http.StatusUnprocessableEntity))
+
+			By("Reading the response body")
+			body, err := ioutil.ReadAll(resp2.Body)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(body)).To(Equal(fmt.Sprintf(
+				"cannot delete bundle_id %s: record in use in nodes_app_bundles", bundleID)))
+		})
+
+		DescribeTable("404 Not Found",
+			func(path func() string) {
+				By("Sending a GET request")
+				resp, err := apiCli.Get("http://127.0.0.1:8080" + path())
+				Expect(err).ToNot(HaveOccurred())
+				defer resp.Body.Close()
+
+				By("Verifying a 404 Not Found response")
+				Expect(resp.StatusCode).To(Equal(http.StatusNotFound))
+			},
+			Entry("GET /nodes/{node_id}/app_bundles with nonexistent node ID",
+				func() string { return fmt.Sprintf("/nodes/%s/app_bundles", uuid.New()) }),
+			Entry("GET /nodes/{node_id}/app_bundles/{bundle_id} with a bundle not deployed",
+				func() string { return fmt.Sprintf("/nodes/%s/app_bundles/%s", nodeCfg.nodeID, bundleID) }),
+		)
+	})
+})
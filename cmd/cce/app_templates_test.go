@@ -0,0 +1,282 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package main_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/open-ness/edgecontroller/swagger"
+	"github.com/open-ness/edgecontroller/uuid"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+const appTemplateBody = `
+	{
+		"name": "video analytics",
+		"parameters": [
+			{"name": "site", "type": "string"},
+			{"name": "memory", "type": "int", "default": 512}
+		],
+		"spec": {
+			"type": "container",
+			"name": "va {{site}}",
+			"version": "latest",
+			"vendor": "smart edge",
+			"cores": 1,
+			"memory": "{{memory}}",
+			"ports": [{"port": 80, "protocol": "tcp"}],
+			"source": "http://www.test.com/my_va_app.tar.gz"
+		}
+	}`
+
+func postAppTemplates() (id string) {
+	By("Sending a POST /app_templates request")
+	resp, err := apiCli.Post(
+		"http://127.0.0.1:8080/app_templates",
+		"application/json",
+		strings.NewReader(appTemplateBody))
+	Expect(err).ToNot(HaveOccurred())
+	defer resp.Body.Close()
+
+	By("Verifying a 201 Created response")
+	Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+
+	var rb respBody
+
+	By("Unmarshaling the response")
+	Expect(json.NewDecoder(resp.Body).Decode(&rb)).To(Succeed())
+
+	return rb.ID
+}
+
+func getAppTemplate(id string) *swagger.AppTemplateDetail {
+	By("Sending a GET /app_templates/{template_id} request")
+	resp, err := apiCli.Get(fmt.Sprintf("http://127.0.0.1:8080/app_templates/%s", id))
+	Expect(err).ToNot(HaveOccurred())
+	defer resp.Body.Close()
+
+	By("Verifying a 200 OK response")
+	Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+	var template *swagger.AppTemplateDetail
+
+	By("Unmarshaling the response")
+	Expect(json.NewDecoder(resp.Body).Decode(&template)).To(Succeed())
+
+	return template
+}
+
+func postNodeAppTemplates(nodeID, templateID, parameters string) *http.Response {
+	By("Sending a POST /nodes/{node_id}/apps request with a template")
+	resp, err := apiCli.Post(
+		fmt.Sprintf("http://127.0.0.1:8080/nodes/%s/apps", nodeID),
+		"application/json",
+		strings.NewReader(fmt.Sprintf(`{"template_id": "%s", "parameters": %s}`, templateID, parameters)))
+	Expect(err).ToNot(HaveOccurred())
+	return resp
+}
+
+var _ = Describe("/app_templates", func() {
+	Describe("POST /app_templates", func() {
+		It("Should create the first version of a template", func() {
+			template := getAppTemplate(postAppTemplates())
+
+			By("Verifying the template")
+			Expect(template.Name).To(Equal("video analytics"))
+			Expect(template.Version).To(Equal(1))
+			Expect(template.Parameters).To(HaveLen(2))
+		})
+
+		DescribeTable("400 Bad Request",
+			func(body, expectedResp string) {
+				By("Sending a POST /app_templates request")
+				resp, err := apiCli.Post(
+					"http://127.0.0.1:8080/app_templates",
+					"application/json",
+					strings.NewReader(body))
+				Expect(err).ToNot(HaveOccurred())
+				defer resp.Body.Close()
+
+				By("Verifying a 400 Bad Request response")
+				Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+
+				By("Reading the response body")
+				respBody, err := ioutil.ReadAll(resp.Body)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(string(respBody)).To(Equal(expectedResp))
+			},
+			Entry("POST /app_templates with an undeclared parameter",
+				`{"name": "va", "spec": {"name": "va {{site}}"}}`,
+				"Validation failed: spec references undeclared parameter site"),
+			Entry("POST /app_templates with a default of the wrong type",
+				`{"name": "va", "parameters": [{"name": "memory", "type": "int", "default": "512"}], "spec": {}}`,
+				"Validation failed: parameters.default of memory must be of type int"),
+		)
+	})
+
+	Describe("PATCH /app_templates/{template_id}", func() {
+		It("Should increment the version of the template", func() {
+			id := postAppTemplates()
+
+			By("Sending a PATCH /app_templates/{template_id} request")
+			resp, err := apiCli.Patch(
+				fmt.Sprintf("http://127.0.0.1:8080/app_templates/%s", id),
+				"application/json",
+				strings.NewReader(appTemplateBody))
+			Expect(err).ToNot(HaveOccurred())
+			defer resp.Body.Close()
+
+			By("Verifying a 200 OK response")
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			Expect(getAppTemplate(id).Version).To(Equal(2))
+		})
+	})
+
+	Describe("POST /nodes/{node_id}/apps with a template", func() {
+		var (
+			nodeCfg    *nodeConfig
+			templateID string
+		)
+
+		BeforeEach(func() {
+			nodeCfg = createAndRegisterNode()
+			templateID = postAppTemplates()
+		})
+
+		It("Should deploy an app instantiated from the template", func() {
+			resp := postNodeAppTemplates(nodeCfg.nodeID, templateID, `{"site": "dublin"}`)
+			defer resp.Body.Close()
+
+			By("Verifying a 202 Accepted response")
+			Expect(resp.StatusCode).To(Equal(http.StatusAccepted))
+
+			By("Reading the operation from the response body")
+			body, err := ioutil.ReadAll(resp.Body)
+			Expect(err).ToNot(HaveOccurred())
+			var op swagger.OperationSummary
+			Expect(json.Unmarshal(body, &op)).To(Succeed())
+			Eventually(func() string {
+				return getOperation(op.ID).State
+			}, "15s", "250ms").Should(Equal("succeeded"))
+			appID := op.AppID
+
+			By("Verifying the app instantiated from the template")
+			app := getApp(appID)
+			Expect(app.Name).To(Equal("va dublin"))
+			Expect(app.Memory).To(Equal(512))
+
+			By("Verifying the node app records the template")
+			nodeApp := getNodeAppByID(nodeCfg.nodeID, appID)
+			Expect(nodeApp.TemplateID).To(Equal(templateID))
+			Expect(nodeApp.TemplateVersion).To(Equal(1))
+			Expect(nodeApp.TemplateParameters).To(Equal(map[string]json.RawMessage{
+				"site":   json.RawMessage(`"dublin"`),
+				"memory": json.RawMessage(`512`),
+			}))
+
+			By("Verifying the template cannot be deleted while the app is deployed")
+			resp2, err := apiCli.Delete(fmt.Sprintf("http://127.0.0.1:8080/app_templates/%s", templateID))
+			Expect(err).ToNot(HaveOccurred())
+			defer resp2.Body.Close()
+			Expect(resp2.StatusCode).To(Equal(http.StatusUnprocessableEntity))
+
+			By("Sending a DELETE /nodes/{node_id}/apps/{app_id} request")
+			resp3, err := apiCli.Delete(
+				fmt.Sprintf("http://127.0.0.1:8080/nodes/%s/apps/%s", nodeCfg.nodeID, appID))
+			Expect(err).ToNot(HaveOccurred())
+			defer resp3.Body.Close()
+			Expect(resp3.StatusCode).To(Equal(http.StatusAccepted))
+			waitForOperation(resp3)
+
+			By("Verifying the app instantiated from the template is deleted")
+			resp4, err := apiCli.Get(fmt.Sprintf("http://127.0.0.1:8080/apps/%s", appID))
+			Expect(err).ToNot(HaveOccurred())
+			defer resp4.Body.Close()
+			Expect(resp4.StatusCode).To(Equal(http.StatusNotFound))
+		})
+
+		It("Should delete the app of a canceled deploy", func() {
+			By("Deferring an operation on the node")
+			appID := postApps("container")
+			postNodeApps(nodeCfg.nodeID, appID)
+			// the window opens once a year for a minute
+			resp, err := apiCli.Post(
+				fmt.Sprintf("http://127.0.0.1:8080/nodes/%s/maintenance_windows", nodeCfg.nodeID),
+				"application/json",
+				strings.NewReader(`{"cron": "0 0 1 1 *", "time_zone": "UTC", "duration": 1}`))
+			Expect(err).ToNot(HaveOccurred())
+			resp.Body.Close()
+			Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+			resp, err = apiCli.Patch(
+				fmt.Sprintf("http://127.0.0.1:8080/nodes/%s/apps/%s", nodeCfg.nodeID, appID),
+				"application/json",
+				strings.NewReader(`{"command": "restart"}`))
+			Expect(err).ToNot(HaveOccurred())
+			resp.Body.Close()
+			Expect(resp.StatusCode).To(Equal(http.StatusAccepted))
+
+			resp2 := postNodeAppTemplates(nodeCfg.nodeID, templateID, `{"site": "dublin"}`)
+			defer resp2.Body.Close()
+			Expect(resp2.StatusCode).To(Equal(http.StatusAccepted))
+			body, err := ioutil.ReadAll(resp2.Body)
+			Expect(err).ToNot(HaveOccurred())
+			var op swagger.OperationSummary
+			Expect(json.Unmarshal(body, &op)).To(Succeed())
+
+			By("Verifying the deploy is deferred behind the operation")
+			Expect(op.State).To(Equal("deferred"))
+
+			By("Canceling the deploy")
+			resp3, err := apiCli.Delete(
+				fmt.Sprintf("http://127.0.0.1:8080/nodes/%s/queue/%s", nodeCfg.nodeID, op.ID))
+			Expect(err).ToNot(HaveOccurred())
+			defer resp3.Body.Close()
+			Expect(resp3.StatusCode).To(Equal(http.StatusNoContent))
+
+			By("Verifying the app instantiated from the template is deleted")
+			resp4, err := apiCli.Get(fmt.Sprintf("http://127.0.0.1:8080/apps/%s", op.AppID))
+			Expect(err).ToNot(HaveOccurred())
+			defer resp4.Body.Close()
+			Expect(resp4.StatusCode).To(Equal(http.StatusNotFound))
+		})
+
+		DescribeTable("400 Bad Request",
+			func(parameters, expectedResp string) {
+				resp := postNodeAppTemplates(nodeCfg.nodeID, templateID, parameters)
+				defer resp.Body.Close()
+
+				By("Verifying a 400 Bad Request response")
+				Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+
+				By("Reading the response body")
+				body, err := ioutil.ReadAll(resp.Body)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(string(body)).To(Equal(expectedResp))
+			},
+			Entry("POST /nodes/{node_id}/apps without a required parameter", `{}`,
+				"Validation failed: parameter site is required"),
+			Entry("POST /nodes/{node_id}/apps with a parameter of the wrong type",
+				`{"site": "dublin", "memory": "lots"}`,
+				"Validation failed: parameter memory must be of type int"),
+			Entry("POST /nodes/{node_id}/apps with an invalid app",
+				`{"site": "dublin", "memory": 0}`,
+				"Validation failed: instantiated app is invalid: memory must be in [1..16384]"),
+		)
+
+		It("Should return 404 Not Found for a nonexistent template", func() {
+			resp := postNodeAppTemplates(nodeCfg.nodeID, uuid.New(), `{}`)
+			defer resp.Body.Close()
+
+			By("Verifying a 404 Not Found response")
+			Expect(resp.StatusCode).To(Equal(http.StatusNotFound))
+		})
+	})
+})
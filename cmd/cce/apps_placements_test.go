@@ -0,0 +1,170 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package main_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/open-ness/edgecontroller/swagger"
+	"github.com/open-ness/edgecontroller/uuid"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("/apps/{app_id}/placements", func() {
+	postAppPlacements := func(appID, reqStr string) *http.Response {
+		By("Sending a POST /apps/{app_id}/placements request")
+		resp, err := apiCli.Post(
+			fmt.Sprintf("http://127.0.0.1:8080/apps/%s/placements", appID),
+			"application/json",
+			strings.NewReader(reqStr))
+		Expect(err).ToNot(HaveOccurred())
+		return resp
+	}
+
+	readPlacementResult := func(resp *http.Response) swagger.PlacementResult {
+		By("Reading the response body")
+		body, err := ioutil.ReadAll(resp.Body)
+		Expect(err).ToNot(HaveOccurred())
+
+		var result swagger.PlacementResult
+
+		By("Unmarshaling the response")
+		Expect(json.Unmarshal(body, &result)).To(Succeed())
+
+		return result
+	}
+
+	var (
+		feature        string
+		firstNodeCfg   *nodeConfig
+		secondNodeCfg  *nodeConfig
+		containerAppID string
+	)
+
+	BeforeEach(func() {
+		clearGRPCTargetsTable()
+		// The feature is unique to this test so nodes of other tests never
+		// match the selector
+		feature = uuid.New()
+		firstNodeCfg = createAndRegisterNode()
+		secondNodeCfg = createAndRegisterNode()
+		containerAppID = postApps("container")
+
+		insertNFDTags(fmt.Sprintf(
+			`'{"id": "%s", "node_id": "%s", "nfd_id": "%s", "nfd_value": "true"}'`,
+			uuid.New(), firstNodeCfg.nodeID, feature))
+		insertNFDTags(fmt.Sprintf(
+			`'{"id": "%s", "node_id": "%s", "nfd_id": "%s", "nfd_value": "false"}'`,
+			uuid.New(), secondNodeCfg.nodeID, feature))
+	})
+
+	Describe("POST /apps/{app_id}/placements", func() {
+		It("Should deploy the app to the nodes matching the selector", func() {
+			resp := postAppPlacements(containerAppID, fmt.Sprintf(`
+				{
+					"replicas": 1,
+					"node_selector": ["%s=true"]
+				}`, feature))
+			defer resp.Body.Close()
+
+			By("Verifying a 202 Accepted response")
+			Expect(resp.StatusCode).To(Equal(http.StatusAccepted))
+
+			result := readPlacementResult(resp)
+
+			By("Verifying the app was placed on the matching node")
+			Expect(result.Strategy).To(Equal("spread"))
+			Expect(result.Placed).To(HaveLen(1))
+			Expect(result.Placed[0].NodeID).To(Equal(firstNodeCfg.nodeID))
+
+			By("Verifying the reason the other node was rejected")
+			Expect(result.Rejected).To(ContainElement(swagger.RejectedNode{
+				NodeID: secondNodeCfg.nodeID,
+				Reasons: []string{fmt.Sprintf(
+					"node selector %s=true not matched, node reports false", feature)},
+			}))
+
+			By("Waiting for the deployment to succeed")
+			Eventually(func() string {
+				return getOperation(result.Placed[0].Operation.ID).State
+			}, 15*time.Second, 250*time.Millisecond).Should(Equal("succeeded"))
+			Expect(getNodeApps(firstNodeCfg.nodeID).NodeApps).To(HaveLen(1))
+		})
+
+		It("Should reject a node the app is already deployed to", func() {
+			postNodeApps(firstNodeCfg.nodeID, containerAppID)
+
+			resp := postAppPlacements(containerAppID, fmt.Sprintf(`
+				{
+					"replicas": 1,
+					"node_selector": ["%s=true"]
+				}`, feature))
+			defer resp.Body.Close()
+
+			By("Verifying a 422 Unprocessable Entity response")
+			Expect(resp.StatusCode).To(Equal(http.StatusUnprocessableEntity))
+
+			result := readPlacementResult(resp)
+
+			By("Verifying nothing was placed")
+			Expect(result.Placed).To(BeEmpty())
+			Expect(result.Rejected).To(ContainElement(swagger.RejectedNode{
+				NodeID:  firstNodeCfg.nodeID,
+				Reasons: []string{"app is already deployed to the node"},
+			}))
+		})
+
+		It("Should not deploy anything if not all replicas can be placed", func() {
+			resp := postAppPlacements(containerAppID, fmt.Sprintf(`
+				{
+					"replicas": 2,
+					"node_selector": ["%s=true"],
+					"strategy": "pack"
+				}`, feature))
+			defer resp.Body.Close()
+
+			By("Verifying a 422 Unprocessable Entity response")
+			Expect(resp.StatusCode).To(Equal(http.StatusUnprocessableEntity))
+
+			result := readPlacementResult(resp)
+
+			By("Verifying nothing was placed")
+			Expect(result.Placed).To(BeEmpty())
+			Expect(getNodeApps(firstNodeCfg.nodeID).NodeApps).To(BeEmpty())
+		})
+
+		It("Should return 400 if the strategy is invalid", func() {
+			resp := postAppPlacements(containerAppID, `
+				{
+					"replicas": 1,
+					"strategy": "random"
+				}`)
+			defer resp.Body.Close()
+
+			By("Verifying a 400 Bad Request response")
+			Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+
+			By("Reading the response body")
+			body, err := ioutil.ReadAll(resp.Body)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(body)).To(Equal(
+				`Validation failed: strategy must be either "spread" or "pack"`))
+		})
+
+		It("Should return 404 if the app does not exist", func() {
+			resp := postAppPlacements(uuid.New(), `{"replicas": 1}`)
+			defer resp.Body.Close()
+
+			By("Verifying a 404 Not Found response")
+			Expect(resp.StatusCode).To(Equal(http.StatusNotFound))
+		})
+	})
+})
@@ -76,6 +76,32 @@ var _ = Describe("/apps", func() {
 					"ports": [{"port": 80, "protocol": "tcp"}],
 					"source": "http://www.test.com/my_container_app.tar.gz"
 				}`),
+			Entry(
+				"POST /apps with a registry source",
+				`
+				{
+					"name": "container app",
+					"version": "1.0",
+					"type": "container",
+					"vendor": "smart edge",
+					"cores": 4,
+					"memory": 1024,
+					"source": "docker://registry.example.com:5000/edge/app:1.0",
+					"pull_policy": "always"
+				}`),
+			Entry(
+				"POST /apps with a sha256 digest",
+				`
+				{
+					"name": "container app",
+					"version": "latest",
+					"type": "container",
+					"vendor": "smart edge",
+					"cores": 4,
+					"memory": 1024,
+					"source": "http://www.test.com/my_container_app.tar.gz",
+					"sha256": "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+				}`),
 		)
 
 		DescribeTable("400 Bad Request",
@@ -246,6 +272,155 @@ var _ = Describe("/apps", func() {
 								"source": "invalid.url"
 							}`,
 				"Validation failed: source cannot be parsed as a URI"),
+			Entry(
+				"POST /apps with an invalid env name",
+				`
+				{
+					"type": "container",
+					"name": "container app",
+					"version": "latest",
+					"vendor": "smart edge",
+					"cores": 4,
+					"memory": 1024,
+					"source": "http://www.test.com/my_container_app.tar.gz",
+					"env": [{"name": "LOG-LEVEL", "value": "debug"}]
+				}`,
+				`Validation failed: env name "LOG-LEVEL" is not a valid environment variable name`),
+			Entry(
+				"POST /apps with a relative config file path",
+				`
+				{
+					"type": "container",
+					"name": "container app",
+					"version": "latest",
+					"vendor": "smart edge",
+					"cores": 4,
+					"memory": 1024,
+					"source": "http://www.test.com/my_container_app.tar.gz",
+					"config_files": [{"path": "app.conf", "content": "port=8080"}]
+				}`,
+				`Validation failed: config file path "app.conf" must be an absolute path`),
+			Entry(
+				"POST /apps with an invalid restart policy",
+				`
+				{
+					"type": "container",
+					"name": "container app",
+					"version": "latest",
+					"vendor": "smart edge",
+					"cores": 4,
+					"memory": 1024,
+					"source": "http://www.test.com/my_container_app.tar.gz",
+					"restart_policy": "sometimes"
+				}`,
+				`Validation failed: restart_policy must be "always", "on-failure" or "never"`),
+			Entry(
+				"POST /apps with an invalid tenant",
+				`
+				{
+					"type": "container",
+					"name": "container app",
+					"version": "latest",
+					"vendor": "smart edge",
+					"cores": 4,
+					"memory": 1024,
+					"source": "http://www.test.com/my_container_app.tar.gz",
+					"tenant": "Operator A"
+				}`,
+				"Validation failed: tenant must be a lowercase DNS label of at most 56 characters"),
+			Entry(
+				"POST /apps with a registry source without tag or digest",
+				`
+				{
+					"type": "container",
+					"name": "container app",
+					"version": "latest",
+					"vendor": "smart edge",
+					"cores": 4,
+					"memory": 1024,
+					"source": "docker://registry.example.com/edge/app"
+				}`,
+				`Validation failed: source "docker://registry.example.com/edge/app" must be an image reference `+
+					`with a tag or digest`),
+			Entry(
+				"POST /apps with an invalid pull policy",
+				`
+				{
+					"type": "container",
+					"name": "container app",
+					"version": "latest",
+					"vendor": "smart edge",
+					"cores": 4,
+					"memory": 1024,
+					"source": "docker://registry.example.com/edge/app:1.0",
+					"pull_policy": "sometimes"
+				}`,
+				`Validation failed: pull_policy must be "always", "if-not-present" or "never"`),
+			Entry(
+				"POST /apps with an invalid sha256 digest",
+				`
+				{
+					"type": "container",
+					"name": "container app",
+					"version": "latest",
+					"vendor": "smart edge",
+					"cores": 4,
+					"memory": 1024,
+					"source": "http://www.test.com/my_container_app.tar.gz",
+					"sha256": "9f86d081"
+				}`,
+				"Validation failed: sha256 must be 64 lowercase hex characters"),
+			Entry(
+				"POST /apps with a signature without sha256 digest",
+				`
+				{
+					"type": "container",
+					"name": "container app",
+					"version": "latest",
+					"vendor": "smart edge",
+					"cores": 4,
+					"memory": 1024,
+					"source": "http://www.test.com/my_container_app.tar.gz",
+					"signature": "c2lnbmF0dXJl"
+				}`,
+				"Validation failed: signature cannot be set without sha256"),
+		)
+
+		DescribeTable("422 Unprocessable Entity",
+			func(req, expectedResp string) {
+				By("Sending a POST /apps request")
+				resp, err := apiCli.Post(
+					"http://127.0.0.1:8080/apps",
+					"application/json",
+					strings.NewReader(req))
+				Expect(err).ToNot(HaveOccurred())
+				defer resp.Body.Close()
+
+				By("Verifying a 422 response")
+				Expect(resp.StatusCode).To(Equal(http.StatusUnprocessableEntity))
+
+				By("Reading the response body")
+				body, err := ioutil.ReadAll(resp.Body)
+				Expect(err).ToNot(HaveOccurred())
+
+				By("Verifying the response body")
+				Expect(string(body)).To(ContainSubstring(expectedResp))
+			},
+			Entry(
+				"POST /apps with a signature and no trust store",
+				`
+				{
+					"type": "container",
+					"name": "container app",
+					"version": "latest",
+					"vendor": "smart edge",
+					"cores": 4,
+					"memory": 1024,
+					"source": "http://www.test.com/my_container_app.tar.gz",
+					"sha256": "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+					"signature": "c2lnbmF0dXJl"
+				}`,
+				"cannot be verified without a trust store"),
 		)
 	})
 
@@ -414,6 +589,72 @@ var _ = Describe("/apps", func() {
 					Ports:  []cce.PortProto{{Port: 80, Protocol: "tcp"}},
 					Source: "http://www.test.com/my_container_app.tar.gz",
 				}),
+			Entry(
+				"PATCH /apps/{app_id} with env, command, volumes and config files",
+				`
+					{
+						"id": "%s",
+						"type": "container",
+						"name": "container app2",
+						"version": "latest",
+						"vendor": "smart edge",
+						"description": "my container app",
+						"cores": 4,
+						"memory": 1024,
+						"ports": [{"port": 80, "protocol": "tcp"}],
+						"source": "http://www.test.com/my_container_app.tar.gz",
+						"env": [{"name": "LOG_LEVEL", "value": "debug"}],
+						"command": ["/bin/app"],
+						"args": ["-config", "/etc/app/app.conf"],
+						"volumes": [
+							{
+								"name": "data",
+								"mount_path": "/var/lib/app",
+								"host_path": "/var/lib/edge/app",
+								"read_only": true
+							}
+						],
+						"config_files": [{"path": "/etc/app/app.conf", "content": "port=8080"}],
+						"liveness_probe": {"type": "http", "path": "/healthz", "port": 80},
+						"readiness_probe": {"type": "exec", "command": ["cat", "/tmp/ready"]},
+						"restart_policy": "on-failure"
+					}
+				`,
+				&swagger.AppDetail{
+					AppSummary: swagger.AppSummary{
+						Type:        "container",
+						Name:        "container app2",
+						Version:     "latest",
+						Vendor:      "smart edge",
+						Description: "my container app",
+					},
+					Cores:   4,
+					Memory:  1024,
+					Ports:   []cce.PortProto{{Port: 80, Protocol: "tcp"}},
+					Source:  "http://www.test.com/my_container_app.tar.gz",
+					Env:     []cce.EnvVar{{Name: "LOG_LEVEL", Value: "debug"}},
+					Command: []string{"/bin/app"},
+					Args:    []string{"-config", "/etc/app/app.conf"},
+					Volumes: []cce.Volume{
+						{
+							Name:      "data",
+							MountPath: "/var/lib/app",
+							HostPath:  "/var/lib/edge/app",
+							ReadOnly:  true,
+						},
+					},
+					ConfigFiles: []cce.ConfigFile{{Path: "/etc/app/app.conf", Content: "port=8080"}},
+					LivenessProbe: &cce.Probe{
+						Type: "http",
+						Path: "/healthz",
+						Port: 80,
+					},
+					ReadinessProbe: &cce.Probe{
+						Type:    "exec",
+						Command: []string{"cat", "/tmp/ready"},
+					},
+					RestartPolicy: "on-failure",
+				}),
 			Entry("PATCH /apps/{app_id} with no description",
 				`
 					{
@@ -0,0 +1,263 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package main_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	cce "github.com/open-ness/edgecontroller"
+	"github.com/open-ness/edgecontroller/swagger"
+	"github.com/open-ness/edgecontroller/uuid"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("/apps/{app_id}/upgrade", func() {
+	postAppVersion := func(appID, version string) (id string) {
+		By("Sending a POST /apps request for a new version")
+		resp, err := apiCli.Post(
+			"http://127.0.0.1:8080/apps",
+			"application/json",
+			strings.NewReader(fmt.Sprintf(`
+				{
+					"type": "container",
+					"name": "container app",
+					"version": "%s",
+					"version_of": "%s",
+					"vendor": "smart edge",
+					"description": "my container app",
+					"cores": 4,
+					"memory": 1024,
+					"ports": [{"port": 80, "protocol": "tcp"}],
+					"source": "http://www.test.com/my_container_app_%s.tar.gz"
+				}`, version, appID, version)))
+		Expect(err).ToNot(HaveOccurred())
+		defer resp.Body.Close()
+
+		By("Verifying a 201 Created response")
+		Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+
+		By("Reading the response body")
+		body, err := ioutil.ReadAll(resp.Body)
+		Expect(err).ToNot(HaveOccurred())
+
+		var rb respBody
+
+		By("Unmarshaling the response")
+		Expect(json.Unmarshal(body, &rb)).To(Succeed())
+
+		return rb.ID
+	}
+
+	postAppUpgrade := func(appID, reqStr string) *http.Response {
+		By("Sending a POST /apps/{app_id}/upgrade request")
+		resp, err := apiCli.Post(
+			fmt.Sprintf("http://127.0.0.1:8080/apps/%s/upgrade", appID),
+			"application/json",
+			strings.NewReader(reqStr))
+		Expect(err).ToNot(HaveOccurred())
+		return resp
+	}
+
+	getUpgrade := func(url string) *swagger.AppUpgradeDetail {
+		resp, err := apiCli.Get("http://127.0.0.1:8080" + url)
+		Expect(err).ToNot(HaveOccurred())
+		defer resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+		body, err := ioutil.ReadAll(resp.Body)
+		Expect(err).ToNot(HaveOccurred())
+
+		var upgrade swagger.AppUpgradeDetail
+		Expect(json.Unmarshal(body, &upgrade)).To(Succeed())
+		return &upgrade
+	}
+
+	Describe("POST /apps/{app_id}/upgrade", func() {
+		DescribeTable("202 Accepted",
+			func(reqStr string) {
+				clearGRPCTargetsTable()
+				nodeCfg := createAndRegisterNode()
+				appID := postApps("container")
+				postNodeApps(nodeCfg.nodeID, appID)
+				versionID := postAppVersion(appID, "2.0")
+
+				resp := postAppUpgrade(appID, reqStr)
+				defer resp.Body.Close()
+
+				By("Verifying a 202 Accepted response")
+				Expect(resp.StatusCode).To(Equal(http.StatusAccepted))
+
+				By("Reading the response body")
+				body, err := ioutil.ReadAll(resp.Body)
+				Expect(err).ToNot(HaveOccurred())
+
+				var summary swagger.AppUpgradeSummary
+				Expect(json.Unmarshal(body, &summary)).To(Succeed())
+				Expect(summary.AppID).To(Equal(appID))
+				Expect(summary.TargetAppID).To(Equal(versionID))
+				Expect(resp.Header.Get("Location")).To(Equal(summary.URL))
+
+				By("Waiting for the upgrade to succeed")
+				Eventually(func() string {
+					return getUpgrade(summary.URL).State
+				}, 30*time.Second, time.Second).Should(Equal(cce.UpgradeStateSucceeded))
+
+				By("Verifying the node runs the new version")
+				upgrade := getUpgrade(summary.URL)
+				Expect(upgrade.Nodes).To(HaveLen(1))
+				Expect(upgrade.Nodes[0].State).To(Equal(cce.UpgradeNodeStateUpgraded))
+				Expect(getNodeApp(nodeCfg.nodeID, appID).VersionID).To(Equal(versionID))
+			},
+			Entry(
+				"POST /apps/{app_id}/upgrade",
+				`
+				{
+					"version": "2.0",
+					"batch_size": 2
+				}`,
+			),
+		)
+
+		DescribeTable("400 Bad Request",
+			func(reqStr string, expectedResp string) {
+				appID := postApps("container")
+
+				resp := postAppUpgrade(appID, reqStr)
+				defer resp.Body.Close()
+
+				By("Verifying a 400 Bad Request response")
+				Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+
+				By("Reading the response body")
+				body, err := ioutil.ReadAll(resp.Body)
+				Expect(err).ToNot(HaveOccurred())
+
+				By("Verifying the response body")
+				Expect(string(body)).To(Equal(expectedResp))
+			},
+			Entry(
+				"POST /apps/{app_id}/upgrade without version",
+				`
+				{
+					"batch_size": 1
+				}`,
+				"Validation failed: version cannot be empty",
+			),
+			Entry(
+				"POST /apps/{app_id}/upgrade with negative batch_size",
+				`
+				{
+					"version": "2.0",
+					"batch_size": -1
+				}`,
+				"Validation failed: batch_size must be at least 1",
+			),
+		)
+
+		DescribeTable("422 Unprocessable Entity",
+			func(reqStr string, expectedResp string) {
+				appID := postApps("container")
+				postAppVersion(appID, "2.0")
+
+				resp := postAppUpgrade(appID, reqStr)
+				defer resp.Body.Close()
+
+				By("Verifying a 422 Unprocessable Entity response")
+				Expect(resp.StatusCode).To(Equal(http.StatusUnprocessableEntity))
+
+				By("Reading the response body")
+				body, err := ioutil.ReadAll(resp.Body)
+				Expect(err).ToNot(HaveOccurred())
+
+				By("Verifying the response body")
+				Expect(string(body)).To(Equal(fmt.Sprintf(expectedResp, appID)))
+			},
+			Entry(
+				"POST /apps/{app_id}/upgrade with an unknown version",
+				`
+				{
+					"version": "3.0"
+				}`,
+				"version 3.0 of app %s not found",
+			),
+			Entry(
+				"POST /apps/{app_id}/upgrade without deployments",
+				`
+				{
+					"version": "2.0"
+				}`,
+				"no node runs a version of app %s other than 2.0",
+			),
+		)
+
+		DescribeTable("404 Not Found",
+			func() {
+				resp := postAppUpgrade(uuid.New(), `{"version": "2.0"}`)
+				defer resp.Body.Close()
+
+				By("Verifying a 404 Not Found response")
+				Expect(resp.StatusCode).To(Equal(http.StatusNotFound))
+			},
+			Entry("POST /apps/{app_id}/upgrade with nonexistent ID"),
+		)
+	})
+
+	Describe("POST /apps", func() {
+		DescribeTable("422 Unprocessable Entity",
+			func(version, tenant, expectedResp string) {
+				appID := postApps("container")
+
+				By("Sending a POST /apps request for a version")
+				resp, err := apiCli.Post(
+					"http://127.0.0.1:8080/apps",
+					"application/json",
+					strings.NewReader(fmt.Sprintf(`
+						{
+							"type": "container",
+							"name": "container app",
+							"version": "%s",
+							"version_of": "%s",
+							"tenant": "%s",
+							"vendor": "smart edge",
+							"description": "my container app",
+							"cores": 4,
+							"memory": 1024,
+							"source": "http://www.test.com/my_container_app.tar.gz"
+						}`, version, appID, tenant)))
+				Expect(err).ToNot(HaveOccurred())
+				defer resp.Body.Close()
+
+				By("Verifying a 422 Unprocessable Entity response")
+				Expect(resp.StatusCode).To(Equal(http.StatusUnprocessableEntity))
+
+				By("Reading the response body")
+				body, err := ioutil.ReadAll(resp.Body)
+				Expect(err).ToNot(HaveOccurred())
+
+				By("Verifying the response body")
+				Expect(string(body)).To(Equal(fmt.Sprintf(expectedResp, appID, appID)))
+			},
+			Entry(
+				"POST /apps with the version of the app it is a version of",
+				"latest",
+				"",
+				"version latest of app %s already exists as app %s",
+			),
+			Entry(
+				"POST /apps with a version for another tenant",
+				"2.0",
+				"operator-a",
+				`tenant "operator-a" does not match tenant "" of app %[1]s`,
+			),
+		)
+	})
+})
@@ -400,8 +400,10 @@ func postNodeApps(nodeID, appID string) {
 	Expect(err).ToNot(HaveOccurred())
 	defer resp.Body.Close()
 
-	By("Verifying a 200 OK response")
-	Expect(resp.StatusCode).To(Equal(http.StatusOK))
+	By("Verifying a 202 Accepted response")
+	Expect(resp.StatusCode).To(Equal(http.StatusAccepted))
+
+	waitForOperation(resp)
 
 	By("Verifying app deployment success")
 	count := 0
@@ -459,7 +461,7 @@ func deployApp(nodeID, appID string) {
 	By("Verifying app start call to Kubernetes API successful")
 	ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
-	err = k8sCli.Start(ctx, nodeID, appID)
+	err = k8sCli.Start(ctx, "", nodeID, appID)
 	Expect(err).ToNot(HaveOccurred())
 
 	// revert image pull policy back to default value: never pull
@@ -473,13 +475,38 @@ func deployApp(nodeID, appID string) {
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 
-		status, err := k8sCli.Status(ctx, nodeID, appID)
+		status, err := k8sCli.Status(ctx, "", nodeID, appID)
 		Expect(err).ToNot(HaveOccurred())
 
 		return status
 	}, 60*time.Second, 1*time.Second).Should(Equal(k8s.Deployed))
 }
 
+// waitForOperation reads the operation from a 202 Accepted response and polls
+// it until it succeeds.
+func waitForOperation(resp *http.Response) {
+	By("Reading the operation from the response body")
+	body, err := ioutil.ReadAll(resp.Body)
+	Expect(err).ToNot(HaveOccurred())
+
+	var op swagger.OperationSummary
+	Expect(json.Unmarshal(body, &op)).To(Succeed())
+
+	By("Waiting for the operation to succeed")
+	Eventually(func() string {
+		By(fmt.Sprintf("Sending a GET %s request", op.URL))
+		resp, err := apiCli.Get("http://127.0.0.1:8080" + op.URL)
+		Expect(err).ToNot(HaveOccurred())
+		defer resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+		var detail swagger.OperationDetail
+		Expect(json.NewDecoder(resp.Body).Decode(&detail)).To(Succeed())
+
+		return detail.State
+	}, 60*time.Second, 1*time.Second).Should(Equal("succeeded"))
+}
+
 func getNodeApp(nodeID, appID string) *swagger.NodeAppDetail {
 	By("Sending a GET /nodes/{node_id}/apps/{app_id} request")
 	resp, err := apiCli.Get(
@@ -6,7 +6,6 @@ package k8s_test
 import (
 	"fmt"
 	"github.com/open-ness/edgecontroller/swagger"
-	"io/ioutil"
 	"net/http"
 	"os/exec"
 	"runtime"
@@ -60,7 +59,7 @@ var _ = Describe("/nodes/{node_id}/apps for k8s", func() {
 	})
 
 	Describe("POST /nodes/{node_id}/apps", func() {
-		DescribeTable("200 OK",
+		DescribeTable("202 Accepted",
 			func() {
 				By("Sending a POST /nodes/{node_id}/apps request")
 				resp, err := apiCli.Post(
@@ -74,12 +73,10 @@ var _ = Describe("/nodes/{node_id}/apps for k8s", func() {
 				Expect(err).ToNot(HaveOccurred())
 				defer resp.Body.Close()
 
-				By("Verifying a 200 response")
-				Expect(resp.StatusCode).To(Equal(http.StatusOK))
+				By("Verifying a 202 response")
+				Expect(resp.StatusCode).To(Equal(http.StatusAccepted))
 
-				By("Reading the response body")
-				_, err = ioutil.ReadAll(resp.Body)
-				Expect(err).ToNot(HaveOccurred())
+				waitForOperation(resp)
 			},
 			Entry("POST /nodes/{node_id}/apps"),
 		)
@@ -187,7 +184,7 @@ var _ = Describe("/nodes/{node_id}/apps for k8s", func() {
 			postNodeApps(nodeID, appID)
 		})
 
-		DescribeTable("200 OK",
+		DescribeTable("202 Accepted",
 			func(reqStr string, expectedNodeAppFull *swagger.NodeAppDetail) {
 				By("Sending a PATCH /nodes/{node_id}/apps/{app_id} request")
 				resp, err := apiCli.Patch(
@@ -202,9 +199,11 @@ var _ = Describe("/nodes/{node_id}/apps for k8s", func() {
 				Expect(err).ToNot(HaveOccurred())
 				defer resp.Body.Close()
 
-				By("Verifying a 200 OK response")
+				By("Verifying a 202 Accepted response")
 				Expect(err).ToNot(HaveOccurred())
-				Expect(resp.StatusCode).To(Equal(http.StatusOK))
+				Expect(resp.StatusCode).To(Equal(http.StatusAccepted))
+
+				waitForOperation(resp)
 
 				By("Verifying the node was updated")
 				expectedNodeAppFull.NodeAppSummary = swagger.NodeAppSummary{
@@ -261,7 +260,7 @@ var _ = Describe("/nodes/{node_id}/apps for k8s", func() {
 			postNodeApps(nodeID, appID)
 		})
 
-		DescribeTable("204 No Content",
+		DescribeTable("202 Accepted",
 			func() {
 				By("Sending a DELETE /nodes/{node_id}/apps/{app_id} request")
 				resp, err := apiCli.Delete(
@@ -270,10 +269,12 @@ var _ = Describe("/nodes/{node_id}/apps for k8s", func() {
 						nodeID,
 						appID))
 
-				By("Verifying a 200 OK response")
+				By("Verifying a 202 Accepted response")
 				Expect(err).ToNot(HaveOccurred())
 				defer resp.Body.Close()
-				Expect(resp.StatusCode).To(Equal(http.StatusNoContent))
+				Expect(resp.StatusCode).To(Equal(http.StatusAccepted))
+
+				waitForOperation(resp)
 
 				By("Verifying the node app was deleted")
 
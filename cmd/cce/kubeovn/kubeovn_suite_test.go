@@ -502,8 +502,35 @@ func postNodeApps(nodeID, appID string) {
 	Expect(err).ToNot(HaveOccurred())
 	defer resp.Body.Close()
 
-	By("Verifying a 200 Created response")
-	Expect(resp.StatusCode).To(Equal(http.StatusOK))
+	By("Verifying a 202 Accepted response")
+	Expect(resp.StatusCode).To(Equal(http.StatusAccepted))
+
+	waitForOperation(resp)
+}
+
+// waitForOperation reads the operation from a 202 Accepted response and polls
+// it until it succeeds.
+func waitForOperation(resp *http.Response) {
+	By("Reading the operation from the response body")
+	body, err := ioutil.ReadAll(resp.Body)
+	Expect(err).ToNot(HaveOccurred())
+
+	var op swagger.OperationSummary
+	Expect(json.Unmarshal(body, &op)).To(Succeed())
+
+	By("Waiting for the operation to succeed")
+	Eventually(func() string {
+		By(fmt.Sprintf("Sending a GET %s request", op.URL))
+		resp, err := apiCli.Get("http://127.0.0.1:8080" + op.URL)
+		Expect(err).ToNot(HaveOccurred())
+		defer resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+		var detail swagger.OperationDetail
+		Expect(json.NewDecoder(resp.Body).Decode(&detail)).To(Succeed())
+
+		return detail.State
+	}, 60*time.Second, 1*time.Second).Should(Equal("succeeded"))
 }
 
 func patchNodesAppsKubeOVNPolicy(nodeID string, appID string, policyID string) {
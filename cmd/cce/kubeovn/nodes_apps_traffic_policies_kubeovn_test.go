@@ -45,7 +45,7 @@ var _ = Describe("/nodes/{node_id}/apps/{app_id}/kube_ovn/policy", func() {
 				Expect(resp.StatusCode).To(Equal(http.StatusOK))
 
 				By("Checking network policies in kubernetes")
-				netpol, err := k8sCli.GetNetworkPolicy(context.TODO(), nodeCfg.nodeID, appID)
+				netpol, err := k8sCli.GetNetworkPolicy(context.TODO(), "", nodeCfg.nodeID, appID)
 				Expect(netpol).ToNot(BeNil())
 				Expect(err).ToNot(HaveOccurred())
 
@@ -68,7 +68,7 @@ var _ = Describe("/nodes/{node_id}/apps/{app_id}/kube_ovn/policy", func() {
 				Expect(resp2.StatusCode).To(Equal(http.StatusOK))
 
 				By("Checking network policies in kubernetes")
-				netpol, err = k8sCli.GetNetworkPolicy(context.TODO(), nodeCfg.nodeID, appID)
+				netpol, err = k8sCli.GetNetworkPolicy(context.TODO(), "", nodeCfg.nodeID, appID)
 				Expect(netpol).ToNot(BeNil())
 				Expect(err).ToNot(HaveOccurred())
 			},
@@ -93,7 +93,7 @@ var _ = Describe("/nodes/{node_id}/apps/{app_id}/kube_ovn/policy", func() {
 				Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
 
 				By("Checking network policies in kubernetes")
-				_, err = k8sCli.GetNetworkPolicy(context.TODO(), nodeID, appID)
+				_, err = k8sCli.GetNetworkPolicy(context.TODO(), "", nodeID, appID)
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(ContainSubstring("not found"))
 			},
@@ -140,7 +140,7 @@ var _ = Describe("/nodes/{node_id}/apps/{app_id}/kube_ovn/policy", func() {
 				Expect(resp.StatusCode).To(Equal(http.StatusNotFound))
 
 				By("Checking network policies in kubernetes")
-				_, err = k8sCli.GetNetworkPolicy(context.TODO(), nodeID, appID)
+				_, err = k8sCli.GetNetworkPolicy(context.TODO(), "", nodeID, appID)
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(ContainSubstring("not found"))
 			},
@@ -238,7 +238,7 @@ var _ = Describe("/nodes/{node_id}/apps/{app_id}/kube_ovn/policy", func() {
 				patchNodesAppsKubeOVNPolicy(nodeCfg.nodeID, appID, policyID)
 
 				By("Checking if network policy exists")
-				_, err := k8sCli.GetNetworkPolicy(context.TODO(), nodeCfg.nodeID, appID)
+				_, err := k8sCli.GetNetworkPolicy(context.TODO(), "", nodeCfg.nodeID, appID)
 				Expect(err).ToNot(HaveOccurred())
 
 				By("Sending a DELETE /nodes/{node_id}/apps/{app_id}/kube_ovn/policy request")
@@ -251,7 +251,7 @@ var _ = Describe("/nodes/{node_id}/apps/{app_id}/kube_ovn/policy", func() {
 				Expect(resp.StatusCode).To(Equal(http.StatusNoContent))
 
 				By("Checking if network policy exists")
-				_, err = k8sCli.GetNetworkPolicy(context.TODO(), nodeCfg.nodeID, appID)
+				_, err = k8sCli.GetNetworkPolicy(context.TODO(), "", nodeCfg.nodeID, appID)
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(ContainSubstring("not found"))
 
@@ -39,6 +39,7 @@ import (
 	"github.com/open-ness/edgecontroller/mysql"
 	"github.com/open-ness/edgecontroller/pki"
 	"github.com/open-ness/edgecontroller/telemetry"
+	apiV1 "k8s.io/api/core/v1"
 )
 
 const certsDir = "./certificates"
@@ -60,11 +61,24 @@ var (
 	statsdOut  string
 	orchMode   string
 	k8sClient  k8s.Client
+
+	reconcileInterval time.Duration
+	queueOfflineOps   bool
+	overcommit        string
+	k8sServiceType    string
+	k8sStatusResync   time.Duration
+	appDNSDomain      string
+	appTrustStore     string
+	orphanPolicy      string
+	orphanInterval    time.Duration
+	logReaderPass     string
 )
 
 func init() {
 	flag.StringVar(&dsn, "dsn", "", "Data source name")
 	flag.StringVar(&adminPass, "adminPass", "", "Admin user password")
+	flag.StringVar(&logReaderPass, "logReaderPass", "",
+		"Password of the log-reader user, who can only read app logs (empty disables the user)")
 	flag.StringVar(&logLevel, "log-level", "info", "Syslog level")
 	flag.IntVar(&httpPort, "httpPort", 8080, "Controller HTTP port")
 	flag.IntVar(&grpcPort, "grpcPort", 8081, "Controller gRPC port")
@@ -74,6 +88,12 @@ func init() {
 	flag.IntVar(&statsdPort, "statsdPort", 8125, "Telemetry ingress port for statsd")
 	flag.StringVar(&syslogOut, "syslog-path", "./syslog.log", "Syslog output file path")
 	flag.StringVar(&statsdOut, "statsd-path", "./statsd.log", "StatsD output file path")
+	flag.DurationVar(&reconcileInterval, "reconcile-interval", 5*time.Minute,
+		"Interval between reconciliations of the nodes' desired state (0 disables)")
+	flag.BoolVar(&queueOfflineOps, "queue-offline-ops", false,
+		"Queue operations for unreachable nodes and replay them when the nodes reconnect")
+	flag.StringVar(&overcommit, "overcommit", cce.OvercommitReject,
+		"Handling of deployments exceeding a node's allocatable resources. options [reject, warn]")
 
 	// application orchestration mode
 	flag.StringVar(&orchMode, "orchestration-mode", "native", "Orchestration mode."+
@@ -86,6 +106,28 @@ func init() {
 	flag.StringVar(&k8sClient.Host, "k8s-master-host", "", "Kubernetes master host")
 	flag.StringVar(&k8sClient.APIPath, "k8s-api-path", "", "Kubernetes api path")
 	flag.StringVar(&k8sClient.Username, "k8s-master-user", "", "Kubernetes default user")
+	flag.StringVar(&k8sClient.KubeConfig, "kubeconfig", "",
+		"Kubernetes kubeconfig path, used instead of the k8s-master and k8s-client flags")
+	flag.StringVar(&k8sClient.KubeContext, "kube-context", "",
+		"Context of the kubeconfig (empty uses its current context)")
+	flag.IntVar(&k8sClient.TenantQuota.Cores, "k8s-tenant-cores", 0,
+		"Cores quota of the namespace of each tenant (0 is unlimited)")
+	flag.IntVar(&k8sClient.TenantQuota.Memory, "k8s-tenant-memory", 0,
+		"Memory quota in MB of the namespace of each tenant (0 is unlimited)")
+	flag.IntVar(&k8sClient.TenantQuota.Hugepages, "k8s-tenant-hugepages", 0,
+		"Hugepages quota in MB of the namespace of each tenant (0 is unlimited)")
+	flag.StringVar(&k8sServiceType, "k8s-service-type", "ClusterIP",
+		"Type of the service created for each app. options [ClusterIP, NodePort]")
+	flag.DurationVar(&k8sStatusResync, "k8s-status-resync", 10*time.Minute,
+		"Interval of resyncing the cached status of Kubernetes apps with the API server")
+	flag.StringVar(&appDNSDomain, "app-dns-domain", "",
+		"Register app services as A records <app_id>.<domain> in the edge DNS (empty disables)")
+	flag.StringVar(&appTrustStore, "app-trust-store", "",
+		"Path of the PEM public keys verifying app signatures; apps must be signed if set")
+	flag.StringVar(&orphanPolicy, "orphan-policy", cce.OrphanPolicyReport,
+		"Handling of Kubernetes objects of apps out of sync with persistence. options [report, adopt, gc]")
+	flag.DurationVar(&orphanInterval, "orphan-interval", 10*time.Minute,
+		"Interval between reconciliations of the Kubernetes objects of apps (0 reconciles at startup only)")
 }
 
 func setupOrchestrator() (cce.OrchestrationMode, error) {
@@ -97,9 +139,11 @@ func setupOrchestrator() (cce.OrchestrationMode, error) {
 		orchestrationMode = cce.OrchestrationModeNative
 	case "kubernetes":
 		orchestrationMode = cce.OrchestrationModeKubernetes
+		k8sClient.ServiceType = apiV1.ServiceType(k8sServiceType)
 		err = k8sClient.Ping()
 	case "kubernetes-ovn":
 		orchestrationMode = cce.OrchestrationModeKubernetesOVN
+		k8sClient.ServiceType = apiV1.ServiceType(k8sServiceType)
 		err = k8sClient.Ping()
 	default:
 		err = errors.New("Invalid orchestration mode " + orchMode)
@@ -123,11 +167,29 @@ func main() {
 		log.Alert("Bad log level %q: %v", logLevel, err)
 		os.Exit(1)
 	}
+	if overcommit != cce.OvercommitReject && overcommit != cce.OvercommitWarn {
+		log.Alertf("Invalid overcommit policy %s", overcommit)
+		os.Exit(1)
+	}
+	if orphanPolicy != cce.OrphanPolicyReport && orphanPolicy != cce.OrphanPolicyAdopt &&
+		orphanPolicy != cce.OrphanPolicyGC {
+		log.Alertf("Invalid orphan policy %s", orphanPolicy)
+		os.Exit(1)
+	}
 	log.Infof("Setting log level to: %s", logLevel)
 	logger.SetLevel(lvl)
 
 	log.Info("Controller CE starting")
 
+	// Load the trust store of app signatures
+	var trustStore *cce.TrustStore
+	if appTrustStore != "" {
+		if trustStore, err = cce.LoadTrustStore(appTrustStore); err != nil {
+			log.Alertf("Error loading app trust store %s: %v", appTrustStore, err)
+			os.Exit(1)
+		}
+	}
+
 	// Setup orchestrator
 	var orchestrationMode cce.OrchestrationMode
 	if orchestrationMode, err = setupOrchestrator(); err != nil {
@@ -158,12 +220,21 @@ func main() {
 		AdminCreds: &cce.AuthCreds{
 			Username: "admin",
 			Password: adminPass,
+			Role:     cce.RoleAdmin,
 		},
+		LogReaderCreds:    logReaderCreds(),
 		OrchestrationMode: orchestrationMode,
 		KubernetesClient:  &k8sClient,
 		ELAPort:           strconv.Itoa(elaPort),
 		EVAPort:           strconv.Itoa(evaPort),
 		EdgeNodeCreds:     newClientTLSConf(rootCA, "controller.openness"),
+
+		QueueOfflineOperations: queueOfflineOps,
+		Overcommit:             overcommit,
+		AppDNSDomain:           appDNSDomain,
+		AppTrustStore:          trustStore,
+		OrphanPolicy:           orphanPolicy,
+		Revocations:            &cce.RevocationCache{},
 	}
 
 	// Create an error group to manage server goroutines
@@ -184,13 +255,32 @@ func main() {
 		}
 	})
 
+	// Cache the status of Kubernetes apps until shutdown. Without the cache
+	// the status is read from the API server.
+	if orchestrationMode != cce.OrchestrationModeNative {
+		go func() {
+			if err := k8sClient.WatchStatus(ctx, k8sStatusResync); err != nil && err != context.Canceled {
+				log.Errf("Error watching the status of apps: %v", err)
+			}
+		}()
+	}
+
 	// Serve handlers
 	httpAddr := fmt.Sprintf(":%d", httpPort)
 	grpcAddr := fmt.Sprintf(":%d", grpcPort)
 	syslogAddr := fmt.Sprintf(":%d", syslogPort)
 	statsdAddr := fmt.Sprintf(":%d", statsdPort)
-	eg.Go(serveHTTP(ctx, controller, httpAddr))
-	eg.Go(serveGRPC(ctx, controller, grpcAddr, getGRPCTLS(rootCA)))
+	koko := gorilla.NewGorilla(controller)
+
+	// Reconcile the Kubernetes objects of apps with persistence until shutdown
+	if orchestrationMode != cce.OrchestrationModeNative {
+		go koko.RunOrphanReconciler(ctx, orphanInterval)
+	}
+
+	eg.Go(serveHTTP(ctx, koko, httpAddr))
+	eg.Go(serveGRPC(ctx, controller, grpcAddr, getGRPCTLS(rootCA), func(addr string) {
+		koko.NodeConnected(ctx, addr)
+	}))
 	eg.Go(serveTelemetry(ctx, syslogOut, syslogAddr, newTLSConf(rootCA, telemetry.SyslogSNI)))
 	eg.Go(serveTelemetry(ctx, statsdOut, statsdAddr, newTLSConf(rootCA, telemetry.StatsdSNI)))
 
@@ -263,7 +353,20 @@ func getTokenSigner() *jose.JWSTokenIssuer {
 	}
 }
 
-func serveHTTP(ctx context.Context, controller *cce.Controller, addr string) func() error {
+// logReaderCreds returns the credentials of the log-reader user, or nil if no
+// password is given for it.
+func logReaderCreds() *cce.AuthCreds {
+	if logReaderPass == "" {
+		return nil
+	}
+	return &cce.AuthCreds{
+		Username: "log-reader",
+		Password: logReaderPass,
+		Role:     cce.RoleLogReader,
+	}
+}
+
+func serveHTTP(ctx context.Context, koko *gorilla.Gorilla, addr string) func() error {
 	lis, err := net.Listen("tcp", addr)
 	if err != nil {
 		log.Alertf("Could not listen on %q: %v", addr, err)
@@ -280,8 +383,13 @@ func serveHTTP(ctx context.Context, controller *cce.Controller, addr string) fun
 		handlers.AllowedMethods([]string{"GET", "POST", "PUT", "PATCH", "DELETE"}),
 	)
 
-	// Configure http server
-	koko := gorilla.NewGorilla(controller)
+	// Execute asynchronous node operations until shutdown
+	go koko.RunOperations(ctx)
+
+	// Reconcile the nodes' desired state until shutdown
+	if reconcileInterval > 0 {
+		go koko.RunReconciler(ctx, reconcileInterval)
+	}
 
 	httpServer := http.NewServer(cors(koko))
 
@@ -308,10 +416,16 @@ func serveHTTP(ctx context.Context, controller *cce.Controller, addr string) fun
 	}
 }
 
-func serveGRPC(ctx context.Context, controller *cce.Controller, addr string, conf *tls.Config) func() error {
+func serveGRPC(
+	ctx context.Context,
+	controller *cce.Controller,
+	addr string,
+	conf *tls.Config,
+	onNodeConnect func(addr string),
+) func() error {
 
 	lis, err := net.Listen("tcp", addr)
-	cce.PrefaceLis = progutil.NewPrefaceListener(lis)
+	cce.PrefaceLis = progutil.NewPrefaceListener(&cce.ProxyListener{Listener: lis, OnConnect: onNodeConnect})
 
 	if err != nil {
 		log.Alertf("Could not listen on %q: %v", addr, err)
@@ -46,8 +46,9 @@ import (
 )
 
 var (
-	adminPass string
-	dbPass    string
+	adminPass     string
+	logReaderPass = "log-reader-pass"
+	dbPass        string
 
 	cmd    *exec.Cmd
 	ctrl   *gexec.Session
@@ -131,7 +132,8 @@ func startup() {
 		"-statsdPort", "8125",
 		"-syslog-path", filepath.Join(telemDir, "syslog.log"),
 		"-statsd-path", filepath.Join(telemDir, "statsd.log"),
-		"-adminPass", adminPass)
+		"-adminPass", adminPass,
+		"-logReaderPass", logReaderPass)
 	ctrl, err = gexec.Start(cmd, GinkgoWriter, GinkgoWriter)
 	Expect(err).ToNot(HaveOccurred(), "Problem starting service")
 
@@ -242,11 +244,15 @@ func insertNFDTags(values string) {
 }
 
 func authToken() string {
+	return userAuthToken("admin", adminPass)
+}
+
+func userAuthToken(username, password string) string {
 	payload, err := json.Marshal(
 		struct {
 			Username string `json:"username"`
 			Password string `json:"password"`
-		}{"admin", adminPass})
+		}{username, password})
 	Expect(err).ToNot(HaveOccurred())
 
 	req, err := http.NewRequest(
@@ -781,8 +787,49 @@ func postNodeApps(nodeID, appID string) {
 	Expect(err).ToNot(HaveOccurred())
 	defer resp.Body.Close()
 
-	By("Verifying a 200 Created response")
+	By("Verifying a 202 Accepted response")
+	Expect(resp.StatusCode).To(Equal(http.StatusAccepted))
+
+	waitForOperation(resp)
+}
+
+// waitForOperation reads the operation from a 202 Accepted response and polls
+// it until it succeeds.
+func waitForOperation(resp *http.Response) {
+	By("Reading the operation from the response body")
+	body, err := ioutil.ReadAll(resp.Body)
+	Expect(err).ToNot(HaveOccurred())
+
+	var op swagger.OperationSummary
+	Expect(json.Unmarshal(body, &op)).To(Succeed())
+	Expect(resp.Header.Get("Location")).To(Equal(op.URL))
+
+	By("Waiting for the operation to succeed")
+	Eventually(func() string {
+		return getOperation(op.ID).State
+	}, 15*time.Second, 250*time.Millisecond).Should(Equal("succeeded"))
+}
+
+func getOperation(id string) *swagger.OperationDetail {
+	By("Sending a GET /operations/{operation_id} request")
+	resp, err := apiCli.Get(
+		fmt.Sprintf("http://127.0.0.1:8080/operations/%s", id))
+
+	By("Verifying a 200 OK response")
+	Expect(err).ToNot(HaveOccurred())
+	defer resp.Body.Close()
 	Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+	By("Reading the response body")
+	body, err := ioutil.ReadAll(resp.Body)
+	Expect(err).ToNot(HaveOccurred())
+
+	var op *swagger.OperationDetail
+
+	By("Unmarshaling the response")
+	Expect(json.Unmarshal(body, &op)).To(Succeed())
+
+	return op
 }
 
 func getNodeApps(nodeID string) *swagger.NodeAppList {
@@ -0,0 +1,228 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package main_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/open-ness/edgecontroller/swagger"
+	"github.com/open-ness/edgecontroller/uuid"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+func postNetworks(name string) (id string) {
+	By("Sending a POST /networks request")
+	resp, err := apiCli.Post(
+		"http://127.0.0.1:8080/networks",
+		"application/json",
+		strings.NewReader(fmt.Sprintf(`
+			{
+				"name": "%s",
+				"cni_config": {"cniVersion": "0.3.1", "type": "macvlan", "master": "eth1"},
+				"interface_name": "net1",
+				"args": "IgnoreUnknown=1"
+			}`, name)))
+	Expect(err).ToNot(HaveOccurred())
+	defer resp.Body.Close()
+
+	By("Verifying a 201 Created response")
+	Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+
+	var rb respBody
+
+	By("Unmarshaling the response")
+	Expect(json.NewDecoder(resp.Body).Decode(&rb)).To(Succeed())
+
+	return rb.ID
+}
+
+func postNetworkApps(networkID string) *http.Response {
+	By("Sending a POST /apps request attached to a network")
+	resp, err := apiCli.Post(
+		"http://127.0.0.1:8080/apps",
+		"application/json",
+		strings.NewReader(fmt.Sprintf(`
+			{
+				"type": "container",
+				"name": "network app",
+				"version": "latest",
+				"vendor": "smart edge",
+				"cores": 1,
+				"memory": 128,
+				"source": "http://www.test.com/my_network_app.tar.gz",
+				"networks": ["%s"]
+			}`, networkID)))
+	Expect(err).ToNot(HaveOccurred())
+	return resp
+}
+
+var _ = Describe("/networks", func() {
+	var name string
+
+	BeforeEach(func() {
+		// network names are unique
+		name = "net-" + uuid.New()[:8]
+	})
+
+	Describe("POST /networks", func() {
+		DescribeTable("400 Bad Request",
+			func(req, expectedResp string) {
+				By("Sending a POST /networks request")
+				resp, err := apiCli.Post(
+					"http://127.0.0.1:8080/networks",
+					"application/json",
+					strings.NewReader(req))
+				Expect(err).ToNot(HaveOccurred())
+				defer resp.Body.Close()
+
+				By("Verifying a 400 Bad Request response")
+				Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+
+				By("Reading the response body")
+				body, err := ioutil.ReadAll(resp.Body)
+				Expect(err).ToNot(HaveOccurred())
+
+				By("Verifying the response body")
+				Expect(string(body)).To(Equal(expectedResp))
+			},
+			Entry("POST /networks without a CNI type",
+				`
+				{
+					"name": "dataplane",
+					"cni_config": {"cniVersion": "0.3.1"}
+				}`,
+				`Validation failed: cni_config must have a "type" or "plugins"`),
+			Entry("POST /networks with invalid args",
+				`
+				{
+					"name": "dataplane",
+					"cni_config": {"cniVersion": "0.3.1", "type": "macvlan"},
+					"args": "IgnoreUnknown"
+				}`,
+				`Validation failed: args "IgnoreUnknown" must be KEY=VALUE pairs separated by semicolons`),
+		)
+
+		DescribeTable("422 Unprocessable Entity",
+			func() {
+				postNetworks(name)
+
+				By("Repeating the POST /networks request")
+				resp, err := apiCli.Post(
+					"http://127.0.0.1:8080/networks",
+					"application/json",
+					strings.NewReader(fmt.Sprintf(`
+					{
+						"name": "%s",
+						"cni_config": {"cniVersion": "0.3.1", "type": "bridge"}
+					}`, name)))
+				Expect(err).ToNot(HaveOccurred())
+				defer resp.Body.Close()
+
+				By("Verifying a 422 response")
+				Expect(resp.StatusCode).To(Equal(http.StatusUnprocessableEntity))
+
+				By("Reading the response body")
+				body, err := ioutil.ReadAll(resp.Body)
+				Expect(err).ToNot(HaveOccurred())
+
+				By("Verifying the response body")
+				Expect(string(body)).To(Equal(fmt.Sprintf(
+					"duplicate record in networks detected for name %s", name)))
+			},
+			Entry("POST /networks with a duplicate name"),
+		)
+	})
+
+	Describe("GET /networks/{network_id}", func() {
+		DescribeTable("200 OK",
+			func() {
+				id := postNetworks(name)
+
+				By("Sending a GET /networks/{network_id} request")
+				resp, err := apiCli.Get(
+					fmt.Sprintf("http://127.0.0.1:8080/networks/%s", id))
+				Expect(err).ToNot(HaveOccurred())
+				defer resp.Body.Close()
+
+				By("Verifying a 200 OK response")
+				Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+				var network swagger.NetworkDetail
+
+				By("Unmarshaling the response")
+				Expect(json.NewDecoder(resp.Body).Decode(&network)).To(Succeed())
+
+				By("Verifying the network was returned")
+				Expect(network.NetworkSummary).To(Equal(swagger.NetworkSummary{ID: id, Name: name}))
+				Expect(network.CNIConfig).To(MatchJSON(
+					`{"cniVersion": "0.3.1", "type": "macvlan", "master": "eth1"}`))
+				Expect(network.InterfaceName).To(Equal("net1"))
+				Expect(network.Args).To(Equal("IgnoreUnknown=1"))
+			},
+			Entry("GET /networks/{network_id}"),
+		)
+	})
+
+	Describe("DELETE /networks/{network_id}", func() {
+		DescribeTable("422 Unprocessable Entity",
+			func() {
+				id := postNetworks(name)
+
+				resp := postNetworkApps(id)
+				defer resp.Body.Close()
+				Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+				var rb respBody
+				Expect(json.NewDecoder(resp.Body).Decode(&rb)).To(Succeed())
+
+				By("Sending a DELETE /networks/{network_id} request")
+				resp2, err := apiCli.Delete(
+					fmt.Sprintf("http://127.0.0.1:8080/networks/%s", id))
+				Expect(err).ToNot(HaveOccurred())
+				defer resp2.Body.Close()
+
+				By("Verifying a 422 response")
+				Expect(resp2.StatusCode).To(Equal(http.StatusUnprocessableEntity))
+
+				By("Reading the response body")
+				body, err := ioutil.ReadAll(resp2.Body)
+				Expect(err).ToNot(HaveOccurred())
+
+				By("Verifying the response body")
+				Expect(string(body)).To(Equal(fmt.Sprintf(
+					"cannot delete network_id %s: network of app %s", id, rb.ID)))
+			},
+			Entry("DELETE /networks/{network_id} of an app"),
+		)
+	})
+
+	Describe("POST /apps", func() {
+		DescribeTable("422 Unprocessable Entity",
+			func() {
+				missingID := uuid.New()
+
+				resp := postNetworkApps(missingID)
+				defer resp.Body.Close()
+
+				By("Verifying a 422 response")
+				Expect(resp.StatusCode).To(Equal(http.StatusUnprocessableEntity))
+
+				By("Reading the response body")
+				body, err := ioutil.ReadAll(resp.Body)
+				Expect(err).ToNot(HaveOccurred())
+
+				By("Verifying the response body")
+				Expect(string(body)).To(MatchRegexp(
+					"^network %s of app [-0-9a-f]{36} not found$", missingID))
+			},
+			Entry("POST /apps with a missing network"),
+		)
+	})
+})
@@ -4,7 +4,10 @@
 package main_test
 
 import (
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"net/http"
 
 	"github.com/open-ness/edgecontroller/swagger"
 	"github.com/open-ness/edgecontroller/uuid"
@@ -43,4 +46,129 @@ var _ = Describe("/nfd", func() {
 		)
 	})
 
+	Describe("GET /nodes?nfd.{feature}{op}{value}", func() {
+		getNodesByNfd := func(query string) *swagger.NodeList {
+			By(fmt.Sprintf("Sending a GET /nodes?%s request", query))
+			resp, err := apiCli.Get(
+				fmt.Sprintf("http://127.0.0.1:8080/nodes?%s", query))
+			Expect(err).ToNot(HaveOccurred())
+			defer resp.Body.Close()
+
+			By("Verifying a 200 OK response")
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+			By("Reading the response body")
+			body, err := ioutil.ReadAll(resp.Body)
+			Expect(err).ToNot(HaveOccurred())
+
+			var output swagger.NodeList
+
+			By("Unmarshaling the response")
+			Expect(json.Unmarshal(body, &output)).To(Succeed())
+
+			return &output
+		}
+
+		DescribeTable("200 OK",
+			func(query string, matchesFirst, matchesSecond bool) {
+				clearGRPCTargetsTable()
+				// Feature keys are unique per entry so tags of other tests do
+				// not match
+				prefix := uuid.New()
+				firstNodeCfg := createAndRegisterNode()
+				secondNodeCfg := createAndRegisterNode()
+
+				insertNFDTags(fmt.Sprintf(
+					`'{"id": "%s", "node_id": "%s", "nfd_id": "%s.avx", "nfd_value": "true"}'`,
+					uuid.New(), firstNodeCfg.nodeID, prefix))
+				insertNFDTags(fmt.Sprintf(
+					`'{"id": "%s", "node_id": "%s", "nfd_id": "%s.major", "nfd_value": "5"}'`,
+					uuid.New(), firstNodeCfg.nodeID, prefix))
+				insertNFDTags(fmt.Sprintf(
+					`'{"id": "%s", "node_id": "%s", "nfd_id": "%s.major", "nfd_value": "4"}'`,
+					uuid.New(), secondNodeCfg.nodeID, prefix))
+
+				nodes := getNodesByNfd(fmt.Sprintf(query, prefix))
+
+				var ids []string
+				for _, node := range nodes.Nodes {
+					ids = append(ids, node.ID)
+				}
+				if matchesFirst {
+					Expect(ids).To(ContainElement(firstNodeCfg.nodeID))
+				} else {
+					Expect(ids).ToNot(ContainElement(firstNodeCfg.nodeID))
+				}
+				if matchesSecond {
+					Expect(ids).To(ContainElement(secondNodeCfg.nodeID))
+				} else {
+					Expect(ids).ToNot(ContainElement(secondNodeCfg.nodeID))
+				}
+			},
+			Entry("equal", "nfd.%[1]s.avx=true", true, false),
+			Entry("greater or equal", "nfd.%[1]s.major>=5", true, false),
+			Entry("less than", "nfd.%[1]s.major<5", false, true),
+			Entry("not equal", "nfd.%[1]s.major!=5", false, true),
+			Entry("several terms", "nfd.%[1]s.avx=true&nfd.%[1]s.major>4", true, false),
+		)
+
+		DescribeTable("400 Bad Request",
+			func(query string) {
+				By(fmt.Sprintf("Sending a GET /nodes?%s request", query))
+				resp, err := apiCli.Get(
+					fmt.Sprintf("http://127.0.0.1:8080/nodes?%s", query))
+				Expect(err).ToNot(HaveOccurred())
+				defer resp.Body.Close()
+
+				By("Verifying a 400 Bad Request response")
+				Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+			},
+			Entry("numeric comparison to a string", "nfd.kernel-version.major>=five"),
+			Entry("missing comparison", "nfd.kernel-version.major"),
+		)
+	})
+
+	Describe("GET /nfd/features", func() {
+		It("Should count the nodes reporting each feature value", func() {
+			clearGRPCTargetsTable()
+			feature := uuid.New()
+			firstNodeCfg := createAndRegisterNode()
+			secondNodeCfg := createAndRegisterNode()
+
+			insertNFDTags(fmt.Sprintf(
+				`'{"id": "%s", "node_id": "%s", "nfd_id": "%s", "nfd_value": "true"}'`,
+				uuid.New(), firstNodeCfg.nodeID, feature))
+			insertNFDTags(fmt.Sprintf(
+				`'{"id": "%s", "node_id": "%s", "nfd_id": "%s", "nfd_value": "false"}'`,
+				uuid.New(), secondNodeCfg.nodeID, feature))
+
+			By("Sending a GET /nfd/features request")
+			resp, err := apiCli.Get("http://127.0.0.1:8080/nfd/features")
+			Expect(err).ToNot(HaveOccurred())
+			defer resp.Body.Close()
+
+			By("Verifying a 200 OK response")
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+			By("Reading the response body")
+			body, err := ioutil.ReadAll(resp.Body)
+			Expect(err).ToNot(HaveOccurred())
+
+			var features swagger.NfdFeatureList
+
+			By("Unmarshaling the response")
+			Expect(json.Unmarshal(body, &features)).To(Succeed())
+
+			By("Verifying the feature is aggregated across both nodes")
+			Expect(features.Features).To(ContainElement(swagger.NfdFeature{
+				ID:    feature,
+				Nodes: 2,
+				Values: []swagger.NfdFeatureValue{
+					{Value: "false", Nodes: 1},
+					{Value: "true", Nodes: 1},
+				},
+			}))
+		})
+	})
+
 })
@@ -0,0 +1,128 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package main_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/open-ness/edgecontroller/uuid"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("/nodes/{node_id}/apps/{app_id}/logs", func() {
+	var (
+		appID string
+	)
+
+	BeforeEach(func() {
+		appID = postApps("container")
+	})
+
+	getNodeAppLogs := func(nodeID, appID, query string) *http.Response {
+		By("Sending a GET /nodes/{node_id}/apps/{app_id}/logs request")
+		resp, err := apiCli.Get(
+			fmt.Sprintf("http://127.0.0.1:8080/nodes/%s/apps/%s/logs%s", nodeID, appID, query))
+		Expect(err).ToNot(HaveOccurred())
+		return resp
+	}
+
+	Describe("GET /nodes/{node_id}/apps/{app_id}/logs", func() {
+		DescribeTable("200 OK",
+			func(query string, expectedLines []string) {
+				nodeCfg := createAndRegisterNode()
+				postNodeApps(nodeCfg.nodeID, appID)
+
+				resp := getNodeAppLogs(nodeCfg.nodeID, appID, query)
+				defer resp.Body.Close()
+
+				By("Verifying a 200 OK response")
+				Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+				By("Reading the response body")
+				body, err := ioutil.ReadAll(resp.Body)
+				Expect(err).ToNot(HaveOccurred())
+
+				By("Verifying the logs of the app")
+				var expected string
+				for _, line := range expectedLines {
+					expected += fmt.Sprintf(line, appID) + "\n"
+				}
+				Expect(string(body)).To(Equal(expected))
+			},
+			Entry("GET /nodes/{node_id}/apps/{app_id}/logs", "",
+				[]string{"Application %s deployed"}),
+			Entry("GET /nodes/{node_id}/apps/{app_id}/logs with tail", "?tail=1",
+				[]string{"Application %s deployed"}),
+			Entry("GET /nodes/{node_id}/apps/{app_id}/logs with since", "?since=1h",
+				[]string{"Application %s deployed"}),
+		)
+
+		It("Should allow the log reader to read the logs", func() {
+			nodeCfg := createAndRegisterNode()
+			postNodeApps(nodeCfg.nodeID, appID)
+			logReaderCli := &apiClient{Token: userAuthToken("log-reader", logReaderPass)}
+
+			By("Sending a GET /nodes/{node_id}/apps/{app_id}/logs request as the log reader")
+			resp, err := logReaderCli.Get(
+				fmt.Sprintf("http://127.0.0.1:8080/nodes/%s/apps/%s/logs", nodeCfg.nodeID, appID))
+			Expect(err).ToNot(HaveOccurred())
+			defer resp.Body.Close()
+
+			By("Verifying a 200 OK response")
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+			By("Sending a GET /nodes/{node_id} request as the log reader")
+			resp, err = logReaderCli.Get(fmt.Sprintf("http://127.0.0.1:8080/nodes/%s", nodeCfg.nodeID))
+			Expect(err).ToNot(HaveOccurred())
+			defer resp.Body.Close()
+
+			By("Verifying a 403 Forbidden response")
+			Expect(resp.StatusCode).To(Equal(http.StatusForbidden))
+		})
+
+		DescribeTable("400 Bad Request",
+			func(query, expectedResp string) {
+				nodeCfg := createAndRegisterNode()
+				postNodeApps(nodeCfg.nodeID, appID)
+
+				resp := getNodeAppLogs(nodeCfg.nodeID, appID, query)
+				defer resp.Body.Close()
+
+				By("Verifying a 400 Bad Request response")
+				Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+
+				By("Reading the response body")
+				body, err := ioutil.ReadAll(resp.Body)
+				Expect(err).ToNot(HaveOccurred())
+
+				By("Verifying the response body")
+				Expect(string(body)).To(Equal(expectedResp + "\n"))
+			},
+			Entry("GET /nodes/{node_id}/apps/{app_id}/logs with invalid follow", "?follow=maybe",
+				`follow "maybe" must be true or false`),
+			Entry("GET /nodes/{node_id}/apps/{app_id}/logs with negative tail", "?tail=-1",
+				`tail "-1" must be a non-negative number`),
+			Entry("GET /nodes/{node_id}/apps/{app_id}/logs with invalid since", "?since=yesterday",
+				`since "yesterday" must be a duration or an RFC 3339 time`),
+		)
+
+		DescribeTable("404 Not Found",
+			func() {
+				nodeCfg := createAndRegisterNode()
+
+				resp := getNodeAppLogs(nodeCfg.nodeID, uuid.New(), "")
+				defer resp.Body.Close()
+
+				By("Verifying a 404 Not Found response")
+				Expect(resp.StatusCode).To(Equal(http.StatusNotFound))
+			},
+			Entry("GET /nodes/{node_id}/apps/{app_id}/logs with nonexistent app ID"),
+		)
+	})
+})
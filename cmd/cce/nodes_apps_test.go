@@ -11,6 +11,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/open-ness/edgecontroller/nfd-master"
@@ -33,7 +34,7 @@ var _ = Describe("/nodes/{node_id}/apps", func() {
 	})
 
 	Describe("POST /nodes/{node_id}/apps", func() {
-		DescribeTable("200 OK",
+		DescribeTable("202 Accepted",
 			func() {
 				nodeCfg := createAndRegisterNode()
 
@@ -50,8 +51,10 @@ var _ = Describe("/nodes/{node_id}/apps", func() {
 				Expect(err).ToNot(HaveOccurred())
 				defer resp.Body.Close()
 
-				By("Verifying a 200 response")
-				Expect(resp.StatusCode).To(Equal(http.StatusOK))
+				By("Verifying a 202 response")
+				Expect(resp.StatusCode).To(Equal(http.StatusAccepted))
+
+				waitForOperation(resp)
 			},
 			Entry(
 				"POST /nodes/{node_id}/apps"),
@@ -115,7 +118,7 @@ var _ = Describe("/nodes/{node_id}/apps", func() {
 				"POST /nodes/{node_id}/apps"),
 		)
 
-		DescribeTable("200 OK with EPAValidate",
+		DescribeTable("202 Accepted with EPAValidate",
 			func() {
 				nodeCfg := createAndRegisterNode()
 
@@ -201,8 +204,10 @@ var _ = Describe("/nodes/{node_id}/apps", func() {
 				Expect(err).ToNot(HaveOccurred())
 				defer respPost.Body.Close()
 
-				By("Verifying a 200 response")
-				Expect(respPost.StatusCode).To(Equal(http.StatusOK))
+				By("Verifying a 202 response")
+				Expect(respPost.StatusCode).To(Equal(http.StatusAccepted))
+
+				waitForOperation(respPost)
 			},
 			Entry(
 				"POST /nodes/{node_id}/apps"),
@@ -286,6 +291,52 @@ var _ = Describe("/nodes/{node_id}/apps", func() {
 			},
 			Entry("POST /nodes/{node_id}/apps with duplicate node_id and app_id"),
 		)
+
+		DescribeTable("422 Unprocessable Entity with a registry source",
+			func() {
+				nodeCfg := createAndRegisterNode()
+
+				By("Sending a POST /apps request with a registry source")
+				resp, err := apiCli.Post(
+					"http://127.0.0.1:8080/apps",
+					"application/json",
+					strings.NewReader(`
+					{
+						"type": "container",
+						"name": "registry app",
+						"version": "1.0",
+						"vendor": "smart edge",
+						"cores": 1,
+						"memory": 128,
+						"source": "docker://registry.example.com/edge/app:1.0"
+					}`))
+				Expect(err).ToNot(HaveOccurred())
+				defer resp.Body.Close()
+				Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+				var rb respBody
+				Expect(json.NewDecoder(resp.Body).Decode(&rb)).To(Succeed())
+
+				By("Sending a POST /nodes/{node_id}/apps request")
+				resp, err = apiCli.Post(
+					fmt.Sprintf("http://127.0.0.1:8080/nodes/%s/apps", nodeCfg.nodeID),
+					"application/json",
+					strings.NewReader(fmt.Sprintf(`{"id": "%s"}`, rb.ID)))
+				Expect(err).ToNot(HaveOccurred())
+				defer resp.Body.Close()
+
+				By("Verifying a 422 response")
+				Expect(resp.StatusCode).To(Equal(http.StatusUnprocessableEntity))
+
+				By("Reading the response body")
+				body, err := ioutil.ReadAll(resp.Body)
+				Expect(err).ToNot(HaveOccurred())
+
+				By("Verifying the response body")
+				Expect(string(body)).To(Equal(fmt.Sprintf(
+					"source of app %s is an image registry, which requires kubernetes mode", rb.ID)))
+			},
+			Entry("POST /nodes/{node_id}/apps with a registry source in native mode"),
+		)
 	})
 
 	Describe("GET /nodes/{node_id}/apps", func() {
@@ -361,7 +412,7 @@ var _ = Describe("/nodes/{node_id}/apps", func() {
 	})
 
 	Describe("PATCH /nodes/{node_id}/apps/{app_id}", func() {
-		DescribeTable("200 OK",
+		DescribeTable("202 Accepted",
 			func(reqStr string, expectedNodeAppResp *swagger.NodeAppDetail) {
 				nodeCfg := createAndRegisterNode()
 				postNodeApps(nodeCfg.nodeID, appID)
@@ -374,9 +425,11 @@ var _ = Describe("/nodes/{node_id}/apps", func() {
 				Expect(err).ToNot(HaveOccurred())
 				defer resp.Body.Close()
 
-				By("Verifying a 200 OK response")
+				By("Verifying a 202 Accepted response")
 				Expect(err).ToNot(HaveOccurred())
-				Expect(resp.StatusCode).To(Equal(http.StatusOK))
+				Expect(resp.StatusCode).To(Equal(http.StatusAccepted))
+
+				waitForOperation(resp)
 
 				By("Getting the updated node")
 				updatedNodeAppResp := getNodeApp(nodeCfg.nodeID, appID)
@@ -433,10 +486,57 @@ var _ = Describe("/nodes/{node_id}/apps", func() {
 				`,
 				"Error unmarshaling json: invalid character 'c' looking for beginning of value"),
 		)
+
+		It("Should accept only one of concurrent commands", func() {
+			nodeCfg := createAndRegisterNode()
+			postNodeApps(nodeCfg.nodeID, appID)
+
+			By("Closing the maintenance windows of the node")
+			// the window opens once a year for a minute, so the accepted
+			// command stays deferred
+			resp, err := apiCli.Post(
+				fmt.Sprintf("http://127.0.0.1:8080/nodes/%s/maintenance_windows", nodeCfg.nodeID),
+				"application/json",
+				strings.NewReader(`{"cron": "0 0 1 1 *", "time_zone": "UTC", "duration": 1}`))
+			Expect(err).ToNot(HaveOccurred())
+			resp.Body.Close()
+			Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+
+			By("Sending concurrent PATCH /nodes/{node_id}/apps/{app_id} requests")
+			var wg sync.WaitGroup
+			codes := make(chan int, 5)
+			for i := 0; i < 5; i++ {
+				wg.Add(1)
+				go func() {
+					defer GinkgoRecover()
+					defer wg.Done()
+					resp, err := apiCli.Patch(
+						fmt.Sprintf("http://127.0.0.1:8080/nodes/%s/apps/%s", nodeCfg.nodeID, appID),
+						"application/json",
+						strings.NewReader(`{"command": "restart"}`))
+					Expect(err).ToNot(HaveOccurred())
+					resp.Body.Close()
+					codes <- resp.StatusCode
+				}()
+			}
+			wg.Wait()
+			close(codes)
+
+			By("Verifying one 202 Accepted and otherwise 422 Unprocessable Entity responses")
+			var accepted int
+			for code := range codes {
+				if code == http.StatusAccepted {
+					accepted++
+					continue
+				}
+				Expect(code).To(Equal(http.StatusUnprocessableEntity))
+			}
+			Expect(accepted).To(Equal(1))
+		})
 	})
 
 	Describe("DELETE /nodes/{node_id}/apps/{app_id}", func() {
-		DescribeTable("204 No Content",
+		DescribeTable("202 Accepted",
 			func() {
 				nodeCfg := createAndRegisterNode()
 				postNodeApps(nodeCfg.nodeID, appID)
@@ -447,10 +547,12 @@ var _ = Describe("/nodes/{node_id}/apps", func() {
 						"http://127.0.0.1:8080/nodes/%s/apps/%s",
 						nodeCfg.nodeID, appID))
 
-				By("Verifying a 204 No Content response")
+				By("Verifying a 202 Accepted response")
 				Expect(err).ToNot(HaveOccurred())
 				defer resp.Body.Close()
-				Expect(resp.StatusCode).To(Equal(http.StatusNoContent))
+				Expect(resp.StatusCode).To(Equal(http.StatusAccepted))
+
+				waitForOperation(resp)
 
 				By("Verifying the node app was deleted")
 
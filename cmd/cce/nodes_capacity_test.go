@@ -0,0 +1,190 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package main_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/open-ness/edgecontroller/swagger"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("/nodes/{node_id}/capacity", func() {
+	patchNodeCapacity := func(nodeID, reqStr string) *http.Response {
+		By("Sending a PATCH /nodes/{node_id}/capacity request")
+		resp, err := apiCli.Patch(
+			fmt.Sprintf("http://127.0.0.1:8080/nodes/%s/capacity", nodeID),
+			"application/json",
+			strings.NewReader(reqStr))
+		Expect(err).ToNot(HaveOccurred())
+		return resp
+	}
+
+	Describe("PATCH /nodes/{node_id}/capacity", func() {
+		DescribeTable("204 No Content",
+			func(reqStr string, expected *swagger.NodeResources) {
+				clearGRPCTargetsTable()
+				nodeCfg := createAndRegisterNode()
+				appID := postApps("container")
+				postNodeApps(nodeCfg.nodeID, appID)
+
+				resp := patchNodeCapacity(nodeCfg.nodeID, reqStr)
+				defer resp.Body.Close()
+
+				By("Verifying a 204 No Content response")
+				Expect(resp.StatusCode).To(Equal(http.StatusNoContent))
+
+				By("Verifying the utilization of the node")
+				Expect(getNode(nodeCfg.nodeID).Utilization).To(Equal(
+					&swagger.NodeUtilization{
+						Allocatable:       expected,
+						AllocatableSource: "admin",
+						Requested: swagger.NodeResources{
+							Cores:  4,
+							Memory: 1024,
+						},
+					}))
+			},
+			Entry(
+				"PATCH /nodes/{node_id}/capacity",
+				`
+				{
+					"cores": 8,
+					"memory": 4096,
+					"hugepages": 1024
+				}`,
+				&swagger.NodeResources{
+					Cores:     8,
+					Memory:    4096,
+					Hugepages: 1024,
+				},
+			),
+		)
+
+		DescribeTable("400 Bad Request",
+			func(reqStr string, expectedResp string) {
+				nodeCfg := createAndRegisterNode()
+
+				resp := patchNodeCapacity(nodeCfg.nodeID, reqStr)
+				defer resp.Body.Close()
+
+				By("Verifying a 400 Bad Request response")
+				Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+
+				By("Reading the response body")
+				body, err := ioutil.ReadAll(resp.Body)
+				Expect(err).ToNot(HaveOccurred())
+
+				By("Verifying the response body")
+				Expect(string(body)).To(Equal(expectedResp))
+			},
+			Entry(
+				"PATCH /nodes/{node_id}/capacity without cores",
+				`
+				{
+					"memory": 4096
+				}`,
+				"Validation failed: cores must be at least 1",
+			),
+			Entry(
+				"PATCH /nodes/{node_id}/capacity with negative hugepages",
+				`
+				{
+					"cores": 8,
+					"memory": 4096,
+					"hugepages": -1
+				}`,
+				"Validation failed: hugepages cannot be negative",
+			),
+		)
+	})
+
+	Describe("DELETE /nodes/{node_id}/capacity", func() {
+		DescribeTable("204 No Content",
+			func() {
+				nodeCfg := createAndRegisterNode()
+
+				resp := patchNodeCapacity(nodeCfg.nodeID, `{"cores": 8, "memory": 4096}`)
+				resp.Body.Close()
+				Expect(resp.StatusCode).To(Equal(http.StatusNoContent))
+
+				By("Sending a DELETE /nodes/{node_id}/capacity request")
+				resp, err := apiCli.Delete(
+					fmt.Sprintf("http://127.0.0.1:8080/nodes/%s/capacity", nodeCfg.nodeID))
+				Expect(err).ToNot(HaveOccurred())
+				defer resp.Body.Close()
+
+				By("Verifying a 204 No Content response")
+				Expect(resp.StatusCode).To(Equal(http.StatusNoContent))
+
+				By("Verifying the allocatable resources are no longer known")
+				Expect(getNode(nodeCfg.nodeID).Utilization.Allocatable).To(BeNil())
+			},
+			Entry("DELETE /nodes/{node_id}/capacity"),
+		)
+
+		DescribeTable("404 Not Found",
+			func() {
+				nodeCfg := createAndRegisterNode()
+
+				By("Sending a DELETE /nodes/{node_id}/capacity request")
+				resp, err := apiCli.Delete(
+					fmt.Sprintf("http://127.0.0.1:8080/nodes/%s/capacity", nodeCfg.nodeID))
+				Expect(err).ToNot(HaveOccurred())
+				defer resp.Body.Close()
+
+				By("Verifying a 404 Not Found response")
+				Expect(resp.StatusCode).To(Equal(http.StatusNotFound))
+			},
+			Entry("DELETE /nodes/{node_id}/capacity without a capacity set"),
+		)
+	})
+
+	Describe("POST /nodes/{node_id}/apps", func() {
+		DescribeTable("422 Unprocessable Entity",
+			func(reqStr string, expectedResp string) {
+				clearGRPCTargetsTable()
+				nodeCfg := createAndRegisterNode()
+				appID := postApps("container")
+
+				resp := patchNodeCapacity(nodeCfg.nodeID, reqStr)
+				resp.Body.Close()
+				Expect(resp.StatusCode).To(Equal(http.StatusNoContent))
+
+				By("Sending a POST /nodes/{node_id}/apps request")
+				resp, err := apiCli.Post(
+					fmt.Sprintf("http://127.0.0.1:8080/nodes/%s/apps", nodeCfg.nodeID),
+					"application/json",
+					strings.NewReader(fmt.Sprintf(`{"id": "%s"}`, appID)))
+				Expect(err).ToNot(HaveOccurred())
+				defer resp.Body.Close()
+
+				By("Verifying a 422 Unprocessable Entity response")
+				Expect(resp.StatusCode).To(Equal(http.StatusUnprocessableEntity))
+
+				By("Reading the response body")
+				body, err := ioutil.ReadAll(resp.Body)
+				Expect(err).ToNot(HaveOccurred())
+
+				By("Verifying the response body")
+				Expect(string(body)).To(Equal(fmt.Sprintf(expectedResp, appID, nodeCfg.nodeID)))
+			},
+			Entry(
+				"POST /nodes/{node_id}/apps over-committing the node's cores",
+				`
+				{
+					"cores": 2,
+					"memory": 4096
+				}`,
+				"app %s over-commits node %s: [cores: 4 requested, 0 of 2 allocated]",
+			),
+		)
+	})
+})
@@ -0,0 +1,70 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package main_test
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/open-ness/edgecontroller/uuid"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("/nodes/{node_id}/decommission", func() {
+	var (
+		appID string
+	)
+
+	BeforeEach(func() {
+		appID = postApps("container")
+	})
+
+	Describe("POST /nodes/{node_id}/decommission", func() {
+		DescribeTable("202 Accepted",
+			func() {
+				nodeCfg := createAndRegisterNode()
+				postNodeApps(nodeCfg.nodeID, appID)
+
+				By("Sending a POST /nodes/{node_id}/decommission request")
+				resp, err := apiCli.Post(
+					fmt.Sprintf("http://127.0.0.1:8080/nodes/%s/decommission", nodeCfg.nodeID),
+					"application/json",
+					nil)
+				Expect(err).ToNot(HaveOccurred())
+				defer resp.Body.Close()
+
+				By("Verifying a 202 Accepted response")
+				Expect(resp.StatusCode).To(Equal(http.StatusAccepted))
+				waitForOperation(resp)
+
+				By("Verifying the node was deleted")
+				resp2, err := apiCli.Get(
+					fmt.Sprintf("http://127.0.0.1:8080/nodes/%s", nodeCfg.nodeID))
+				Expect(err).ToNot(HaveOccurred())
+				defer resp2.Body.Close()
+				Expect(resp2.StatusCode).To(Equal(http.StatusNotFound))
+			},
+			Entry("POST /nodes/{node_id}/decommission with a deployed app"),
+		)
+
+		DescribeTable("404 Not Found",
+			func(id string) {
+				By("Sending a POST /nodes/{node_id}/decommission request")
+				resp, err := apiCli.Post(
+					fmt.Sprintf("http://127.0.0.1:8080/nodes/%s/decommission", id),
+					"application/json",
+					nil)
+				Expect(err).ToNot(HaveOccurred())
+				defer resp.Body.Close()
+
+				By("Verifying a 404 Not Found response")
+				Expect(resp.StatusCode).To(Equal(http.StatusNotFound))
+			},
+			Entry("POST /nodes/{node_id}/decommission with nonexistent ID", uuid.New()),
+		)
+	})
+})
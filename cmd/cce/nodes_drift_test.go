@@ -0,0 +1,76 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package main_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/open-ness/edgecontroller/swagger"
+	"github.com/open-ness/edgecontroller/uuid"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("/nodes/{node_id}/drift", func() {
+	var (
+		appID string
+	)
+
+	BeforeEach(func() {
+		appID = postApps("container")
+	})
+
+	Describe("GET /nodes/{node_id}/drift", func() {
+		DescribeTable("200 OK",
+			func(query string, expectedInSync bool) {
+				nodeCfg := createAndRegisterNode()
+				postNodeApps(nodeCfg.nodeID, appID)
+
+				By("Sending a GET /nodes/{node_id}/drift request")
+				resp, err := apiCli.Get(
+					fmt.Sprintf("http://127.0.0.1:8080/nodes/%s/drift%s", nodeCfg.nodeID, query))
+				Expect(err).ToNot(HaveOccurred())
+				defer resp.Body.Close()
+
+				By("Verifying a 200 OK response")
+				Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+				By("Reading the response body")
+				body, err := ioutil.ReadAll(resp.Body)
+				Expect(err).ToNot(HaveOccurred())
+
+				var drift swagger.NodeDriftDetail
+
+				By("Unmarshaling the response")
+				Expect(json.Unmarshal(body, &drift)).To(Succeed())
+
+				By("Verifying the response body")
+				Expect(drift.NodeID).To(Equal(nodeCfg.nodeID))
+				Expect(drift.InSync).To(Equal(expectedInSync))
+				Expect(drift.Items).To(BeEmpty())
+			},
+			Entry("GET /nodes/{node_id}/drift before reconciliation", "", false),
+			Entry("GET /nodes/{node_id}/drift?refresh=true", "?refresh=true", true),
+		)
+
+		DescribeTable("404 Not Found",
+			func(id string) {
+				By("Sending a GET /nodes/{node_id}/drift request")
+				resp, err := apiCli.Get(
+					fmt.Sprintf("http://127.0.0.1:8080/nodes/%s/drift", id))
+				Expect(err).ToNot(HaveOccurred())
+				defer resp.Body.Close()
+
+				By("Verifying a 404 Not Found response")
+				Expect(resp.StatusCode).To(Equal(http.StatusNotFound))
+			},
+			Entry("GET /nodes/{node_id}/drift with nonexistent ID", uuid.New()),
+		)
+	})
+})
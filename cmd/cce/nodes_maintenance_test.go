@@ -0,0 +1,220 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package main_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/open-ness/edgecontroller/swagger"
+	"github.com/open-ness/edgecontroller/uuid"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("/nodes/{node_id}/maintenance_windows", func() {
+	postNodeMaintenanceWindows := func(nodeID, reqStr string) *http.Response {
+		By("Sending a POST /nodes/{node_id}/maintenance_windows request")
+		resp, err := apiCli.Post(
+			fmt.Sprintf("http://127.0.0.1:8080/nodes/%s/maintenance_windows", nodeID),
+			"application/json",
+			strings.NewReader(reqStr))
+		Expect(err).ToNot(HaveOccurred())
+		return resp
+	}
+
+	// The window opens once a year for a minute, so it is closed while the
+	// tests run
+	closedWindow := `
+		{
+			"cron": "0 0 1 1 *",
+			"time_zone": "UTC",
+			"duration": 1
+		}`
+
+	Describe("POST /nodes/{node_id}/maintenance_windows", func() {
+		DescribeTable("201 Created",
+			func(reqStr string, expected swagger.MaintenanceWindowSummary) {
+				nodeCfg := createAndRegisterNode()
+
+				resp := postNodeMaintenanceWindows(nodeCfg.nodeID, reqStr)
+				defer resp.Body.Close()
+
+				By("Verifying a 201 Created response")
+				Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+
+				By("Reading the response body")
+				body, err := ioutil.ReadAll(resp.Body)
+				Expect(err).ToNot(HaveOccurred())
+				var created swagger.BaseResource
+				Expect(json.Unmarshal(body, &created)).To(Succeed())
+
+				By("Sending a GET /nodes/{node_id}/maintenance_windows request")
+				resp2, err := apiCli.Get(
+					fmt.Sprintf("http://127.0.0.1:8080/nodes/%s/maintenance_windows", nodeCfg.nodeID))
+				Expect(err).ToNot(HaveOccurred())
+				defer resp2.Body.Close()
+
+				By("Verifying a 200 OK response")
+				Expect(resp2.StatusCode).To(Equal(http.StatusOK))
+
+				By("Reading the response body")
+				body, err = ioutil.ReadAll(resp2.Body)
+				Expect(err).ToNot(HaveOccurred())
+
+				var windows swagger.MaintenanceWindowList
+
+				By("Unmarshaling the response")
+				Expect(json.Unmarshal(body, &windows)).To(Succeed())
+
+				By("Verifying the window was created for the node")
+				expected.ID = created.ID
+				Expect(windows.MaintenanceWindows).To(ConsistOf(expected))
+			},
+			Entry(
+				"POST /nodes/{node_id}/maintenance_windows",
+				closedWindow,
+				swagger.MaintenanceWindowSummary{
+					Cron:     "0 0 1 1 *",
+					TimeZone: "UTC",
+					Duration: 1,
+				},
+			),
+		)
+
+		DescribeTable("400 Bad Request",
+			func(reqStr string, expectedResp string) {
+				nodeCfg := createAndRegisterNode()
+
+				resp := postNodeMaintenanceWindows(nodeCfg.nodeID, reqStr)
+				defer resp.Body.Close()
+
+				By("Verifying a 400 Bad Request response")
+				Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+
+				By("Reading the response body")
+				body, err := ioutil.ReadAll(resp.Body)
+				Expect(err).ToNot(HaveOccurred())
+
+				By("Verifying the response body")
+				Expect(string(body)).To(Equal(expectedResp))
+			},
+			Entry(
+				"POST /nodes/{node_id}/maintenance_windows without duration",
+				`
+				{
+					"cron": "0 2 * * 6"
+				}`,
+				"Validation failed: duration must be in range [1..10080]",
+			),
+			Entry(
+				"POST /nodes/{node_id}/maintenance_windows with unknown time zone",
+				`
+				{
+					"cron": "0 2 * * 6",
+					"time_zone": "Europe/Atlantis",
+					"duration": 60
+				}`,
+				`Validation failed: time zone "Europe/Atlantis" is unknown`,
+			),
+		)
+
+		DescribeTable("404 Not Found",
+			func(id string) {
+				resp := postNodeMaintenanceWindows(id, closedWindow)
+				defer resp.Body.Close()
+
+				By("Verifying a 404 Not Found response")
+				Expect(resp.StatusCode).To(Equal(http.StatusNotFound))
+			},
+			Entry("POST /nodes/{node_id}/maintenance_windows with nonexistent ID", uuid.New()),
+		)
+	})
+
+	Describe("DELETE /nodes/{node_id}/maintenance_windows/{window_id}", func() {
+		DescribeTable("200 OK",
+			func() {
+				nodeCfg := createAndRegisterNode()
+
+				resp := postNodeMaintenanceWindows(nodeCfg.nodeID, closedWindow)
+				defer resp.Body.Close()
+				Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+				body, err := ioutil.ReadAll(resp.Body)
+				Expect(err).ToNot(HaveOccurred())
+				var created swagger.BaseResource
+				Expect(json.Unmarshal(body, &created)).To(Succeed())
+
+				By("Sending a DELETE /nodes/{node_id}/maintenance_windows/{window_id} request")
+				resp2, err := apiCli.Delete(fmt.Sprintf(
+					"http://127.0.0.1:8080/nodes/%s/maintenance_windows/%s", nodeCfg.nodeID, created.ID))
+				Expect(err).ToNot(HaveOccurred())
+				defer resp2.Body.Close()
+
+				By("Verifying a 200 OK response")
+				Expect(resp2.StatusCode).To(Equal(http.StatusOK))
+
+				By("Verifying the window was deleted")
+				resp3, err := apiCli.Get(fmt.Sprintf(
+					"http://127.0.0.1:8080/nodes/%s/maintenance_windows/%s", nodeCfg.nodeID, created.ID))
+				Expect(err).ToNot(HaveOccurred())
+				defer resp3.Body.Close()
+				Expect(resp3.StatusCode).To(Equal(http.StatusNotFound))
+			},
+			Entry("DELETE /nodes/{node_id}/maintenance_windows/{window_id}"),
+		)
+	})
+
+	Describe("Deferred operations", func() {
+		It("Should defer disruptive operations until a maintenance window opens", func() {
+			nodeCfg := createAndRegisterNode()
+			appID := postApps("container")
+			postNodeApps(nodeCfg.nodeID, appID)
+
+			resp := postNodeMaintenanceWindows(nodeCfg.nodeID, closedWindow)
+			resp.Body.Close()
+			Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+
+			By("Sending a PATCH /nodes/{node_id}/apps/{app_id} request")
+			resp, err := apiCli.Patch(
+				fmt.Sprintf("http://127.0.0.1:8080/nodes/%s/apps/%s", nodeCfg.nodeID, appID),
+				"application/json",
+				strings.NewReader(`{"command": "restart"}`))
+			Expect(err).ToNot(HaveOccurred())
+			defer resp.Body.Close()
+
+			By("Verifying a 202 Accepted response")
+			Expect(resp.StatusCode).To(Equal(http.StatusAccepted))
+			body, err := ioutil.ReadAll(resp.Body)
+			Expect(err).ToNot(HaveOccurred())
+			var op swagger.OperationSummary
+			Expect(json.Unmarshal(body, &op)).To(Succeed())
+
+			By("Verifying the operation is deferred")
+			Expect(op.State).To(Equal("deferred"))
+
+			By("Verifying the operation waits in the queue of the node")
+			resp2, err := apiCli.Get(fmt.Sprintf("http://127.0.0.1:8080/nodes/%s/queue", nodeCfg.nodeID))
+			Expect(err).ToNot(HaveOccurred())
+			defer resp2.Body.Close()
+			body, err = ioutil.ReadAll(resp2.Body)
+			Expect(err).ToNot(HaveOccurred())
+			var ops swagger.OperationList
+			Expect(json.Unmarshal(body, &ops)).To(Succeed())
+			Expect(ops.Operations).To(ConsistOf(op))
+
+			By("Canceling the deferred operation")
+			resp3, err := apiCli.Delete(
+				fmt.Sprintf("http://127.0.0.1:8080/nodes/%s/queue/%s", nodeCfg.nodeID, op.ID))
+			Expect(err).ToNot(HaveOccurred())
+			defer resp3.Body.Close()
+			Expect(resp3.StatusCode).To(Equal(http.StatusNoContent))
+			Expect(getOperation(op.ID).State).To(Equal("canceled"))
+		})
+	})
+})
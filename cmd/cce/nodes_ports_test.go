@@ -0,0 +1,225 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package main_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/open-ness/edgecontroller/swagger"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("/nodes/{node_id}/ports", func() {
+	postNodePorts := func(nodeID, reqStr string) *http.Response {
+		By("Sending a POST /nodes/{node_id}/ports request")
+		resp, err := apiCli.Post(
+			fmt.Sprintf("http://127.0.0.1:8080/nodes/%s/ports", nodeID),
+			"application/json",
+			strings.NewReader(reqStr))
+		Expect(err).ToNot(HaveOccurred())
+		return resp
+	}
+
+	getNodePorts := func(nodeID string) swagger.PortList {
+		By("Sending a GET /nodes/{node_id}/ports request")
+		resp, err := apiCli.Get(
+			fmt.Sprintf("http://127.0.0.1:8080/nodes/%s/ports", nodeID))
+		Expect(err).ToNot(HaveOccurred())
+		defer resp.Body.Close()
+
+		By("Verifying a 200 OK response")
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+		By("Reading the response body")
+		body, err := ioutil.ReadAll(resp.Body)
+		Expect(err).ToNot(HaveOccurred())
+
+		var ports swagger.PortList
+
+		By("Unmarshaling the response")
+		Expect(json.Unmarshal(body, &ports)).To(Succeed())
+
+		return ports
+	}
+
+	Describe("GET /nodes/{node_id}/ports", func() {
+		It("Should list the ports of the node", func() {
+			nodeCfg := createAndRegisterNode()
+
+			ports := getNodePorts(nodeCfg.nodeID)
+
+			By("Verifying the ports of the node are listed")
+			Expect(ports.Ports).To(ContainElement(swagger.PortSummary{
+				PCI:        "0000:00:00.0",
+				Driver:     "none",
+				MACAddress: "mac0",
+			}))
+		})
+	})
+
+	Describe("POST /nodes/{node_id}/ports", func() {
+		DescribeTable("204 No Content",
+			func(reqStr string, expected swagger.PortSummary) {
+				nodeCfg := createAndRegisterNode()
+
+				resp := postNodePorts(nodeCfg.nodeID, reqStr)
+				defer resp.Body.Close()
+
+				By("Verifying a 204 No Content response")
+				Expect(resp.StatusCode).To(Equal(http.StatusNoContent))
+
+				By("Verifying the port was attached on the node")
+				Expect(getNodePorts(nodeCfg.nodeID).Ports).To(ContainElement(expected))
+			},
+			Entry(
+				"POST /nodes/{node_id}/ports",
+				`
+				{
+					"ports": [
+						{
+							"pci": "0000:00:00.1",
+							"bridge": "br-userspace",
+							"driver": "userspace"
+						}
+					]
+				}`,
+				swagger.PortSummary{
+					PCI:        "0000:00:00.1",
+					Driver:     "userspace",
+					Bridge:     "br-userspace",
+					MACAddress: "mac1",
+				},
+			),
+			Entry(
+				"POST /nodes/{node_id}/ports without bridge",
+				`
+				{
+					"ports": [
+						{
+							"pci": "0000:00:00.0",
+							"driver": "kernel"
+						}
+					]
+				}`,
+				swagger.PortSummary{
+					PCI:        "0000:00:00.0",
+					Driver:     "kernel",
+					Bridge:     "br-local",
+					MACAddress: "mac0",
+				},
+			),
+		)
+
+		DescribeTable("400 Bad Request",
+			func(reqStr string, expectedResp string) {
+				nodeCfg := createAndRegisterNode()
+
+				resp := postNodePorts(nodeCfg.nodeID, reqStr)
+				defer resp.Body.Close()
+
+				By("Verifying a 400 Bad Request response")
+				Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+
+				By("Reading the response body")
+				body, err := ioutil.ReadAll(resp.Body)
+				Expect(err).ToNot(HaveOccurred())
+
+				By("Verifying the response body")
+				Expect(string(body)).To(Equal(expectedResp))
+			},
+			Entry(
+				"POST /nodes/{node_id}/ports without ports",
+				`
+				{
+					"ports": []
+				}`,
+				"Validation failed: ports cannot be empty",
+			),
+			Entry(
+				"POST /nodes/{node_id}/ports with invalid driver",
+				`
+				{
+					"ports": [
+						{
+							"pci": "0000:00:00.0",
+							"driver": "dpdk"
+						}
+					]
+				}`,
+				`Validation failed: driver must be either "kernel" or "userspace"`,
+			),
+		)
+
+		DescribeTable("422 Unprocessable Entity",
+			func(reqStr string) {
+				nodeCfg := createAndRegisterNode()
+
+				resp := postNodePorts(nodeCfg.nodeID, reqStr)
+				defer resp.Body.Close()
+
+				By("Verifying a 422 Unprocessable Entity response")
+				Expect(resp.StatusCode).To(Equal(http.StatusUnprocessableEntity))
+			},
+			Entry(
+				"POST /nodes/{node_id}/ports with unknown port",
+				`
+				{
+					"ports": [
+						{
+							"pci": "0000:00:00.9",
+							"driver": "kernel"
+						}
+					]
+				}`,
+			),
+		)
+	})
+
+	Describe("DELETE /nodes/{node_id}/ports/{pci}", func() {
+		It("Should detach the port", func() {
+			nodeCfg := createAndRegisterNode()
+
+			resp := postNodePorts(nodeCfg.nodeID,
+				`{"ports": [{"pci": "0000:00:00.0", "driver": "kernel"}]}`)
+			resp.Body.Close()
+			Expect(resp.StatusCode).To(Equal(http.StatusNoContent))
+
+			By("Sending a DELETE /nodes/{node_id}/ports/{pci} request")
+			resp, err := apiCli.Delete(
+				fmt.Sprintf("http://127.0.0.1:8080/nodes/%s/ports/0000:00:00.0", nodeCfg.nodeID))
+			Expect(err).ToNot(HaveOccurred())
+			defer resp.Body.Close()
+
+			By("Verifying a 204 No Content response")
+			Expect(resp.StatusCode).To(Equal(http.StatusNoContent))
+
+			By("Verifying the port was detached on the node")
+			Expect(getNodePorts(nodeCfg.nodeID).Ports).To(ContainElement(swagger.PortSummary{
+				PCI:        "0000:00:00.0",
+				Driver:     "none",
+				MACAddress: "mac0",
+			}))
+		})
+
+		It("Should return 404 if the port does not exist", func() {
+			nodeCfg := createAndRegisterNode()
+
+			By("Sending a DELETE /nodes/{node_id}/ports/{pci} request")
+			resp, err := apiCli.Delete(
+				fmt.Sprintf("http://127.0.0.1:8080/nodes/%s/ports/0000:00:00.9", nodeCfg.nodeID))
+			Expect(err).ToNot(HaveOccurred())
+			defer resp.Body.Close()
+
+			By("Verifying a 404 Not Found response")
+			Expect(resp.StatusCode).To(Equal(http.StatusNotFound))
+		})
+	})
+})
@@ -0,0 +1,82 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package main_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/open-ness/edgecontroller/swagger"
+	"github.com/open-ness/edgecontroller/uuid"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("/nodes/{node_id}/queue", func() {
+	Describe("GET /nodes/{node_id}/queue", func() {
+		DescribeTable("200 OK",
+			func() {
+				nodeCfg := createAndRegisterNode()
+
+				By("Sending a GET /nodes/{node_id}/queue request")
+				resp, err := apiCli.Get(
+					fmt.Sprintf("http://127.0.0.1:8080/nodes/%s/queue", nodeCfg.nodeID))
+				Expect(err).ToNot(HaveOccurred())
+				defer resp.Body.Close()
+
+				By("Verifying a 200 OK response")
+				Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+				By("Reading the response body")
+				body, err := ioutil.ReadAll(resp.Body)
+				Expect(err).ToNot(HaveOccurred())
+
+				var ops swagger.OperationList
+
+				By("Unmarshaling the response")
+				Expect(json.Unmarshal(body, &ops)).To(Succeed())
+
+				By("Verifying the response body")
+				Expect(ops.Operations).To(BeEmpty())
+			},
+			Entry("GET /nodes/{node_id}/queue of a connected node"),
+		)
+
+		DescribeTable("404 Not Found",
+			func(id string) {
+				By("Sending a GET /nodes/{node_id}/queue request")
+				resp, err := apiCli.Get(
+					fmt.Sprintf("http://127.0.0.1:8080/nodes/%s/queue", id))
+				Expect(err).ToNot(HaveOccurred())
+				defer resp.Body.Close()
+
+				By("Verifying a 404 Not Found response")
+				Expect(resp.StatusCode).To(Equal(http.StatusNotFound))
+			},
+			Entry("GET /nodes/{node_id}/queue with nonexistent ID", uuid.New()),
+		)
+	})
+
+	Describe("DELETE /nodes/{node_id}/queue/{operation_id}", func() {
+		DescribeTable("404 Not Found",
+			func() {
+				nodeCfg := createAndRegisterNode()
+
+				By("Sending a DELETE /nodes/{node_id}/queue/{operation_id} request")
+				resp, err := apiCli.Delete(
+					fmt.Sprintf("http://127.0.0.1:8080/nodes/%s/queue/%s", nodeCfg.nodeID, uuid.New()))
+				Expect(err).ToNot(HaveOccurred())
+				defer resp.Body.Close()
+
+				By("Verifying a 404 Not Found response")
+				Expect(resp.StatusCode).To(Equal(http.StatusNotFound))
+			},
+			Entry("DELETE /nodes/{node_id}/queue/{operation_id} with nonexistent ID"),
+		)
+	})
+})
@@ -169,6 +169,9 @@ var _ = Describe("/nodes", func() {
 							Location: "Localhost port 42101",
 							Serial:   nodeCfg.serial,
 						},
+						Utilization: &swagger.NodeUtilization{
+							Requested: swagger.NodeResources{},
+						},
 					},
 				))
 			},
@@ -0,0 +1,202 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package main_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/open-ness/edgecontroller/swagger"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("/nodes/{node_id}/zones", func() {
+	postNodeZones := func(nodeID, reqStr string) *http.Response {
+		By("Sending a POST /nodes/{node_id}/zones request")
+		resp, err := apiCli.Post(
+			fmt.Sprintf("http://127.0.0.1:8080/nodes/%s/zones", nodeID),
+			"application/json",
+			strings.NewReader(reqStr))
+		Expect(err).ToNot(HaveOccurred())
+		return resp
+	}
+
+	Describe("POST /nodes/{node_id}/zones", func() {
+		DescribeTable("201 Created",
+			func(reqStr string, expected swagger.ZoneSummary) {
+				nodeCfg := createAndRegisterNode()
+
+				resp := postNodeZones(nodeCfg.nodeID, reqStr)
+				defer resp.Body.Close()
+
+				By("Verifying a 201 Created response")
+				Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+
+				By("Sending a GET /nodes/{node_id}/zones request")
+				resp2, err := apiCli.Get(
+					fmt.Sprintf("http://127.0.0.1:8080/nodes/%s/zones", nodeCfg.nodeID))
+				Expect(err).ToNot(HaveOccurred())
+				defer resp2.Body.Close()
+
+				By("Verifying a 200 OK response")
+				Expect(resp2.StatusCode).To(Equal(http.StatusOK))
+
+				By("Reading the response body")
+				body, err := ioutil.ReadAll(resp2.Body)
+				Expect(err).ToNot(HaveOccurred())
+
+				var zones swagger.ZoneList
+
+				By("Unmarshaling the response")
+				Expect(json.Unmarshal(body, &zones)).To(Succeed())
+
+				By("Verifying the zone was created on the node")
+				Expect(zones.Zones).To(ContainElement(expected))
+			},
+			Entry(
+				"POST /nodes/{node_id}/zones",
+				`
+				{
+					"id": "edge",
+					"description": "edge traffic"
+				}`,
+				swagger.ZoneSummary{
+					ID:          "edge",
+					Description: "edge traffic",
+				},
+			),
+		)
+
+		DescribeTable("400 Bad Request",
+			func(reqStr string, expectedResp string) {
+				nodeCfg := createAndRegisterNode()
+
+				resp := postNodeZones(nodeCfg.nodeID, reqStr)
+				defer resp.Body.Close()
+
+				By("Verifying a 400 Bad Request response")
+				Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+
+				By("Reading the response body")
+				body, err := ioutil.ReadAll(resp.Body)
+				Expect(err).ToNot(HaveOccurred())
+
+				By("Verifying the response body")
+				Expect(string(body)).To(Equal(expectedResp))
+			},
+			Entry(
+				"POST /nodes/{node_id}/zones without id",
+				`
+				{
+					"description": "edge traffic"
+				}`,
+				"Validation failed: zone_id cannot be empty",
+			),
+		)
+
+		DescribeTable("422 Unprocessable Entity",
+			func(reqStr string) {
+				nodeCfg := createAndRegisterNode()
+
+				resp := postNodeZones(nodeCfg.nodeID, reqStr)
+				resp.Body.Close()
+				Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+
+				resp = postNodeZones(nodeCfg.nodeID, reqStr)
+				defer resp.Body.Close()
+
+				By("Verifying a 422 Unprocessable Entity response")
+				Expect(resp.StatusCode).To(Equal(http.StatusUnprocessableEntity))
+			},
+			Entry(
+				"POST /nodes/{node_id}/zones with duplicate id",
+				`
+				{
+					"id": "edge",
+					"description": "edge traffic"
+				}`,
+			),
+		)
+	})
+
+	Describe("DELETE /nodes/{node_id}/zones/{zone_id}", func() {
+		DescribeTable("204 No Content",
+			func(zoneID string) {
+				nodeCfg := createAndRegisterNode()
+
+				resp := postNodeZones(nodeCfg.nodeID, fmt.Sprintf(`{"id": "%s"}`, zoneID))
+				resp.Body.Close()
+				Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+
+				By("Sending a DELETE /nodes/{node_id}/zones/{zone_id} request")
+				resp, err := apiCli.Delete(
+					fmt.Sprintf("http://127.0.0.1:8080/nodes/%s/zones/%s", nodeCfg.nodeID, zoneID))
+				Expect(err).ToNot(HaveOccurred())
+				defer resp.Body.Close()
+
+				By("Verifying a 204 No Content response")
+				Expect(resp.StatusCode).To(Equal(http.StatusNoContent))
+
+				By("Verifying the zone was deleted")
+				resp2, err := apiCli.Get(
+					fmt.Sprintf("http://127.0.0.1:8080/nodes/%s/zones/%s", nodeCfg.nodeID, zoneID))
+				Expect(err).ToNot(HaveOccurred())
+				defer resp2.Body.Close()
+				Expect(resp2.StatusCode).To(Equal(http.StatusNotFound))
+			},
+			Entry("DELETE /nodes/{node_id}/zones/{zone_id}", "core"),
+		)
+	})
+
+	Describe("PATCH /nodes/{node_id}/interfaces", func() {
+		DescribeTable("422 Unprocessable Entity",
+			func(reqStr string, expectedResp string) {
+				nodeCfg := createAndRegisterNode()
+
+				By("Sending a PATCH /nodes/{node_id}/interfaces request")
+				resp, err := apiCli.Patch(
+					fmt.Sprintf("http://127.0.0.1:8080/nodes/%s/interfaces", nodeCfg.nodeID),
+					"application/json",
+					strings.NewReader(reqStr))
+				Expect(err).ToNot(HaveOccurred())
+				defer resp.Body.Close()
+
+				By("Verifying a 422 Unprocessable Entity response")
+				Expect(resp.StatusCode).To(Equal(http.StatusUnprocessableEntity))
+
+				By("Reading the response body")
+				body, err := ioutil.ReadAll(resp.Body)
+				Expect(err).ToNot(HaveOccurred())
+
+				By("Verifying the response body")
+				Expect(string(body)).To(Equal(expectedResp))
+			},
+			Entry(
+				"PATCH /nodes/{node_id}/interfaces with an undefined zone",
+				`
+				{
+					"interfaces": [
+						{
+							"id": "if0",
+							"description": "interface0",
+							"driver": "kernel",
+							"type": "none",
+							"mac_address": "mac0",
+							"vlan": 0,
+							"zones": ["undefined"],
+							"fallback_interface": ""
+						}
+					]
+				}`,
+				"network interface if0 references undefined zone_id undefined",
+			),
+		)
+	})
+})
@@ -0,0 +1,235 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package main_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/open-ness/edgecontroller/swagger"
+	"github.com/open-ness/edgecontroller/uuid"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+func postRegistries(host string) (id string) {
+	By("Sending a POST /registries request")
+	resp, err := apiCli.Post(
+		"http://127.0.0.1:8080/registries",
+		"application/json",
+		strings.NewReader(fmt.Sprintf(`
+			{
+				"host": "%s",
+				"username": "edge",
+				"password": "secret"
+			}`, host)))
+	Expect(err).ToNot(HaveOccurred())
+	defer resp.Body.Close()
+
+	By("Verifying a 201 Created response")
+	Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+
+	var rb respBody
+
+	By("Unmarshaling the response")
+	Expect(json.NewDecoder(resp.Body).Decode(&rb)).To(Succeed())
+
+	return rb.ID
+}
+
+func getRegistry(id string) *swagger.RegistryDetail {
+	By("Sending a GET /registries/{registry_id} request")
+	resp, err := apiCli.Get(
+		fmt.Sprintf("http://127.0.0.1:8080/registries/%s", id))
+	Expect(err).ToNot(HaveOccurred())
+	defer resp.Body.Close()
+
+	By("Verifying a 200 OK response")
+	Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+	var registry swagger.RegistryDetail
+
+	By("Unmarshaling the response")
+	Expect(json.NewDecoder(resp.Body).Decode(&registry)).To(Succeed())
+
+	return &registry
+}
+
+var _ = Describe("/registries", func() {
+	var host string
+
+	BeforeEach(func() {
+		// registry hosts are unique
+		host = fmt.Sprintf("registry-%s.example.com:5000", uuid.New()[:8])
+	})
+
+	Describe("POST /registries", func() {
+		DescribeTable("400 Bad Request",
+			func(req, expectedResp string) {
+				By("Sending a POST /registries request")
+				resp, err := apiCli.Post(
+					"http://127.0.0.1:8080/registries",
+					"application/json",
+					strings.NewReader(req))
+				Expect(err).ToNot(HaveOccurred())
+				defer resp.Body.Close()
+
+				By("Verifying a 400 Bad Request response")
+				Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+
+				By("Reading the response body")
+				body, err := ioutil.ReadAll(resp.Body)
+				Expect(err).ToNot(HaveOccurred())
+
+				By("Verifying the response body")
+				Expect(string(body)).To(Equal(expectedResp))
+			},
+			Entry("POST /registries with a URL as host",
+				`
+				{
+					"host": "https://registry.example.com",
+					"username": "edge",
+					"password": "secret"
+				}`,
+				`Validation failed: host "https://registry.example.com" must be a registry host name `+
+					`with an optional port`),
+			Entry("POST /registries without password",
+				`
+				{
+					"host": "registry.example.com",
+					"username": "edge"
+				}`,
+				"Validation failed: password cannot be empty"),
+		)
+
+		DescribeTable("422 Unprocessable Entity",
+			func() {
+				postRegistries(host)
+
+				By("Repeating the POST /registries request")
+				resp, err := apiCli.Post(
+					"http://127.0.0.1:8080/registries",
+					"application/json",
+					strings.NewReader(fmt.Sprintf(`
+					{
+						"host": "%s",
+						"username": "other",
+						"password": "other"
+					}`, host)))
+				Expect(err).ToNot(HaveOccurred())
+				defer resp.Body.Close()
+
+				By("Verifying a 422 response")
+				Expect(resp.StatusCode).To(Equal(http.StatusUnprocessableEntity))
+
+				By("Reading the response body")
+				body, err := ioutil.ReadAll(resp.Body)
+				Expect(err).ToNot(HaveOccurred())
+
+				By("Verifying the response body")
+				Expect(string(body)).To(Equal(fmt.Sprintf(
+					"duplicate record in registries detected for host %s", host)))
+			},
+			Entry("POST /registries with a duplicate host"),
+		)
+	})
+
+	Describe("GET /registries", func() {
+		DescribeTable("200 OK",
+			func() {
+				id := postRegistries(host)
+
+				By("Sending a GET /registries request")
+				resp, err := apiCli.Get("http://127.0.0.1:8080/registries")
+				Expect(err).ToNot(HaveOccurred())
+				defer resp.Body.Close()
+
+				By("Verifying a 200 OK response")
+				Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+				var registries swagger.RegistryList
+
+				By("Unmarshaling the response")
+				Expect(json.NewDecoder(resp.Body).Decode(&registries)).To(Succeed())
+
+				By("Verifying the created registry was returned")
+				Expect(registries.Registries).To(ContainElement(
+					swagger.RegistrySummary{ID: id, Host: host}))
+			},
+			Entry("GET /registries"),
+		)
+	})
+
+	Describe("GET /registries/{registry_id}", func() {
+		DescribeTable("200 OK",
+			func() {
+				id := postRegistries(host)
+
+				By("Verifying the password is not returned")
+				Expect(*getRegistry(id)).To(Equal(swagger.RegistryDetail{
+					RegistrySummary: swagger.RegistrySummary{ID: id, Host: host},
+					Username:        "edge",
+				}))
+			},
+			Entry("GET /registries/{registry_id}"),
+		)
+	})
+
+	Describe("PATCH /registries/{registry_id}", func() {
+		DescribeTable("200 OK",
+			func() {
+				id := postRegistries(host)
+
+				By("Sending a PATCH /registries/{registry_id} request without password")
+				resp, err := apiCli.Patch(
+					fmt.Sprintf("http://127.0.0.1:8080/registries/%s", id),
+					"application/json",
+					strings.NewReader(fmt.Sprintf(`
+					{
+						"host": "%s",
+						"username": "renamed"
+					}`, host)))
+				Expect(err).ToNot(HaveOccurred())
+				defer resp.Body.Close()
+
+				By("Verifying a 200 OK response")
+				Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+				By("Verifying the registry was updated")
+				Expect(getRegistry(id).Username).To(Equal("renamed"))
+			},
+			Entry("PATCH /registries/{registry_id} keeping the password"),
+		)
+	})
+
+	Describe("DELETE /registries/{registry_id}", func() {
+		DescribeTable("200 OK",
+			func() {
+				id := postRegistries(host)
+
+				By("Sending a DELETE /registries/{registry_id} request")
+				resp, err := apiCli.Delete(
+					fmt.Sprintf("http://127.0.0.1:8080/registries/%s", id))
+				Expect(err).ToNot(HaveOccurred())
+				defer resp.Body.Close()
+
+				By("Verifying a 200 OK response")
+				Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+				By("Verifying the registry was deleted")
+				resp2, err := apiCli.Get(
+					fmt.Sprintf("http://127.0.0.1:8080/registries/%s", id))
+				Expect(err).ToNot(HaveOccurred())
+				defer resp2.Body.Close()
+				Expect(resp2.StatusCode).To(Equal(http.StatusNotFound))
+			},
+			Entry("DELETE /registries/{registry_id}"),
+		)
+	})
+})
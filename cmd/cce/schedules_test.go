@@ -0,0 +1,209 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package main_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/open-ness/edgecontroller/swagger"
+	"github.com/open-ness/edgecontroller/uuid"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("/schedules", func() {
+	var (
+		appID string
+	)
+
+	BeforeEach(func() {
+		appID = postApps("container")
+	})
+
+	postSchedules := func(reqStr string) *http.Response {
+		By("Sending a POST /schedules request")
+		resp, err := apiCli.Post(
+			"http://127.0.0.1:8080/schedules",
+			"application/json",
+			strings.NewReader(reqStr))
+		Expect(err).ToNot(HaveOccurred())
+		return resp
+	}
+
+	getSchedule := func(id string) *http.Response {
+		By("Sending a GET /schedules/{schedule_id} request")
+		resp, err := apiCli.Get(fmt.Sprintf("http://127.0.0.1:8080/schedules/%s", id))
+		Expect(err).ToNot(HaveOccurred())
+		return resp
+	}
+
+	Describe("POST /schedules", func() {
+		DescribeTable("201 Created",
+			func(cmd, cron, timeZone string) {
+				nodeCfg := createAndRegisterNode()
+				postNodeApps(nodeCfg.nodeID, appID)
+
+				resp := postSchedules(fmt.Sprintf(`
+					{
+						"node_id": "%s",
+						"app_id": "%s",
+						"cmd": "%s",
+						"cron": "%s",
+						"time_zone": "%s"
+					}`, nodeCfg.nodeID, appID, cmd, cron, timeZone))
+				defer resp.Body.Close()
+
+				By("Verifying a 201 Created response")
+				Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+
+				By("Reading the response body")
+				body, err := ioutil.ReadAll(resp.Body)
+				Expect(err).ToNot(HaveOccurred())
+				var created swagger.BaseResource
+				Expect(json.Unmarshal(body, &created)).To(Succeed())
+
+				resp2 := getSchedule(created.ID)
+				defer resp2.Body.Close()
+
+				By("Verifying a 200 OK response")
+				Expect(resp2.StatusCode).To(Equal(http.StatusOK))
+
+				By("Reading the response body")
+				body, err = ioutil.ReadAll(resp2.Body)
+				Expect(err).ToNot(HaveOccurred())
+
+				var schedule swagger.ScheduleDetail
+
+				By("Unmarshaling the response")
+				Expect(json.Unmarshal(body, &schedule)).To(Succeed())
+
+				By("Verifying the schedule was created")
+				Expect(schedule.ScheduleSummary).To(Equal(swagger.ScheduleSummary{
+					ID:     created.ID,
+					NodeID: nodeCfg.nodeID,
+					AppID:  appID,
+					Cmd:    cmd,
+					Cron:   cron,
+				}))
+				Expect(schedule.TimeZone).To(Equal(timeZone))
+				Expect(schedule.NextRunAt).ToNot(BeNil())
+				Expect(schedule.LastRunAt).To(BeNil())
+			},
+			Entry("POST /schedules starting an app on weekdays", "start", "0 8 * * 1-5", "Europe/Dublin"),
+			Entry("POST /schedules stopping an app on weekdays", "stop", "0 18 * * 1-5", "Europe/Dublin"),
+		)
+
+		DescribeTable("400 Bad Request",
+			func(cmd, cron, expectedResp string) {
+				nodeCfg := createAndRegisterNode()
+
+				resp := postSchedules(fmt.Sprintf(`
+					{
+						"node_id": "%s",
+						"app_id": "%s",
+						"cmd": "%s",
+						"cron": "%s"
+					}`, nodeCfg.nodeID, appID, cmd, cron))
+				defer resp.Body.Close()
+
+				By("Verifying a 400 Bad Request response")
+				Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+
+				By("Reading the response body")
+				body, err := ioutil.ReadAll(resp.Body)
+				Expect(err).ToNot(HaveOccurred())
+
+				By("Verifying the response body")
+				Expect(string(body)).To(Equal(expectedResp))
+			},
+			Entry("POST /schedules with an invalid cmd", "undeploy", "0 18 * * *",
+				`Validation failed: cmd "undeploy" is invalid`),
+			Entry("POST /schedules with an invalid cron", "stop", "0 18 * *",
+				`Validation failed: cron expression "0 18 * *" must have 5 fields`),
+		)
+
+		DescribeTable("422 Unprocessable Entity",
+			func() {
+				nodeCfg := createAndRegisterNode()
+
+				resp := postSchedules(fmt.Sprintf(`
+					{
+						"node_id": "%s",
+						"app_id": "%s",
+						"cmd": "stop",
+						"cron": "0 18 * * *"
+					}`, nodeCfg.nodeID, appID))
+				defer resp.Body.Close()
+
+				By("Verifying a 422 Unprocessable Entity response")
+				Expect(resp.StatusCode).To(Equal(http.StatusUnprocessableEntity))
+
+				By("Reading the response body")
+				body, err := ioutil.ReadAll(resp.Body)
+				Expect(err).ToNot(HaveOccurred())
+
+				By("Verifying the response body")
+				Expect(string(body)).To(Equal(fmt.Sprintf(
+					"app_id %s is not deployed to node_id %s", appID, nodeCfg.nodeID)))
+			},
+			Entry("POST /schedules for an app not deployed to the node"),
+		)
+	})
+
+	Describe("GET /schedules/{schedule_id}", func() {
+		DescribeTable("404 Not Found",
+			func(id string) {
+				resp := getSchedule(id)
+				defer resp.Body.Close()
+
+				By("Verifying a 404 Not Found response")
+				Expect(resp.StatusCode).To(Equal(http.StatusNotFound))
+			},
+			Entry("GET /schedules/{schedule_id} with nonexistent ID", uuid.New()),
+		)
+	})
+
+	Describe("DELETE /schedules/{schedule_id}", func() {
+		DescribeTable("200 OK",
+			func() {
+				nodeCfg := createAndRegisterNode()
+				postNodeApps(nodeCfg.nodeID, appID)
+
+				resp := postSchedules(fmt.Sprintf(`
+					{
+						"node_id": "%s",
+						"app_id": "%s",
+						"cmd": "restart",
+						"cron": "0 3 * * *"
+					}`, nodeCfg.nodeID, appID))
+				defer resp.Body.Close()
+				Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+				body, err := ioutil.ReadAll(resp.Body)
+				Expect(err).ToNot(HaveOccurred())
+				var created swagger.BaseResource
+				Expect(json.Unmarshal(body, &created)).To(Succeed())
+
+				By("Sending a DELETE /schedules/{schedule_id} request")
+				resp2, err := apiCli.Delete(fmt.Sprintf("http://127.0.0.1:8080/schedules/%s", created.ID))
+				Expect(err).ToNot(HaveOccurred())
+				defer resp2.Body.Close()
+
+				By("Verifying a 200 OK response")
+				Expect(resp2.StatusCode).To(Equal(http.StatusOK))
+
+				By("Verifying the schedule was deleted")
+				resp3 := getSchedule(created.ID)
+				defer resp3.Body.Close()
+				Expect(resp3.StatusCode).To(Equal(http.StatusNotFound))
+			},
+			Entry("DELETE /schedules/{schedule_id}"),
+		)
+	})
+})
@@ -16,15 +16,71 @@ const MaxHTTPRequestTime = 2 * time.Minute
 // MaxDBRequestTime is the maximum time to request database data before timing out
 const MaxDBRequestTime = 10 * time.Second
 
+// MaxOperationTime is the maximum time a single attempt of an asynchronous
+// operation may take before timing out
+const MaxOperationTime = 30 * time.Minute
+
+// MaxOperationAttempts is the number of times an asynchronous operation is
+// attempted before it is marked as failed
+const MaxOperationAttempts = 3
+
+// OperationRetryInterval is the base delay between attempts of an
+// asynchronous operation. The delay grows linearly with each attempt.
+const OperationRetryInterval = 10 * time.Second
+
+// OperationWorkers is the number of asynchronous operations executed
+// concurrently
+const OperationWorkers = 4
+
+// UpgradeHealthCheckDelay is the time a batch of upgraded nodes is given to
+// start the new version of an app before their health is checked
+const UpgradeHealthCheckDelay = 10 * time.Second
+
+// MaxReconcileNodeTime is the maximum time the reconciliation of a single node
+// may take before timing out
+const MaxReconcileNodeTime = 5 * time.Minute
+
+// ScheduleCheckInterval is the interval at which schedules are checked for
+// lifecycle commands that are due
+const ScheduleCheckInterval = 15 * time.Second
+
+// MaintenanceCheckInterval is the interval at which the maintenance windows
+// of nodes with deferred operations are checked
+const MaintenanceCheckInterval = time.Minute
+
+// BundleMemberStartTimeout is the time a member of an app bundle is given to
+// run after it is started before the apps depending on it are started
+const BundleMemberStartTimeout = 5 * time.Minute
+
+// BundleMemberPollInterval is the interval at which the status of a started
+// member of an app bundle is checked
+const BundleMemberPollInterval = 2 * time.Second
+
+// MaxMaintenanceWindowMinutes is the maximum duration (in minutes) of a
+// maintenance window of a node
+const MaxMaintenanceWindowMinutes = 7 * 24 * 60
+
+// OrphanGracePeriod is the age below which Kubernetes objects of apps that are
+// not in persistence are left alone, as their apps may be being deployed
+const OrphanGracePeriod = 5 * time.Minute
+
 // MaxCores is the maximum number of cores that an application can use.
 const MaxCores = 8
 
 // MaxMemory is the maximum memory (in MB) that an application can use.
 const MaxMemory = 16 * 1024
 
+// MaxTenantLength is the maximum length of a tenant, which leaves room for the
+// prefix of its Kubernetes namespace.
+const MaxTenantLength = 56
+
 // MaxPort is the maximum port allowed in the TCP/IP stack
 const MaxPort = 65535
 
+// MaxProbeSeconds is the maximum delay, period or timeout (in seconds) of an
+// application's health probe
+const MaxProbeSeconds = 3600
+
 // LifecycleStatus is an application's status.
 type LifecycleStatus int
 
@@ -45,6 +101,8 @@ const (
 	Stopped
 	// Error is an error status
 	Error
+	// Unhealthy is running but failing its health probes
+	Unhealthy
 )
 
 func (s LifecycleStatus) String() string {
@@ -63,6 +121,8 @@ func (s LifecycleStatus) String() string {
 		return "stopped"
 	case Error:
 		return "error"
+	case Unhealthy:
+		return "unhealthy"
 	case Unknown:
 		fallthrough
 	default:
@@ -11,6 +11,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"sync"
 )
 
 // Credentials defines a response for a request to obtain authentication
@@ -82,3 +83,37 @@ Credentials[
 		c.Certificate,
 	)
 }
+
+// RevocationCache caches whether the credentials of nodes were revoked, so
+// that they are not read on every request of a node. Whoever creates or
+// deletes credentials sets the state of the node. The zero value is an empty
+// cache and a nil cache caches nothing.
+type RevocationCache struct {
+	mu      sync.RWMutex
+	revoked map[string]bool
+}
+
+// Lookup returns whether the credentials of a node were revoked and true, or
+// false if the node is not in the cache.
+func (c *RevocationCache) Lookup(nodeID string) (revoked bool, ok bool) {
+	if c == nil {
+		return false, false
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	revoked, ok = c.revoked[nodeID]
+	return revoked, ok
+}
+
+// Set records whether the credentials of a node were revoked.
+func (c *RevocationCache) Set(nodeID string, revoked bool) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.revoked == nil {
+		c.revoked = make(map[string]bool)
+	}
+	c.revoked[nodeID] = revoked
+}
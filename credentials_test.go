@@ -113,3 +113,28 @@ HjqhNuXFDM1RVkkcBuAD0lQZHQJdXJGmqNju
 		})
 	})
 })
+
+var _ = Describe("RevocationCache", func() {
+	It("Should record whether credentials were revoked", func() {
+		cache := &cce.RevocationCache{}
+		_, ok := cache.Lookup("node-1")
+		Expect(ok).To(BeFalse())
+
+		cache.Set("node-1", false)
+		revoked, ok := cache.Lookup("node-1")
+		Expect(ok).To(BeTrue())
+		Expect(revoked).To(BeFalse())
+
+		cache.Set("node-1", true)
+		revoked, ok = cache.Lookup("node-1")
+		Expect(ok).To(BeTrue())
+		Expect(revoked).To(BeTrue())
+	})
+
+	It("Should cache nothing if nil", func() {
+		var cache *cce.RevocationCache
+		cache.Set("node-1", true)
+		_, ok := cache.Lookup("node-1")
+		Expect(ok).To(BeFalse())
+	})
+})
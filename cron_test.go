@@ -0,0 +1,69 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package cce_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	cce "github.com/open-ness/edgecontroller"
+)
+
+var _ = Describe("Cron", func() {
+	// Wednesday
+	from := time.Date(2020, time.March, 4, 10, 30, 20, 0, time.UTC)
+
+	DescribeTable("Next",
+		func(expr, timeZone string, expected time.Time) {
+			cron, err := cce.ParseCron(expr, timeZone)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cron.Next(from).Equal(expected)).To(BeTrue(), cron.Next(from).String())
+		},
+		Entry("every minute", "* * * * *", "",
+			time.Date(2020, time.March, 4, 10, 31, 0, 0, time.UTC)),
+		Entry("every quarter hour", "*/15 * * * *", "",
+			time.Date(2020, time.March, 4, 10, 45, 0, 0, time.UTC)),
+		Entry("business hours on weekdays", "0 8-18 * * 1-5", "",
+			time.Date(2020, time.March, 4, 11, 0, 0, 0, time.UTC)),
+		Entry("lists of hours", "0 6,22 * * *", "",
+			time.Date(2020, time.March, 4, 22, 0, 0, 0, time.UTC)),
+		Entry("Sunday as 7", "0 2 * * 7", "",
+			time.Date(2020, time.March, 8, 2, 0, 0, 0, time.UTC)),
+		Entry("day of month or day of week", "0 0 1 * 5", "",
+			time.Date(2020, time.March, 6, 0, 0, 0, 0, time.UTC)),
+		Entry("leap day", "0 0 29 2 *", "",
+			time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC)),
+		Entry("a time zone", "0 9 * * *", "America/New_York",
+			time.Date(2020, time.March, 4, 14, 0, 0, 0, time.UTC)),
+		Entry("a time zone across a change of daylight saving time", "0 9 * * 1", "America/New_York",
+			time.Date(2020, time.March, 9, 13, 0, 0, 0, time.UTC)),
+	)
+
+	It("Should return the zero time if no time matches", func() {
+		cron, err := cce.ParseCron("0 0 30 2 *", "")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cron.Next(from).IsZero()).To(BeTrue())
+	})
+
+	DescribeTable("ParseCron errors",
+		func(expr, timeZone, message string) {
+			_, err := cce.ParseCron(expr, timeZone)
+			Expect(err).To(MatchError(message))
+		},
+		Entry("too few fields", "* * * *", "",
+			`cron expression "* * * *" must have 5 fields`),
+		Entry("value out of range", "60 * * * *", "",
+			`cron expression "60 * * * *": minute "60" must be within 0-59`),
+		Entry("inverted range", "* 18-8 * * *", "",
+			`cron expression "* 18-8 * * *": hour "18-8" must be within 0-23`),
+		Entry("not a number", "* * * jan *", "",
+			`cron expression "* * * jan *": month "jan" must be a number, range or *`),
+		Entry("zero step", "*/0 * * * *", "",
+			`cron expression "*/0 * * * *": step of minute "*/0" must be a positive number`),
+		Entry("unknown time zone", "* * * * *", "Mars/Olympus_Mons",
+			`time zone "Mars/Olympus_Mons" is unknown`),
+	)
+})
@@ -0,0 +1,163 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package gorilla
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	cce "github.com/open-ness/edgecontroller"
+	"github.com/open-ness/edgecontroller/k8s"
+	"github.com/open-ness/edgecontroller/swagger"
+	"github.com/pkg/errors"
+)
+
+// appDNSName returns the name of the A record of an app's service, or an
+// empty string if service records are disabled.
+func appDNSName(ctrl *cce.Controller, appID string) string {
+	if ctrl.AppDNSDomain == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s.%s", appID, ctrl.AppDNSDomain)
+}
+
+// appDNSRecord returns the A record of the service of an app on a node, or nil
+// if service records are disabled or the app has no service.
+func appDNSRecord(ctx context.Context, nodeApp *cce.NodeApp) (*cce.DNSARecord, error) {
+	ctrl := getController(ctx)
+	name := appDNSName(ctrl, nodeApp.AppID)
+	if name == "" {
+		return nil, nil
+	}
+
+	addr, err := ctrl.KubernetesClient.ServiceAddress(ctx, nodeApp.Tenant, nodeApp.NodeID, nodeApp.AppID)
+	if err != nil {
+		return nil, err
+	}
+	if addr == nil || addr.ClusterIP == "" {
+		return nil, nil
+	}
+
+	return &cce.DNSARecord{
+		Name:        name,
+		Description: fmt.Sprintf("Service of app %s", nodeApp.AppID),
+		IPs:         []string{addr.ClusterIP},
+	}, nil
+}
+
+// setAppDNSRecord registers the service of an app in the DNS of its node.
+func setAppDNSRecord(ctx context.Context, ps cce.PersistenceService, nodeApp *cce.NodeApp) error {
+	record, err := appDNSRecord(ctx, nodeApp)
+	if err != nil || record == nil {
+		return err
+	}
+
+	ctrl := getController(ctx)
+	nodePort := ctrl.ELAPort
+	if nodePort == "" {
+		nodePort = defaultELAPort
+	}
+	nodeCC, err := connectNode(ctx, ps, nodeApp, nodePort, ctrl.EdgeNodeCreds)
+	if err != nil {
+		return errors.Wrap(err, "error connecting to node")
+	}
+	defer disconnectNode(nodeCC)
+
+	return nodeCC.DNSSvcCli.SetA(ctx, record)
+}
+
+// deleteAppDNSRecord removes the service of an app from the DNS of its node.
+// It must be called before the service is deleted. A record that cannot be
+// removed is kept as stale for the reconciler to remove later.
+func deleteAppDNSRecord(ctx context.Context, ps cce.PersistenceService, nodeApp *cce.NodeApp) error {
+	record, err := appDNSRecord(ctx, nodeApp)
+	if err != nil || record == nil {
+		return err
+	}
+
+	if err = removeAppDNSRecord(ctx, ps, nodeApp, record); err != nil {
+		staleAppDNSRecords.add(nodeApp, record)
+		return err
+	}
+	return nil
+}
+
+func removeAppDNSRecord(
+	ctx context.Context,
+	ps cce.PersistenceService,
+	nodeApp *cce.NodeApp,
+	record *cce.DNSARecord,
+) error {
+	ctrl := getController(ctx)
+	nodePort := ctrl.ELAPort
+	if nodePort == "" {
+		nodePort = defaultELAPort
+	}
+	nodeCC, err := connectNode(ctx, ps, nodeApp, nodePort, ctrl.EdgeNodeCreds)
+	if err != nil {
+		return errors.Wrap(err, "error connecting to node")
+	}
+	defer disconnectNode(nodeCC)
+
+	if err = nodeCC.DNSSvcCli.DeleteA(ctx, record); err != nil && !isNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// staleAppDNSRecords holds the A records of undeployed apps that could not be
+// removed from the DNS of their nodes, until the reconciler removes them.
+var staleAppDNSRecords = &appDNSRecords{
+	records: make(map[string][]staleAppDNSRecord),
+}
+
+type staleAppDNSRecord struct {
+	nodeApp *cce.NodeApp
+	record  *cce.DNSARecord
+}
+
+// appDNSRecords holds A records of apps by node ID.
+type appDNSRecords struct {
+	mu      sync.Mutex
+	records map[string][]staleAppDNSRecord
+}
+
+func (r *appDNSRecords) add(nodeApp *cce.NodeApp, record *cce.DNSARecord) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.records[nodeApp.NodeID] = append(r.records[nodeApp.NodeID], staleAppDNSRecord{nodeApp, record})
+}
+
+// take removes and returns the records of a node.
+func (r *appDNSRecords) take(nodeID string) []staleAppDNSRecord {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	records := r.records[nodeID]
+	delete(r.records, nodeID)
+	return records
+}
+
+// toNodeAppService converts the address of an app's service to its swagger
+// representation.
+func toNodeAppService(ctrl *cce.Controller, appID string, addr *k8s.ServiceAddress) *swagger.NodeAppService {
+	if addr == nil {
+		return nil
+	}
+
+	service := &swagger.NodeAppService{
+		Name:      addr.Name,
+		Type:      addr.Type,
+		ClusterIP: addr.ClusterIP,
+		DNSName:   appDNSName(ctrl, appID),
+	}
+	for _, port := range addr.Ports {
+		service.Ports = append(service.Ports, swagger.NodeAppSvcPort{
+			Port:     port.Port,
+			NodePort: port.NodePort,
+			Protocol: port.Protocol,
+		})
+	}
+	return service
+}
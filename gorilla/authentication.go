@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/gorilla/mux"
 	cce "github.com/open-ness/edgecontroller"
 )
 
@@ -25,20 +26,24 @@ func authenticate(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Verify the user name and password
-	if u.Username != ctrl.AdminCreds.Username {
+	creds := ctrl.AdminCreds
+	if ctrl.LogReaderCreds != nil && u.Username == ctrl.LogReaderCreds.Username {
+		creds = ctrl.LogReaderCreds
+	}
+	if u.Username != creds.Username {
 		log.Debugf("Unsuccessful login attempt for user '%s'", u.Username)
 		http.Error(w, "Invalid username or password", http.StatusUnauthorized)
 		return
 	}
-	if u.Password != ctrl.AdminCreds.Password {
+	if u.Password != creds.Password {
 		log.Debugf("Unsuccessful login attempt for user '%s'", u.Username)
 		http.Error(w, "Invalid username or password", http.StatusUnauthorized)
 		return
 	}
 	log.Debugf("Successfully authenticated user: %s", u.Username)
 
-	// Create an auth token
-	token, err := ctrl.TokenService.Issue()
+	// Create an auth token granting the role of the user
+	token, err := ctrl.TokenService.Issue(creds.Role)
 	if err != nil {
 		log.Debugf("Error signing authentication token: %v", err)
 		w.WriteHeader(http.StatusInternalServerError)
@@ -68,8 +73,36 @@ func authenticate(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// roleRequests are the requests allowed to the roles other than the admin,
+// by the method and path template of their route.
+var roleRequests = map[string][]string{
+	cce.RoleLogReader: {"GET /nodes/{node_id}/apps/{app_id}/logs"},
+}
+
+// authorized returns true if the role is allowed the request.
+func authorized(r *http.Request, role string) bool {
+	if role == cce.RoleAdmin {
+		return true
+	}
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return false
+	}
+	path, err := route.GetPathTemplate()
+	if err != nil {
+		return false
+	}
+	for _, request := range roleRequests[role] {
+		if request == r.Method+" "+path {
+			return true
+		}
+	}
+	return false
+}
+
 // requireAuthHandler is a handler that only allows HTTP requests with a valid
-// JSON Web Token issued by the Controller Token Authentication service.
+// JSON Web Token issued by the Controller Token Authentication service whose
+// role is allowed the request.
 func requireAuthHandler(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
@@ -89,12 +122,19 @@ func requireAuthHandler(next http.Handler) http.Handler {
 		}
 
 		// Validate the auth token
-		err := ctrl.TokenService.Validate(bearer[1])
+		role, err := ctrl.TokenService.Validate(bearer[1])
 		if err != nil {
 			w.WriteHeader(http.StatusUnauthorized)
 			return
 		}
 
+		// Authorize the request for the role of the token
+		if !authorized(r, role) {
+			log.Noticef("Role %s is not allowed %s %s", role, r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
 		next.ServeHTTP(w, r)
 	})
 }
@@ -0,0 +1,519 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package gorilla
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	cce "github.com/open-ness/edgecontroller"
+	"github.com/open-ness/edgecontroller/swagger"
+	"github.com/open-ness/edgecontroller/uuid"
+	"github.com/pkg/errors"
+)
+
+// bundlePayload is the payload of app bundle operations.
+type bundlePayload struct {
+	BundleID string `json:"bundle_id"`
+}
+
+func toBundlePayload(bundleID string) json.RawMessage {
+	payload, _ := json.Marshal(bundlePayload{BundleID: bundleID})
+	return payload
+}
+
+// bundleCommands maps the lifecycle commands of a node app bundle to their
+// operation types.
+var bundleCommands = map[string]string{
+	cce.OperationTypeStart:   cce.OperationTypeStartBundle,
+	cce.OperationTypeStop:    cce.OperationTypeStopBundle,
+	cce.OperationTypeRestart: cce.OperationTypeRestartBundle,
+}
+
+// isBundleOperation returns true if the operation targets an app bundle.
+func isBundleOperation(op *cce.Operation) bool {
+	switch op.Type {
+	case cce.OperationTypeDeployBundle, cce.OperationTypeUndeployBundle,
+		cce.OperationTypeStartBundle, cce.OperationTypeStopBundle, cce.OperationTypeRestartBundle:
+		return true
+	}
+	return false
+}
+
+// operationBundle returns the bundle targeted by an operation and the app IDs
+// of its members in the order they are started.
+func operationBundle(
+	ctx context.Context,
+	ps cce.PersistenceService,
+	op *cce.Operation,
+) (*cce.AppBundle, []string, error) {
+	var payload bundlePayload
+	if err := json.Unmarshal(op.Payload, &payload); err != nil {
+		return nil, nil, permanent(errors.Wrap(err, "error unmarshaling payload"))
+	}
+	return readBundle(ctx, ps, payload.BundleID)
+}
+
+// readBundle returns a bundle and the app IDs of its members in the order they
+// are started.
+func readBundle(
+	ctx context.Context,
+	ps cce.PersistenceService,
+	bundleID string,
+) (*cce.AppBundle, []string, error) {
+	e, err := ps.Read(ctx, bundleID, &cce.AppBundle{})
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "error reading app bundle")
+	}
+	if e == nil {
+		return nil, nil, permanent(fmt.Errorf("app bundle %s not found", bundleID))
+	}
+
+	bundle := e.(*cce.AppBundle)
+	order, err := bundle.Order()
+	if err != nil {
+		return nil, nil, err
+	}
+	return bundle, order, nil
+}
+
+// findNodeAppBundle returns the association of a bundle with a node, or nil
+// if the bundle is not deployed to the node.
+func findNodeAppBundle(
+	ctx context.Context,
+	ps cce.PersistenceService,
+	nodeID string,
+	bundleID string,
+) (*cce.NodeAppBundle, error) {
+	es, err := ps.Filter(
+		ctx,
+		&cce.NodeAppBundle{},
+		[]cce.Filter{
+			{
+				Field: "node_id",
+				Value: nodeID,
+			},
+			{
+				Field: "bundle_id",
+				Value: bundleID,
+			},
+		})
+	if err != nil {
+		return nil, errors.Wrap(err, "error filtering nodes_app_bundles")
+	}
+	if len(es) == 0 {
+		return nil, nil
+	}
+	return es[0].(*cce.NodeAppBundle), nil
+}
+
+// runDeployBundleOperation deploys and starts the members of a bundle one at
+// a time, each after the apps it depends on. The bundle is associated with
+// the node first so that its status is reported while it is deployed. Every
+// step is safe to repeat so that a failed attempt can be retried.
+func runDeployBundleOperation(ctx context.Context, ps cce.PersistenceService, op *cce.Operation) error {
+	bundle, order, err := operationBundle(ctx, ps, op)
+	if err != nil {
+		return err
+	}
+
+	nodeBundle, err := findNodeAppBundle(ctx, ps, op.NodeID, bundle.ID)
+	if err != nil {
+		return err
+	}
+	if nodeBundle == nil {
+		if err = ps.Create(ctx, &cce.NodeAppBundle{
+			ID:       uuid.New(),
+			NodeID:   op.NodeID,
+			BundleID: bundle.ID,
+		}); err != nil {
+			return errors.Wrap(err, "error creating node app bundle")
+		}
+	}
+
+	for _, appID := range order {
+		if err = reportProgress(ctx, ps, op, fmt.Sprintf("deploying app %s", appID)); err != nil {
+			return err
+		}
+		member := &cce.Operation{NodeID: op.NodeID, AppID: appID}
+		if err = runDeployOperation(ctx, ps, member); err != nil {
+			return errors.Wrapf(err, "error deploying app %s", appID)
+		}
+		if err = startBundleMember(ctx, ps, op, appID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// runUndeployBundleOperation stops and undeploys the members of a bundle in
+// reverse order and then removes the bundle from the node.
+func runUndeployBundleOperation(ctx context.Context, ps cce.PersistenceService, op *cce.Operation) error {
+	bundle, order, err := operationBundle(ctx, ps, op)
+	if err != nil {
+		return err
+	}
+	return undeployBundle(ctx, ps, op, bundle, order)
+}
+
+// undeployBundle stops and undeploys the members of a bundle on the node of an
+// operation in reverse order and then removes the bundle from the node.
+func undeployBundle(
+	ctx context.Context,
+	ps cce.PersistenceService,
+	op *cce.Operation,
+	bundle *cce.AppBundle,
+	order []string,
+) error {
+	if err := stopBundleMembers(ctx, ps, op, order); err != nil {
+		return err
+	}
+	for i := len(order) - 1; i >= 0; i-- {
+		if err := reportProgress(ctx, ps, op, fmt.Sprintf("undeploying app %s", order[i])); err != nil {
+			return err
+		}
+		member := &cce.Operation{NodeID: op.NodeID, AppID: order[i]}
+		if err := runUndeployOperation(ctx, ps, member); err != nil {
+			return errors.Wrapf(err, "error undeploying app %s", order[i])
+		}
+	}
+
+	// a previous attempt may have removed the bundle from the node already
+	nodeBundle, err := findNodeAppBundle(ctx, ps, op.NodeID, bundle.ID)
+	if err != nil || nodeBundle == nil {
+		return err
+	}
+	if _, err = ps.Delete(ctx, nodeBundle.ID, &cce.NodeAppBundle{}); err != nil {
+		return errors.Wrap(err, "error deleting node app bundle")
+	}
+
+	return nil
+}
+
+// runBundleLifecycleOperation starts the members of a bundle in order, stops
+// them in reverse order or does both to restart them.
+func runBundleLifecycleOperation(ctx context.Context, ps cce.PersistenceService, op *cce.Operation) error {
+	_, order, err := operationBundle(ctx, ps, op)
+	if err != nil {
+		return err
+	}
+
+	if op.Type == cce.OperationTypeStopBundle || op.Type == cce.OperationTypeRestartBundle {
+		if err = stopBundleMembers(ctx, ps, op, order); err != nil {
+			return err
+		}
+	}
+	if op.Type == cce.OperationTypeStartBundle || op.Type == cce.OperationTypeRestartBundle {
+		for _, appID := range order {
+			if err = startBundleMember(ctx, ps, op, appID); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// startBundleMember starts a member of a bundle unless it is running already
+// and waits until it runs, so that the apps depending on it start after it.
+func startBundleMember(ctx context.Context, ps cce.PersistenceService, op *cce.Operation, appID string) error {
+	nodeApp, err := findNodeApp(ctx, ps, &cce.Operation{NodeID: op.NodeID, AppID: appID})
+	if err != nil {
+		return err
+	}
+
+	status, err := nodeAppStatus(ctx, ps, nodeApp)
+	if err != nil {
+		return err
+	}
+	if status == cce.Running.String() {
+		return nil
+	}
+
+	if err = reportProgress(ctx, ps, op, fmt.Sprintf("starting app %s", appID)); err != nil {
+		return err
+	}
+	if _, err = handleUpdateNodesApps(ctx, ps, &cce.NodeAppReq{
+		NodeApp: *nodeApp,
+		Cmd:     cce.OperationTypeStart,
+	}); err != nil {
+		return errors.Wrapf(err, "error starting app %s", appID)
+	}
+
+	deadline := time.Now().Add(cce.BundleMemberStartTimeout)
+	for {
+		if status, err = nodeAppStatus(ctx, ps, nodeApp); err != nil {
+			return err
+		}
+		if status == cce.Running.String() {
+			return nil
+		}
+		if status == cce.Error.String() {
+			return fmt.Errorf("app %s failed to start", appID)
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("app %s is %s instead of running after %v", appID, status, cce.BundleMemberStartTimeout)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(cce.BundleMemberPollInterval):
+		}
+	}
+}
+
+// stopBundleMembers stops the running members of a bundle in reverse order.
+// Members that are not deployed are skipped.
+func stopBundleMembers(ctx context.Context, ps cce.PersistenceService, op *cce.Operation, order []string) error {
+	for i := len(order) - 1; i >= 0; i-- {
+		nodeApps, err := ps.Filter(
+			ctx,
+			&cce.NodeApp{},
+			[]cce.Filter{
+				{
+					Field: "node_id",
+					Value: op.NodeID,
+				},
+				{
+					Field: "app_id",
+					Value: order[i],
+				},
+			})
+		if err != nil {
+			return errors.Wrap(err, "error filtering nodes_apps")
+		}
+		if len(nodeApps) == 0 {
+			continue
+		}
+		nodeApp := nodeApps[0].(*cce.NodeApp)
+
+		status, err := nodeAppStatus(ctx, ps, nodeApp)
+		if err != nil {
+			return err
+		}
+		switch status {
+		case cce.Deployed.String(), cce.Stopped.String():
+			continue
+		}
+
+		if err = reportProgress(ctx, ps, op, fmt.Sprintf("stopping app %s", order[i])); err != nil {
+			return err
+		}
+		if _, err = handleUpdateNodesApps(ctx, ps, &cce.NodeAppReq{
+			NodeApp: *nodeApp,
+			Cmd:     cce.OperationTypeStop,
+		}); err != nil {
+			return errors.Wrapf(err, "error stopping app %s", order[i])
+		}
+	}
+
+	return nil
+}
+
+// nodeAppStatus returns the status of an app on its node.
+func nodeAppStatus(ctx context.Context, ps cce.PersistenceService, nodeApp *cce.NodeApp) (string, error) {
+	resp, err := handleGetNodesApps(ctx, ps, nodeApp)
+	if err != nil {
+		return "", errors.Wrapf(err, "error getting status of app %s", nodeApp.AppID)
+	}
+	return resp.(*cce.NodeAppResp).Status, nil
+}
+
+// nodeAppBundleDetail returns the status of a bundle on a node and of each of
+// its members, in the order the members are started.
+func nodeAppBundleDetail(
+	ctx context.Context,
+	ps cce.PersistenceService,
+	nodeID string,
+	bundle *cce.AppBundle,
+) (*swagger.NodeAppBundleDetail, error) {
+	order, err := bundle.Order()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		members  []swagger.NodeAppBundleMember
+		statuses []string
+	)
+	for _, appID := range order {
+		nodeApps, err := ps.Filter(
+			ctx,
+			&cce.NodeApp{},
+			[]cce.Filter{
+				{
+					Field: "node_id",
+					Value: nodeID,
+				},
+				{
+					Field: "app_id",
+					Value: appID,
+				},
+			})
+		if err != nil {
+			return nil, errors.Wrap(err, "error filtering nodes_apps")
+		}
+
+		status := cce.AppBundleStatusNotDeployed
+		if len(nodeApps) != 0 {
+			if status, err = nodeAppStatus(ctx, ps, nodeApps[0].(*cce.NodeApp)); err != nil {
+				return nil, err
+			}
+		}
+		members = append(members, swagger.NodeAppBundleMember{
+			AppID:  appID,
+			Status: status,
+		})
+		statuses = append(statuses, status)
+	}
+
+	return &swagger.NodeAppBundleDetail{
+		NodeAppBundleSummary: swagger.NodeAppBundleSummary{
+			ID:     bundle.ID,
+			Status: cce.BundleStatus(statuses),
+		},
+		Members: members,
+	}, nil
+}
+
+// checkPendingBundleOperations returns an error if an operation for the
+// bundle on the node, or for one of its members, has not finished yet.
+func checkPendingBundleOperations(
+	ctx context.Context,
+	ps cce.PersistenceService,
+	nodeID string,
+	bundle *cce.AppBundle,
+) (statusCode int, err error) {
+	es, err := ps.Filter(
+		ctx,
+		&cce.Operation{},
+		[]cce.Filter{
+			{
+				Field: "node_id",
+				Value: nodeID,
+			},
+		})
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	for _, e := range es {
+		op := e.(*cce.Operation)
+		if !isBundleOperation(op) {
+			continue
+		}
+		switch op.State {
+		case cce.OperationStatePending, cce.OperationStateRunning, cce.OperationStateDeferred:
+		default:
+			continue
+		}
+		var payload bundlePayload
+		if err = json.Unmarshal(op.Payload, &payload); err != nil {
+			return http.StatusInternalServerError, errors.Wrap(err, "error unmarshaling payload")
+		}
+		if payload.BundleID == bundle.ID {
+			return http.StatusUnprocessableEntity, fmt.Errorf(
+				"operation %s is %s for node_id %s and bundle_id %s",
+				op.ID, op.State, nodeID, bundle.ID)
+		}
+	}
+
+	for _, m := range bundle.Members {
+		if statusCode, err = checkPendingOperations(ctx, ps, nodeID, m.AppID); err != nil {
+			return statusCode, err
+		}
+	}
+
+	return 0, nil
+}
+
+// checkDBCreateNodesAppBundles returns an error if a member of the bundle
+// cannot be deployed to the node on its own, if the node lacks the features a
+// member requires, or if the members together would over-commit the node.
+func checkDBCreateNodesAppBundles(
+	ctx context.Context,
+	ps cce.PersistenceService,
+	nodeID string,
+	bundle *cce.AppBundle,
+) (statusCode int, err error) {
+	features, err := getNfdFeatures(ctx, nodeID)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	var members []*cce.App
+	for _, m := range bundle.Members {
+		if statusCode, err = checkDBCreateNodesApps(ctx, ps, &cce.NodeApp{
+			ID:     uuid.New(),
+			NodeID: nodeID,
+			AppID:  m.AppID,
+		}); err != nil {
+			return statusCode, err
+		}
+
+		app, err := ps.Read(ctx, m.AppID, &cce.App{})
+		if err != nil {
+			return http.StatusInternalServerError, err
+		}
+		if app == nil {
+			return http.StatusUnprocessableEntity, fmt.Errorf("app %s of bundle_id %s not found", m.AppID, bundle.ID)
+		}
+		if err = app.(*cce.App).EPAValidate(features); err != nil {
+			return http.StatusUnprocessableEntity, errors.Wrapf(err, "cannot deploy app %s to node_id %s", m.AppID, nodeID)
+		}
+		members = append(members, app.(*cce.App))
+	}
+
+	return checkBundleOvercommit(ctx, ps, nodeID, bundle, members)
+}
+
+// checkPendingBundleUpdate returns an error if an operation for the bundle
+// has not finished yet on any node, as it would run against the updated
+// members.
+func checkPendingBundleUpdate(
+	ctx context.Context,
+	ps cce.PersistenceService,
+	bundleID string,
+) (statusCode int, err error) {
+	for _, state := range []string{
+		cce.OperationStatePending, cce.OperationStateRunning,
+		cce.OperationStateQueued, cce.OperationStateDeferred,
+	} {
+		es, err := ps.Filter(
+			ctx,
+			&cce.Operation{},
+			[]cce.Filter{
+				{
+					Field: "state",
+					Value: state,
+				},
+			})
+		if err != nil {
+			return http.StatusInternalServerError, err
+		}
+
+		for _, e := range es {
+			op := e.(*cce.Operation)
+			if !isBundleOperation(op) {
+				continue
+			}
+			var payload bundlePayload
+			if err = json.Unmarshal(op.Payload, &payload); err != nil {
+				return http.StatusInternalServerError, errors.Wrap(err, "error unmarshaling payload")
+			}
+			if payload.BundleID == bundleID {
+				return http.StatusUnprocessableEntity, fmt.Errorf(
+					"operation %s is %s for node_id %s and bundle_id %s",
+					op.ID, state, op.NodeID, bundleID)
+			}
+		}
+	}
+
+	return 0, nil
+}
@@ -0,0 +1,315 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package gorilla
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	cce "github.com/open-ness/edgecontroller"
+	"github.com/open-ness/edgecontroller/swagger"
+	"github.com/pkg/errors"
+)
+
+// NFD labels a node can publish, e.g. from a local feature hook, to report its
+// allocatable resources. Memory and hugepages are in MB.
+const (
+	nfdAllocatableCores     = "allocatable-cores"
+	nfdAllocatableMemory    = "allocatable-memory"
+	nfdAllocatableHugepages = "allocatable-hugepages"
+)
+
+// Sources of a node's allocatable resources, from the most to the least
+// preferred.
+const (
+	allocatableSourceAdmin      = "admin"
+	allocatableSourceKubernetes = "kubernetes"
+	allocatableSourceNFD        = "nfd"
+)
+
+// nodeResources is an amount of a node's resources.
+type nodeResources struct {
+	cores     int
+	memory    int // in MB
+	hugepages int // in MB
+}
+
+func (r *nodeResources) add(app *cce.App) {
+	r.cores += app.Cores
+	r.memory += app.Memory
+	r.hugepages += app.Hugepages
+}
+
+// overcommits returns the resources of which the node would not have enough
+// if the app was deployed on top of the requested resources.
+func (r *nodeResources) overcommits(requested nodeResources, app *cce.App) []string {
+	var exceeded []string
+	if requested.cores+app.Cores > r.cores {
+		exceeded = append(exceeded, fmt.Sprintf(
+			"cores: %d requested, %d of %d allocated",
+			app.Cores, requested.cores, r.cores))
+	}
+	if requested.memory+app.Memory > r.memory {
+		exceeded = append(exceeded, fmt.Sprintf(
+			"memory: %d MB requested, %d MB of %d MB allocated",
+			app.Memory, requested.memory, r.memory))
+	}
+	if requested.hugepages+app.Hugepages > r.hugepages {
+		exceeded = append(exceeded, fmt.Sprintf(
+			"hugepages: %d MB requested, %d MB of %d MB allocated",
+			app.Hugepages, requested.hugepages, r.hugepages))
+	}
+	return exceeded
+}
+
+func toSwaggerNodeResources(r *nodeResources) *swagger.NodeResources {
+	if r == nil {
+		return nil
+	}
+	return &swagger.NodeResources{
+		Cores:     r.cores,
+		Memory:    r.memory,
+		Hugepages: r.hugepages,
+	}
+}
+
+// findNodeCapacity returns the allocatable resources set by an administrator
+// for a node or nil if none were set.
+func findNodeCapacity(ctx context.Context, ps cce.PersistenceService, nodeID string) (*cce.NodeCapacity, error) {
+	capacities, err := ps.Filter(
+		ctx,
+		&cce.NodeCapacity{},
+		[]cce.Filter{
+			{
+				Field: "node_id",
+				Value: nodeID,
+			},
+		})
+	if err != nil {
+		return nil, errors.Wrap(err, "error filtering nodes_capacities")
+	}
+	if len(capacities) == 0 {
+		return nil, nil
+	}
+
+	return capacities[0].(*cce.NodeCapacity), nil
+}
+
+// getNodeAllocatable returns the resources of a node that apps can request and
+// where they were taken from. The resources set by an administrator take
+// precedence over the ones reported by Kubernetes, which take precedence over
+// the ones published as NFD labels. It returns nil if none are known.
+func getNodeAllocatable(
+	ctx context.Context,
+	ps cce.PersistenceService,
+	nodeID string,
+) (*nodeResources, string, error) {
+	capacity, err := findNodeCapacity(ctx, ps, nodeID)
+	if err != nil {
+		return nil, "", err
+	}
+	if capacity != nil {
+		return &nodeResources{
+			cores:     capacity.Cores,
+			memory:    capacity.Memory,
+			hugepages: capacity.Hugepages,
+		}, allocatableSourceAdmin, nil
+	}
+
+	ctrl := getController(ctx)
+	if ctrl.OrchestrationMode != cce.OrchestrationModeNative {
+		resources, err := ctrl.KubernetesClient.NodeAllocatable(ctx, nodeID)
+		if err == nil {
+			return &nodeResources{
+				cores:     resources.Cores,
+				memory:    resources.Memory,
+				hugepages: resources.Hugepages,
+			}, allocatableSourceKubernetes, nil
+		}
+		log.Debugf("Unable to get allocatable resources of node %s from Kubernetes: %v", nodeID, err)
+	}
+
+	features, err := getNfdFeatures(ctx, nodeID)
+	if err != nil {
+		return nil, "", err
+	}
+	cores, coresErr := strconv.Atoi(features[nfdAllocatableCores])
+	memory, memoryErr := strconv.Atoi(features[nfdAllocatableMemory])
+	if coresErr != nil || memoryErr != nil {
+		return nil, "", nil
+	}
+	// hugepages are optional, a node without them has none to allocate
+	hugepages, _ := strconv.Atoi(features[nfdAllocatableHugepages])
+
+	return &nodeResources{
+		cores:     cores,
+		memory:    memory,
+		hugepages: hugepages,
+	}, allocatableSourceNFD, nil
+}
+
+// getNodeRequested returns the resources requested by the apps deployed, or
+// being deployed, to a node.
+func getNodeRequested(ctx context.Context, ps cce.PersistenceService, nodeID string) (nodeResources, error) {
+	var requested nodeResources
+
+	var appIDs []string
+	nodeApps, err := ps.Filter(
+		ctx,
+		&cce.NodeApp{},
+		[]cce.Filter{
+			{
+				Field: "node_id",
+				Value: nodeID,
+			},
+		})
+	if err != nil {
+		return requested, errors.Wrap(err, "error filtering nodes_apps")
+	}
+	for _, e := range nodeApps {
+		appIDs = append(appIDs, e.(*cce.NodeApp).RunningAppID())
+	}
+
+	// apps being deployed are persisted once the deployment succeeds, so the
+	// members of bundles and apps with a deploy operation in flight count too
+	for _, state := range []string{
+		cce.OperationStatePending, cce.OperationStateRunning,
+		cce.OperationStateQueued, cce.OperationStateDeferred,
+	} {
+		ops, err := ps.Filter(
+			ctx,
+			&cce.Operation{},
+			[]cce.Filter{
+				{
+					Field: "node_id",
+					Value: nodeID,
+				},
+				{
+					Field: "state",
+					Value: state,
+				},
+			})
+		if err != nil {
+			return requested, errors.Wrap(err, "error filtering operations")
+		}
+		for _, e := range ops {
+			op := e.(*cce.Operation)
+			switch op.Type {
+			case cce.OperationTypeDeploy:
+				appIDs = append(appIDs, op.AppID)
+			case cce.OperationTypeDeployBundle:
+				_, members, err := operationBundle(ctx, ps, op)
+				if err != nil {
+					return requested, err
+				}
+				appIDs = append(appIDs, members...)
+			}
+		}
+	}
+
+	// an app deployed again, e.g. as a member of a bundle, is counted once
+	seen := make(map[string]bool)
+	for _, appID := range appIDs {
+		if seen[appID] {
+			continue
+		}
+		seen[appID] = true
+		app, err := ps.Read(ctx, appID, &cce.App{})
+		if err != nil {
+			return requested, errors.Wrap(err, "error reading app")
+		}
+		if app != nil {
+			requested.add(app.(*cce.App))
+		}
+	}
+
+	return requested, nil
+}
+
+// getNodeUtilization returns the allocatable and requested resources of a
+// node.
+func getNodeUtilization(ctx context.Context, ps cce.PersistenceService, nodeID string) (*swagger.NodeUtilization, error) {
+	allocatable, source, err := getNodeAllocatable(ctx, ps, nodeID)
+	if err != nil {
+		return nil, err
+	}
+	requested, err := getNodeRequested(ctx, ps, nodeID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &swagger.NodeUtilization{
+		Allocatable:       toSwaggerNodeResources(allocatable),
+		AllocatableSource: source,
+		Requested:         *toSwaggerNodeResources(&requested),
+	}, nil
+}
+
+// checkOvercommit returns an error if deploying the app would request more
+// resources than the node has left, unless the controller is configured to
+// only warn about over-commit. Nodes with unknown allocatable resources are
+// never over-committed.
+func checkOvercommit(
+	ctx context.Context,
+	ps cce.PersistenceService,
+	nodeID string,
+	app *cce.App,
+) (statusCode int, err error) {
+	return checkRequestOvercommit(ctx, ps, nodeID, fmt.Sprintf("app %s", app.ID), app)
+}
+
+// checkBundleOvercommit returns an error if deploying all the members of a
+// bundle together would request more resources than the node has left, like
+// checkOvercommit does for a single app.
+func checkBundleOvercommit(
+	ctx context.Context,
+	ps cce.PersistenceService,
+	nodeID string,
+	bundle *cce.AppBundle,
+	members []*cce.App,
+) (statusCode int, err error) {
+	request := &cce.App{}
+	for _, app := range members {
+		request.Cores += app.Cores
+		request.Memory += app.Memory
+		request.Hugepages += app.Hugepages
+	}
+	return checkRequestOvercommit(ctx, ps, nodeID, fmt.Sprintf("bundle %s", bundle.ID), request)
+}
+
+// checkRequestOvercommit returns an error if the resources of the request, an
+// app or the sum of several, exceed the resources the node has left.
+func checkRequestOvercommit(
+	ctx context.Context,
+	ps cce.PersistenceService,
+	nodeID string,
+	name string,
+	request *cce.App,
+) (statusCode int, err error) {
+	allocatable, _, err := getNodeAllocatable(ctx, ps, nodeID)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if allocatable == nil {
+		return 0, nil
+	}
+	requested, err := getNodeRequested(ctx, ps, nodeID)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	exceeded := allocatable.overcommits(requested, request)
+	if len(exceeded) == 0 {
+		return 0, nil
+	}
+	if getController(ctx).Overcommit == cce.OvercommitWarn {
+		log.Warningf("Deploying %s over-commits node %s: %v", name, nodeID, exceeded)
+		return 0, nil
+	}
+
+	return http.StatusUnprocessableEntity, fmt.Errorf(
+		"%s over-commits node %s: %v", name, nodeID, exceeded)
+}
@@ -8,13 +8,20 @@ import (
 	"fmt"
 
 	cce "github.com/open-ness/edgecontroller"
+	elapb "github.com/open-ness/edgecontroller/pb/ela"
+	ifsvcpb "github.com/open-ness/edgecontroller/pb/interfaceservice"
+	"github.com/pkg/errors"
 )
 
 func handleCreateNodesApps(ctx context.Context, ps cce.PersistenceService, e cce.Persistable) error {
-	app, err := ps.Read(ctx, e.(*cce.NodeApp).AppID, &cce.App{})
+	nodeApp := e.(*cce.NodeApp)
+	app, err := ps.Read(ctx, nodeApp.RunningAppID(), &cce.App{})
 	if err != nil {
 		return fmt.Errorf("Error fetching app from DB: %v", err)
 	}
+	if app == nil {
+		return permanent(fmt.Errorf("app %s not found", nodeApp.RunningAppID()))
+	}
 
 	log.Debugf("Loaded app %s\n%+v", app.GetID(), app)
 
@@ -23,25 +30,50 @@ func handleCreateNodesApps(ctx context.Context, ps cce.PersistenceService, e cce
 	if nodePort == "" {
 		nodePort = defaultEVAPort
 	}
-	nodeCC, err := connectNode(ctx, ps, e.(*cce.NodeApp), nodePort, ctrl.EdgeNodeCreds)
+	nodeCC, err := connectNode(ctx, ps, nodeApp, nodePort, ctrl.EdgeNodeCreds)
 	if err != nil {
-		return fmt.Errorf("Error connecting to node: %v", err)
+		return errors.Wrap(err, "Error connecting to node")
 	}
 	defer disconnectNode(nodeCC)
 
-	if err := nodeCC.AppDeploySvcCli.Deploy(ctx, app.(*cce.App)); err != nil {
-		return err
-	}
+	// the node app keeps the tenant it was deployed for, which is the
+	// namespace of its deployment in Kubernetes mode
+	nodeApp.Tenant = app.(*cce.App).Tenant
 
 	if ctrl.OrchestrationMode == cce.OrchestrationModeKubernetes ||
 		ctrl.OrchestrationMode == cce.OrchestrationModeKubernetesOVN {
-		err := ctrl.KubernetesClient.Deploy(
-			ctx,
-			e.(*cce.NodeApp).GetNodeID(),
-			toK8SApp(app.(*cce.App)))
+		// the node loads the image of each version under the version's ID,
+		// unless Kubernetes pulls it from a registry
+		if app.(*cce.App).ImageRef() == "" {
+			if err := nodeCC.AppDeploySvcCli.Deploy(ctx, app.(*cce.App)); err != nil {
+				return err
+			}
+		}
+
+		k8sApp, err := toK8SNodeApp(ctx, ps, nodeApp, app.(*cce.App))
+		if err != nil {
+			return err
+		}
+		if err := ctrl.KubernetesClient.Deploy(ctx, nodeApp.GetNodeID(), k8sApp); err != nil {
+			return err
+		}
+		// the app is deployed even if its service cannot be registered in
+		// DNS: the reconciler registers it again and records the drift
+		if err := setAppDNSRecord(ctx, ps, nodeApp); err != nil {
+			log.Warningf("Error registering service of app %s in DNS of node %s: %v",
+				app.GetID(), nodeApp.NodeID, err)
+		}
+	} else {
+		// the node knows every version by the ID the app was deployed with
+		deployed := *app.(*cce.App)
+		deployed.ID = nodeApp.AppID
+		networks, err := getAppNetworks(ctx, ps, &deployed)
 		if err != nil {
 			return err
 		}
+		if err := nodeCC.AppDeploySvcCli.Deploy(ctx, &deployed, networks...); err != nil {
+			return err
+		}
 	}
 
 	log.Infof("App %s deployed to node", app.GetID())
@@ -124,3 +156,103 @@ func handleCreateNodesDNSConfigsWithAliases(
 
 	return nil
 }
+
+// handleCreateNodesDNS applies a DNS configuration to a node and persists it.
+func handleCreateNodesDNS(
+	ctx context.Context,
+	ps cce.PersistenceService,
+	nodeDNS *cce.NodeDNSConfig,
+	dnsConfig *cce.DNSConfig,
+	dnsAliases []cce.Persistable,
+) error {
+	// Create the DNS config and aliases from the node
+	if err := handleCreateNodesDNSConfigsWithAliases(ctx, ps, nodeDNS, dnsConfig, dnsAliases); err != nil {
+		return err
+	}
+
+	// Create the config in persistence
+	if err := ps.Create(ctx, dnsConfig); err != nil {
+		return err
+	}
+
+	// Create the aliases in persistence
+	for _, alias := range dnsAliases {
+		if err := ps.Create(ctx, alias); err != nil {
+			return err
+		}
+	}
+
+	// Create the association in persistence
+	return ps.Create(ctx, nodeDNS)
+}
+
+func handleCreateNodesZones(ctx context.Context, ps cce.PersistenceService, zone *cce.NodeZone) error {
+	ctrl := getController(ctx)
+	nodePort := ctrl.ELAPort
+	if nodePort == "" {
+		nodePort = defaultELAPort
+	}
+
+	nodeCC, err := connectNode(ctx, ps, zone, nodePort, ctrl.EdgeNodeCreds)
+	if err != nil {
+		return err
+	}
+	defer disconnectNode(nodeCC)
+
+	return nodeCC.ZoneSvcCli.Create(ctx, &elapb.NetworkZone{
+		Id:          zone.ZoneID,
+		Description: zone.Description,
+	})
+}
+
+func handleAttachNodesPorts(
+	ctx context.Context,
+	ps cce.PersistenceService,
+	node *cce.Node,
+	ports []*cce.NodePort,
+) error {
+	ctrl := getController(ctx)
+	nodePort := ctrl.ELAPort
+	if nodePort == "" {
+		nodePort = defaultELAPort
+	}
+
+	nodeCC, err := connectNode(ctx, ps, node, nodePort, ctrl.EdgeNodeCreds)
+	if err != nil {
+		return err
+	}
+	defer disconnectNode(nodeCC)
+
+	var pbPorts []*ifsvcpb.Port
+	for _, port := range ports {
+		pbPorts = append(pbPorts, &ifsvcpb.Port{
+			Pci:    port.PCI,
+			Bridge: port.Bridge,
+			Driver: toPBPortDriver(port.Driver),
+		})
+	}
+	if err = nodeCC.PortSvcCli.Attach(ctx, pbPorts); err != nil {
+		return err
+	}
+
+	// Record the desired bridge membership, replacing any previous record of
+	// the same port
+	for _, port := range ports {
+		persisted, err := findNodePort(ctx, ps, port.NodeID, port.PCI)
+		if err != nil {
+			return err
+		}
+		if persisted != nil {
+			port.ID = persisted.ID
+			if err = ps.BulkUpdate(ctx, []cce.Persistable{port}); err != nil {
+				return err
+			}
+			continue
+		}
+		if err = ps.Create(ctx, port); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -11,6 +11,57 @@ import (
 	cce "github.com/open-ness/edgecontroller"
 )
 
+func checkDBCreateApps(
+	ctx context.Context,
+	ps cce.PersistenceService,
+	e cce.Persistable,
+) (statusCode int, err error) {
+	app := e.(*cce.App)
+	if statusCode, err = checkAppNetworks(ctx, ps, app); err != nil {
+		return statusCode, err
+	}
+	if err = checkAppSignature(ctx, app); err != nil {
+		return http.StatusUnprocessableEntity, err
+	}
+	if app.VersionOf == "" {
+		return 0, nil
+	}
+
+	first, err := ps.Read(ctx, app.VersionOf, &cce.App{})
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if first == nil {
+		return http.StatusUnprocessableEntity, fmt.Errorf(
+			"version_of %s not found", app.VersionOf)
+	}
+	if first.(*cce.App).VersionOf != "" {
+		return http.StatusUnprocessableEntity, fmt.Errorf(
+			"version_of %s is itself a version of app %s", app.VersionOf, first.(*cce.App).VersionOf)
+	}
+	if first.(*cce.App).Type != app.Type {
+		return http.StatusUnprocessableEntity, fmt.Errorf(
+			"type %s does not match type %s of app %s", app.Type, first.(*cce.App).Type, app.VersionOf)
+	}
+	if first.(*cce.App).Tenant != app.Tenant {
+		return http.StatusUnprocessableEntity, fmt.Errorf(
+			"tenant %q does not match tenant %q of app %s", app.Tenant, first.(*cce.App).Tenant, app.VersionOf)
+	}
+
+	versions, err := getAppVersions(ctx, ps, app.VersionOf)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	for _, version := range versions {
+		if version.Version == app.Version {
+			return http.StatusUnprocessableEntity, fmt.Errorf(
+				"version %s of app %s already exists as app %s", app.Version, app.VersionOf, version.ID)
+		}
+	}
+
+	return 0, nil
+}
+
 func checkDBCreateNodesApps(
 	ctx context.Context,
 	ps cce.PersistenceService,
@@ -43,7 +94,21 @@ func checkDBCreateNodesApps(
 			e.(*cce.NodeApp).AppID)
 	}
 
-	return 0, nil
+	app, err := ps.Read(ctx, e.(*cce.NodeApp).AppID, &cce.App{})
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if app == nil {
+		return 0, nil
+	}
+	if err = checkOrchestrationSupport(ctx, app.(*cce.App)); err != nil {
+		return http.StatusUnprocessableEntity, err
+	}
+	if err = checkAppSignature(ctx, app.(*cce.App)); err != nil {
+		return http.StatusUnprocessableEntity, err
+	}
+
+	return checkOvercommit(ctx, ps, e.(*cce.NodeApp).NodeID, app.(*cce.App))
 }
 
 func checkDBCreateDNSConfigsAppAliases(
@@ -111,3 +176,157 @@ func checkDBCreateNodesDNSConfigs(
 
 	return 0, nil
 }
+
+func checkDBCreateNodesZones(
+	ctx context.Context,
+	ps cce.PersistenceService,
+	e cce.Persistable,
+) (statusCode int, err error) {
+	var es []cce.Persistable
+
+	if es, err = ps.Filter(
+		ctx,
+		&cce.NodeZone{},
+		[]cce.Filter{
+			{
+				Field: "node_id",
+				Value: e.(*cce.NodeZone).NodeID,
+			},
+			{
+				Field: "zone_id",
+				Value: e.(*cce.NodeZone).ZoneID,
+			},
+		},
+	); err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	if len(es) != 0 {
+		return http.StatusUnprocessableEntity, fmt.Errorf(
+			"duplicate record in %s detected for node_id %s and zone_id %s",
+			e.(*cce.NodeZone).GetTableName(),
+			e.(*cce.NodeZone).NodeID,
+			e.(*cce.NodeZone).ZoneID)
+	}
+
+	return 0, nil
+}
+
+func checkDBCreateRegistries(
+	ctx context.Context,
+	ps cce.PersistenceService,
+	e cce.Persistable,
+) (statusCode int, err error) {
+	var es []cce.Persistable
+
+	if es, err = ps.Filter(
+		ctx,
+		&cce.Registry{},
+		[]cce.Filter{
+			{
+				Field: "host",
+				Value: e.(*cce.Registry).Host,
+			},
+		},
+	); err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	// an updated registry may keep its host
+	for _, persisted := range es {
+		if persisted.GetID() != e.GetID() {
+			return http.StatusUnprocessableEntity, fmt.Errorf(
+				"duplicate record in %s detected for host %s",
+				e.(*cce.Registry).GetTableName(),
+				e.(*cce.Registry).Host)
+		}
+	}
+
+	return 0, nil
+}
+
+func checkDBCreateNetworks(
+	ctx context.Context,
+	ps cce.PersistenceService,
+	e cce.Persistable,
+) (statusCode int, err error) {
+	var es []cce.Persistable
+
+	if es, err = ps.Filter(
+		ctx,
+		&cce.Network{},
+		[]cce.Filter{
+			{
+				Field: "name",
+				Value: e.(*cce.Network).Name,
+			},
+		},
+	); err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	// an updated network may keep its name
+	for _, persisted := range es {
+		if persisted.GetID() != e.GetID() {
+			return http.StatusUnprocessableEntity, fmt.Errorf(
+				"duplicate record in %s detected for name %s",
+				e.(*cce.Network).GetTableName(),
+				e.(*cce.Network).Name)
+		}
+	}
+
+	return 0, nil
+}
+
+func checkDBCreateSchedules(
+	ctx context.Context,
+	ps cce.PersistenceService,
+	e cce.Persistable,
+) (statusCode int, err error) {
+	var es []cce.Persistable
+
+	if es, err = ps.Filter(
+		ctx,
+		&cce.NodeApp{},
+		[]cce.Filter{
+			{
+				Field: "node_id",
+				Value: e.(*cce.Schedule).NodeID,
+			},
+			{
+				Field: "app_id",
+				Value: e.(*cce.Schedule).AppID,
+			},
+		},
+	); err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	if len(es) == 0 {
+		return http.StatusUnprocessableEntity, fmt.Errorf(
+			"app_id %s is not deployed to node_id %s",
+			e.(*cce.Schedule).AppID,
+			e.(*cce.Schedule).NodeID)
+	}
+
+	return 0, nil
+}
+
+func checkDBCreateAppBundles(
+	ctx context.Context,
+	ps cce.PersistenceService,
+	e cce.Persistable,
+) (statusCode int, err error) {
+	for _, m := range e.(*cce.AppBundle).Members {
+		app, err := ps.Read(ctx, m.AppID, &cce.App{})
+		if err != nil {
+			return http.StatusInternalServerError, err
+		}
+		if app == nil {
+			return http.StatusUnprocessableEntity, fmt.Errorf(
+				"members.app_id %s not found", m.AppID)
+		}
+	}
+
+	return 0, nil
+}
@@ -56,6 +56,25 @@ func checkDBDeleteNodes(
 			id)
 	}
 
+	if es, err = ps.Filter(
+		ctx,
+		&cce.NodeAppBundle{},
+		[]cce.Filter{
+			{
+				Field: "node_id",
+				Value: id,
+			},
+		},
+	); err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	if len(es) > 0 {
+		return http.StatusUnprocessableEntity, fmt.Errorf(
+			"cannot delete node_id %s: record in use in nodes_app_bundles",
+			id)
+	}
+
 	return 0, nil
 }
 
@@ -104,6 +123,39 @@ func checkDBDeleteApps(
 			id)
 	}
 
+	if es, err = ps.ReadAll(ctx, &cce.NodeApp{}); err != nil {
+		return http.StatusInternalServerError, err
+	}
+	for _, e := range es {
+		if e.(*cce.NodeApp).VersionID == id {
+			return http.StatusUnprocessableEntity, fmt.Errorf(
+				"cannot delete app_id %s: version running on node_id %s",
+				id, e.(*cce.NodeApp).NodeID)
+		}
+	}
+
+	if es, err = ps.ReadAll(ctx, &cce.App{}); err != nil {
+		return http.StatusInternalServerError, err
+	}
+	for _, e := range es {
+		if e.(*cce.App).VersionOf == id {
+			return http.StatusUnprocessableEntity, fmt.Errorf(
+				"cannot delete app_id %s: app %s is a version of it",
+				id, e.GetID())
+		}
+	}
+
+	if es, err = ps.ReadAll(ctx, &cce.AppBundle{}); err != nil {
+		return http.StatusInternalServerError, err
+	}
+	for _, e := range es {
+		if e.(*cce.AppBundle).HasMember(id) {
+			return http.StatusUnprocessableEntity, fmt.Errorf(
+				"cannot delete app_id %s: record in use in app_bundles",
+				id)
+		}
+	}
+
 	return 0, nil
 }
 
@@ -215,3 +267,104 @@ func checkDBDeleteDNSConfigs(
 
 	return 0, nil
 }
+
+func checkDBDeleteNodesZones(
+	ctx context.Context,
+	ps cce.PersistenceService,
+	nodeID string,
+	zoneID string,
+) (statusCode int, err error) {
+	var e cce.Persistable
+
+	if e, err = ps.Read(ctx, nodeID, &cce.NodeReq{}); err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if e == nil {
+		return 0, nil
+	}
+
+	for _, iface := range e.(*cce.NodeReq).NetworkInterfaces {
+		for _, zone := range iface.Zones {
+			if zone == zoneID {
+				return http.StatusUnprocessableEntity, fmt.Errorf(
+					"cannot delete zone_id %s: zone in use by network interface %s",
+					zoneID, iface.ID)
+			}
+		}
+	}
+
+	return 0, nil
+}
+
+func checkDBDeleteNetworks(
+	ctx context.Context,
+	ps cce.PersistenceService,
+	id string,
+) (statusCode int, err error) {
+	var es []cce.Persistable
+
+	if es, err = ps.ReadAll(ctx, &cce.App{}); err != nil {
+		return http.StatusInternalServerError, err
+	}
+	for _, e := range es {
+		for _, networkID := range e.(*cce.App).Networks {
+			if networkID == id {
+				return http.StatusUnprocessableEntity, fmt.Errorf(
+					"cannot delete network_id %s: network of app %s",
+					id, e.GetID())
+			}
+		}
+	}
+
+	return 0, nil
+}
+
+func checkDBDeleteAppBundles(
+	ctx context.Context,
+	ps cce.PersistenceService,
+	id string,
+) (statusCode int, err error) {
+	var es []cce.Persistable
+
+	if es, err = ps.Filter(
+		ctx,
+		&cce.NodeAppBundle{},
+		[]cce.Filter{
+			{
+				Field: "bundle_id",
+				Value: id,
+			},
+		},
+	); err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	if len(es) > 0 {
+		return http.StatusUnprocessableEntity, fmt.Errorf(
+			"cannot delete bundle_id %s: record in use in nodes_app_bundles",
+			id)
+	}
+
+	return 0, nil
+}
+
+func checkDBDeleteAppTemplates(
+	ctx context.Context,
+	ps cce.PersistenceService,
+	id string,
+) (statusCode int, err error) {
+	var es []cce.Persistable
+
+	if es, err = ps.ReadAll(ctx, &cce.NodeApp{}); err != nil {
+		return http.StatusInternalServerError, err
+	}
+	for _, e := range es {
+		if e.(*cce.NodeApp).TemplateID == id {
+			return http.StatusUnprocessableEntity, fmt.Errorf(
+				"cannot delete template_id %s: app %s instantiated from it on node_id %s",
+				id, e.(*cce.NodeApp).AppID, e.(*cce.NodeApp).NodeID)
+		}
+	}
+
+	return 0, nil
+}
@@ -0,0 +1,50 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package gorilla
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	cce "github.com/open-ness/edgecontroller"
+)
+
+func checkDBUpdateNodesInterfaces(
+	ctx context.Context,
+	ps cce.PersistenceService,
+	e cce.Persistable,
+) (statusCode int, err error) {
+	var es []cce.Persistable
+
+	if es, err = ps.Filter(
+		ctx,
+		&cce.NodeZone{},
+		[]cce.Filter{
+			{
+				Field: "node_id",
+				Value: e.(*cce.NodeReq).ID,
+			},
+		},
+	); err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	zones := make(map[string]bool)
+	for _, zone := range es {
+		zones[zone.(*cce.NodeZone).ZoneID] = true
+	}
+
+	for _, iface := range e.(*cce.NodeReq).NetworkInterfaces {
+		for _, zone := range iface.Zones {
+			if !zones[zone] {
+				return http.StatusUnprocessableEntity, fmt.Errorf(
+					"network interface %s references undefined zone_id %s",
+					iface.ID, zone)
+			}
+		}
+	}
+
+	return 0, nil
+}
@@ -0,0 +1,224 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package gorilla
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	cce "github.com/open-ness/edgecontroller"
+	"github.com/pkg/errors"
+)
+
+// runDecommissionOperation removes everything the controller deployed to a
+// node, revokes the node's credentials and deletes the node. Every step is
+// safe to repeat so that a failed attempt can be retried from the start.
+func runDecommissionOperation(ctx context.Context, ps cce.PersistenceService, op *cce.Operation) error {
+	// undeploy bundles first so that their members are stopped in order
+	nodeBundles, err := ps.Filter(
+		ctx,
+		&cce.NodeAppBundle{},
+		[]cce.Filter{
+			{
+				Field: "node_id",
+				Value: op.NodeID,
+			},
+		})
+	if err != nil {
+		return errors.Wrap(err, "error filtering nodes_app_bundles")
+	}
+	for _, e := range nodeBundles {
+		bundle, order, err := readBundle(ctx, ps, e.(*cce.NodeAppBundle).BundleID)
+		if err != nil {
+			return err
+		}
+		if err = undeployBundle(ctx, ps, op, bundle, order); err != nil {
+			return errors.Wrapf(err, "error undeploying bundle %s", bundle.ID)
+		}
+	}
+
+	nodeApps, err := ps.Filter(
+		ctx,
+		&cce.NodeApp{},
+		[]cce.Filter{
+			{
+				Field: "node_id",
+				Value: op.NodeID,
+			},
+		})
+	if err != nil {
+		return errors.Wrap(err, "error filtering nodes_apps")
+	}
+	for _, nodeApp := range nodeApps {
+		if err = decommissionNodeApp(ctx, ps, op, nodeApp.(*cce.NodeApp)); err != nil {
+			return err
+		}
+	}
+
+	ifacePolicies, err := ps.Filter(
+		ctx,
+		&cce.NodeInterfaceTrafficPolicy{},
+		[]cce.Filter{
+			{
+				Field: "node_id",
+				Value: op.NodeID,
+			},
+		})
+	if err != nil {
+		return errors.Wrap(err, "error filtering nodes_network_interfaces_traffic_policies")
+	}
+	for _, e := range ifacePolicies {
+		ifaceID := e.(*cce.NodeInterfaceTrafficPolicy).NetworkInterfaceID
+		if err = reportProgress(ctx, ps, op, fmt.Sprintf("removing policy of interface %s", ifaceID)); err != nil {
+			return err
+		}
+		if _, err = handleUpdateNodesInterfacesPolicy(ctx, ps, op.NodeID, ifaceID, ""); err != nil {
+			return errors.Wrapf(err, "error removing policy of interface %s", ifaceID)
+		}
+	}
+
+	if err = reportProgress(ctx, ps, op, "removing DNS configuration"); err != nil {
+		return err
+	}
+	if err = handleDeleteNodesDNS(ctx, ps, op.NodeID); err != nil {
+		return errors.Wrap(err, "error removing DNS configuration")
+	}
+
+	if err = reportProgress(ctx, ps, op, "revoking credentials"); err != nil {
+		return err
+	}
+	if _, err = ps.Delete(ctx, op.NodeID, &cce.Credentials{}); err != nil {
+		return errors.Wrap(err, "error deleting credentials")
+	}
+	getController(ctx).Revocations.Set(op.NodeID, true)
+
+	if err = reportProgress(ctx, ps, op, "deleting gRPC target"); err != nil {
+		return err
+	}
+	targets, err := ps.Filter(
+		ctx,
+		&cce.NodeGRPCTarget{},
+		[]cce.Filter{
+			{
+				Field: "node_id",
+				Value: op.NodeID,
+			},
+		})
+	if err != nil {
+		return errors.Wrap(err, "error filtering node_grpc_targets")
+	}
+	for _, target := range targets {
+		if _, err = ps.Delete(ctx, target.GetID(), &cce.NodeGRPCTarget{}); err != nil {
+			return errors.Wrap(err, "error deleting from node_grpc_targets")
+		}
+	}
+
+	if err = reportProgress(ctx, ps, op, "deleting node"); err != nil {
+		return err
+	}
+	if _, err = ps.Delete(ctx, op.NodeID, &cce.Node{}); err != nil {
+		return errors.Wrap(err, "error deleting node")
+	}
+
+	return nil
+}
+
+// decommissionNodeApp removes the traffic policy of an app deployed to a
+// node being decommissioned and then undeploys the app.
+func decommissionNodeApp(
+	ctx context.Context,
+	ps cce.PersistenceService,
+	op *cce.Operation,
+	nodeApp *cce.NodeApp,
+) error {
+	nodeAppPolicies, err := ps.Filter(
+		ctx,
+		&cce.NodeAppTrafficPolicy{},
+		[]cce.Filter{
+			{
+				Field: "nodes_apps_id",
+				Value: nodeApp.ID,
+			},
+		})
+	if err != nil {
+		return errors.Wrap(err, "error filtering nodes_apps_traffic_policies")
+	}
+
+	if len(nodeAppPolicies) != 0 {
+		if err = reportProgress(ctx, ps, op, fmt.Sprintf("removing policy of app %s", nodeApp.AppID)); err != nil {
+			return err
+		}
+
+		ctrl := getController(ctx)
+		if ctrl.OrchestrationMode == cce.OrchestrationModeKubernetesOVN {
+			if err = ctrl.KubernetesClient.DeleteNetworkPolicy(ctx, nodeApp.Tenant, nodeApp.NodeID, nodeApp.AppID); err != nil {
+				return errors.Wrapf(err, "error removing policy of app %s", nodeApp.AppID)
+			}
+			if _, err = ps.Delete(ctx, nodeAppPolicies[0].GetID(), &cce.NodeAppTrafficPolicy{}); err != nil {
+				return errors.Wrap(err, "error deleting from nodes_apps_traffic_policies")
+			}
+		} else if err = handleDeleteNodesAppsPolicy(ctx, ps, nodeApp); err != nil {
+			return errors.Wrapf(err, "error removing policy of app %s", nodeApp.AppID)
+		}
+	}
+
+	if err = reportProgress(ctx, ps, op, fmt.Sprintf("undeploying app %s", nodeApp.AppID)); err != nil {
+		return err
+	}
+	// a previous attempt may have undeployed the app from the node already
+	if err = handleDeleteNodesApps(ctx, ps, nodeApp); err != nil && !isNotFound(err) {
+		return errors.Wrapf(err, "error undeploying app %s", nodeApp.AppID)
+	}
+	if _, err = ps.Delete(ctx, nodeApp.ID, &cce.NodeApp{}); err != nil {
+		return errors.Wrap(err, "error deleting from nodes_apps")
+	}
+
+	return deleteTemplateApp(ctx, ps, nodeApp)
+}
+
+// checkDecommission returns an error if the node is already being
+// decommissioned.
+func checkDecommission(
+	ctx context.Context,
+	ps cce.PersistenceService,
+	nodeID string,
+) (statusCode int, err error) {
+	decommissioning, err := isDecommissioning(ctx, ps, nodeID)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if decommissioning {
+		return http.StatusUnprocessableEntity, fmt.Errorf(
+			"node_id %s is being decommissioned", nodeID)
+	}
+
+	return 0, nil
+}
+
+// isDecommissioning returns true if a decommission operation for the node is
+// pending, running or queued.
+func isDecommissioning(ctx context.Context, ps cce.PersistenceService, nodeID string) (bool, error) {
+	ops, err := ps.Filter(
+		ctx,
+		&cce.Operation{},
+		[]cce.Filter{
+			{
+				Field: "node_id",
+				Value: nodeID,
+			},
+		})
+	if err != nil {
+		return false, errors.Wrap(err, "error filtering operations")
+	}
+
+	for _, e := range ops {
+		op := e.(*cce.Operation)
+		if op.Type == cce.OperationTypeDecommission && !op.Done() {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
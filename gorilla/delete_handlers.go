@@ -7,6 +7,8 @@ import (
 	"context"
 
 	cce "github.com/open-ness/edgecontroller"
+	ifsvcpb "github.com/open-ness/edgecontroller/pb/interfaceservice"
+	"github.com/pkg/errors"
 )
 
 func handleDeleteNodesApps(ctx context.Context, ps cce.PersistenceService, e cce.Persistable) error {
@@ -32,13 +34,33 @@ func handleDeleteNodesApps(ctx context.Context, ps cce.PersistenceService, e cce
 	// if kubernetes un-deploy application
 	if ctrl.OrchestrationMode == cce.OrchestrationModeKubernetes ||
 		ctrl.OrchestrationMode == cce.OrchestrationModeKubernetesOVN {
+		// the app is undeployed even if its service cannot be removed from
+		// DNS: the reconciler removes the stale record later
+		if err = deleteAppDNSRecord(ctx, ps, e.(*cce.NodeApp)); err != nil {
+			log.Warningf("Error removing service of app %s from DNS of node %s: %v",
+				e.(*cce.NodeApp).AppID, e.(*cce.NodeApp).NodeID, err)
+		}
+
 		if err = ctrl.KubernetesClient.Undeploy(
 			ctx,
+			e.(*cce.NodeApp).Tenant,
 			e.(*cce.NodeApp).NodeID,
 			e.(*cce.NodeApp).AppID,
 		); err != nil {
 			return err
 		}
+
+		// the image of the running version was loaded under its own ID
+		if versionID := e.(*cce.NodeApp).VersionID; versionID != "" {
+			if err = nodeCC.AppDeploySvcCli.Undeploy(ctx, versionID); err != nil && !isNotFound(err) {
+				return err
+			}
+		}
+
+		// the node never loaded an image pulled from a registry
+		if app.(*cce.App).ImageRef() != "" {
+			return nil
+		}
 	}
 
 	err = nodeCC.AppDeploySvcCli.Undeploy(ctx, app.GetID())
@@ -120,3 +142,172 @@ func handleDeleteNodesDNSConfigsWithAliases(
 
 	return nil
 }
+
+func handleDeleteNodesAppsPolicy(
+	ctx context.Context,
+	ps cce.PersistenceService,
+	nodeApp *cce.NodeApp,
+) error {
+	// Filter nodes_apps_traffic_policies to get the ID
+	nodeAppPolicies, err := ps.Filter(
+		ctx,
+		&cce.NodeAppTrafficPolicy{},
+		[]cce.Filter{
+			{
+				Field: "nodes_apps_id",
+				Value: nodeApp.ID,
+			},
+		})
+	if err != nil {
+		return errors.Wrap(err, "error reading nodes_apps_traffic_policies")
+	}
+
+	ctrl := getController(ctx)
+	nodePort := ctrl.ELAPort
+	if nodePort == "" {
+		nodePort = defaultELAPort
+	}
+	nodeCC, err := connectNode(ctx, ps, nodeApp, nodePort, ctrl.EdgeNodeCreds)
+	if err != nil {
+		return errors.Wrap(err, "error connecting to node")
+	}
+	defer disconnectNode(nodeCC)
+
+	// Make gRPC call to node to delete the policy
+	if err = nodeCC.AppPolicySvcCli.Delete(ctx, nodeApp.AppID); err != nil {
+		return errors.Wrap(err, "error deleting policy")
+	}
+
+	if len(nodeAppPolicies) == 0 {
+		return nil
+	}
+
+	// Delete the resource
+	ok, err := ps.Delete(ctx, nodeAppPolicies[0].GetID(), &cce.NodeAppTrafficPolicy{})
+	if err != nil {
+		return errors.Wrap(err, "error deleting from nodes_apps_traffic_policies")
+	}
+	if !ok {
+		return errors.New("did not delete 1 record from nodes_apps_traffic_policies")
+	}
+
+	return nil
+}
+
+// handleDeleteNodesDNS deletes the DNS configuration of a node from the node
+// and from persistence.
+func handleDeleteNodesDNS(
+	ctx context.Context,
+	ps cce.PersistenceService,
+	nodeID string,
+) error {
+	// Fetch the entity from persistence
+	persistedNode, err := ps.Filter(
+		ctx,
+		&cce.NodeDNSConfig{},
+		[]cce.Filter{{Field: "node_id", Value: nodeID}},
+	)
+	if err != nil {
+		return err
+	}
+
+	// If there's no persisted DNS data there is nothing to delete
+	if len(persistedNode) == 0 {
+		return nil
+	}
+
+	// Fetch the DNS config from persistence
+	persistedConfig, err := ps.Read(
+		ctx,
+		persistedNode[0].(*cce.NodeDNSConfig).DNSConfigID,
+		&cce.DNSConfig{},
+	)
+	if err != nil {
+		return err
+	}
+
+	// Fetch the DNS aliases from persistence
+	persistedAliases, err := ps.Filter(
+		ctx,
+		&cce.DNSConfigAppAlias{},
+		[]cce.Filter{
+			{Field: "dns_config_id", Value: persistedNode[0].(*cce.NodeDNSConfig).DNSConfigID},
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	// Delete the DNS config and aliases from the node
+	if err := handleDeleteNodesDNSConfigsWithAliases(
+		ctx, ps, persistedNode[0], persistedConfig, persistedAliases,
+	); err != nil {
+		return err
+	}
+
+	// Delete the association from persistence
+	if _, err := ps.Delete(ctx, persistedNode[0].GetID(), persistedNode[0]); err != nil {
+		return err
+	}
+
+	// Delete the aliases from persistence
+	for _, alias := range persistedAliases {
+		if _, err := ps.Delete(ctx, alias.GetID(), alias); err != nil {
+			return err
+		}
+	}
+
+	// Delete the config from persistence
+	_, err = ps.Delete(ctx, persistedConfig.GetID(), persistedConfig)
+
+	return err
+}
+
+func handleDeleteNodesZones(ctx context.Context, ps cce.PersistenceService, zone *cce.NodeZone) error {
+	ctrl := getController(ctx)
+	nodePort := ctrl.ELAPort
+	if nodePort == "" {
+		nodePort = defaultELAPort
+	}
+
+	nodeCC, err := connectNode(ctx, ps, zone, nodePort, ctrl.EdgeNodeCreds)
+	if err != nil {
+		return err
+	}
+	defer disconnectNode(nodeCC)
+
+	return nodeCC.ZoneSvcCli.Delete(ctx, zone.ZoneID)
+}
+
+func handleDetachNodesPorts(
+	ctx context.Context,
+	ps cce.PersistenceService,
+	node *cce.Node,
+	pci string,
+) error {
+	ctrl := getController(ctx)
+	nodePort := ctrl.ELAPort
+	if nodePort == "" {
+		nodePort = defaultELAPort
+	}
+
+	nodeCC, err := connectNode(ctx, ps, node, nodePort, ctrl.EdgeNodeCreds)
+	if err != nil {
+		return err
+	}
+	defer disconnectNode(nodeCC)
+
+	if err = nodeCC.PortSvcCli.Detach(ctx, []*ifsvcpb.Port{{Pci: pci}}); err != nil {
+		return err
+	}
+
+	// The port may have been attached without the controller, in which case
+	// there is nothing to forget
+	persisted, err := findNodePort(ctx, ps, node.ID, pci)
+	if err != nil || persisted == nil {
+		return err
+	}
+	_, err = ps.Delete(ctx, persisted.ID, persisted)
+
+	return err
+}
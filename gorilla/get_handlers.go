@@ -7,6 +7,8 @@ import (
 	"context"
 
 	cce "github.com/open-ness/edgecontroller"
+	elapb "github.com/open-ness/edgecontroller/pb/ela"
+	ifsvcpb "github.com/open-ness/edgecontroller/pb/interfaceservice"
 )
 
 func handleGetNodes(
@@ -38,6 +40,51 @@ func handleGetNodes(
 	}, nil
 }
 
+func handleGetNodesZones(
+	ctx context.Context,
+	ps cce.PersistenceService,
+	e cce.Persistable,
+) ([]*elapb.NetworkZone, error) {
+	ctrl := getController(ctx)
+	nodePort := ctrl.ELAPort
+	if nodePort == "" {
+		nodePort = defaultELAPort
+	}
+
+	nodeCC, err := connectNode(ctx, ps, e.(*cce.Node), nodePort, ctrl.EdgeNodeCreds)
+	if err != nil {
+		return nil, err
+	}
+	defer disconnectNode(nodeCC)
+
+	zones, err := nodeCC.ZoneSvcCli.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return zones.NetworkZones, nil
+}
+
+func handleGetNodesPorts(
+	ctx context.Context,
+	ps cce.PersistenceService,
+	e cce.Persistable,
+) ([]*ifsvcpb.Port, error) {
+	ctrl := getController(ctx)
+	nodePort := ctrl.ELAPort
+	if nodePort == "" {
+		nodePort = defaultELAPort
+	}
+
+	nodeCC, err := connectNode(ctx, ps, e.(*cce.Node), nodePort, ctrl.EdgeNodeCreds)
+	if err != nil {
+		return nil, err
+	}
+	defer disconnectNode(nodeCC)
+
+	return nodeCC.PortSvcCli.GetAll(ctx)
+}
+
 func handleGetNodesApps(ctx context.Context, ps cce.PersistenceService, e cce.Persistable) (cce.RespEntity, error) {
 	ctrl := getController(ctx)
 	nodePort := ctrl.EVAPort
@@ -64,22 +111,25 @@ func handleGetNodesApps(ctx context.Context, ps cce.PersistenceService, e cce.Pe
 	}
 
 	// Kubernetes status
-	// For Unknown, Deploying and Error return immediately
+	// For Unknown, Deploying, Error and Unhealthy return immediately
 	switch s {
-	case cce.Unknown, cce.Deploying, cce.Error:
+	case cce.Unknown, cce.Deploying, cce.Error, cce.Unhealthy:
 		return &cce.NodeAppResp{
 			NodeApp: *e.(*cce.NodeApp),
 			Status:  s.String(),
 		}, nil
 	}
 
-	k8sStatus, err := ctrl.KubernetesClient.Status(ctx, e.(*cce.NodeApp).NodeID, e.(*cce.NodeApp).AppID)
+	k8sStatus, err := ctrl.KubernetesClient.AppStatus(
+		ctx, e.(*cce.NodeApp).Tenant, e.(*cce.NodeApp).NodeID, e.(*cce.NodeApp).AppID)
 	if err != nil {
 		return nil, err
 	}
 
 	return &cce.NodeAppResp{
-		NodeApp: *e.(*cce.NodeApp),
-		Status:  string(k8sStatus),
+		NodeApp:       *e.(*cce.NodeApp),
+		Status:        string(k8sStatus.Status),
+		StatusReason:  k8sStatus.Reason,
+		StatusMessage: k8sStatus.Message,
 	}, nil
 }
@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"runtime/debug"
 	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
 	logger "github.com/open-ness/common/log"
@@ -17,11 +18,27 @@ import (
 
 var log = logger.DefaultLogger.WithField("pkg", "gorilla")
 
+// auditLog records changes made to nodes and reads of their apps' logs
+// through the API.
+var auditLog = logger.DefaultLogger.WithFields(map[string]interface{}{
+	"pkg":   "gorilla",
+	"audit": true,
+})
+
 // Gorilla wraps the gorilla router and application routes.
 type Gorilla struct {
 	// router
 	router *mux.Router
 
+	// asynchronous node operations
+	operations *operationPool
+
+	// desired-state reconciliation of nodes
+	reconciler *reconciler
+
+	// reconciliation of the Kubernetes objects of apps
+	orphans *orphanReconciler
+
 	// TODO: Check if these handlers are still necessary
 	// entity routes handlers
 	nodesHandler                  *handler
@@ -29,21 +46,36 @@ type Gorilla struct {
 	trafficPoliciesHandler        *handler
 	trafficPoliciesKubeOVNHandler *handler
 	dnsConfigsHandler             *handler
+	registriesHandler             *handler
+	networksHandler               *handler
+	appBundlesHandler             *handler
 
 	// join routes handlers
 	dnsConfigsAppAliasesHandler *handler
 	nodesDNSConfigsHandler      *handler
 	nodesAppsHandler            *handler
+	schedulesHandler            *handler
 }
 
 // NewGorilla creates a new Gorilla.
 func NewGorilla( //nolint:gocyclo
 	controller *cce.Controller,
 ) *Gorilla {
+	operations := newOperationPool(controller)
+
 	g := &Gorilla{
 		// router
 		router: mux.NewRouter(),
 
+		// asynchronous node operations
+		operations: operations,
+
+		// desired-state reconciliation of nodes
+		reconciler: newReconciler(controller, operations),
+
+		// reconciliation of the Kubernetes objects of apps
+		orphans: newOrphanReconciler(controller, operations),
+
 		// entity routes handlers
 		nodesHandler: &handler{
 			model:    &cce.Node{},
@@ -56,6 +88,7 @@ func NewGorilla( //nolint:gocyclo
 		},
 		appsHandler: &handler{
 			model:         &cce.App{},
+			checkDBCreate: checkDBCreateApps,
 			checkDBDelete: checkDBDeleteApps,
 		},
 		trafficPoliciesHandler: &handler{
@@ -70,12 +103,30 @@ func NewGorilla( //nolint:gocyclo
 			model:         &cce.DNSConfig{},
 			checkDBDelete: checkDBDeleteDNSConfigs,
 		},
+		registriesHandler: &handler{
+			model:         &cce.Registry{},
+			checkDBCreate: checkDBCreateRegistries,
+		},
+		networksHandler: &handler{
+			model:         &cce.Network{},
+			checkDBCreate: checkDBCreateNetworks,
+			checkDBDelete: checkDBDeleteNetworks,
+		},
+		appBundlesHandler: &handler{
+			model:         &cce.AppBundle{},
+			checkDBCreate: checkDBCreateAppBundles,
+			checkDBDelete: checkDBDeleteAppBundles,
+		},
 
 		// join routes handlers
 		dnsConfigsAppAliasesHandler: &handler{
 			model:         &cce.DNSConfigAppAlias{},
 			checkDBCreate: checkDBCreateDNSConfigsAppAliases,
 		},
+		schedulesHandler: &handler{
+			model:         &cce.Schedule{},
+			checkDBCreate: checkDBCreateSchedules,
+		},
 		nodesAppsHandler: &handler{
 			model:    &cce.NodeApp{},
 			reqModel: &cce.NodeAppReq{},
@@ -141,6 +192,35 @@ func NewGorilla( //nolint:gocyclo
 		"PATCH    /apps/{app_id}": g.swagPATCHAppByID,
 		"DELETE   /apps/{app_id}": g.swagDELETEAppByID,
 
+		"POST     /apps/{app_id}/placements": g.swagPOSTAppPlacements,
+
+		"POST     /apps/{app_id}/upgrade": g.swagPOSTAppUpgrade,
+		"GET      /upgrades/{upgrade_id}": g.swagGETUpgradeByID,
+
+		"GET      /registries":               g.swagGETRegistries,
+		"POST     /registries":               g.swagPOSTRegistries,
+		"GET      /registries/{registry_id}": g.swagGETRegistryByID,
+		"PATCH    /registries/{registry_id}": g.swagPATCHRegistryByID,
+		"DELETE   /registries/{registry_id}": g.swagDELETERegistryByID,
+
+		"GET      /networks":              g.swagGETNetworks,
+		"POST     /networks":              g.swagPOSTNetworks,
+		"GET      /networks/{network_id}": g.swagGETNetworkByID,
+		"PATCH    /networks/{network_id}": g.swagPATCHNetworkByID,
+		"DELETE   /networks/{network_id}": g.swagDELETENetworkByID,
+
+		"GET      /app_bundles":             g.swagGETAppBundles,
+		"POST     /app_bundles":             g.swagPOSTAppBundles,
+		"GET      /app_bundles/{bundle_id}": g.swagGETAppBundleByID,
+		"PATCH    /app_bundles/{bundle_id}": g.swagPATCHAppBundleByID,
+		"DELETE   /app_bundles/{bundle_id}": g.swagDELETEAppBundleByID,
+
+		"GET      /app_templates":               g.swagGETAppTemplates,
+		"POST     /app_templates":               g.swagPOSTAppTemplates,
+		"GET      /app_templates/{template_id}": g.swagGETAppTemplateByID,
+		"PATCH    /app_templates/{template_id}": g.swagPATCHAppTemplateByID,
+		"DELETE   /app_templates/{template_id}": g.swagDELETEAppTemplateByID,
+
 		"GET      /nodes/{node_id}/dns": g.swagGETNodeDNS,
 		"PATCH    /nodes/{node_id}/dns": g.swagPATCHNodeDNS,
 		"DELETE   /nodes/{node_id}/dns": g.swagDELETENodeDNS,
@@ -149,13 +229,64 @@ func NewGorilla( //nolint:gocyclo
 		"PATCH    /nodes/{node_id}/interfaces":                g.swagPATCHInterfaces,
 		"GET      /nodes/{node_id}/interfaces/{interface_id}": g.swagGETInterfaceByID,
 
+		"GET      /nodes/{node_id}/zones":           g.swagGETNodeZones,
+		"POST     /nodes/{node_id}/zones":           g.swagPOSTNodeZones,
+		"GET      /nodes/{node_id}/zones/{zone_id}": g.swagGETNodeZoneByID,
+		"PATCH    /nodes/{node_id}/zones/{zone_id}": g.swagPATCHNodeZoneByID,
+		"DELETE   /nodes/{node_id}/zones/{zone_id}": g.swagDELETENodeZoneByID,
+
+		"GET      /nodes/{node_id}/ports":       g.swagGETNodePorts,
+		"POST     /nodes/{node_id}/ports":       g.swagPOSTNodePorts,
+		"DELETE   /nodes/{node_id}/ports/{pci}": g.swagDELETENodePortByPCI,
+
+		"PATCH    /nodes/{node_id}/capacity": g.swagPATCHNodeCapacity,
+		"DELETE   /nodes/{node_id}/capacity": g.swagDELETENodeCapacity,
+
 		"GET      /nodes/{node_id}/apps":          g.swagGETNodeApps,
 		"POST     /nodes/{node_id}/apps":          g.swagPOSTNodeApp,
 		"GET      /nodes/{node_id}/apps/{app_id}": g.swagGETNodeAppsByID,
 		"PATCH    /nodes/{node_id}/apps/{app_id}": g.swagPATCHNodeAppsByID,
 		"DELETE   /nodes/{node_id}/apps/{app_id}": g.swagDELETENodeAppByID,
 
+		"GET      /nodes/{node_id}/apps/{app_id}/logs": g.swagGETNodeAppLogs,
+
+		"GET      /nodes/{node_id}/app_bundles":             g.swagGETNodeAppBundles,
+		"POST     /nodes/{node_id}/app_bundles":             g.swagPOSTNodeAppBundle,
+		"GET      /nodes/{node_id}/app_bundles/{bundle_id}": g.swagGETNodeAppBundleByID,
+		"PATCH    /nodes/{node_id}/app_bundles/{bundle_id}": g.swagPATCHNodeAppBundleByID,
+		"DELETE   /nodes/{node_id}/app_bundles/{bundle_id}": g.swagDELETENodeAppBundleByID,
+
 		"GET      /nodes/{node_id}/nfd": g.swagGETNodeNFDTags,
+
+		"GET      /nfd/features": g.swagGETNfdFeatures,
+
+		"GET      /nodes/{node_id}/drift": g.swagGETNodeDrift,
+
+		"POST     /nodes/{node_id}/decommission": g.swagPOSTNodeDecommission,
+
+		"GET      /nodes/{node_id}/queue":                g.swagGETNodeQueue,
+		"DELETE   /nodes/{node_id}/queue/{operation_id}": g.swagDELETENodeQueuedOperation,
+
+		"GET      /nodes/{node_id}/maintenance_windows":             g.swagGETNodeMaintenanceWindows,
+		"POST     /nodes/{node_id}/maintenance_windows":             g.swagPOSTNodeMaintenanceWindows,
+		"GET      /nodes/{node_id}/maintenance_windows/{window_id}": g.swagGETNodeMaintenanceWindowByID,
+		"PATCH    /nodes/{node_id}/maintenance_windows/{window_id}": g.swagPATCHNodeMaintenanceWindowByID,
+		"DELETE   /nodes/{node_id}/maintenance_windows/{window_id}": g.swagDELETENodeMaintenanceWindowByID,
+
+		"GET      /schedules":               g.swagGETSchedules,
+		"POST     /schedules":               g.swagPOSTSchedules,
+		"GET      /schedules/{schedule_id}": g.swagGETScheduleByID,
+		"PATCH    /schedules/{schedule_id}": g.swagPATCHScheduleByID,
+		"DELETE   /schedules/{schedule_id}": g.swagDELETEScheduleByID,
+
+		"GET      /operations":                g.swagGETOperations,
+		"GET      /operations/{operation_id}": g.swagGETOperationByID,
+	}
+
+	// Status transitions and orphaned objects exist in Kubernetes only
+	if controller.OrchestrationMode != cce.OrchestrationModeNative {
+		routes["GET      /nodes/{node_id}/apps/{app_id}/events"] = g.swagGETNodeAppEvents
+		routes["GET      /orphans"] = g.swagGETOrphans
 	}
 
 	if controller.OrchestrationMode == cce.OrchestrationModeKubernetesOVN {
@@ -198,6 +329,12 @@ func NewGorilla( //nolint:gocyclo
 	// Set a timeout on all requests to prevent resource starvation
 	g.router.Use(func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Followed logs stream until the client disconnects
+			if isFollowingLogs(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
 			ctx, cancel := context.WithTimeout(r.Context(), cce.MaxHTTPRequestTime)
 			defer cancel()
 			next.ServeHTTP(w, r.WithContext(ctx))
@@ -264,6 +401,31 @@ func (c contextKey) String() string {
 	return "controller-ce context key " + string(c)
 }
 
+// RunOperations executes asynchronous node operations until the context is
+// canceled. Operations interrupted by a previous shutdown are resumed.
+func (g *Gorilla) RunOperations(ctx context.Context) {
+	g.operations.run(ctx)
+}
+
+// RunReconciler reconciles the desired state of all nodes every interval
+// until the context is canceled.
+func (g *Gorilla) RunReconciler(ctx context.Context, interval time.Duration) {
+	g.reconciler.run(ctx, interval)
+}
+
+// RunOrphanReconciler reconciles the Kubernetes objects of apps with
+// persistence at once and then every interval until the context is canceled.
+// A zero interval reconciles once.
+func (g *Gorilla) RunOrphanReconciler(ctx context.Context, interval time.Duration) {
+	g.orphans.run(ctx, interval)
+}
+
+// NodeConnected replays the operations queued for the node with the given
+// address. It is called when a node connects to the controller's proxy.
+func (g *Gorilla) NodeConnected(ctx context.Context, addr string) {
+	g.operations.nodeConnected(ctx, addr)
+}
+
 // ServeHTTP wraps mux.ServeHTTP.
 func (g *Gorilla) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	g.router.ServeHTTP(w, req)
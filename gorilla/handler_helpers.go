@@ -7,16 +7,25 @@ import (
 	"context"
 	"crypto/tls"
 	"fmt"
+	"net/http"
+	"strings"
 
 	cce "github.com/open-ness/edgecontroller"
 	"github.com/open-ness/edgecontroller/grpc/node"
 	"github.com/open-ness/edgecontroller/k8s"
+	ifsvcpb "github.com/open-ness/edgecontroller/pb/interfaceservice"
 	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	apiV1 "k8s.io/api/core/v1"
 )
 
 const (
 	defaultELAPort = "42101"
 	defaultEVAPort = "42102"
+
+	// defaultOVSBridge is the bridge ports are attached to when none is given
+	defaultOVSBridge = "br-local"
 )
 
 func connectNode(
@@ -38,6 +47,12 @@ func connectNode(
 	if err != nil {
 		return nil, errors.Wrapf(err, "could not fetch gRPC target from DB")
 	}
+	// a node without a target never connected to the controller
+	if len(targets) == 0 {
+		return nil, &nodeUnreachableError{
+			err: fmt.Errorf("no gRPC target for node %s", e.GetNodeID()),
+		}
+	}
 	// sanity check since we are about to access targets[0]
 	if len(targets) != 1 {
 		return nil, fmt.Errorf("filter returned %v", targets)
@@ -55,13 +70,64 @@ func connectNode(
 	nodeCC := node.ClientConn{Addr: addr, Port: port, TLS: conf}
 	if err := nodeCC.Connect(ctx); err != nil {
 		log.Noticef("Could not connect to node: %v", err)
-		return nil, errors.Wrap(err, "could not connect to node")
+		return nil, &nodeUnreachableError{err: errors.Wrap(err, "could not connect to node")}
 	}
 	log.Debugf("Connection to node %s established: %s", e.GetNodeID(), addr)
 
 	return &nodeCC, nil
 }
 
+// nodeUnreachableError is returned by connectNode if the node cannot be
+// reached.
+type nodeUnreachableError struct {
+	err error
+}
+
+func (e *nodeUnreachableError) Error() string {
+	return e.err.Error()
+}
+
+// isNodeUnreachable returns true if err reports that the node could not be
+// connected to or that it stopped responding.
+func isNodeUnreachable(err error) bool {
+	if err == nil {
+		return false
+	}
+	if _, ok := errors.Cause(err).(*nodeUnreachableError); ok {
+		return true
+	}
+	s, ok := status.FromError(errors.Cause(err))
+	return ok && s.Code() == codes.Unavailable
+}
+
+// permanentError is returned by an operation if retrying it cannot succeed,
+// e.g. because the app it targets is not found.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+// isPermanent returns true if err reports that an operation cannot succeed
+// when retried, either as a permanentError or because the node rejected the
+// request as invalid or found nothing to act on.
+func isPermanent(err error) bool {
+	if err == nil {
+		return false
+	}
+	if _, ok := errors.Cause(err).(*permanentError); ok {
+		return true
+	}
+	s, ok := status.FromError(errors.Cause(err))
+	return ok && (s.Code() == codes.InvalidArgument || s.Code() == codes.NotFound)
+}
+
 func disconnectNode(nodeCC *node.ClientConn) {
 	log.Debugf("Disconnecting %v", nodeCC)
 	nodeCC.Disconnect()
@@ -71,6 +137,35 @@ func getController(ctx context.Context) *cce.Controller {
 	return ctx.Value(contextKey("controller")).(*cce.Controller)
 }
 
+// audit records a change made to a node, or a read of the logs of its apps,
+// through the API.
+func audit(r *http.Request, format string, args ...interface{}) {
+	auditLog.Noticef("%s %s from %s: %s",
+		r.Method, r.URL.Path, r.RemoteAddr, fmt.Sprintf(format, args...))
+}
+
+func toPBPortDriver(driver string) ifsvcpb.Port_InterfaceDriver {
+	switch driver {
+	case "kernel":
+		return ifsvcpb.Port_KERNEL
+	case "userspace":
+		return ifsvcpb.Port_USERSPACE
+	default:
+		return ifsvcpb.Port_NONE
+	}
+}
+
+func fromPBPortDriver(driver ifsvcpb.Port_InterfaceDriver) string {
+	return strings.ToLower(driver.String())
+}
+
+// pull policies of apps mapped to the pull policies of Kubernetes
+var k8sPullPolicies = map[string]apiV1.PullPolicy{
+	cce.PullPolicyAlways:       apiV1.PullAlways,
+	cce.PullPolicyIfNotPresent: apiV1.PullIfNotPresent,
+	cce.PullPolicyNever:        apiV1.PullNever,
+}
+
 func toK8SApp(app *cce.App) k8s.App {
 	var ports []*k8s.PortProto
 	for _, port := range app.Ports {
@@ -80,11 +175,139 @@ func toK8SApp(app *cce.App) k8s.App {
 		})
 	}
 
+	var env []k8s.EnvVar
+	for _, e := range app.Env {
+		env = append(env, k8s.EnvVar{Name: e.Name, Value: e.Value})
+	}
+	var volumes []k8s.Volume
+	for _, v := range app.Volumes {
+		volumes = append(volumes, k8s.Volume{
+			Name:      v.Name,
+			MountPath: v.MountPath,
+			HostPath:  v.HostPath,
+			ReadOnly:  v.ReadOnly,
+		})
+	}
+	var configFiles []k8s.ConfigFile
+	for _, f := range app.ConfigFiles {
+		configFiles = append(configFiles, k8s.ConfigFile{Path: f.Path, Content: f.Content})
+	}
+
+	// the node loads the image of other sources under the app's ID
+	image := app.ID + ":latest"
+	pullPolicy := k8sPullPolicies[app.PullPolicy]
+	if ref := app.ImageRef(); ref != "" {
+		image = ref
+		// pin the image so that Kubernetes refuses a different one
+		if app.SHA256 != "" && !strings.Contains(ref, "@") {
+			image += "@sha256:" + app.SHA256
+		}
+		if pullPolicy == "" {
+			pullPolicy = apiV1.PullIfNotPresent
+		}
+	}
+
 	return k8s.App{
-		ID:     app.ID,
-		Image:  app.ID + ":latest",
-		Cores:  app.Cores,
-		Memory: app.Memory,
-		Ports:  ports,
+		ID:              app.ID,
+		Tenant:          app.Tenant,
+		VM:              app.Type == "vm",
+		Image:           image,
+		ImagePullPolicy: pullPolicy,
+		Cores:           app.Cores,
+		Memory:          app.Memory,
+		Hugepages:       app.Hugepages,
+		Ports:           ports,
+		Env:             env,
+		Command:         app.Command,
+		Args:            app.Args,
+		Volumes:         volumes,
+		ConfigFiles:     configFiles,
+
+		LivenessProbe:  toK8SProbe(app.LivenessProbe),
+		ReadinessProbe: toK8SProbe(app.ReadinessProbe),
+		RestartPolicy:  app.RestartPolicy,
+	}
+}
+
+// toK8SNodeApp converts the app to be deployed as the node app. It adds the
+// networks of the app and the credential of its image's registry, if the
+// registry is managed by the controller. Images of other registries are
+// pulled anonymously.
+func toK8SNodeApp(
+	ctx context.Context,
+	ps cce.PersistenceService,
+	nodeApp *cce.NodeApp,
+	app *cce.App,
+) (k8s.App, error) {
+	k8sApp := toK8SApp(app)
+	k8sApp.ID = nodeApp.AppID
+	k8sApp.Tenant = nodeApp.Tenant
+
+	networks, err := getAppNetworks(ctx, ps, app)
+	if err != nil {
+		return k8s.App{}, err
+	}
+	if k8sApp.Networks, err = toK8SNetworks(networks); err != nil {
+		return k8s.App{}, err
+	}
+
+	host := app.ImageRegistry()
+	if host == "" {
+		return k8sApp, nil
+	}
+	registries, err := ps.Filter(ctx, &cce.Registry{}, []cce.Filter{{Field: "host", Value: host}})
+	if err != nil {
+		return k8s.App{}, errors.Wrap(err, "error filtering registries")
+	}
+	if len(registries) != 0 {
+		registry := registries[0].(*cce.Registry)
+		k8sApp.PullSecret = &k8s.RegistryCredential{
+			Server:   registry.Host,
+			Username: registry.Username,
+			Password: registry.Password,
+		}
+	}
+
+	return k8sApp, nil
+}
+
+// checkOrchestrationSupport returns an error if the app needs Kubernetes while
+// apps are deployed natively: only Kubernetes pulls images from registries
+// and the node attaches an app to a single network.
+func checkOrchestrationSupport(ctx context.Context, app *cce.App) error {
+	if getController(ctx).OrchestrationMode != cce.OrchestrationModeNative {
+		return nil
+	}
+	if app.ImageRef() != "" {
+		return fmt.Errorf("source of app %s is an image registry, which requires kubernetes mode", app.ID)
+	}
+	if len(app.Networks) > 1 {
+		return fmt.Errorf("app %s is attached to %d networks, which requires kubernetes mode",
+			app.ID, len(app.Networks))
+	}
+	return nil
+}
+
+// checkAppSignature returns an error if the signature of the app cannot be
+// verified with the trust store of the controller. Such apps must not be
+// deployed, as their image may have been tampered with.
+func checkAppSignature(ctx context.Context, app *cce.App) error {
+	return getController(ctx).AppTrustStore.VerifyApp(app)
+}
+
+func toK8SProbe(probe *cce.Probe) *k8s.Probe {
+	if probe == nil {
+		return nil
+	}
+
+	return &k8s.Probe{
+		Type:                probe.Type,
+		Path:                probe.Path,
+		Port:                int32(probe.Port),
+		Command:             probe.Command,
+		InitialDelaySeconds: int32(probe.InitialDelaySeconds),
+		PeriodSeconds:       int32(probe.PeriodSeconds),
+		TimeoutSeconds:      int32(probe.TimeoutSeconds),
+		FailureThreshold:    int32(probe.FailureThreshold),
 	}
 }
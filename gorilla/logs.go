@@ -0,0 +1,128 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package gorilla
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	cce "github.com/open-ness/edgecontroller"
+	"github.com/open-ness/edgecontroller/grpc/node"
+	"github.com/open-ness/edgecontroller/k8s"
+	"github.com/pkg/errors"
+)
+
+// nodeAppLogsPath is the path template of the logs of a node app
+const nodeAppLogsPath = "/nodes/{node_id}/apps/{app_id}/logs"
+
+// parseLogOptions parses the follow, tail and since query parameters of a
+// request for the logs of a node app. Since is either a duration before now,
+// e.g. 10m, or an RFC 3339 time.
+func parseLogOptions(r *http.Request) (cce.NodeAppLogOptions, error) {
+	var (
+		opts  cce.NodeAppLogOptions
+		query = r.URL.Query()
+		err   error
+	)
+
+	if follow := query.Get("follow"); follow != "" {
+		if opts.Follow, err = strconv.ParseBool(follow); err != nil {
+			return opts, fmt.Errorf("follow %q must be true or false", follow)
+		}
+	}
+
+	if tail := query.Get("tail"); tail != "" {
+		if opts.Tail, err = strconv.ParseInt(tail, 10, 64); err != nil || opts.Tail < 0 {
+			return opts, fmt.Errorf("tail %q must be a non-negative number", tail)
+		}
+	}
+
+	if since := query.Get("since"); since != "" {
+		if d, err := time.ParseDuration(since); err == nil && d >= 0 {
+			opts.Since = time.Now().Add(-d)
+		} else if opts.Since, err = time.Parse(time.RFC3339, since); err != nil {
+			return opts, fmt.Errorf("since %q must be a duration or an RFC 3339 time", since)
+		}
+	}
+
+	return opts, nil
+}
+
+// isFollowingLogs returns true if the request follows the logs of a node app.
+// Such requests stream until the client disconnects.
+func isFollowingLogs(r *http.Request) bool {
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return false
+	}
+	if path, err := route.GetPathTemplate(); err != nil || path != nodeAppLogsPath {
+		return false
+	}
+	follow, err := strconv.ParseBool(r.URL.Query().Get("follow"))
+	return err == nil && follow
+}
+
+// nodeAppLogs opens the logs of a node app, from the pod API in Kubernetes
+// mode and from the EVA of the node in native mode.
+func nodeAppLogs(
+	ctx context.Context,
+	ctrl *cce.Controller,
+	nodeApp *cce.NodeApp,
+	opts cce.NodeAppLogOptions,
+) (io.ReadCloser, error) {
+	if ctrl.OrchestrationMode != cce.OrchestrationModeNative {
+		return ctrl.KubernetesClient.Logs(ctx, nodeApp.Tenant, nodeApp.NodeID, nodeApp.AppID, k8s.LogOptions{
+			Follow: opts.Follow,
+			Tail:   opts.Tail,
+			Since:  opts.Since,
+		})
+	}
+
+	nodePort := ctrl.EVAPort
+	if nodePort == "" {
+		nodePort = defaultEVAPort
+	}
+	nodeCC, err := connectNode(ctx, ctrl.PersistenceService, nodeApp, nodePort, ctrl.EdgeNodeCreds)
+	if err != nil {
+		return nil, errors.Wrap(err, "error connecting to node")
+	}
+
+	logs, err := nodeCC.AppLifeSvcCli.GetLogs(ctx, nodeApp.AppID, opts)
+	if err != nil {
+		disconnectNode(nodeCC)
+		return nil, err
+	}
+	return &nodeLogs{ReadCloser: logs, nodeCC: nodeCC}, nil
+}
+
+// nodeLogs disconnects from the node when its logs are closed.
+type nodeLogs struct {
+	io.ReadCloser
+	nodeCC *node.ClientConn
+}
+
+func (l *nodeLogs) Close() error {
+	err := l.ReadCloser.Close()
+	disconnectNode(l.nodeCC)
+	return err
+}
+
+// flushWriter flushes every write so that followed logs reach the client as
+// they are written.
+type flushWriter struct {
+	w http.ResponseWriter
+}
+
+func (fw flushWriter) Write(p []byte) (int, error) {
+	n, err := fw.w.Write(p)
+	if f, ok := fw.w.(http.Flusher); ok {
+		f.Flush()
+	}
+	return n, err
+}
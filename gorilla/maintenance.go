@@ -0,0 +1,196 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package gorilla
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	cce "github.com/open-ness/edgecontroller"
+	"github.com/pkg/errors"
+)
+
+// maintenanceWindows returns the maintenance windows of a node.
+func maintenanceWindows(
+	ctx context.Context,
+	ps cce.PersistenceService,
+	nodeID string,
+) ([]*cce.NodeMaintenanceWindow, error) {
+	es, err := ps.Filter(
+		ctx,
+		&cce.NodeMaintenanceWindow{},
+		[]cce.Filter{
+			{
+				Field: "node_id",
+				Value: nodeID,
+			},
+		})
+	if err != nil {
+		return nil, errors.Wrap(err, "error filtering nodes_maintenance_windows")
+	}
+
+	windows := make([]*cce.NodeMaintenanceWindow, 0, len(es))
+	for _, e := range es {
+		windows = append(windows, e.(*cce.NodeMaintenanceWindow))
+	}
+	return windows, nil
+}
+
+// maintenanceOpen returns true if a maintenance window of a node is open at t
+// or if the node has no windows. Otherwise it returns the time the next
+// window opens.
+func (p *operationPool) maintenanceOpen(
+	ctx context.Context,
+	nodeID string,
+	t time.Time,
+) (open bool, next time.Time, err error) {
+	windows, err := maintenanceWindows(ctx, p.controller.PersistenceService, nodeID)
+	if err != nil {
+		return false, time.Time{}, err
+	}
+	if len(windows) == 0 {
+		return true, time.Time{}, nil
+	}
+
+	for _, w := range windows {
+		if w.OpenAt(t) {
+			return true, time.Time{}, nil
+		}
+		if opens := w.NextOpen(t); !opens.IsZero() && (next.IsZero() || opens.Before(next)) {
+			next = opens
+		}
+	}
+	return false, next, nil
+}
+
+// deferBehind defers the operation if the node has deferred operations, so
+// that operations reach the node in the order they were requested, or if
+// the operation is disruptive and the maintenance windows of the node are
+// closed.
+func (p *operationPool) deferBehind(ctx context.Context, op *cce.Operation) (deferred bool, err error) {
+	message, deferred, err := p.deferral(ctx, op)
+	if err != nil || !deferred {
+		return false, err
+	}
+	return true, p.persistWaiting(ctx, op, cce.OperationStateDeferred, message)
+}
+
+// deferral returns why the operation has to be deferred and true, or false
+// if it can be executed now. See deferBehind.
+func (p *operationPool) deferral(ctx context.Context, op *cce.Operation) (message string, deferred bool, err error) {
+	ops, err := p.deferredOperations(ctx, op.NodeID)
+	if err != nil {
+		return "", false, err
+	}
+	if len(ops) != 0 {
+		return "node has deferred operations", true, nil
+	}
+
+	if !op.Disruptive() {
+		return "", false, nil
+	}
+	open, next, err := p.maintenanceOpen(ctx, op.NodeID, time.Now())
+	if err != nil || open {
+		return "", false, err
+	}
+
+	message = "waiting for a maintenance window"
+	if !next.IsZero() {
+		message = fmt.Sprintf("waiting for the maintenance window opening at %s", next.Format(time.RFC3339))
+	}
+	return message, true, nil
+}
+
+// runMaintenance executes the deferred operations of the nodes whose
+// maintenance windows are open until the context is canceled. Operations
+// deferred before a controller restart are executed as well.
+func (p *operationPool) runMaintenance(ctx context.Context) {
+	ticker := time.NewTicker(cce.MaintenanceCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		p.executeDeferred(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// executeDeferred starts executing the deferred operations of every node
+// whose maintenance window is open or which has no windows anymore.
+func (p *operationPool) executeDeferred(ctx context.Context) {
+	ops, err := p.controller.PersistenceService.Filter(
+		ctx,
+		&cce.Operation{},
+		[]cce.Filter{
+			{
+				Field: "state",
+				Value: cce.OperationStateDeferred,
+			},
+		})
+	if err != nil {
+		log.Errf("Error loading deferred operations: %v", err)
+		return
+	}
+
+	nodes := make(map[string]bool)
+	for _, op := range ops {
+		nodes[op.(*cce.Operation).NodeID] = true
+	}
+	for nodeID := range nodes {
+		open, _, err := p.maintenanceOpen(ctx, nodeID, time.Now())
+		if err != nil {
+			log.Errf("Error checking the maintenance windows of node %s: %v", nodeID, err)
+			continue
+		}
+		if open {
+			go p.replayDeferred(ctx, nodeID)
+		}
+	}
+}
+
+// replayDeferred executes the deferred operations of a node one at a time in
+// the order they were requested, while a maintenance window of the node is
+// open. It stops if the node becomes unreachable so that the remaining
+// operations keep their order.
+func (p *operationPool) replayDeferred(ctx context.Context, nodeID string) {
+	if !p.lockNode(nodeID) {
+		return
+	}
+	defer p.unlockNode(nodeID)
+
+	ops, err := p.deferredOperations(ctx, nodeID)
+	if err != nil {
+		log.Errf("Error loading deferred operations of node %s: %v", nodeID, err)
+		return
+	}
+	if len(ops) == 0 {
+		return
+	}
+	log.Infof("Executing %d deferred operation(s) for node %s", len(ops), nodeID)
+
+	for _, op := range ops {
+		open, _, err := p.maintenanceOpen(ctx, nodeID, time.Now())
+		if err != nil {
+			log.Errf("Error checking the maintenance windows of node %s: %v", nodeID, err)
+			return
+		}
+		if !open {
+			return
+		}
+
+		op.Transition(cce.OperationStatePending, "maintenance window is open, executing deferred operation")
+		if err = p.update(ctx, op); err != nil {
+			log.Errf("Error updating operation %s: %v", op.ID, err)
+			return
+		}
+		if p.execute(ctx, op.ID) == cce.OperationStateQueued {
+			return
+		}
+	}
+}
@@ -0,0 +1,70 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package gorilla
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	cce "github.com/open-ness/edgecontroller"
+	"github.com/open-ness/edgecontroller/k8s"
+)
+
+// errNetworkNotFound is returned when a network referenced by an app is
+// missing
+type errNetworkNotFound struct {
+	networkID string
+	appID     string
+}
+
+func (e errNetworkNotFound) Error() string {
+	return fmt.Sprintf("network %s of app %s not found", e.networkID, e.appID)
+}
+
+// getAppNetworks returns the networks the app is attached to, in the order
+// the app references them.
+func getAppNetworks(ctx context.Context, ps cce.PersistenceService, app *cce.App) ([]*cce.Network, error) {
+	var networks []*cce.Network
+	for _, id := range app.Networks {
+		network, err := ps.Read(ctx, id, &cce.Network{})
+		if err != nil {
+			return nil, err
+		}
+		if network == nil {
+			return nil, errNetworkNotFound{networkID: id, appID: app.ID}
+		}
+		networks = append(networks, network.(*cce.Network))
+	}
+	return networks, nil
+}
+
+// checkAppNetworks returns an error if a network referenced by the app is
+// missing.
+func checkAppNetworks(ctx context.Context, ps cce.PersistenceService, app *cce.App) (statusCode int, err error) {
+	if _, err = getAppNetworks(ctx, ps, app); err != nil {
+		if _, ok := err.(errNetworkNotFound); ok {
+			return http.StatusUnprocessableEntity, err
+		}
+		return http.StatusInternalServerError, err
+	}
+	return 0, nil
+}
+
+func toK8SNetworks(networks []*cce.Network) ([]k8s.Network, error) {
+	var k8sNetworks []k8s.Network
+	for _, network := range networks {
+		args, err := network.ArgsMap()
+		if err != nil {
+			return nil, err
+		}
+		k8sNetworks = append(k8sNetworks, k8s.Network{
+			Name:          network.Name,
+			Config:        string(network.CNIConfig),
+			InterfaceName: network.InterfaceName,
+			Args:          args,
+		})
+	}
+	return k8sNetworks, nil
+}
@@ -0,0 +1,206 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package gorilla
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	cce "github.com/open-ness/edgecontroller"
+	"github.com/open-ness/edgecontroller/nfd-master"
+	"github.com/open-ness/edgecontroller/swagger"
+)
+
+// nfdQueryPrefix marks the query parameters of GET /nodes that filter nodes by
+// their NFD features.
+const nfdQueryPrefix = "nfd."
+
+// nfdTerm is a single condition on an NFD feature, e.g. the query parameter
+// nfd.kernel-version.major>=5 is the term {"kernel-version.major", ">=", "5"}.
+type nfdTerm struct {
+	feature string
+	op      string
+	value   string
+	number  float64
+}
+
+// parseNfdQuery parses the NFD terms of a raw query string. The query cannot
+// be parsed with url.ParseQuery because the comparison operators are part of
+// the parameter, e.g. nfd.x>=5 would be parsed as the key "nfd.x>".
+func parseNfdQuery(rawQuery string) ([]nfdTerm, error) {
+	var terms []nfdTerm
+	for _, rawParam := range strings.Split(rawQuery, "&") {
+		param, err := url.QueryUnescape(rawParam)
+		if err != nil {
+			return nil, fmt.Errorf("invalid query parameter %q: %v", rawParam, err)
+		}
+		if !strings.HasPrefix(param, nfdQueryPrefix) {
+			continue
+		}
+
+		term, err := parseNfdTerm(strings.TrimPrefix(param, nfdQueryPrefix))
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, term)
+	}
+
+	return terms, nil
+}
+
+// parseNfdTerm parses a single condition of the form {feature}{op}{value}.
+func parseNfdTerm(s string) (nfdTerm, error) {
+	i := strings.IndexAny(s, "!<>=")
+	if i <= 0 {
+		return nfdTerm{}, fmt.Errorf("nfd.%s is missing a feature or comparison", s)
+	}
+	term := nfdTerm{feature: s[:i], op: s[i : i+1]}
+	if strings.HasPrefix(s[i+1:], "=") {
+		term.op += "="
+	}
+	term.value = s[i+len(term.op):]
+
+	switch term.op {
+	case "=", "!=":
+	case "<", "<=", ">", ">=":
+		var err error
+		if term.number, err = strconv.ParseFloat(term.value, 64); err != nil {
+			return nfdTerm{}, fmt.Errorf("nfd.%s%s%s compares to a value that is not a number",
+				term.feature, term.op, term.value)
+		}
+	default:
+		return nfdTerm{}, fmt.Errorf("nfd.%s has an invalid comparison %s", s, term.op)
+	}
+
+	return term, nil
+}
+
+// String returns the term in the form it was parsed from.
+func (t nfdTerm) String() string {
+	return t.feature + t.op + t.value
+}
+
+// matches returns true if the features of a node satisfy the term. A node
+// that does not report the feature never matches.
+func (t nfdTerm) matches(features map[string]string) bool {
+	value, ok := features[t.feature]
+	if !ok {
+		return false
+	}
+
+	switch t.op {
+	case "=":
+		return value == t.value
+	case "!=":
+		return value != t.value
+	}
+
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return false
+	}
+	switch t.op {
+	case "<":
+		return number < t.number
+	case "<=":
+		return number <= t.number
+	case ">":
+		return number > t.number
+	default:
+		return number >= t.number
+	}
+}
+
+// getFleetNfdFeatures returns the NFD features of all nodes keyed by node ID.
+func getFleetNfdFeatures(ctx context.Context, ps cce.PersistenceService) (map[string]map[string]string, error) {
+	persisted, err := ps.ReadAll(ctx, &nfd.NodeFeatureNFD{})
+	if err != nil {
+		return nil, err
+	}
+
+	fleet := make(map[string]map[string]string)
+	for _, e := range persisted {
+		f := e.(*nfd.NodeFeatureNFD)
+		if fleet[f.NodeID] == nil {
+			fleet[f.NodeID] = make(map[string]string)
+		}
+		fleet[f.NodeID][f.NfdID] = f.NfdValue
+	}
+
+	return fleet, nil
+}
+
+// filterNodesByNfd returns the nodes whose NFD features satisfy all terms.
+func filterNodesByNfd(
+	ctx context.Context,
+	ps cce.PersistenceService,
+	nodes []cce.Persistable,
+	terms []nfdTerm,
+) ([]cce.Persistable, error) {
+	if len(terms) == 0 {
+		return nodes, nil
+	}
+
+	fleet, err := getFleetNfdFeatures(ctx, ps)
+	if err != nil {
+		return nil, err
+	}
+
+	var matching []cce.Persistable
+	for _, node := range nodes {
+		features := fleet[node.GetID()]
+		matches := true
+		for _, term := range terms {
+			if !term.matches(features) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			matching = append(matching, node)
+		}
+	}
+
+	return matching, nil
+}
+
+// aggregateNfdFeatures counts the nodes reporting each NFD feature and each of
+// its values. Features and values are sorted to keep the response stable.
+func aggregateNfdFeatures(fleet map[string]map[string]string) swagger.NfdFeatureList {
+	nodes := make(map[string]int)
+	values := make(map[string]map[string]int)
+	for _, features := range fleet {
+		for id, value := range features {
+			nodes[id]++
+			if values[id] == nil {
+				values[id] = make(map[string]int)
+			}
+			values[id][value]++
+		}
+	}
+
+	list := swagger.NfdFeatureList{Features: []swagger.NfdFeature{}}
+	for id, count := range nodes {
+		feature := swagger.NfdFeature{ID: id, Nodes: count}
+		for value, count := range values[id] {
+			feature.Values = append(feature.Values, swagger.NfdFeatureValue{
+				Value: value,
+				Nodes: count,
+			})
+		}
+		sort.Slice(feature.Values, func(i, j int) bool {
+			return feature.Values[i].Value < feature.Values[j].Value
+		})
+		list.Features = append(list.Features, feature)
+	}
+	sort.Slice(list.Features, func(i, j int) bool {
+		return list.Features[i].ID < list.Features[j].ID
+	})
+
+	return list
+}
@@ -0,0 +1,860 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package gorilla
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	cce "github.com/open-ness/edgecontroller"
+	"github.com/open-ness/edgecontroller/swagger"
+	"github.com/open-ness/edgecontroller/uuid"
+	"github.com/pkg/errors"
+)
+
+// operationFunc executes a single attempt of an operation.
+type operationFunc func(context.Context, cce.PersistenceService, *cce.Operation) error
+
+var operationFuncs = map[string]operationFunc{
+	cce.OperationTypeDeploy:                runDeployOperation,
+	cce.OperationTypeUndeploy:              runUndeployOperation,
+	cce.OperationTypeStart:                 runLifecycleOperation,
+	cce.OperationTypeStop:                  runLifecycleOperation,
+	cce.OperationTypeRestart:               runLifecycleOperation,
+	cce.OperationTypeSetAppPolicy:          runSetAppPolicyOperation,
+	cce.OperationTypeDeleteAppPolicy:       runDeleteAppPolicyOperation,
+	cce.OperationTypeSetInterfacePolicy:    runInterfacePolicyOperation,
+	cce.OperationTypeDeleteInterfacePolicy: runInterfacePolicyOperation,
+	cce.OperationTypeSetDNS:                runSetDNSOperation,
+	cce.OperationTypeDeleteDNS:             runDeleteDNSOperation,
+	cce.OperationTypeDecommission:          runDecommissionOperation,
+	cce.OperationTypeUpgrade:               runUpgradeOperation,
+	cce.OperationTypeDeployBundle:          runDeployBundleOperation,
+	cce.OperationTypeUndeployBundle:        runUndeployBundleOperation,
+	cce.OperationTypeStartBundle:           runBundleLifecycleOperation,
+	cce.OperationTypeStopBundle:            runBundleLifecycleOperation,
+	cce.OperationTypeRestartBundle:         runBundleLifecycleOperation,
+}
+
+// interfacePolicyPayload is the payload of interface policy operations.
+type interfacePolicyPayload struct {
+	InterfaceID string `json:"interface_id"`
+	PolicyID    string `json:"policy_id,omitempty"`
+}
+
+func toInterfacePolicyPayload(interfaceID, policyID string) json.RawMessage {
+	payload, _ := json.Marshal(interfacePolicyPayload{
+		InterfaceID: interfaceID,
+		PolicyID:    policyID,
+	})
+	return payload
+}
+
+// operationPool executes persisted operations on a fixed number of workers.
+// Operations for offline nodes are queued per node and replayed in order,
+// outside of the workers, once the node is seen again. App upgrades are
+// rolled out by submitting operations for one batch of nodes at a time.
+type operationPool struct {
+	controller *cce.Controller
+	queue      chan string
+	upgrades   chan string
+
+	mu        sync.Mutex
+	replaying map[string]bool
+	// node apps whose operations are being submitted, or that are being
+	// changed outside of an operation, see lockNodeApp
+	submitting map[string]bool
+	submitted  *sync.Cond
+}
+
+func newOperationPool(controller *cce.Controller) *operationPool {
+	p := &operationPool{
+		controller: controller,
+		queue:      make(chan string, cce.OperationWorkers),
+		upgrades:   make(chan string),
+		replaying:  make(map[string]bool),
+		submitting: make(map[string]bool),
+	}
+	p.submitted = sync.NewCond(&p.mu)
+	return p
+}
+
+// submit persists a new operation and queues it for execution. If the node
+// has queued operations, the new operation is queued behind them. A
+// disruptive operation is deferred while the maintenance windows of the node
+// are closed, and so are the operations requested behind it.
+func (p *operationPool) submit(ctx context.Context, op *cce.Operation) error {
+	return p.submitOp(ctx, op, true)
+}
+
+// submitNodeApp submits an operation of a node app unless another operation
+// of the node app has not finished yet. The check and the submission are made
+// under a lock of the node app, so of concurrent requests only one is
+// submitted. An operation that is not windowed, e.g. one issued by a schedule
+// that sets its time, is not deferred to a maintenance window of the node.
+func (p *operationPool) submitNodeApp(
+	ctx context.Context,
+	op *cce.Operation,
+	windowed bool,
+) (statusCode int, err error) {
+	return p.lockNodeApp(ctx, op.NodeID, op.AppID, func() error {
+		return p.submitOp(ctx, op, windowed)
+	})
+}
+
+// lockNodeApp runs f under the lock of a node app unless an operation of the
+// node app has not finished yet, in which case it returns 422. No operation
+// of the node app is submitted until f returns, so f can change the node app
+// without racing its operations, e.g. to redeploy it.
+func (p *operationPool) lockNodeApp(
+	ctx context.Context,
+	nodeID string,
+	appID string,
+	f func() error,
+) (statusCode int, err error) {
+	key := nodeID + "/" + appID
+	p.mu.Lock()
+	for p.submitting[key] {
+		p.submitted.Wait()
+	}
+	p.submitting[key] = true
+	p.mu.Unlock()
+	defer func() {
+		p.mu.Lock()
+		delete(p.submitting, key)
+		p.mu.Unlock()
+		p.submitted.Broadcast()
+	}()
+
+	if statusCode, err = checkPendingOperations(ctx, p.controller.PersistenceService, nodeID, appID); err != nil {
+		return statusCode, err
+	}
+	if err = f(); err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	return 0, nil
+}
+
+func (p *operationPool) submitOp(ctx context.Context, op *cce.Operation, windowed bool) error {
+	if windowed {
+		deferred, err := p.deferBehind(ctx, op)
+		if err != nil || deferred {
+			return err
+		}
+	}
+	queued, err := p.queueBehind(ctx, op)
+	if err != nil || queued {
+		return err
+	}
+
+	op.ID = uuid.New()
+	op.CreatedAt = time.Now().UTC()
+	op.Transition(cce.OperationStatePending, "operation accepted")
+
+	if err := op.Validate(); err != nil {
+		return errors.Wrap(err, "invalid operation")
+	}
+	if err := p.controller.PersistenceService.Create(ctx, op); err != nil {
+		return errors.Wrap(err, "error persisting operation")
+	}
+
+	p.enqueue(op.ID)
+
+	return nil
+}
+
+// enqueue hands an operation to the workers without blocking the caller.
+func (p *operationPool) enqueue(id string) {
+	go func() { p.queue <- id }()
+}
+
+// run resumes any operations and upgrades interrupted by a controller restart
+// and then executes queued operations, rolls out upgrades, issues the commands
+// of schedules and executes deferred operations in maintenance windows until
+// the context is canceled.
+func (p *operationPool) run(ctx context.Context) {
+	ctx = context.WithValue(ctx, contextKey("controller"), p.controller)
+
+	for _, state := range []string{cce.OperationStateRunning, cce.OperationStatePending} {
+		ops, err := p.controller.PersistenceService.Filter(
+			ctx,
+			&cce.Operation{},
+			[]cce.Filter{
+				{
+					Field: "state",
+					Value: state,
+				},
+			})
+		if err != nil {
+			log.Errf("Error loading %s operations: %v", state, err)
+			continue
+		}
+		for _, op := range ops {
+			if state == cce.OperationStateRunning {
+				op.(*cce.Operation).Transition(cce.OperationStatePending, "resumed after controller restart")
+				if err := p.update(ctx, op.(*cce.Operation)); err != nil {
+					log.Errf("Error resuming operation %s: %v", op.GetID(), err)
+					continue
+				}
+			}
+			p.enqueue(op.GetID())
+		}
+	}
+
+	p.resumeUpgrades(ctx)
+	go p.runSchedules(ctx)
+	go p.runMaintenance(ctx)
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case id := <-p.upgrades:
+				go p.rollOut(ctx, id)
+			}
+		}
+	}()
+
+	for i := 0; i < cce.OperationWorkers; i++ {
+		go func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case id := <-p.queue:
+					p.execute(ctx, id)
+				}
+			}
+		}()
+	}
+
+	<-ctx.Done()
+}
+
+// execute runs an operation, retrying failed attempts unless retrying cannot
+// succeed, and records each state transition. The state the operation was
+// left in is returned.
+func (p *operationPool) execute(ctx context.Context, id string) string { //nolint:gocyclo
+	ps := p.controller.PersistenceService
+
+	e, err := ps.Read(ctx, id, &cce.Operation{})
+	if err != nil {
+		log.Errf("Error loading operation %s: %v", id, err)
+		return ""
+	}
+	if e == nil {
+		log.Errf("Operation %s not found", id)
+		return ""
+	}
+	op := e.(*cce.Operation)
+	if op.State != cce.OperationStatePending {
+		return op.State
+	}
+
+	run, ok := operationFuncs[op.Type]
+	if !ok {
+		op.Error = fmt.Sprintf("unsupported operation type %s", op.Type)
+		op.Transition(cce.OperationStateFailed, op.Error)
+		if err = p.update(ctx, op); err != nil {
+			log.Errf("Error updating operation %s: %v", op.ID, err)
+		}
+		return op.State
+	}
+
+	for op.Attempts < cce.MaxOperationAttempts {
+		op.Attempts++
+		op.Transition(cce.OperationStateRunning,
+			fmt.Sprintf("attempt %d of %d", op.Attempts, cce.MaxOperationAttempts))
+		if err = p.update(ctx, op); err != nil {
+			log.Errf("Error updating operation %s: %v", op.ID, err)
+			return op.State
+		}
+
+		opCtx, cancel := context.WithTimeout(ctx, cce.MaxOperationTime)
+		err = run(opCtx, ps, op)
+		cancel()
+		if err == nil {
+			op.Error = ""
+			op.Transition(cce.OperationStateSucceeded, "operation completed")
+			if err = p.update(ctx, op); err != nil {
+				log.Errf("Error updating operation %s: %v", op.ID, err)
+			}
+			log.Infof("Operation %s (%s) completed", op.ID, op.Type)
+			return op.State
+		}
+
+		log.Errf("Operation %s (%s) attempt %d failed: %v", op.ID, op.Type, op.Attempts, err)
+		op.Error = err.Error()
+		if p.controller.QueueOfflineOperations && isNodeUnreachable(err) {
+			// attempts are counted anew once the node is back
+			op.Attempts = 0
+			op.Transition(cce.OperationStateQueued, fmt.Sprintf("node is unreachable: %v", err))
+			if err = p.update(ctx, op); err != nil {
+				log.Errf("Error updating operation %s: %v", op.ID, err)
+			}
+			return op.State
+		}
+		if op.Attempts == cce.MaxOperationAttempts || isPermanent(err) {
+			break
+		}
+
+		op.Transition(cce.OperationStatePending, fmt.Sprintf("retrying after error: %v", err))
+		if err = p.update(ctx, op); err != nil {
+			log.Errf("Error updating operation %s: %v", op.ID, err)
+			return op.State
+		}
+
+		select {
+		case <-ctx.Done():
+			return op.State
+		case <-time.After(time.Duration(op.Attempts) * cce.OperationRetryInterval):
+		}
+	}
+
+	op.Transition(cce.OperationStateFailed, op.Error)
+	if err = p.update(ctx, op); err != nil {
+		log.Errf("Error updating operation %s: %v", op.ID, err)
+	}
+	if err = abandonTemplateApp(ctx, ps, op); err != nil {
+		log.Errf("Error deleting app of operation %s: %v", op.ID, err)
+	}
+
+	return op.State
+}
+
+func (p *operationPool) update(ctx context.Context, op *cce.Operation) error {
+	return p.controller.PersistenceService.BulkUpdate(ctx, []cce.Persistable{op})
+}
+
+// reportProgress records the step a running operation has reached.
+func reportProgress(ctx context.Context, ps cce.PersistenceService, op *cce.Operation, message string) error {
+	op.Transition(cce.OperationStateRunning, message)
+	if err := ps.BulkUpdate(ctx, []cce.Persistable{op}); err != nil {
+		return errors.Wrap(err, "error updating operation")
+	}
+	return nil
+}
+
+// queueBehind queues the operation if queueing for offline nodes is enabled
+// and the node already has queued operations, so that operations reach the
+// node in the order they were requested.
+func (p *operationPool) queueBehind(ctx context.Context, op *cce.Operation) (queued bool, err error) {
+	if !p.controller.QueueOfflineOperations {
+		return false, nil
+	}
+
+	ops, err := p.queuedOperations(ctx, op.NodeID)
+	if err != nil {
+		return false, err
+	}
+	if len(ops) == 0 {
+		return false, nil
+	}
+
+	return true, p.persistWaiting(ctx, op, cce.OperationStateQueued, "node has queued operations")
+}
+
+// queueOnUnreachable queues the operation if queueing for offline nodes is
+// enabled and cause reports that the node is unreachable.
+func (p *operationPool) queueOnUnreachable(
+	ctx context.Context,
+	op *cce.Operation,
+	cause error,
+) (queued bool, err error) {
+	if !p.controller.QueueOfflineOperations || !isNodeUnreachable(cause) {
+		return false, nil
+	}
+
+	return true, p.persistWaiting(ctx, op, cce.OperationStateQueued, fmt.Sprintf("node is unreachable: %v", cause))
+}
+
+// persistWaiting persists a new operation that waits in the queued or
+// deferred state.
+func (p *operationPool) persistWaiting(ctx context.Context, op *cce.Operation, state, message string) error {
+	op.ID = uuid.New()
+	op.CreatedAt = time.Now().UTC()
+	op.Transition(state, message)
+
+	if err := op.Validate(); err != nil {
+		return errors.Wrap(err, "invalid operation")
+	}
+	if err := p.controller.PersistenceService.Create(ctx, op); err != nil {
+		return errors.Wrap(err, "error persisting operation")
+	}
+	log.Infof("Operation %s (%s) %s for node %s", op.ID, op.Type, state, op.NodeID)
+
+	return nil
+}
+
+// queuedOperations returns the queued operations of a node in the order they
+// were requested.
+func (p *operationPool) queuedOperations(ctx context.Context, nodeID string) ([]*cce.Operation, error) {
+	return p.nodeOperations(ctx, nodeID, cce.OperationStateQueued)
+}
+
+// deferredOperations returns the deferred operations of a node in the order
+// they were requested.
+func (p *operationPool) deferredOperations(ctx context.Context, nodeID string) ([]*cce.Operation, error) {
+	return p.nodeOperations(ctx, nodeID, cce.OperationStateDeferred)
+}
+
+// nodeOperations returns the operations of a node in a state in the order
+// they were requested.
+func (p *operationPool) nodeOperations(ctx context.Context, nodeID, state string) ([]*cce.Operation, error) {
+	es, err := p.controller.PersistenceService.Filter(
+		ctx,
+		&cce.Operation{},
+		[]cce.Filter{
+			{
+				Field: "node_id",
+				Value: nodeID,
+			},
+			{
+				Field: "state",
+				Value: state,
+			},
+		})
+	if err != nil {
+		return nil, errors.Wrap(err, "error filtering operations")
+	}
+
+	ops := make([]*cce.Operation, 0, len(es))
+	for _, e := range es {
+		ops = append(ops, e.(*cce.Operation))
+	}
+	sort.SliceStable(ops, func(i, j int) bool {
+		return ops[i].CreatedAt.Before(ops[j].CreatedAt)
+	})
+
+	return ops, nil
+}
+
+// replay executes the queued operations of a node one at a time in the
+// order they were requested. Replay stops if the node becomes unreachable
+// again so that the remaining operations keep their order. Operations that
+// would be deferred if they were submitted now, e.g. disruptive ones while
+// the maintenance windows of the node are closed, are deferred instead.
+func (p *operationPool) replay(ctx context.Context, nodeID string) {
+	if !p.controller.QueueOfflineOperations {
+		return
+	}
+
+	if !p.lockNode(nodeID) {
+		return
+	}
+	defer p.unlockNode(nodeID)
+
+	ctx = context.WithValue(ctx, contextKey("controller"), p.controller)
+
+	ops, err := p.queuedOperations(ctx, nodeID)
+	if err != nil {
+		log.Errf("Error loading queued operations of node %s: %v", nodeID, err)
+		return
+	}
+	if len(ops) == 0 {
+		return
+	}
+	log.Infof("Replaying %d queued operation(s) for node %s", len(ops), nodeID)
+
+	for _, op := range ops {
+		message, deferred, err := p.deferral(ctx, op)
+		if err != nil {
+			log.Errf("Error checking deferral of operation %s: %v", op.ID, err)
+			return
+		}
+		if deferred {
+			// the operations behind it are deferred too, keeping their order
+			op.Transition(cce.OperationStateDeferred, message)
+			if err = p.update(ctx, op); err != nil {
+				log.Errf("Error updating operation %s: %v", op.ID, err)
+				return
+			}
+			continue
+		}
+
+		op.Transition(cce.OperationStatePending, "node is reachable, replaying queued operation")
+		if err = p.update(ctx, op); err != nil {
+			log.Errf("Error updating operation %s: %v", op.ID, err)
+			return
+		}
+		if p.execute(ctx, op.ID) == cce.OperationStateQueued {
+			return
+		}
+	}
+}
+
+// lockNode marks the waiting operations of a node as being replayed. It
+// returns false if they are replayed already.
+func (p *operationPool) lockNode(nodeID string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.replaying[nodeID] {
+		return false
+	}
+	p.replaying[nodeID] = true
+	return true
+}
+
+func (p *operationPool) unlockNode(nodeID string) {
+	p.mu.Lock()
+	delete(p.replaying, nodeID)
+	p.mu.Unlock()
+}
+
+// nodeConnected replays the queued operations of the node with the given
+// gRPC target address.
+func (p *operationPool) nodeConnected(ctx context.Context, addr string) {
+	if !p.controller.QueueOfflineOperations {
+		return
+	}
+
+	targets, err := p.controller.PersistenceService.Filter(
+		ctx,
+		&cce.NodeGRPCTarget{},
+		[]cce.Filter{
+			{
+				Field: "grpc_target",
+				Value: addr,
+			},
+		})
+	if err != nil {
+		log.Errf("Error filtering node_grpc_targets: %v", err)
+		return
+	}
+
+	for _, target := range targets {
+		p.replay(ctx, target.(*cce.NodeGRPCTarget).NodeID)
+	}
+}
+
+// cancel cancels a queued or deferred operation.
+func (p *operationPool) cancel(ctx context.Context, op *cce.Operation) (statusCode int, err error) {
+	if op.State != cce.OperationStateQueued && op.State != cce.OperationStateDeferred {
+		return http.StatusUnprocessableEntity, fmt.Errorf(
+			"cannot cancel operation %s: operation is %s", op.ID, op.State)
+	}
+
+	op.Transition(cce.OperationStateCanceled, "canceled by request")
+	if err = p.update(ctx, op); err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if err = abandonTemplateApp(ctx, p.controller.PersistenceService, op); err != nil {
+		log.Errf("Error deleting app of operation %s: %v", op.ID, err)
+	}
+
+	return 0, nil
+}
+
+func runDeployOperation(ctx context.Context, ps cce.PersistenceService, op *cce.Operation) error {
+	nodeApps, err := ps.Filter(
+		ctx,
+		&cce.NodeApp{},
+		[]cce.Filter{
+			{
+				Field: "node_id",
+				Value: op.NodeID,
+			},
+			{
+				Field: "app_id",
+				Value: op.AppID,
+			},
+		})
+	if err != nil {
+		return errors.Wrap(err, "error filtering nodes_apps")
+	}
+	// a previous attempt may have deployed and persisted the app already
+	if len(nodeApps) != 0 {
+		return nil
+	}
+
+	nodeApp := &cce.NodeApp{
+		ID:     uuid.New(),
+		NodeID: op.NodeID,
+		AppID:  op.AppID,
+	}
+	if err = setNodeAppTemplate(nodeApp, op); err != nil {
+		return err
+	}
+	if err = handleCreateNodesApps(ctx, ps, nodeApp); err != nil {
+		return err
+	}
+
+	return ps.Create(ctx, nodeApp)
+}
+
+func runUndeployOperation(ctx context.Context, ps cce.PersistenceService, op *cce.Operation) error {
+	nodeApps, err := ps.Filter(
+		ctx,
+		&cce.NodeApp{},
+		[]cce.Filter{
+			{
+				Field: "node_id",
+				Value: op.NodeID,
+			},
+			{
+				Field: "app_id",
+				Value: op.AppID,
+			},
+		})
+	if err != nil {
+		return errors.Wrap(err, "error filtering nodes_apps")
+	}
+	// a previous attempt may have undeployed and deleted the app already
+	if len(nodeApps) == 0 {
+		return nil
+	}
+
+	if err = handleDeleteNodesApps(ctx, ps, nodeApps[0]); err != nil {
+		return err
+	}
+
+	ok, err := ps.Delete(ctx, nodeApps[0].GetID(), &cce.NodeApp{})
+	if err != nil {
+		return errors.Wrap(err, "error deleting node app")
+	}
+	if !ok {
+		return fmt.Errorf("node app %s was not deleted", nodeApps[0].GetID())
+	}
+
+	return deleteTemplateApp(ctx, ps, nodeApps[0].(*cce.NodeApp))
+}
+
+func runLifecycleOperation(ctx context.Context, ps cce.PersistenceService, op *cce.Operation) error {
+	nodeApps, err := ps.Filter(
+		ctx,
+		&cce.NodeApp{},
+		[]cce.Filter{
+			{
+				Field: "node_id",
+				Value: op.NodeID,
+			},
+			{
+				Field: "app_id",
+				Value: op.AppID,
+			},
+		})
+	if err != nil {
+		return errors.Wrap(err, "error filtering nodes_apps")
+	}
+	if len(nodeApps) != 1 {
+		return permanent(fmt.Errorf("app %s is not deployed to node %s", op.AppID, op.NodeID))
+	}
+
+	_, err = handleUpdateNodesApps(ctx, ps, &cce.NodeAppReq{
+		NodeApp: *nodeApps[0].(*cce.NodeApp),
+		Cmd:     op.Type,
+	})
+
+	return err
+}
+
+// findNodeApp returns the node app targeted by an operation.
+func findNodeApp(ctx context.Context, ps cce.PersistenceService, op *cce.Operation) (*cce.NodeApp, error) {
+	nodeApps, err := ps.Filter(
+		ctx,
+		&cce.NodeApp{},
+		[]cce.Filter{
+			{
+				Field: "node_id",
+				Value: op.NodeID,
+			},
+			{
+				Field: "app_id",
+				Value: op.AppID,
+			},
+		})
+	if err != nil {
+		return nil, errors.Wrap(err, "error filtering nodes_apps")
+	}
+	if len(nodeApps) != 1 {
+		return nil, permanent(fmt.Errorf("app %s is not deployed to node %s", op.AppID, op.NodeID))
+	}
+
+	return nodeApps[0].(*cce.NodeApp), nil
+}
+
+func runSetAppPolicyOperation(ctx context.Context, ps cce.PersistenceService, op *cce.Operation) error {
+	var baseResource swagger.BaseResource
+	if err := json.Unmarshal(op.Payload, &baseResource); err != nil {
+		return permanent(errors.Wrap(err, "error unmarshaling payload"))
+	}
+
+	nodeApp, err := findNodeApp(ctx, ps, op)
+	if err != nil {
+		return err
+	}
+
+	policy, err := ps.Read(ctx, baseResource.ID, &cce.TrafficPolicy{})
+	if err != nil {
+		return errors.Wrap(err, "error reading traffic_policies")
+	}
+	if policy == nil {
+		return permanent(fmt.Errorf("traffic policy %s not found", baseResource.ID))
+	}
+
+	return handleUpdateNodesAppsPolicy(ctx, ps, nodeApp, policy.(*cce.TrafficPolicy))
+}
+
+func runDeleteAppPolicyOperation(ctx context.Context, ps cce.PersistenceService, op *cce.Operation) error {
+	nodeApp, err := findNodeApp(ctx, ps, op)
+	if err != nil {
+		return err
+	}
+
+	return handleDeleteNodesAppsPolicy(ctx, ps, nodeApp)
+}
+
+func runInterfacePolicyOperation(ctx context.Context, ps cce.PersistenceService, op *cce.Operation) error {
+	var payload interfacePolicyPayload
+	if err := json.Unmarshal(op.Payload, &payload); err != nil {
+		return permanent(errors.Wrap(err, "error unmarshaling payload"))
+	}
+
+	_, err := handleUpdateNodesInterfacesPolicy(ctx, ps, op.NodeID, payload.InterfaceID, payload.PolicyID)
+
+	return err
+}
+
+func runSetDNSOperation(ctx context.Context, ps cce.PersistenceService, op *cce.Operation) error {
+	var requested swagger.DNSDetail
+	if err := json.Unmarshal(op.Payload, &requested); err != nil {
+		return permanent(errors.Wrap(err, "error unmarshaling payload"))
+	}
+
+	nodeDNS, newConfig, newAliases, _, err := toNodeDNSConfig(op.NodeID, &requested)
+	if err != nil {
+		return err
+	}
+
+	if err = handleDeleteNodesDNS(ctx, ps, op.NodeID); err != nil {
+		return err
+	}
+
+	return handleCreateNodesDNS(ctx, ps, nodeDNS, newConfig, newAliases)
+}
+
+func runDeleteDNSOperation(ctx context.Context, ps cce.PersistenceService, op *cce.Operation) error {
+	return handleDeleteNodesDNS(ctx, ps, op.NodeID)
+}
+
+// checkPendingOperations returns an error if an operation for the node app
+// has not finished yet.
+func checkPendingOperations(
+	ctx context.Context,
+	ps cce.PersistenceService,
+	nodeID string,
+	appID string,
+) (statusCode int, err error) {
+	for _, state := range []string{
+		cce.OperationStatePending, cce.OperationStateRunning, cce.OperationStateDeferred,
+	} {
+		var es []cce.Persistable
+
+		if es, err = ps.Filter(
+			ctx,
+			&cce.Operation{},
+			[]cce.Filter{
+				{
+					Field: "node_id",
+					Value: nodeID,
+				},
+				{
+					Field: "app_id",
+					Value: appID,
+				},
+				{
+					Field: "state",
+					Value: state,
+				},
+			},
+		); err != nil {
+			return http.StatusInternalServerError, err
+		}
+
+		if len(es) != 0 {
+			return http.StatusUnprocessableEntity, fmt.Errorf(
+				"operation %s is %s for node_id %s and app_id %s",
+				es[0].GetID(), state, nodeID, appID)
+		}
+	}
+
+	return 0, nil
+}
+
+func operationURL(id string) string {
+	return "/operations/" + id
+}
+
+func toOperationSummary(op *cce.Operation) swagger.OperationSummary {
+	return swagger.OperationSummary{
+		ID:     op.ID,
+		Type:   op.Type,
+		NodeID: op.NodeID,
+		AppID:  op.AppID,
+		State:  op.State,
+		URL:    operationURL(op.ID),
+	}
+}
+
+// writeQueuedBehind queues the operation behind the node's queued operations,
+// if any, and writes the response. It returns true if the response was
+// written.
+func (g *Gorilla) writeQueuedBehind(w http.ResponseWriter, r *http.Request, op *cce.Operation) bool {
+	queued, err := g.operations.queueBehind(r.Context(), op)
+	if err != nil {
+		log.Errf("Error queueing operation: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return true
+	}
+	if queued {
+		writeOperationAccepted(w, op)
+	}
+
+	return queued
+}
+
+// writeQueuedOnUnreachable queues the operation if cause reports that the
+// node is unreachable and writes the response. It returns true if the
+// response was written.
+func (g *Gorilla) writeQueuedOnUnreachable(
+	w http.ResponseWriter,
+	r *http.Request,
+	op *cce.Operation,
+	cause error,
+) bool {
+	queued, err := g.operations.queueOnUnreachable(r.Context(), op, cause)
+	if err != nil {
+		log.Errf("Error queueing operation: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return true
+	}
+	if queued {
+		writeOperationAccepted(w, op)
+	}
+
+	return queued
+}
+
+// writeOperationAccepted responds with 202 Accepted and the location of the
+// operation that will complete the request.
+func writeOperationAccepted(w http.ResponseWriter, op *cce.Operation) {
+	opJSON, err := json.Marshal(toOperationSummary(op))
+	if err != nil {
+		log.Errf("Error marshaling response: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", operationURL(op.ID))
+	w.WriteHeader(http.StatusAccepted)
+	if _, err = w.Write(opJSON); err != nil {
+		log.Errf("Error writing response: %v", err)
+	}
+}
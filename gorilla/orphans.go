@@ -0,0 +1,384 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package gorilla
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	cce "github.com/open-ness/edgecontroller"
+	"github.com/open-ness/edgecontroller/k8s"
+	"github.com/open-ness/edgecontroller/swagger"
+	"github.com/open-ness/edgecontroller/uuid"
+	"github.com/pkg/errors"
+)
+
+// Problems and actions of the items of an orphan report.
+const (
+	orphanProblemOrphaned = "orphaned"
+	orphanProblemMissing  = "missing"
+
+	orphanActionReported  = "reported"
+	orphanActionAdopted   = "adopted"
+	orphanActionDeleted   = "deleted"
+	orphanActionRecreated = "recreated"
+	orphanActionFailed    = "failed"
+)
+
+// orphanReconciler diffs the Kubernetes objects of apps against nodes_apps and
+// nodes_apps_traffic_policies, which get out of sync if the controller stops
+// between deploying an app and persisting it, or if a node app is deleted
+// while Kubernetes is unreachable. The differences are handled according to
+// the orphan policy of the controller and the last report is kept.
+type orphanReconciler struct {
+	controller *cce.Controller
+	operations *operationPool
+
+	mu     sync.Mutex
+	report *swagger.OrphanReport
+}
+
+func newOrphanReconciler(controller *cce.Controller, operations *operationPool) *orphanReconciler {
+	return &orphanReconciler{controller: controller, operations: operations}
+}
+
+// run reconciles at once and then every interval until the context is
+// canceled. A zero interval reconciles once.
+func (oc *orphanReconciler) run(ctx context.Context, interval time.Duration) {
+	ctx = context.WithValue(ctx, contextKey("controller"), oc.controller)
+
+	oc.reconcileAndLog(ctx)
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			oc.reconcileAndLog(ctx)
+		}
+	}
+}
+
+func (oc *orphanReconciler) reconcileAndLog(ctx context.Context) {
+	report, err := oc.reconcileAll(ctx)
+	if err != nil {
+		log.Errf("Error reconciling Kubernetes objects of apps: %v", err)
+		return
+	}
+	if len(report.Items) != 0 {
+		log.Noticef("Found %d orphaned or missing Kubernetes object(s) of apps", len(report.Items))
+	}
+}
+
+// reconcileAll reconciles the objects of all apps and keeps the report.
+func (oc *orphanReconciler) reconcileAll(ctx context.Context) (*swagger.OrphanReport, error) {
+	report, err := oc.reconcile(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	oc.mu.Lock()
+	oc.report = report
+	oc.mu.Unlock()
+	return report, nil
+}
+
+// lastReport returns the report of the last reconciliation, or nil if none
+// completed yet.
+func (oc *orphanReconciler) lastReport() *swagger.OrphanReport {
+	oc.mu.Lock()
+	defer oc.mu.Unlock()
+	return oc.report
+}
+
+// nodeAppKey identifies an app on a node.
+type nodeAppKey struct {
+	nodeID string
+	appID  string
+}
+
+func (oc *orphanReconciler) reconcile(ctx context.Context) (*swagger.OrphanReport, error) { //nolint:gocyclo
+	ctrl := getController(ctx)
+	ps := ctrl.PersistenceService
+
+	policy := ctrl.OrphanPolicy
+	if policy == "" {
+		policy = cce.OrphanPolicyReport
+	}
+	report := &swagger.OrphanReport{
+		CheckedAt: time.Now().UTC(),
+		Policy:    policy,
+		Items:     []swagger.OrphanItem{},
+	}
+
+	// the objects and node apps are not read at once: objects created
+	// meanwhile are left alone for the grace period, and so are node apps
+	// with an operation in flight or finished within the grace period
+	objects, err := ctrl.KubernetesClient.ListAppObjects(ctx)
+	if err != nil {
+		return nil, err
+	}
+	nodeApps, err := ps.ReadAll(ctx, &cce.NodeApp{})
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading nodes_apps")
+	}
+	nodeAppPolicies, err := ps.ReadAll(ctx, &cce.NodeAppTrafficPolicy{})
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading nodes_apps_traffic_policies")
+	}
+
+	persisted := make(map[nodeAppKey]*cce.NodeApp)
+	for _, e := range nodeApps {
+		nodeApp := e.(*cce.NodeApp)
+		persisted[nodeAppKey{nodeApp.NodeID, nodeApp.AppID}] = nodeApp
+	}
+	policyIDs := make(map[string]string)
+	for _, e := range nodeAppPolicies {
+		policyIDs[e.(*cce.NodeAppTrafficPolicy).NodeAppID] = e.(*cce.NodeAppTrafficPolicy).TrafficPolicyID
+	}
+
+	workloads := make(map[nodeAppKey]bool)
+	networkPolicies := make(map[nodeAppKey]bool)
+	for _, object := range objects {
+		key := nodeAppKey{object.NodeID, object.AppID}
+		if object.Kind == k8s.KindNetworkPolicy {
+			networkPolicies[key] = true
+		} else {
+			workloads[key] = true
+		}
+
+		// the node app of a new object may not be persisted yet
+		if time.Since(object.Created) < cce.OrphanGracePeriod {
+			continue
+		}
+		nodeApp := persisted[key]
+		switch {
+		case nodeApp == nil:
+		case object.Kind == k8s.KindNetworkPolicy && policyIDs[nodeApp.ID] == "":
+		default:
+			continue
+		}
+
+		item := orphanItem(object, orphanProblemOrphaned)
+		handleOrphan(ctx, ps, policy, object, &item)
+		report.Items = append(report.Items, item)
+	}
+
+	for key, nodeApp := range persisted {
+		settling, err := nodeAppSettling(ctx, ps, nodeApp)
+		if err != nil {
+			return nil, err
+		}
+		if settling {
+			continue
+		}
+
+		if !workloads[key] {
+			kind := k8s.KindDeployment
+			if app, err := ps.Read(ctx, nodeApp.RunningAppID(), &cce.App{}); err == nil && app != nil &&
+				app.(*cce.App).Type == "vm" {
+				kind = k8s.KindVirtualMachine
+			}
+			item := swagger.OrphanItem{
+				Kind:    kind,
+				Tenant:  nodeApp.Tenant,
+				NodeID:  nodeApp.NodeID,
+				AppID:   nodeApp.AppID,
+				Problem: orphanProblemMissing,
+				Action:  orphanActionReported,
+			}
+			// the app is recreated under the lock of its operations, unless
+			// an operation was submitted since it was found settled
+			if policy != cce.OrphanPolicyReport {
+				code, err := oc.operations.lockNodeApp(ctx, nodeApp.NodeID, nodeApp.AppID, func() error {
+					return handleCreateNodesApps(ctx, ps, nodeApp)
+				})
+				if code == http.StatusUnprocessableEntity {
+					continue
+				}
+				recreate(&item, err)
+			}
+			report.Items = append(report.Items, item)
+		}
+
+		policyID := policyIDs[nodeApp.ID]
+		if ctrl.OrchestrationMode == cce.OrchestrationModeKubernetesOVN && policyID != "" && !networkPolicies[key] {
+			item := swagger.OrphanItem{
+				Kind:    k8s.KindNetworkPolicy,
+				Tenant:  nodeApp.Tenant,
+				NodeID:  nodeApp.NodeID,
+				AppID:   nodeApp.AppID,
+				Problem: orphanProblemMissing,
+				Action:  orphanActionReported,
+			}
+			if policy != cce.OrphanPolicyReport {
+				recreate(&item, reconcileNodeAppKubeOVNPolicy(ctx, ps, nodeApp, policyID))
+			}
+			report.Items = append(report.Items, item)
+		}
+	}
+
+	return report, nil
+}
+
+// nodeAppSettling returns true if the objects of a node app may be out of sync
+// with it only for the moment: an operation on the app, or on a bundle it is a
+// member of, is in flight, or an operation on the app finished within the
+// grace period, e.g. after persisting the node app between the objects and the
+// node apps being read.
+func nodeAppSettling(ctx context.Context, ps cce.PersistenceService, nodeApp *cce.NodeApp) (bool, error) {
+	es, err := ps.Filter(
+		ctx,
+		&cce.Operation{},
+		[]cce.Filter{
+			{
+				Field: "node_id",
+				Value: nodeApp.NodeID,
+			},
+			{
+				Field: "app_id",
+				Value: nodeApp.AppID,
+			},
+		})
+	if err != nil {
+		return false, errors.Wrap(err, "error filtering operations")
+	}
+	for _, e := range es {
+		op := e.(*cce.Operation)
+		if op.State == cce.OperationStatePending || op.State == cce.OperationStateRunning ||
+			time.Since(op.UpdatedAt) < cce.OrphanGracePeriod {
+			return true, nil
+		}
+	}
+
+	for _, state := range []string{cce.OperationStatePending, cce.OperationStateRunning} {
+		es, err = ps.Filter(
+			ctx,
+			&cce.Operation{},
+			[]cce.Filter{
+				{
+					Field: "node_id",
+					Value: nodeApp.NodeID,
+				},
+				{
+					Field: "state",
+					Value: state,
+				},
+			})
+		if err != nil {
+			return false, errors.Wrap(err, "error filtering operations")
+		}
+		for _, e := range es {
+			op := e.(*cce.Operation)
+			if !isBundleOperation(op) {
+				continue
+			}
+			bundle, _, err := operationBundle(ctx, ps, op)
+			if err != nil || bundle.HasMember(nodeApp.AppID) {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+func orphanItem(object k8s.AppObject, problem string) swagger.OrphanItem {
+	return swagger.OrphanItem{
+		Kind:      object.Kind,
+		Tenant:    object.Tenant,
+		Namespace: object.Namespace,
+		Name:      object.Name,
+		NodeID:    object.NodeID,
+		AppID:     object.AppID,
+		Problem:   problem,
+		Action:    orphanActionReported,
+	}
+}
+
+// handleOrphan adopts or deletes an orphaned object according to the policy.
+// Only the Deployment or VirtualMachine of an app whose app and node are
+// persisted can be adopted: a NetworkPolicy does not tell which traffic
+// policy it was applied from.
+func handleOrphan(
+	ctx context.Context,
+	ps cce.PersistenceService,
+	policy string,
+	object k8s.AppObject,
+	item *swagger.OrphanItem,
+) {
+	ctrl := getController(ctx)
+
+	var err error
+	switch {
+	case policy == cce.OrphanPolicyReport:
+		return
+	case policy == cce.OrphanPolicyGC && object.Kind == k8s.KindNetworkPolicy:
+		log.Noticef("Deleting orphaned network policy %s/%s", object.Namespace, object.Name)
+		err = ctrl.KubernetesClient.DeleteNetworkPolicy(ctx, object.Tenant, object.NodeID, object.AppID)
+		item.Action = orphanActionDeleted
+	case policy == cce.OrphanPolicyGC:
+		log.Noticef("Deleting orphaned app %s of node %s", object.AppID, object.NodeID)
+		err = ctrl.KubernetesClient.Undeploy(ctx, object.Tenant, object.NodeID, object.AppID)
+		item.Action = orphanActionDeleted
+	case object.Kind == k8s.KindNetworkPolicy:
+		item.Error = "network policies cannot be adopted without their traffic policy"
+		return
+	default:
+		err = adoptOrphan(ctx, ps, object)
+		item.Action = orphanActionAdopted
+	}
+	if err != nil {
+		item.Action = orphanActionFailed
+		item.Error = err.Error()
+	}
+}
+
+// adoptOrphan persists the node app of an orphaned Deployment or
+// VirtualMachine.
+func adoptOrphan(ctx context.Context, ps cce.PersistenceService, object k8s.AppObject) error {
+	app, err := ps.Read(ctx, object.AppID, &cce.App{})
+	if err != nil {
+		return errors.Wrap(err, "error reading apps")
+	}
+	if app == nil {
+		return errors.Errorf("app %s not found", object.AppID)
+	}
+	node, err := ps.Read(ctx, object.NodeID, &cce.Node{})
+	if err != nil {
+		return errors.Wrap(err, "error reading nodes")
+	}
+	if node == nil {
+		return errors.Errorf("node %s not found", object.NodeID)
+	}
+
+	log.Noticef("Adopting orphaned app %s of node %s", object.AppID, object.NodeID)
+	nodeApp := &cce.NodeApp{
+		ID:     uuid.New(),
+		NodeID: object.NodeID,
+		AppID:  object.AppID,
+		Tenant: object.Tenant,
+	}
+	if err = nodeApp.Validate(); err != nil {
+		return err
+	}
+	return ps.Create(ctx, nodeApp)
+}
+
+// recreate records the result of recreating a missing object.
+func recreate(item *swagger.OrphanItem, err error) {
+	item.Action = orphanActionRecreated
+	if err != nil {
+		item.Action = orphanActionFailed
+		item.Error = err.Error()
+	}
+}
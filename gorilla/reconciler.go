@@ -0,0 +1,670 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package gorilla
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"time"
+
+	cce "github.com/open-ness/edgecontroller"
+	"github.com/open-ness/edgecontroller/grpc/node"
+	"github.com/open-ness/edgecontroller/uuid"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// reconciler periodically compares the desired state of every node in
+// persistence with the state reported by the node, re-applies what is
+// missing and records the drift it could not resolve.
+type reconciler struct {
+	controller *cce.Controller
+	operations *operationPool
+}
+
+func newReconciler(controller *cce.Controller, operations *operationPool) *reconciler {
+	return &reconciler{
+		controller: controller,
+		operations: operations,
+	}
+}
+
+// run reconciles all nodes every interval until the context is canceled.
+func (rc *reconciler) run(ctx context.Context, interval time.Duration) {
+	ctx = context.WithValue(ctx, contextKey("controller"), rc.controller)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			rc.reconcileAll(ctx)
+		}
+	}
+}
+
+func (rc *reconciler) reconcileAll(ctx context.Context) {
+	nodes, err := rc.controller.PersistenceService.ReadAll(ctx, &cce.Node{})
+	if err != nil {
+		log.Errf("Error loading nodes for reconciliation: %v", err)
+		return
+	}
+
+	for _, n := range nodes {
+		if ctx.Err() != nil {
+			return
+		}
+		if _, err := rc.reconcile(ctx, n.(*cce.Node)); err != nil {
+			log.Errf("Error reconciling node %s: %v", n.GetID(), err)
+		}
+	}
+}
+
+// reconcile brings a single node in line with persistence and records the
+// drift that remains. Nodes that never connected to the controller or that
+// are being decommissioned are skipped and nil is returned.
+func (rc *reconciler) reconcile(ctx context.Context, n *cce.Node) (*cce.NodeDrift, error) {
+	ctx, cancel := context.WithTimeout(ctx, cce.MaxReconcileNodeTime)
+	defer cancel()
+
+	ps := rc.controller.PersistenceService
+
+	targets, err := ps.Filter(
+		ctx,
+		&cce.NodeGRPCTarget{},
+		[]cce.Filter{
+			{
+				Field: "node_id",
+				Value: n.ID,
+			},
+		})
+	if err != nil {
+		return nil, errors.Wrap(err, "error filtering node_grpc_targets")
+	}
+	if len(targets) == 0 {
+		return nil, nil
+	}
+
+	// Leave nodes that are being decommissioned alone
+	decommissioning, err := isDecommissioning(ctx, ps, n.ID)
+	if err != nil {
+		return nil, err
+	}
+	if decommissioning {
+		return nil, nil
+	}
+
+	// Deliver the operations queued while the node was offline before
+	// comparing its state
+	rc.operations.replay(ctx, n.ID)
+
+	var items []*cce.DriftItem
+
+	appItems, err := reconcileNodeApps(ctx, ps, rc.operations, n)
+	if err != nil {
+		return nil, err
+	}
+	items = append(items, appItems...)
+
+	elaItems, err := reconcileNodeELA(ctx, ps, n)
+	if err != nil {
+		return nil, err
+	}
+	items = append(items, elaItems...)
+
+	if len(items) != 0 {
+		log.Noticef("Node %s has %d unresolved drift item(s)", n.ID, len(items))
+	}
+
+	return saveNodeDrift(ctx, ps, n.ID, items)
+}
+
+// reconcileNodeApps redeploys apps that are missing from the node. An app is
+// redeployed under the lock of its operations, so that it is not redeployed
+// while an operation submitted meanwhile, e.g. to undeploy it, runs.
+func reconcileNodeApps(
+	ctx context.Context,
+	ps cce.PersistenceService,
+	operations *operationPool,
+	n *cce.Node,
+) ([]*cce.DriftItem, error) {
+	nodeApps, err := ps.Filter(
+		ctx,
+		&cce.NodeApp{},
+		[]cce.Filter{
+			{
+				Field: "node_id",
+				Value: n.ID,
+			},
+		})
+	if err != nil {
+		return nil, errors.Wrap(err, "error filtering nodes_apps")
+	}
+	if len(nodeApps) == 0 {
+		return nil, nil
+	}
+
+	ctrl := getController(ctx)
+	nodePort := ctrl.EVAPort
+	if nodePort == "" {
+		nodePort = defaultEVAPort
+	}
+	nodeCC, err := connectNode(ctx, ps, n, nodePort, ctrl.EdgeNodeCreds)
+	if err != nil {
+		return []*cce.DriftItem{unreachableDriftItem(err)}, nil
+	}
+	defer disconnectNode(nodeCC)
+
+	var items []*cce.DriftItem
+	for _, e := range nodeApps {
+		nodeApp := e.(*cce.NodeApp)
+
+		// apps with an operation in flight are left to the operation
+		if code, _ := checkPendingOperations(ctx, ps, nodeApp.NodeID, nodeApp.AppID); code != 0 {
+			continue
+		}
+
+		s, err := nodeCC.AppLifeSvcCli.GetStatus(ctx, nodeApp.AppID)
+		switch {
+		case isNotFound(err) || (err == nil && s == cce.Unknown):
+			log.Noticef("App %s is missing from node %s, redeploying", nodeApp.AppID, n.ID)
+			code, err := operations.lockNodeApp(ctx, nodeApp.NodeID, nodeApp.AppID, func() error {
+				return handleCreateNodesApps(ctx, ps, nodeApp)
+			})
+			// an operation submitted meanwhile is left to change the app
+			if code == http.StatusUnprocessableEntity {
+				continue
+			}
+			if err != nil {
+				items = append(items, &cce.DriftItem{
+					Kind:       cce.DriftKindApp,
+					ResourceID: nodeApp.AppID,
+					Expected:   "deployed",
+					Actual:     "missing",
+					Error:      err.Error(),
+				})
+			}
+		case err != nil:
+			items = append(items, &cce.DriftItem{
+				Kind:       cce.DriftKindApp,
+				ResourceID: nodeApp.AppID,
+				Expected:   "deployed",
+				Actual:     cce.Unknown.String(),
+				Error:      err.Error(),
+			})
+		case s == cce.Error || s == cce.Unhealthy:
+			items = append(items, &cce.DriftItem{
+				Kind:       cce.DriftKindApp,
+				ResourceID: nodeApp.AppID,
+				Expected:   "deployed",
+				Actual:     s.String(),
+			})
+		}
+	}
+
+	return items, nil
+}
+
+// reconcileNodeELA reconciles the state configured through the node's ELA:
+// network interfaces, traffic policies and DNS. The node does not report
+// traffic policies or DNS records, so they are re-applied on every pass.
+func reconcileNodeELA(
+	ctx context.Context,
+	ps cce.PersistenceService,
+	n *cce.Node,
+) ([]*cce.DriftItem, error) {
+	ctrl := getController(ctx)
+	nodePort := ctrl.ELAPort
+	if nodePort == "" {
+		nodePort = defaultELAPort
+	}
+	nodeCC, err := connectNode(ctx, ps, n, nodePort, ctrl.EdgeNodeCreds)
+	if err != nil {
+		return []*cce.DriftItem{unreachableDriftItem(err)}, nil
+	}
+	defer disconnectNode(nodeCC)
+
+	var items []*cce.DriftItem
+
+	ifaceItems, err := reconcileNodeInterfaces(ctx, ps, nodeCC, n)
+	if err != nil {
+		return nil, err
+	}
+	items = append(items, ifaceItems...)
+
+	policyItems, err := reconcileNodePolicies(ctx, ps, nodeCC, n)
+	if err != nil {
+		return nil, err
+	}
+	items = append(items, policyItems...)
+
+	dnsItems, err := reconcileNodeDNS(ctx, ps, n)
+	if err != nil {
+		return nil, err
+	}
+	items = append(items, dnsItems...)
+
+	return items, nil
+}
+
+// reconcileNodeInterfaces re-applies the persisted network interface
+// configuration if it differs from the one reported by the node.
+func reconcileNodeInterfaces(
+	ctx context.Context,
+	ps cce.PersistenceService,
+	nodeCC *node.ClientConn,
+	n *cce.Node,
+) ([]*cce.DriftItem, error) {
+	e, err := ps.Read(ctx, n.ID, &cce.NodeReq{})
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading node")
+	}
+	if e == nil || len(e.(*cce.NodeReq).NetworkInterfaces) == 0 {
+		return nil, nil
+	}
+	desired := e.(*cce.NodeReq).NetworkInterfaces
+
+	reported, err := nodeCC.IfaceSvcCli.GetAll(ctx)
+	if err != nil {
+		return []*cce.DriftItem{
+			{
+				Kind:  cce.DriftKindInterface,
+				Error: err.Error(),
+			},
+		}, nil
+	}
+	reportedByID := make(map[string]*cce.NetworkInterface)
+	for _, ni := range reported {
+		reportedByID[ni.ID] = ni
+	}
+
+	var (
+		items   []*cce.DriftItem
+		drifted []*cce.NetworkInterface
+	)
+	for _, ni := range desired {
+		actual, ok := reportedByID[ni.ID]
+		switch {
+		case !ok:
+			items = append(items, &cce.DriftItem{
+				Kind:       cce.DriftKindInterface,
+				ResourceID: ni.ID,
+				Expected:   interfaceConfig(ni),
+				Actual:     "missing",
+			})
+		case !interfaceMatches(ni, actual):
+			drifted = append(drifted, ni)
+		}
+	}
+	if len(drifted) == 0 {
+		return items, nil
+	}
+
+	log.Noticef("%d interface(s) drifted on node %s, re-applying", len(drifted), n.ID)
+	if err = nodeCC.IfaceSvcCli.BulkUpdate(ctx, drifted); err != nil {
+		for _, ni := range drifted {
+			items = append(items, &cce.DriftItem{
+				Kind:       cce.DriftKindInterface,
+				ResourceID: ni.ID,
+				Expected:   interfaceConfig(ni),
+				Actual:     interfaceConfig(reportedByID[ni.ID]),
+				Error:      err.Error(),
+			})
+		}
+	}
+
+	return items, nil
+}
+
+// reconcileNodePolicies re-applies the traffic policies of the node's
+// interfaces and apps.
+func reconcileNodePolicies( //nolint:gocyclo
+	ctx context.Context,
+	ps cce.PersistenceService,
+	nodeCC *node.ClientConn,
+	n *cce.Node,
+) ([]*cce.DriftItem, error) {
+	ctrl := getController(ctx)
+
+	var items []*cce.DriftItem
+
+	if ctrl.OrchestrationMode != cce.OrchestrationModeKubernetesOVN {
+		ifacePolicies, err := ps.Filter(
+			ctx,
+			&cce.NodeInterfaceTrafficPolicy{},
+			[]cce.Filter{
+				{
+					Field: "node_id",
+					Value: n.ID,
+				},
+			})
+		if err != nil {
+			return nil, errors.Wrap(err, "error filtering nodes_network_interfaces_traffic_policies")
+		}
+		for _, e := range ifacePolicies {
+			ifacePolicy := e.(*cce.NodeInterfaceTrafficPolicy)
+			policy, err := ps.Read(ctx, ifacePolicy.TrafficPolicyID, &cce.TrafficPolicy{})
+			if err != nil {
+				return nil, errors.Wrap(err, "error reading traffic_policies")
+			}
+			if policy == nil {
+				continue
+			}
+			if err = nodeCC.IfacePolicySvcCli.Set(
+				ctx, ifacePolicy.NetworkInterfaceID, policy.(*cce.TrafficPolicy),
+			); err != nil {
+				items = append(items, &cce.DriftItem{
+					Kind:       cce.DriftKindInterfacePolicy,
+					ResourceID: ifacePolicy.NetworkInterfaceID,
+					Expected:   ifacePolicy.TrafficPolicyID,
+					Error:      err.Error(),
+				})
+			}
+		}
+	}
+
+	nodeApps, err := ps.Filter(
+		ctx,
+		&cce.NodeApp{},
+		[]cce.Filter{
+			{
+				Field: "node_id",
+				Value: n.ID,
+			},
+		})
+	if err != nil {
+		return nil, errors.Wrap(err, "error filtering nodes_apps")
+	}
+	for _, e := range nodeApps {
+		nodeApp := e.(*cce.NodeApp)
+
+		appPolicies, err := ps.Filter(
+			ctx,
+			&cce.NodeAppTrafficPolicy{},
+			[]cce.Filter{
+				{
+					Field: "nodes_apps_id",
+					Value: nodeApp.ID,
+				},
+			})
+		if err != nil {
+			return nil, errors.Wrap(err, "error filtering nodes_apps_traffic_policies")
+		}
+		if len(appPolicies) == 0 {
+			continue
+		}
+		policyID := appPolicies[0].(*cce.NodeAppTrafficPolicy).TrafficPolicyID
+
+		if ctrl.OrchestrationMode == cce.OrchestrationModeKubernetesOVN {
+			err = reconcileNodeAppKubeOVNPolicy(ctx, ps, nodeApp, policyID)
+		} else {
+			var policy cce.Persistable
+			if policy, err = ps.Read(ctx, policyID, &cce.TrafficPolicy{}); err != nil {
+				return nil, errors.Wrap(err, "error reading traffic_policies")
+			}
+			if policy == nil {
+				continue
+			}
+			err = nodeCC.AppPolicySvcCli.Set(ctx, nodeApp.AppID, policy.(*cce.TrafficPolicy))
+		}
+		if err != nil {
+			items = append(items, &cce.DriftItem{
+				Kind:       cce.DriftKindAppPolicy,
+				ResourceID: nodeApp.AppID,
+				Expected:   policyID,
+				Error:      err.Error(),
+			})
+		}
+	}
+
+	return items, nil
+}
+
+// reconcileNodeAppKubeOVNPolicy applies the app's network policy if it is
+// missing from Kubernetes.
+func reconcileNodeAppKubeOVNPolicy(
+	ctx context.Context,
+	ps cce.PersistenceService,
+	nodeApp *cce.NodeApp,
+	policyID string,
+) error {
+	ctrl := getController(ctx)
+
+	if _, err := ctrl.KubernetesClient.GetNetworkPolicy(ctx, nodeApp.Tenant, nodeApp.NodeID, nodeApp.AppID); err == nil {
+		return nil
+	}
+
+	policy, err := ps.Read(ctx, policyID, &cce.TrafficPolicyKubeOVN{})
+	if err != nil {
+		return errors.Wrap(err, "error reading traffic_policies")
+	}
+	if policy == nil {
+		return nil
+	}
+
+	log.Noticef("Network policy of app %s is missing on node %s, re-applying", nodeApp.AppID, nodeApp.NodeID)
+	return ctrl.KubernetesClient.ApplyNetworkPolicy(
+		ctx, nodeApp.Tenant, nodeApp.NodeID, nodeApp.AppID, policy.(*cce.TrafficPolicyKubeOVN).ToK8s())
+}
+
+// reconcileNodeDNS re-applies the node's DNS configurations.
+func reconcileNodeDNS(
+	ctx context.Context,
+	ps cce.PersistenceService,
+	n *cce.Node,
+) ([]*cce.DriftItem, error) {
+	nodeDNSConfigs, err := ps.Filter(
+		ctx,
+		&cce.NodeDNSConfig{},
+		[]cce.Filter{
+			{
+				Field: "node_id",
+				Value: n.ID,
+			},
+		})
+	if err != nil {
+		return nil, errors.Wrap(err, "error filtering nodes_dns_configs")
+	}
+
+	var items []*cce.DriftItem
+	for _, nodeDNS := range nodeDNSConfigs {
+		dnsConfigID := nodeDNS.(*cce.NodeDNSConfig).DNSConfigID
+
+		dnsConfig, err := ps.Read(ctx, dnsConfigID, &cce.DNSConfig{})
+		if err != nil {
+			return nil, errors.Wrap(err, "error reading dns_configs")
+		}
+		if dnsConfig == nil {
+			continue
+		}
+
+		dnsAliases, err := ps.Filter(
+			ctx,
+			&cce.DNSConfigAppAlias{},
+			[]cce.Filter{
+				{
+					Field: "dns_config_id",
+					Value: dnsConfigID,
+				},
+			})
+		if err != nil {
+			return nil, errors.Wrap(err, "error filtering dns_configs_app_aliases")
+		}
+
+		if err = handleCreateNodesDNSConfigsWithAliases(ctx, ps, nodeDNS, dnsConfig, dnsAliases); err != nil {
+			items = append(items, &cce.DriftItem{
+				Kind:       cce.DriftKindDNS,
+				ResourceID: dnsConfigID,
+				Error:      err.Error(),
+			})
+		}
+	}
+
+	appItems, err := reconcileNodeAppDNS(ctx, ps, n)
+	if err != nil {
+		return nil, err
+	}
+	items = append(items, appItems...)
+
+	return items, nil
+}
+
+// reconcileNodeAppDNS re-registers the services of the node's apps in its DNS
+// in Kubernetes mode and removes the stale records of undeployed apps. Deploys
+// and undeploys succeed even if the DNS of the node cannot be updated, so this
+// is where the failure is recorded.
+func reconcileNodeAppDNS(
+	ctx context.Context,
+	ps cce.PersistenceService,
+	n *cce.Node,
+) ([]*cce.DriftItem, error) {
+	ctrl := getController(ctx)
+	if ctrl.AppDNSDomain == "" ||
+		(ctrl.OrchestrationMode != cce.OrchestrationModeKubernetes &&
+			ctrl.OrchestrationMode != cce.OrchestrationModeKubernetesOVN) {
+		return nil, nil
+	}
+
+	nodeApps, err := ps.Filter(
+		ctx,
+		&cce.NodeApp{},
+		[]cce.Filter{
+			{
+				Field: "node_id",
+				Value: n.ID,
+			},
+		})
+	if err != nil {
+		return nil, errors.Wrap(err, "error filtering nodes_apps")
+	}
+
+	var items []*cce.DriftItem
+	// records of undeployed apps are removed before records are set, as an
+	// app may have been deployed to the node again
+	for _, stale := range staleAppDNSRecords.take(n.ID) {
+		if err = removeAppDNSRecord(ctx, ps, stale.nodeApp, stale.record); err != nil {
+			staleAppDNSRecords.add(stale.nodeApp, stale.record)
+			items = append(items, &cce.DriftItem{
+				Kind:       cce.DriftKindDNS,
+				ResourceID: stale.nodeApp.AppID,
+				Actual:     stale.record.Name,
+				Error:      err.Error(),
+			})
+		}
+	}
+	for _, e := range nodeApps {
+		nodeApp := e.(*cce.NodeApp)
+
+		if err = setAppDNSRecord(ctx, ps, nodeApp); err != nil {
+			items = append(items, &cce.DriftItem{
+				Kind:       cce.DriftKindDNS,
+				ResourceID: nodeApp.AppID,
+				Expected:   appDNSName(ctrl, nodeApp.AppID),
+				Error:      err.Error(),
+			})
+		}
+	}
+
+	return items, nil
+}
+
+// saveNodeDrift replaces the drift recorded for a node.
+func saveNodeDrift(
+	ctx context.Context,
+	ps cce.PersistenceService,
+	nodeID string,
+	items []*cce.DriftItem,
+) (*cce.NodeDrift, error) {
+	persisted, err := ps.Filter(
+		ctx,
+		&cce.NodeDrift{},
+		[]cce.Filter{
+			{
+				Field: "node_id",
+				Value: nodeID,
+			},
+		})
+	if err != nil {
+		return nil, errors.Wrap(err, "error filtering node_drifts")
+	}
+
+	drift := &cce.NodeDrift{
+		ID:        uuid.New(),
+		NodeID:    nodeID,
+		CheckedAt: time.Now().UTC(),
+		Items:     items,
+	}
+	if len(persisted) != 0 {
+		drift.ID = persisted[0].GetID()
+	}
+	if err = drift.Validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid node drift")
+	}
+
+	if len(persisted) != 0 {
+		err = ps.BulkUpdate(ctx, []cce.Persistable{drift})
+	} else {
+		err = ps.Create(ctx, drift)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "error persisting node drift")
+	}
+
+	return drift, nil
+}
+
+func unreachableDriftItem(err error) *cce.DriftItem {
+	return &cce.DriftItem{
+		Kind:     cce.DriftKindNode,
+		Expected: "reachable",
+		Actual:   "unreachable",
+		Error:    err.Error(),
+	}
+}
+
+func isNotFound(err error) bool {
+	if err == nil {
+		return false
+	}
+	s, ok := status.FromError(errors.Cause(err))
+	return ok && s.Code() == codes.NotFound
+}
+
+func isAlreadyExists(err error) bool {
+	if err == nil {
+		return false
+	}
+	s, ok := status.FromError(errors.Cause(err))
+	return ok && s.Code() == codes.AlreadyExists
+}
+
+// interfaceMatches returns true if the configurable fields of the reported
+// interface match the desired ones.
+func interfaceMatches(desired, actual *cce.NetworkInterface) bool {
+	if desired.Driver != actual.Driver ||
+		desired.Type != actual.Type ||
+		desired.VLAN != actual.VLAN ||
+		desired.FallbackInterface != actual.FallbackInterface {
+		return false
+	}
+
+	desiredZones := append([]string{}, desired.Zones...)
+	actualZones := append([]string{}, actual.Zones...)
+	sort.Strings(desiredZones)
+	sort.Strings(actualZones)
+
+	return reflect.DeepEqual(desiredZones, actualZones)
+}
+
+func interfaceConfig(ni *cce.NetworkInterface) string {
+	return fmt.Sprintf("driver=%s type=%s vlan=%d zones=%v fallback=%s",
+		ni.Driver, ni.Type, ni.VLAN, ni.Zones, ni.FallbackInterface)
+}
@@ -0,0 +1,271 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package gorilla
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	cce "github.com/open-ness/edgecontroller"
+	"github.com/open-ness/edgecontroller/swagger"
+	"github.com/pkg/errors"
+)
+
+const (
+	// placementSpread prefers the least loaded nodes
+	placementSpread = "spread"
+	// placementPack prefers the most loaded nodes that still fit the app
+	placementPack = "pack"
+)
+
+// placementPolicy is a validated placement request.
+type placementPolicy struct {
+	replicas int
+	selector []nfdTerm
+	strategy string
+}
+
+// newPlacementPolicy validates a placement request.
+func newPlacementPolicy(req *swagger.PlacementRequest) (*placementPolicy, error) {
+	policy := &placementPolicy{
+		replicas: req.Replicas,
+		strategy: req.Strategy,
+	}
+	if policy.replicas < 1 {
+		return nil, errors.New("replicas must be at least 1")
+	}
+	if policy.strategy == "" {
+		policy.strategy = placementSpread
+	}
+	if policy.strategy != placementSpread && policy.strategy != placementPack {
+		return nil, fmt.Errorf(`strategy must be either "%s" or "%s"`, placementSpread, placementPack)
+	}
+	for _, s := range req.NodeSelector {
+		term, err := parseNfdTerm(s)
+		if err != nil {
+			return nil, err
+		}
+		policy.selector = append(policy.selector, term)
+	}
+
+	return policy, nil
+}
+
+// fleetState is the state of all nodes the scheduler decides on, read once
+// per placement.
+type fleetState struct {
+	features   map[string]map[string]string
+	targets    map[string]bool
+	drifts     map[string]*cce.NodeDrift
+	nodeApps   map[string]map[string]bool
+	operations map[string][]*cce.Operation
+	// overcommit is the handling of nodes the app would over-commit, see
+	// cce.Controller
+	overcommit string
+}
+
+func readFleetState(ctx context.Context, ps cce.PersistenceService) (*fleetState, error) { //nolint:gocyclo
+	fleet := &fleetState{
+		targets:    make(map[string]bool),
+		drifts:     make(map[string]*cce.NodeDrift),
+		nodeApps:   make(map[string]map[string]bool),
+		operations: make(map[string][]*cce.Operation),
+		overcommit: getController(ctx).Overcommit,
+	}
+
+	var err error
+	if fleet.features, err = getFleetNfdFeatures(ctx, ps); err != nil {
+		return nil, errors.Wrap(err, "error reading nodes_nfd_features")
+	}
+
+	targets, err := ps.ReadAll(ctx, &cce.NodeGRPCTarget{})
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading node_grpc_targets")
+	}
+	for _, e := range targets {
+		fleet.targets[e.(*cce.NodeGRPCTarget).NodeID] = true
+	}
+
+	drifts, err := ps.ReadAll(ctx, &cce.NodeDrift{})
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading node_drifts")
+	}
+	for _, e := range drifts {
+		fleet.drifts[e.(*cce.NodeDrift).NodeID] = e.(*cce.NodeDrift)
+	}
+
+	nodeApps, err := ps.ReadAll(ctx, &cce.NodeApp{})
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading nodes_apps")
+	}
+	for _, e := range nodeApps {
+		nodeApp := e.(*cce.NodeApp)
+		if fleet.nodeApps[nodeApp.NodeID] == nil {
+			fleet.nodeApps[nodeApp.NodeID] = make(map[string]bool)
+		}
+		fleet.nodeApps[nodeApp.NodeID][nodeApp.AppID] = true
+	}
+
+	ops, err := ps.ReadAll(ctx, &cce.Operation{})
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading operations")
+	}
+	for _, e := range ops {
+		op := e.(*cce.Operation)
+		if op.Done() {
+			continue
+		}
+		fleet.operations[op.NodeID] = append(fleet.operations[op.NodeID], op)
+	}
+
+	return fleet, nil
+}
+
+// rejectReasons returns why a node does not qualify for the app, or nothing if
+// it does. All reasons are collected so that the caller learns everything that
+// has to change for the node to qualify. The requested resources of the node
+// are those counted by getNodeRequested, so that placement over-commits nodes
+// exactly when deploying to them does.
+func (fleet *fleetState) rejectReasons( //nolint:gocyclo
+	node *cce.Node,
+	app *cce.App,
+	policy *placementPolicy,
+	allocatable *nodeResources,
+	requested nodeResources,
+) []string {
+	var reasons []string
+
+	if !fleet.targets[node.ID] {
+		reasons = append(reasons, "node has not connected to the controller")
+	}
+	if drift := fleet.drifts[node.ID]; drift != nil {
+		for _, item := range drift.Items {
+			if item.Kind == cce.DriftKindNode {
+				reasons = append(reasons, fmt.Sprintf(
+					"node was unreachable at the last reconciliation: %s", item.Error))
+			}
+		}
+	}
+	for _, op := range fleet.operations[node.ID] {
+		switch {
+		case op.Type == cce.OperationTypeDecommission:
+			reasons = append(reasons, "node is being decommissioned")
+		case op.AppID == app.ID:
+			reasons = append(reasons, fmt.Sprintf("operation %s is %s for the app", op.ID, op.State))
+		}
+	}
+	if fleet.nodeApps[node.ID][app.ID] {
+		reasons = append(reasons, "app is already deployed to the node")
+	}
+
+	features := fleet.features[node.ID]
+	for _, term := range policy.selector {
+		if term.matches(features) {
+			continue
+		}
+		if value, ok := features[term.feature]; ok {
+			reasons = append(reasons, fmt.Sprintf(
+				"node selector %s not matched, node reports %s", term, value))
+		} else {
+			reasons = append(reasons, fmt.Sprintf(
+				"node selector %s not matched, node does not report %s", term, term.feature))
+		}
+	}
+	if err := app.EPAValidate(features); err != nil {
+		reasons = append(reasons, err.Error())
+	}
+
+	if allocatable != nil {
+		exceeded := allocatable.overcommits(requested, app)
+		if len(exceeded) != 0 && fleet.overcommit == cce.OvercommitWarn {
+			log.Warningf("Placing app %s over-commits node %s: %v", app.ID, node.ID, exceeded)
+			exceeded = nil
+		}
+		for _, e := range exceeded {
+			reasons = append(reasons, "insufficient "+e)
+		}
+	}
+
+	return reasons
+}
+
+// candidate is a node that qualifies for an app.
+type candidate struct {
+	node  *cce.Node
+	score float64
+}
+
+// schedule chooses the nodes to deploy an app to. The qualifying nodes are
+// returned from the most to the least preferred together with the nodes that
+// were rejected.
+func schedule(
+	ctx context.Context,
+	ps cce.PersistenceService,
+	app *cce.App,
+	policy *placementPolicy,
+) ([]*cce.Node, []swagger.RejectedNode, error) {
+	nodes, err := ps.ReadAll(ctx, &cce.Node{})
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "error reading nodes")
+	}
+	fleet, err := readFleetState(ctx, ps)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		candidates []candidate
+		rejected   = []swagger.RejectedNode{}
+	)
+	for _, e := range nodes {
+		node := e.(*cce.Node)
+		allocatable, _, err := getNodeAllocatable(ctx, ps, node.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		requested, err := getNodeRequested(ctx, ps, node.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if reasons := fleet.rejectReasons(node, app, policy, allocatable, requested); len(reasons) != 0 {
+			rejected = append(rejected, swagger.RejectedNode{
+				NodeID:  node.ID,
+				Reasons: reasons,
+			})
+			continue
+		}
+
+		// The score is the share of the node's cores that would be requested
+		// after the deployment, or the number of cores if the node's capacity
+		// is not known
+		score := float64(requested.cores + app.Cores)
+		if allocatable != nil && allocatable.cores > 0 {
+			score /= float64(allocatable.cores)
+		}
+		candidates = append(candidates, candidate{node: node, score: score})
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].score != candidates[j].score {
+			if policy.strategy == placementPack {
+				return candidates[i].score > candidates[j].score
+			}
+			return candidates[i].score < candidates[j].score
+		}
+		return candidates[i].node.ID < candidates[j].node.ID
+	})
+	sort.Slice(rejected, func(i, j int) bool {
+		return rejected[i].NodeID < rejected[j].NodeID
+	})
+
+	var qualified []*cce.Node
+	for _, c := range candidates {
+		qualified = append(qualified, c.node)
+	}
+
+	return qualified, rejected, nil
+}
@@ -0,0 +1,85 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package gorilla
+
+import (
+	"context"
+	"time"
+
+	cce "github.com/open-ness/edgecontroller"
+	"github.com/pkg/errors"
+)
+
+// runSchedules issues the lifecycle commands of the schedules that fall due
+// until the context is canceled. Commands that fell due while the controller
+// was stopped are not issued.
+func (p *operationPool) runSchedules(ctx context.Context) {
+	ticker := time.NewTicker(cce.ScheduleCheckInterval)
+	defer ticker.Stop()
+
+	last := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			p.issueScheduled(ctx, last, now)
+			last = now
+		}
+	}
+}
+
+// issueScheduled issues the commands of the schedules that fell due after
+// from and no later than to.
+func (p *operationPool) issueScheduled(ctx context.Context, from, to time.Time) {
+	es, err := p.controller.PersistenceService.ReadAll(ctx, &cce.Schedule{})
+	if err != nil {
+		log.Errf("Error loading schedules: %v", err)
+		return
+	}
+
+	for _, e := range es {
+		s := e.(*cce.Schedule)
+		cron, err := s.CronSchedule()
+		if err != nil {
+			log.Errf("Error parsing schedule %s: %v", s.ID, err)
+			continue
+		}
+		due := cron.Next(from)
+		if due.IsZero() || due.After(to) {
+			continue
+		}
+
+		if err = p.issue(ctx, s, due); err != nil {
+			log.Errf("Error issuing %s of schedule %s: %v", s.Cmd, s.ID, err)
+		}
+	}
+}
+
+// issue submits the command of a schedule and records it. The command is
+// skipped if the app is not deployed to the node or if another operation of
+// the node app has not finished yet.
+func (p *operationPool) issue(ctx context.Context, s *cce.Schedule, due time.Time) error {
+	ps := p.controller.PersistenceService
+
+	op := &cce.Operation{
+		Type:   s.Cmd,
+		NodeID: s.NodeID,
+		AppID:  s.AppID,
+	}
+	if _, err := findNodeApp(ctx, ps, op); err != nil {
+		return err
+	}
+	// the schedule sets the time of the operation, so it is not deferred to a
+	// maintenance window of the node
+	if _, err := p.submitNodeApp(ctx, op, false); err != nil {
+		return errors.Wrap(err, "error submitting operation")
+	}
+	log.Infof("Schedule %s issued %s of app %s on node %s", s.ID, s.Cmd, s.AppID, s.NodeID)
+
+	due = due.UTC()
+	s.LastRunAt = &due
+	s.LastOperationID = op.ID
+	return ps.BulkUpdate(ctx, []cce.Persistable{s})
+}
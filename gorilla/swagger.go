@@ -18,10 +18,13 @@ package gorilla
 // TODO: Remove nolint when possible and address the issues
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"time"
 
 	"github.com/gorilla/mux"
 	cce "github.com/open-ness/edgecontroller"
@@ -37,6 +40,17 @@ func (g *Gorilla) swagGETNodes(w http.ResponseWriter, r *http.Request) {
 	// Load the controller to access the persistence
 	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
 
+	// Parse the NFD feature filters, e.g. ?nfd.cpu-cpuid.AVX512F=true
+	terms, err := parseNfdQuery(r.URL.RawQuery)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, err = w.Write([]byte(fmt.Sprintf("Validation failed: %v", err)))
+		if err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
+		return
+	}
+
 	// Fetch the nodes from persistence
 	persisted, err := ctrl.PersistenceService.ReadAll(r.Context(), &cce.Node{})
 	if err != nil {
@@ -44,6 +58,14 @@ func (g *Gorilla) swagGETNodes(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Keep only the nodes matching the NFD feature filters
+	persisted, err = filterNodesByNfd(r.Context(), ctrl.PersistenceService, persisted, terms)
+	if err != nil {
+		log.Errf("Error filtering nodes by NFD features: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
 	// Construct the response object
 	nodes := swagger.NodeList{Nodes: []swagger.NodeSummary{}}
 	for _, n := range persisted {
@@ -99,6 +121,13 @@ func (g *Gorilla) swagGETNodeByID(w http.ResponseWriter, r *http.Request) {
 		},
 	}
 
+	// Add the resources of the node and how much of them is requested
+	if node.Utilization, err = getNodeUtilization(r.Context(), ctrl.PersistenceService, node.ID); err != nil {
+		log.Errf("Error getting utilization of node %s: %v", node.ID, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
 	// Marshal the response object to JSON
 	nodeJSON, err := json.Marshal(node)
 	if err != nil {
@@ -218,6 +247,8 @@ func (g *Gorilla) swagGETApps(w http.ResponseWriter, r *http.Request) {
 			Type:        a.(*cce.App).Type,
 			Name:        a.(*cce.App).Name,
 			Version:     a.(*cce.App).Version,
+			VersionOf:   a.(*cce.App).VersionOf,
+			Tenant:      a.(*cce.App).Tenant,
 			Vendor:      a.(*cce.App).Vendor,
 			Description: a.(*cce.App).Description,
 		}
@@ -264,14 +295,30 @@ func (g *Gorilla) swagGETAppByID(w http.ResponseWriter, r *http.Request) {
 			Type:        persisted.(*cce.App).Type,
 			Name:        persisted.(*cce.App).Name,
 			Version:     persisted.(*cce.App).Version,
+			VersionOf:   persisted.(*cce.App).VersionOf,
+			Tenant:      persisted.(*cce.App).Tenant,
 			Vendor:      persisted.(*cce.App).Vendor,
 			Description: persisted.(*cce.App).Description,
 		},
 		Cores:       persisted.(*cce.App).Cores,
 		Memory:      persisted.(*cce.App).Memory,
+		Hugepages:   persisted.(*cce.App).Hugepages,
 		Source:      persisted.(*cce.App).Source,
+		SHA256:      persisted.(*cce.App).SHA256,
+		Signature:   persisted.(*cce.App).Signature,
 		Ports:       persisted.(*cce.App).Ports,
 		EPAFeatures: persisted.(*cce.App).EPAFeatures,
+		Env:         persisted.(*cce.App).Env,
+		Command:     persisted.(*cce.App).Command,
+		Args:        persisted.(*cce.App).Args,
+		Volumes:     persisted.(*cce.App).Volumes,
+		ConfigFiles: persisted.(*cce.App).ConfigFiles,
+		Networks:    persisted.(*cce.App).Networks,
+
+		LivenessProbe:  persisted.(*cce.App).LivenessProbe,
+		ReadinessProbe: persisted.(*cce.App).ReadinessProbe,
+		RestartPolicy:  persisted.(*cce.App).RestartPolicy,
+		PullPolicy:     persisted.(*cce.App).PullPolicy,
 	}
 
 	// Marshal the response object to JSON
@@ -306,13 +353,29 @@ func (g *Gorilla) swagPATCHAppByID(w http.ResponseWriter, r *http.Request) {
 		Type:        app.Type,
 		Name:        app.Name,
 		Version:     app.Version,
+		VersionOf:   app.VersionOf,
+		Tenant:      app.Tenant,
 		Vendor:      app.Vendor,
 		Description: app.Description,
 		Cores:       app.Cores,
 		Memory:      app.Memory,
+		Hugepages:   app.Hugepages,
 		Source:      app.Source,
+		SHA256:      app.SHA256,
+		Signature:   app.Signature,
 		Ports:       app.Ports,
 		EPAFeatures: app.EPAFeatures,
+		Env:         app.Env,
+		Command:     app.Command,
+		Args:        app.Args,
+		Volumes:     app.Volumes,
+		ConfigFiles: app.ConfigFiles,
+		Networks:    app.Networks,
+
+		LivenessProbe:  app.LivenessProbe,
+		ReadinessProbe: app.ReadinessProbe,
+		RestartPolicy:  app.RestartPolicy,
+		PullPolicy:     app.PullPolicy,
 	}
 
 	// Validate the object
@@ -325,6 +388,22 @@ func (g *Gorilla) swagPATCHAppByID(w http.ResponseWriter, r *http.Request) {
 		}
 		return
 	}
+	if statusCode, err := checkAppNetworks(r.Context(), ctrl.PersistenceService, &persisted); err != nil {
+		log.Errf("Error running DB logic: %v", err)
+		w.WriteHeader(statusCode)
+		_, err = w.Write([]byte(err.Error()))
+		if err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
+		return
+	}
+	if err := checkAppSignature(r.Context(), &persisted); err != nil {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		if _, err = w.Write([]byte(err.Error())); err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
+		return
+	}
 
 	// Persist the object
 	if err := ctrl.PersistenceService.BulkUpdate(r.Context(), []cce.Persistable{&persisted}); err != nil {
@@ -380,6 +459,137 @@ func (g *Gorilla) swagDELETEAppByID(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// Used for POST /apps/{app_id}/placements endpoint
+func (g *Gorilla) swagPOSTAppPlacements(w http.ResponseWriter, r *http.Request) { //nolint:gocyclo
+	// Load the controller to access the persistence and the payload
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+	body := r.Context().Value(contextKey("body")).([]byte)
+
+	// Unmarshal the payload
+	var requested swagger.PlacementRequest
+	if err := json.Unmarshal(body, &requested); err != nil {
+		log.Errf("Error unmarshaling json: %v", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	// Validate the request
+	policy, err := newPlacementPolicy(&requested)
+	if err != nil {
+		log.Debugf("Validation failed for %#v: %v", requested, err)
+		w.WriteHeader(http.StatusBadRequest)
+		_, err = w.Write([]byte(fmt.Sprintf("Validation failed: %v", err)))
+		if err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
+		return
+	}
+
+	// Fetch the app from persistence and check if it's there
+	app, err := ctrl.PersistenceService.Read(r.Context(), mux.Vars(r)["app_id"], &cce.App{})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if app == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if err = checkOrchestrationSupport(r.Context(), app.(*cce.App)); err != nil {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		if _, err = w.Write([]byte(err.Error())); err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
+		return
+	}
+	if err = checkAppSignature(r.Context(), app.(*cce.App)); err != nil {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		if _, err = w.Write([]byte(err.Error())); err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
+		return
+	}
+
+	// Choose the nodes
+	qualified, rejected, err := schedule(r.Context(), ctrl.PersistenceService, app.(*cce.App), policy)
+	if err != nil {
+		log.Errf("Error scheduling app %s: %v", app.GetID(), err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	result := swagger.PlacementResult{
+		AppID:    app.GetID(),
+		Strategy: policy.strategy,
+		Placed:   []swagger.PlacedNode{},
+		Rejected: rejected,
+	}
+
+	// Nothing is deployed unless all replicas can be placed
+	statusCode := http.StatusAccepted
+	if len(qualified) < policy.replicas {
+		log.Infof("Only %d of %d replicas of app %s can be placed",
+			len(qualified), policy.replicas, app.GetID())
+		statusCode = http.StatusUnprocessableEntity
+	} else {
+		// Deploy the app to the chosen nodes asynchronously, the node apps are
+		// persisted once the deployments succeed
+		var ops []*cce.Operation
+		for _, node := range qualified[:policy.replicas] {
+			op := &cce.Operation{
+				Type:   cce.OperationTypeDeploy,
+				NodeID: node.ID,
+				AppID:  app.GetID(),
+			}
+			if statusCode, err = g.operations.submitNodeApp(r.Context(), op, true); err != nil {
+				log.Errf("Error submitting operation: %v", err)
+				result.Error = err.Error()
+				g.cancelPlacement(r.Context(), ops)
+				break
+			}
+			ops = append(ops, op)
+		}
+		if err == nil {
+			statusCode = http.StatusAccepted
+		}
+		// Operations that already started when a later replica failed cannot
+		// be canceled and are reported with their state
+		for _, op := range ops {
+			result.Placed = append(result.Placed, swagger.PlacedNode{
+				NodeID:    op.NodeID,
+				Operation: toOperationSummary(op),
+			})
+		}
+	}
+
+	// Marshal the response object to JSON
+	resultJSON, err := json.Marshal(result)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	if _, err = w.Write(resultJSON); err != nil {
+		log.Errf("Error writing response: %v", err)
+	}
+}
+
+// cancelPlacement cancels the operations of the replicas of a placement that
+// could not be placed completely. Only queued and deferred operations can be
+// canceled, the others are left to run.
+func (g *Gorilla) cancelPlacement(ctx context.Context, ops []*cce.Operation) {
+	for _, op := range ops {
+		if op.State != cce.OperationStateQueued && op.State != cce.OperationStateDeferred {
+			log.Warningf("Operation %s of the placement of app %s is %s and cannot be canceled",
+				op.ID, op.AppID, op.State)
+			continue
+		}
+		if _, err := g.operations.cancel(ctx, op); err != nil {
+			log.Errf("Error canceling operation %s: %v", op.ID, err)
+		}
+	}
+}
+
 // Used for GET /policies endpoint
 func (g *Gorilla) swagGETPolicies(w http.ResponseWriter, r *http.Request) {
 	// Load the controller to access the persistence
@@ -819,6 +1029,7 @@ func (g *Gorilla) swagGETNodeDNS(w http.ResponseWriter, r *http.Request) {
 func (g *Gorilla) swagPATCHNodeDNS(w http.ResponseWriter, r *http.Request) {
 	// Load the controller to access the persistence and the payload
 	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+	body := r.Context().Value(contextKey("body")).([]byte)
 
 	// Fetch the nodes from persistence and check if it's there
 	persisted, err := ctrl.PersistenceService.Read(r.Context(), mux.Vars(r)["node_id"], &cce.Node{})
@@ -831,8 +1042,18 @@ func (g *Gorilla) swagPATCHNodeDNS(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Delete the old persisted data
-	if err := g.swagDNSDeleteHelper(w, r); err != nil {
+	// Unmarshal the requested DNS configurations
+	requested := swagger.DNSDetail{}
+	if err = json.Unmarshal(body, &requested); err != nil {
+		log.Errf("Error unmarshaling json: %v", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	// Construct the persistable entities
+	nodeDNS, newConfig, newAliases, code, err := toNodeDNSConfig(mux.Vars(r)["node_id"], &requested)
+	if err != nil {
+		w.WriteHeader(code)
 		_, err = w.Write([]byte(fmt.Sprintf("DNS call failed mid operation: %v", err)))
 		if err != nil {
 			log.Errf("Error writing response: %v", err)
@@ -840,8 +1061,25 @@ func (g *Gorilla) swagPATCHNodeDNS(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Create the new requested data
-	if err := g.swagDNSCreateHelper(w, r); err != nil {
+	// Queue the request behind the node's queued operations, if any
+	op := &cce.Operation{
+		Type:    cce.OperationTypeSetDNS,
+		NodeID:  mux.Vars(r)["node_id"],
+		Payload: body,
+	}
+	if g.writeQueuedBehind(w, r, op) {
+		return
+	}
+
+	// Replace the old DNS data with the requested data
+	if err = handleDeleteNodesDNS(r.Context(), ctrl.PersistenceService, op.NodeID); err == nil {
+		err = handleCreateNodesDNS(r.Context(), ctrl.PersistenceService, nodeDNS, newConfig, newAliases)
+	}
+	if err != nil {
+		if g.writeQueuedOnUnreachable(w, r, op, err) {
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
 		_, err = w.Write([]byte(fmt.Sprintf("DNS call failed mid operation: %v", err)))
 		if err != nil {
 			log.Errf("Error writing response: %v", err)
@@ -866,8 +1104,21 @@ func (g *Gorilla) swagDELETENodeDNS(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Queue the request behind the node's queued operations, if any
+	op := &cce.Operation{
+		Type:   cce.OperationTypeDeleteDNS,
+		NodeID: mux.Vars(r)["node_id"],
+	}
+	if g.writeQueuedBehind(w, r, op) {
+		return
+	}
+
 	// Delete the old persisted data
-	if err := g.swagDNSDeleteHelper(w, r); err != nil {
+	if err = handleDeleteNodesDNS(r.Context(), ctrl.PersistenceService, op.NodeID); err != nil {
+		if g.writeQueuedOnUnreachable(w, r, op, err) {
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
 		_, err = w.Write([]byte(fmt.Sprintf("DNS call failed mid operation: %v", err)))
 		if err != nil {
 			log.Errf("Error writing response: %v", err)
@@ -878,41 +1129,37 @@ func (g *Gorilla) swagDELETENodeDNS(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
-func (g *Gorilla) swagDNSCreateHelper(w http.ResponseWriter, r *http.Request) error { //nolint:gocyclo
-	// Load the controller to access the persistence and the payload
-	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
-	body := r.Context().Value(contextKey("body")).([]byte)
-
-	// Unmarshal the requested DNS configurations
-	requested := swagger.DNSDetail{}
-	if err := json.Unmarshal(body, &requested); err != nil {
-		log.Errf("Error unmarshaling json: %v", err)
-		w.WriteHeader(http.StatusBadRequest)
-		return err
-	}
-
+// toNodeDNSConfig converts a requested DNS configuration of a node to its
+// persistable entities. On error the returned status code is set.
+func toNodeDNSConfig( //nolint:gocyclo
+	nodeID string,
+	requested *swagger.DNSDetail,
+) (
+	nodeDNS *cce.NodeDNSConfig,
+	newConfig *cce.DNSConfig,
+	newAliases []cce.Persistable,
+	statusCode int,
+	err error,
+) {
 	if len(requested.Configurations.Forwarders) != 0 {
 		log.Err("Received unimplemented field forwarders in request")
-		w.WriteHeader(http.StatusNotImplemented)
-		return fmt.Errorf("received unimplemented field forwarders in request")
+		return nil, nil, nil, http.StatusNotImplemented,
+			fmt.Errorf("received unimplemented field forwarders in request")
 	}
 
 	// Create the new persistable entity for the DNS config
-	newConfig := &cce.DNSConfig{
+	newConfig = &cce.DNSConfig{
 		ID:   uuid.New(),
 		Name: requested.Name,
 	}
 
 	// Create the new persistable association
-	nodeDNS := &cce.NodeDNSConfig{
+	nodeDNS = &cce.NodeDNSConfig{
 		ID:          uuid.New(),
-		NodeID:      mux.Vars(r)["node_id"],
+		NodeID:      nodeID,
 		DNSConfigID: newConfig.ID,
 	}
 
-	// Create the new persistable entity for the DNS aliases
-	var newAliases []cce.Persistable
-
 	// Construct the persistable entities
 	for _, req := range requested.Records.A {
 		switch {
@@ -924,10 +1171,9 @@ func (g *Gorilla) swagDNSCreateHelper(w http.ResponseWriter, r *http.Request) er
 				Description: req.Description,
 				AppID:       req.Values[0],
 			}
-			if err := record.Validate(); err != nil {
+			if err = record.Validate(); err != nil {
 				log.Errf("Error creating DNS config aliases: %v", err)
-				w.WriteHeader(http.StatusBadRequest)
-				return err
+				return nil, nil, nil, http.StatusBadRequest, err
 			}
 			newAliases = append(newAliases, &record)
 		case !req.Alias:
@@ -936,10 +1182,9 @@ func (g *Gorilla) swagDNSCreateHelper(w http.ResponseWriter, r *http.Request) er
 				Description: req.Description,
 				IPs:         req.Values,
 			}
-			if err := record.Validate(); err != nil {
+			if err = record.Validate(); err != nil {
 				log.Errf("Error creating DNS config non-aliases: %v", err)
-				w.WriteHeader(http.StatusBadRequest)
-				return err
+				return nil, nil, nil, http.StatusBadRequest, err
 			}
 			newConfig.ARecords = append(newConfig.ARecords, record)
 		}
@@ -950,118 +1195,14 @@ func (g *Gorilla) swagDNSCreateHelper(w http.ResponseWriter, r *http.Request) er
 			Description: req.Description,
 			IP:          req.Value,
 		}
-		if err := config.Validate(); err != nil {
+		if err = config.Validate(); err != nil {
 			log.Errf("Error creating DNS config forwarders: %v", err)
-			w.WriteHeader(http.StatusBadRequest)
-			return err
+			return nil, nil, nil, http.StatusBadRequest, err
 		}
 		newConfig.Forwarders = append(newConfig.Forwarders, config)
 	}
 
-	// Create the DNS config and aliases from the node
-	if err := handleCreateNodesDNSConfigsWithAliases(
-		r.Context(), ctrl.PersistenceService, nodeDNS, newConfig, newAliases,
-	); err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		return err
-	}
-
-	// Create the config in persistence
-	if err := ctrl.PersistenceService.Create(r.Context(), newConfig); err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		return err
-	}
-
-	// Create the aliases in persistence
-	for _, alias := range newAliases {
-		if err := ctrl.PersistenceService.Create(r.Context(), alias); err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
-			return err
-		}
-	}
-
-	// Create the association in persistence
-	if err := ctrl.PersistenceService.Create(r.Context(), nodeDNS); err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		return err
-	}
-
-	return nil
-}
-
-func (g *Gorilla) swagDNSDeleteHelper(w http.ResponseWriter, r *http.Request) error {
-	// Load the controller to access the persistence and the payload
-	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
-
-	// Fetch the entity from persistence
-	persistedNode, err := ctrl.PersistenceService.Filter(
-		r.Context(),
-		&cce.NodeDNSConfig{},
-		[]cce.Filter{{Field: "node_id", Value: mux.Vars(r)["node_id"]}},
-	)
-	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		return err
-	}
-
-	// If there's persisted DNS data, delete it from the node and from persistence
-	if len(persistedNode) != 0 {
-		// Fetch the DNS config from persistence
-		persistedConfig, err := ctrl.PersistenceService.Read(
-			r.Context(),
-			persistedNode[0].(*cce.NodeDNSConfig).DNSConfigID,
-			&cce.DNSConfig{},
-		)
-		if err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
-			return err
-		}
-
-		// Fetch the DNS aliases from persistence
-		persistedAliases, err := ctrl.PersistenceService.Filter(
-			r.Context(),
-			&cce.DNSConfigAppAlias{},
-			[]cce.Filter{
-				{Field: "dns_config_id", Value: persistedNode[0].(*cce.NodeDNSConfig).DNSConfigID},
-			},
-		)
-		if err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
-			return err
-		}
-
-		// Delete the DNS config and aliases from the node
-		if err := handleDeleteNodesDNSConfigsWithAliases(
-			r.Context(), ctrl.PersistenceService, persistedNode[0], persistedConfig, persistedAliases,
-		); err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
-			return err
-		}
-
-		// Delete the association from persistence
-		if _, err := ctrl.PersistenceService.Delete(
-			r.Context(), persistedNode[0].GetID(), persistedNode[0],
-		); err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
-			return err
-		}
-
-		// Delete the aliases from persistence
-		for _, alias := range persistedAliases {
-			if _, err := ctrl.PersistenceService.Delete(r.Context(), alias.GetID(), alias); err != nil {
-				w.WriteHeader(http.StatusInternalServerError)
-				return err
-			}
-		}
-
-		// Delete the config from persistence
-		if _, err := ctrl.PersistenceService.Delete(r.Context(), persistedConfig.GetID(), persistedConfig); err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
-			return err
-		}
-
-	}
-	return nil
+	return nodeDNS, newConfig, newAliases, 0, nil
 }
 
 // Used for GET /nodes/{node_id}/interfaces endpoint
@@ -1171,6 +1312,17 @@ func (g *Gorilla) swagPATCHInterfaces(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Check that the interfaces are only assigned to defined zones
+	if statusCode, err := checkDBUpdateNodesInterfaces(r.Context(), ctrl.PersistenceService, &requested); err != nil {
+		log.Errf("Error running DB logic: %v", err)
+		w.WriteHeader(statusCode)
+		_, err = w.Write([]byte(err.Error()))
+		if err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
+		return
+	}
+
 	code, err := handleUpdateNodes(r.Context(), ctrl.PersistenceService, &requested)
 	switch {
 	case code != 0:
@@ -1340,23 +1492,24 @@ func (g *Gorilla) swagPATCHNodeInterfacePolicy(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	// Construct the update object to dial to the node
-	requested := cce.NodeReq{
-		Node: cce.Node{
-			ID: mux.Vars(r)["node_id"],
-		},
-		TrafficPolicies: []cce.NetworkInterfaceTrafficPolicy{
-			{
-				NetworkInterfaceID: mux.Vars(r)["interface_id"],
-				TrafficPolicyID:    baseResource.ID,
-			},
-		},
+	// Queue the request behind the node's queued operations, if any
+	op := &cce.Operation{
+		Type:    cce.OperationTypeSetInterfacePolicy,
+		NodeID:  mux.Vars(r)["node_id"],
+		Payload: toInterfacePolicyPayload(mux.Vars(r)["interface_id"], baseResource.ID),
+	}
+	if g.writeQueuedBehind(w, r, op) {
+		return
 	}
 
-	// Update the remote node
-	code, err := handleUpdateNodes(r.Context(), ctrl.PersistenceService, &requested)
+	// Update the remote node and persist the association
+	code, err := handleUpdateNodesInterfacesPolicy(
+		r.Context(), ctrl.PersistenceService, op.NodeID, mux.Vars(r)["interface_id"], baseResource.ID)
 	switch {
 	case code != 0:
+		if g.writeQueuedOnUnreachable(w, r, op, err) {
+			return
+		}
 		log.Errf("Error updating remote entities: %v", err)
 		w.WriteHeader(code)
 		_, err = w.Write([]byte(err.Error()))
@@ -1365,92 +1518,43 @@ func (g *Gorilla) swagPATCHNodeInterfacePolicy(w http.ResponseWriter, r *http.Re
 		}
 		return
 	}
+}
 
-	// Filter nodes_interfaces_traffic_policies to see if a record already exists
-	nodeIfacePolicy, err := ctrl.PersistenceService.Filter(
-		r.Context(),
-		&cce.NodeInterfaceTrafficPolicy{},
-		[]cce.Filter{
-			{
-				Field: "network_interface_id",
-				Value: mux.Vars(r)["interface_id"],
-			},
-		})
+// Used for DELETE /nodes/{node_id}/interfaces/{interface_id}/policy endpoint
+func (g *Gorilla) swagDELETENodeInterfacePolicy(w http.ResponseWriter, r *http.Request) {
+	// Load the controller to access the persistence and the payload
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+
+	// Fetch the nodes from persistence and check if it's there
+	node, err := ctrl.PersistenceService.Read(r.Context(), mux.Vars(r)["node_id"], &cce.Node{})
 	if err != nil {
-		log.Errf("Error reading nodes_interfaces_traffic_policies: %v", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
-
-	// If it exists, delete it
-	if len(nodeIfacePolicy) == 1 {
-		ok, err := ctrl.PersistenceService.Delete(
-			r.Context(),
-			nodeIfacePolicy[0].GetID(),
-			&cce.NodeInterfaceTrafficPolicy{},
-		)
-		if err != nil {
-			log.Errf("Error deleting from nodes_interfaces_traffic_policies: %v", err)
-			w.WriteHeader(http.StatusInternalServerError)
-			return
-		}
-		if !ok {
-			log.Err("Did not delete 1 record from nodes_interfaces_traffic_policies")
-			w.WriteHeader(http.StatusInternalServerError)
-			return
-		}
+	if node == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
 	}
+	// TODO: Verify the interface ID is valid
 
-	// Convert the base resource to a persistable object
-	persisted := cce.NodeInterfaceTrafficPolicy{
-		ID:                 uuid.New(),
-		NodeID:             mux.Vars(r)["node_id"],
-		NetworkInterfaceID: mux.Vars(r)["interface_id"],
-		TrafficPolicyID:    baseResource.ID,
+	// Queue the request behind the node's queued operations, if any
+	op := &cce.Operation{
+		Type:    cce.OperationTypeDeleteInterfacePolicy,
+		NodeID:  mux.Vars(r)["node_id"],
+		Payload: toInterfacePolicyPayload(mux.Vars(r)["interface_id"], ""),
 	}
-
-	// Persist the object
-	if err := ctrl.PersistenceService.Create(r.Context(), &persisted); err != nil {
-		log.Errf("Error creating entity: %v", err)
-		w.WriteHeader(http.StatusInternalServerError)
-		return
-	}
-}
-
-// Used for DELETE /nodes/{node_id}/interfaces/{interface_id}/policy endpoint
-func (g *Gorilla) swagDELETENodeInterfacePolicy(w http.ResponseWriter, r *http.Request) {
-	// Load the controller to access the persistence and the payload
-	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
-
-	// Fetch the nodes from persistence and check if it's there
-	node, err := ctrl.PersistenceService.Read(r.Context(), mux.Vars(r)["node_id"], &cce.Node{})
-	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		return
-	}
-	if node == nil {
-		w.WriteHeader(http.StatusNotFound)
+	if g.writeQueuedBehind(w, r, op) {
 		return
 	}
-	// TODO: Verify the interface ID is valid
-
-	// Construct the update object to dial to the node
-	requested := cce.NodeReq{
-		Node: cce.Node{
-			ID: mux.Vars(r)["node_id"],
-		},
-		TrafficPolicies: []cce.NetworkInterfaceTrafficPolicy{
-			{
-				NetworkInterfaceID: mux.Vars(r)["interface_id"],
-				TrafficPolicyID:    "", // set no policy
-			},
-		},
-	}
 
-	// Update the remote node
-	code, err := handleUpdateNodes(r.Context(), ctrl.PersistenceService, &requested)
+	// Update the remote node and delete the association
+	code, err := handleUpdateNodesInterfacesPolicy(
+		r.Context(), ctrl.PersistenceService, op.NodeID, mux.Vars(r)["interface_id"], "")
 	switch {
 	case code != 0:
+		if g.writeQueuedOnUnreachable(w, r, op, err) {
+			return
+		}
 		log.Errf("Error updating remote entities: %v", err)
 		w.WriteHeader(code)
 		_, err = w.Write([]byte(err.Error()))
@@ -1459,41 +1563,6 @@ func (g *Gorilla) swagDELETENodeInterfacePolicy(w http.ResponseWriter, r *http.R
 		}
 		return
 	}
-
-	// Filter nodes_interfaces_traffic_policies to see if a record already exists
-	nodeIfacePolicy, err := ctrl.PersistenceService.Filter(
-		r.Context(),
-		&cce.NodeInterfaceTrafficPolicy{},
-		[]cce.Filter{
-			{
-				Field: "network_interface_id",
-				Value: mux.Vars(r)["interface_id"],
-			},
-		})
-	if err != nil {
-		log.Errf("Error reading nodes_interfaces_traffic_policies: %v", err)
-		w.WriteHeader(http.StatusInternalServerError)
-		return
-	}
-
-	// If it exists, delete it
-	if len(nodeIfacePolicy) == 1 {
-		ok, err := ctrl.PersistenceService.Delete(
-			r.Context(),
-			nodeIfacePolicy[0].GetID(),
-			&cce.NodeInterfaceTrafficPolicy{},
-		)
-		if err != nil {
-			log.Errf("Error deleting from nodes_interfaces_traffic_policies: %v", err)
-			w.WriteHeader(http.StatusInternalServerError)
-			return
-		}
-		if !ok {
-			log.Err("Did not delete 1 record from nodes_interfaces_traffic_policies")
-			w.WriteHeader(http.StatusInternalServerError)
-			return
-		}
-	}
 	w.WriteHeader(http.StatusNoContent)
 }
 
@@ -1583,14 +1652,18 @@ func (g *Gorilla) swagGETNodeApps(w http.ResponseWriter, r *http.Request) {
 // TODO: Change /nodes/{node_id}/apps POST -> PATCH
 //			- Ensure the UI is in sync when changed.
 
-// Used for POST /nodes/{node_id}/apps endpoint
+// Used for POST /nodes/{node_id}/apps endpoint. The body references either an
+// app or a template to instantiate with the values of its parameters.
 func (g *Gorilla) swagPOSTNodeApp(w http.ResponseWriter, r *http.Request) { //nolint:gocyclo
 	// Load the controller to access the persistence and the payload
 	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
 	body := r.Context().Value(contextKey("body")).([]byte)
 
 	// Unmarshal the payload
-	var baseResource swagger.BaseResource
+	var (
+		baseResource swagger.BaseResource
+		templateReq  swagger.NodeAppTemplateReq
+	)
 	if err := json.Unmarshal(body, &baseResource); err != nil {
 		log.Errf("Error unmarshaling json: %v", err)
 		w.WriteHeader(http.StatusBadRequest)
@@ -1601,6 +1674,12 @@ func (g *Gorilla) swagPOSTNodeApp(w http.ResponseWriter, r *http.Request) { //no
 		return
 	}
 
+	// An app instantiated from a template is deployed instead of an app
+	if err := json.Unmarshal(body, &templateReq); err == nil && templateReq.TemplateID != "" {
+		g.postNodeAppFromTemplate(w, r, &templateReq)
+		return
+	}
+
 	// Fetch the entity from persistence and check if it's there
 	nodeApps, err := ctrl.PersistenceService.Filter(
 		r.Context(),
@@ -1688,20 +1767,39 @@ func (g *Gorilla) swagPOSTNodeApp(w http.ResponseWriter, r *http.Request) { //no
 		return
 	}
 
-	// Create the remote node app
-	err = handleCreateNodesApps(r.Context(), ctrl.PersistenceService, &nodeApp)
-	if err != nil {
-		log.Errf("Error creating node app: %v", err)
-		w.WriteHeader(http.StatusInternalServerError)
+	// Check that the node has the resources left to run the app
+	if statusCode, err := checkDBCreateNodesApps(
+		r.Context(), ctrl.PersistenceService, &nodeApp,
+	); err != nil {
+		log.Errf("Error checking node app: %v", err)
+		w.WriteHeader(statusCode)
+		_, err = w.Write([]byte(err.Error()))
+		if err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
 		return
 	}
 
-	// Persist the object
-	if err := ctrl.PersistenceService.Create(r.Context(), &nodeApp); err != nil {
-		log.Errf("Error creating entity: %v", err)
-		w.WriteHeader(http.StatusInternalServerError)
+	// Deploy the app to the node asynchronously, the node app is persisted
+	// once the deployment succeeds
+	op := &cce.Operation{
+		Type:   cce.OperationTypeDeploy,
+		NodeID: nodeApp.NodeID,
+		AppID:  nodeApp.AppID,
+	}
+	// Check that no other operation is in progress for the node app and
+	// submit the operation at once
+	if statusCode, err := g.operations.submitNodeApp(r.Context(), op, true); err != nil {
+		log.Errf("Error submitting operation: %v", err)
+		w.WriteHeader(statusCode)
+		_, err = w.Write([]byte(err.Error()))
+		if err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
 		return
 	}
+
+	writeOperationAccepted(w, op)
 }
 
 // Used for GET /nodes/{node_id}/apps/{app_id} endpoint
@@ -1752,7 +1850,28 @@ func (g *Gorilla) swagGETNodeAppsByID(w http.ResponseWriter, r *http.Request) {
 		NodeAppSummary: swagger.NodeAppSummary{
 			ID: nodeApps[0].(*cce.NodeApp).AppID,
 		},
-		Status: response.(*cce.NodeAppResp).Status,
+		Status:        response.(*cce.NodeAppResp).Status,
+		StatusReason:  response.(*cce.NodeAppResp).StatusReason,
+		StatusMessage: response.(*cce.NodeAppResp).StatusMessage,
+		VersionID:     nodeApps[0].(*cce.NodeApp).VersionID,
+		Tenant:        nodeApps[0].(*cce.NodeApp).Tenant,
+
+		TemplateID:         nodeApps[0].(*cce.NodeApp).TemplateID,
+		TemplateVersion:    nodeApps[0].(*cce.NodeApp).TemplateVersion,
+		TemplateParameters: nodeApps[0].(*cce.NodeApp).TemplateParameters,
+	}
+
+	// In Kubernetes mode the app is reached through its service
+	if ctrl.OrchestrationMode == cce.OrchestrationModeKubernetes ||
+		ctrl.OrchestrationMode == cce.OrchestrationModeKubernetesOVN {
+		addr, err := ctrl.KubernetesClient.ServiceAddress(r.Context(), nodeApps[0].(*cce.NodeApp).Tenant,
+			nodeApps[0].(*cce.NodeApp).NodeID, nodeApps[0].(*cce.NodeApp).AppID)
+		if err != nil {
+			log.Errf("Error getting app service: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		nodeAppDetail.Service = toNodeAppService(ctrl, nodeApps[0].(*cce.NodeApp).AppID, addr)
 	}
 
 	// Marshal the response object to JSON
@@ -1767,6 +1886,143 @@ func (g *Gorilla) swagGETNodeAppsByID(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// Used for GET /nodes/{node_id}/apps/{app_id}/events endpoint
+func (g *Gorilla) swagGETNodeAppEvents(w http.ResponseWriter, r *http.Request) {
+	// Load the controller to access the persistence
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+
+	// Filter nodes_apps to get the node app's tenant
+	nodeApps, err := ctrl.PersistenceService.Filter(
+		r.Context(),
+		&cce.NodeApp{},
+		[]cce.Filter{
+			{
+				Field: "node_id",
+				Value: mux.Vars(r)["node_id"],
+			},
+			{
+				Field: "app_id",
+				Value: mux.Vars(r)["app_id"],
+			},
+		})
+	if err != nil {
+		log.Errf("Error filtering node_apps: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if len(nodeApps) == 0 {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if len(nodeApps) > 1 {
+		log.Errf("Filter node_apps returned %d records", len(nodeApps))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	// Construct the response object
+	events := swagger.NodeAppEventList{Events: []swagger.NodeAppEvent{}}
+	for _, event := range ctrl.KubernetesClient.StatusEvents(r.Context(), nodeApps[0].(*cce.NodeApp).Tenant,
+		nodeApps[0].(*cce.NodeApp).NodeID, nodeApps[0].(*cce.NodeApp).AppID) {
+		events.Events = append(events.Events, swagger.NodeAppEvent{
+			Time:    event.Time,
+			Status:  string(event.Status),
+			Reason:  event.Reason,
+			Message: event.Message,
+		})
+	}
+
+	// Marshal the response object to JSON
+	eventsJSON, err := json.Marshal(events)
+	if err != nil {
+		log.Errf("Error marshaling response: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err = w.Write(eventsJSON); err != nil {
+		log.Errf("Error writing response: %v", err)
+	}
+}
+
+// Used for GET /nodes/{node_id}/apps/{app_id}/logs endpoint. The logs can be
+// read by the admin and the log reader, see requireAuthHandler, and every read
+// is audited.
+func (g *Gorilla) swagGETNodeAppLogs(w http.ResponseWriter, r *http.Request) {
+	// Load the controller to access the persistence
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+
+	opts, err := parseLogOptions(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Filter nodes_apps to get the node app's tenant
+	nodeApps, err := ctrl.PersistenceService.Filter(
+		r.Context(),
+		&cce.NodeApp{},
+		[]cce.Filter{
+			{
+				Field: "node_id",
+				Value: mux.Vars(r)["node_id"],
+			},
+			{
+				Field: "app_id",
+				Value: mux.Vars(r)["app_id"],
+			},
+		})
+	if err != nil {
+		log.Errf("Error filtering node_apps: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if len(nodeApps) == 0 {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if len(nodeApps) > 1 {
+		log.Errf("Filter node_apps returned %d records", len(nodeApps))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	nodeApp := nodeApps[0].(*cce.NodeApp)
+
+	logs, err := nodeAppLogs(r.Context(), ctrl, nodeApp, opts)
+	if err != nil {
+		log.Errf("Error getting logs of app %s on node %s: %v", nodeApp.AppID, nodeApp.NodeID, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	defer logs.Close()
+
+	// Errors of the node are only known once its logs are read. Followed logs
+	// may not be written for a long time, so their errors are only logged
+	// once the response is streaming.
+	body := bufio.NewReader(logs)
+	if !opts.Follow {
+		if _, err = body.Peek(1); err != nil && err != io.EOF {
+			log.Errf("Error reading logs of app %s on node %s: %v", nodeApp.AppID, nodeApp.NodeID, err)
+			if isNotFound(err) {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	}
+	audit(r, "read logs of app %s on node %s", nodeApp.AppID, nodeApp.NodeID)
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	if f, ok := w.(http.Flusher); ok {
+		f.Flush()
+	}
+	if _, err = io.Copy(flushWriter{w}, body); err != nil && r.Context().Err() == nil {
+		log.Errf("Error streaming logs of app %s on node %s: %v", nodeApp.AppID, nodeApp.NodeID, err)
+	}
+}
+
 // Used for PATCH /nodes/{node_id}/apps/{app_id} endpoint
 func (g *Gorilla) swagPATCHNodeAppsByID(w http.ResponseWriter, r *http.Request) {
 	// Load the controller to access the persistence and the payload
@@ -1831,17 +2087,25 @@ func (g *Gorilla) swagPATCHNodeAppsByID(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	code, err := handleUpdateNodesApps(r.Context(), ctrl.PersistenceService, &requested)
-	switch {
-	case code != 0:
-		log.Errf("Error updating remote entities: %v", err)
-		w.WriteHeader(code)
-		_, err = w.Write([]byte("error updating remote entity"))
+	// Send the lifecycle command to the node asynchronously
+	op := &cce.Operation{
+		Type:   requested.Cmd,
+		NodeID: requested.NodeID,
+		AppID:  requested.AppID,
+	}
+	// Check that no other operation is in progress for the node app and
+	// submit the operation at once
+	if statusCode, err := g.operations.submitNodeApp(r.Context(), op, true); err != nil {
+		log.Errf("Error submitting operation: %v", err)
+		w.WriteHeader(statusCode)
+		_, err = w.Write([]byte(err.Error()))
 		if err != nil {
 			log.Errf("Error writing response: %v", err)
 		}
 		return
 	}
+
+	writeOperationAccepted(w, op)
 }
 
 // Used for DELETE /nodes/{node_id}/apps/{app_id} endpoint
@@ -1893,28 +2157,26 @@ func (g *Gorilla) swagDELETENodeAppByID(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	// Delete the app from the node
-	if err = handleDeleteNodesApps(
-		r.Context(), ctrl.PersistenceService, nodeApps[0],
-	); err != nil {
-		log.Errf("Error making remote call: %v", err)
-		w.WriteHeader(http.StatusInternalServerError)
-		return
-	}
-
-	// Delete the resource
-	ok, err := ctrl.PersistenceService.Delete(r.Context(), nodeApps[0].(*cce.NodeApp).ID, &cce.NodeApp{})
-	if err != nil {
-		log.Errf("Error deleting entity: %v", err)
-		w.WriteHeader(http.StatusInternalServerError)
-		return
+	// Delete the app from the node asynchronously, the node app is deleted
+	// once the removal succeeds
+	op := &cce.Operation{
+		Type:   cce.OperationTypeUndeploy,
+		NodeID: mux.Vars(r)["node_id"],
+		AppID:  mux.Vars(r)["app_id"],
 	}
-	if !ok {
-		w.WriteHeader(http.StatusInternalServerError)
+	// Check that no other operation is in progress for the node app and
+	// submit the operation at once
+	if statusCode, err = g.operations.submitNodeApp(r.Context(), op, true); err != nil {
+		log.Errf("Error submitting operation: %v", err)
+		w.WriteHeader(statusCode)
+		_, err = w.Write([]byte(err.Error()))
+		if err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
 		return
 	}
 
-	w.WriteHeader(http.StatusNoContent)
+	writeOperationAccepted(w, op)
 }
 
 // Used for GET /nodes/{node_id}/apps/{app_id}/policy endpoint
@@ -2048,170 +2310,87 @@ func (g *Gorilla) swagPATCHNodeAppPolicy(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	// Connect to node
-	nodePort := ctrl.ELAPort
-	if nodePort == "" {
-		nodePort = defaultELAPort
+	// Queue the request behind the node's queued operations, if any
+	op := &cce.Operation{
+		Type:    cce.OperationTypeSetAppPolicy,
+		NodeID:  mux.Vars(r)["node_id"],
+		AppID:   mux.Vars(r)["app_id"],
+		Payload: body,
 	}
-	nodeCC, err := connectNode(
-		r.Context(),
-		ctrl.PersistenceService,
-		nodeApps[0].(*cce.NodeApp),
-		nodePort,
-		ctrl.EdgeNodeCreds)
-	if err != nil {
-		log.Errf("Error connecting to node: %v", err)
-		w.WriteHeader(http.StatusInternalServerError)
+	if g.writeQueuedBehind(w, r, op) {
 		return
 	}
 
-	// Make gRPC call to node to set the policy
-	if err = nodeCC.AppPolicySvcCli.Set(
+	// Set the policy on the node and persist the association
+	if err = handleUpdateNodesAppsPolicy(
 		r.Context(),
-		nodeApps[0].(*cce.NodeApp).AppID,
+		ctrl.PersistenceService,
+		nodeApps[0].(*cce.NodeApp),
 		policy.(*cce.TrafficPolicy),
 	); err != nil {
+		if g.writeQueuedOnUnreachable(w, r, op, err) {
+			return
+		}
 		log.Errf("Error setting policy: %v", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
+}
 
-	// Filter nodes_apps_traffic_policies to see if a record already exists
-	nodeAppPolicies, err := ctrl.PersistenceService.Filter(
+// Used for DELETE /nodes/{node_id}/apps/{app_id}/policy endpoint
+func (g *Gorilla) swagDELETENodeAppPolicy(w http.ResponseWriter, r *http.Request) {
+	// Load the controller to access the persistence and the payload
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+
+	// Filter nodes_apps to get the node_app_id
+	nodeApps, err := ctrl.PersistenceService.Filter(
 		r.Context(),
-		&cce.NodeAppTrafficPolicy{},
+		&cce.NodeApp{},
 		[]cce.Filter{
 			{
-				Field: "nodes_apps_id",
-				Value: nodeApps[0].GetID(),
+				Field: "node_id",
+				Value: mux.Vars(r)["node_id"],
+			},
+			{
+				Field: "app_id",
+				Value: mux.Vars(r)["app_id"],
 			},
 		})
 	if err != nil {
-		log.Errf("Error reading nodes_apps_traffic_policies: %v", err)
+		log.Errf("Error filtering node_apps: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if len(nodeApps) == 0 {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if len(nodeApps) > 1 {
+		log.Errf("Filter node_apps returned %d records", len(nodeApps))
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
-	// If it exists, delete it
-	if len(nodeAppPolicies) == 1 {
-		ok, err := ctrl.PersistenceService.Delete(r.Context(), nodeAppPolicies[0].GetID(), &cce.NodeAppTrafficPolicy{})
-		if err != nil {
-			log.Errf("Error deleting from nodes_apps_traffic_policies: %v", err)
-			w.WriteHeader(http.StatusInternalServerError)
-			return
-		}
-		if !ok {
-			log.Err("Did not delete 1 record from nodes_apps_traffic_policies")
-			w.WriteHeader(http.StatusInternalServerError)
-			return
-		}
-	}
-
-	// Convert the base resource to a persistable object
-	persisted := cce.NodeAppTrafficPolicy{
-		ID:              uuid.New(),
-		NodeAppID:       nodeApps[0].GetID(),
-		TrafficPolicyID: baseResource.ID,
-	}
-
-	// Persist the object
-	if err := ctrl.PersistenceService.Create(r.Context(), &persisted); err != nil {
-		log.Errf("Error creating entity: %v", err)
-		w.WriteHeader(http.StatusInternalServerError)
-		return
-	}
-}
-
-// Used for DELETE /nodes/{node_id}/apps/{app_id}/policy endpoint
-func (g *Gorilla) swagDELETENodeAppPolicy(w http.ResponseWriter, r *http.Request) {
-	// Load the controller to access the persistence and the payload
-	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
-
-	// Filter nodes_apps to get the node_app_id
-	nodeApps, err := ctrl.PersistenceService.Filter(
-		r.Context(),
-		&cce.NodeApp{},
-		[]cce.Filter{
-			{
-				Field: "node_id",
-				Value: mux.Vars(r)["node_id"],
-			},
-			{
-				Field: "app_id",
-				Value: mux.Vars(r)["app_id"],
-			},
-		})
-	if err != nil {
-		log.Errf("Error filtering node_apps: %v", err)
-		w.WriteHeader(http.StatusInternalServerError)
-		return
-	}
-	if len(nodeApps) == 0 {
-		w.WriteHeader(http.StatusNotFound)
-		return
-	}
-	if len(nodeApps) > 1 {
-		log.Errf("Filter node_apps returned %d records", len(nodeApps))
-		w.WriteHeader(http.StatusInternalServerError)
-		return
-	}
-
-	// Filter nodes_apps_traffic_policies to get the ID
-	nodeAppPolicies, err := ctrl.PersistenceService.Filter(
-		r.Context(),
-		&cce.NodeAppTrafficPolicy{},
-		[]cce.Filter{
-			{
-				Field: "nodes_apps_id",
-				Value: nodeApps[0].GetID(),
-			},
-		})
-	if err != nil {
-		log.Errf("Error reading nodes_apps_traffic_policies: %v", err)
-		w.WriteHeader(http.StatusInternalServerError)
-		return
-	}
-
-	// Connect to node
-	nodePort := ctrl.ELAPort
-	if nodePort == "" {
-		nodePort = defaultELAPort
+	// Queue the request behind the node's queued operations, if any
+	op := &cce.Operation{
+		Type:   cce.OperationTypeDeleteAppPolicy,
+		NodeID: mux.Vars(r)["node_id"],
+		AppID:  mux.Vars(r)["app_id"],
 	}
-	nodeCC, err := connectNode(
-		r.Context(),
-		ctrl.PersistenceService,
-		nodeApps[0].(*cce.NodeApp),
-		nodePort,
-		ctrl.EdgeNodeCreds)
-	if err != nil {
-		log.Errf("Error connecting to node: %v", err)
-		w.WriteHeader(http.StatusInternalServerError)
+	if g.writeQueuedBehind(w, r, op) {
 		return
 	}
 
-	// Make gRPC call to node to delete the policy
-	if err = nodeCC.AppPolicySvcCli.Delete(
-		r.Context(),
-		nodeApps[0].(*cce.NodeApp).AppID,
-	); err != nil {
+	// Delete the policy from the node and from persistence
+	if err = handleDeleteNodesAppsPolicy(r.Context(), ctrl.PersistenceService, nodeApps[0].(*cce.NodeApp)); err != nil {
+		if g.writeQueuedOnUnreachable(w, r, op, err) {
+			return
+		}
 		log.Errf("Error deleting policy: %v", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
-	// Delete the resource
-	ok, err := ctrl.PersistenceService.Delete(r.Context(), nodeAppPolicies[0].GetID(), &cce.NodeAppTrafficPolicy{})
-	if err != nil {
-		log.Errf("Error deleting from nodes_apps_traffic_policies: %v", err)
-		w.WriteHeader(http.StatusInternalServerError)
-		return
-	}
-	if !ok {
-		log.Err("Did not delete 1 record from nodes_apps_traffic_policies")
-		w.WriteHeader(http.StatusInternalServerError)
-		return
-	}
-
 	w.WriteHeader(http.StatusNoContent)
 }
 
@@ -2347,12 +2526,13 @@ func (g *Gorilla) swagPATCHNodeAppKubeOVNPolicy(w http.ResponseWriter, r *http.R
 	}
 
 	// Try delete network policy for app
-	_ = ctrl.KubernetesClient.DeleteNetworkPolicy(r.Context(), nodeApps[0].(*cce.NodeApp).NodeID,
-		nodeApps[0].(*cce.NodeApp).AppID)
+	_ = ctrl.KubernetesClient.DeleteNetworkPolicy(r.Context(), nodeApps[0].(*cce.NodeApp).Tenant,
+		nodeApps[0].(*cce.NodeApp).NodeID, nodeApps[0].(*cce.NodeApp).AppID)
 
 	// Apply new network policy for app
-	if err = ctrl.KubernetesClient.ApplyNetworkPolicy(r.Context(), nodeApps[0].(*cce.NodeApp).NodeID,
-		nodeApps[0].(*cce.NodeApp).AppID, policy.(*cce.TrafficPolicyKubeOVN).ToK8s(),
+	if err = ctrl.KubernetesClient.ApplyNetworkPolicy(r.Context(), nodeApps[0].(*cce.NodeApp).Tenant,
+		nodeApps[0].(*cce.NodeApp).NodeID, nodeApps[0].(*cce.NodeApp).AppID,
+		policy.(*cce.TrafficPolicyKubeOVN).ToK8s(),
 	); err != nil {
 		log.Errf("Error setting policy: %v", err)
 		w.WriteHeader(http.StatusInternalServerError)
@@ -2457,7 +2637,8 @@ func (g *Gorilla) swagDELETENodeAppKubeOVNPolicy(w http.ResponseWriter, r *http.
 
 	// Make gRPC call to node to delete the policy
 	if err = ctrl.KubernetesClient.DeleteNetworkPolicy(
-		r.Context(), nodeApps[0].(*cce.NodeApp).NodeID, nodeApps[0].(*cce.NodeApp).AppID,
+		r.Context(), nodeApps[0].(*cce.NodeApp).Tenant,
+		nodeApps[0].(*cce.NodeApp).NodeID, nodeApps[0].(*cce.NodeApp).AppID,
 	); err != nil {
 		log.Errf("Error deleting policy: %v", err)
 		w.WriteHeader(http.StatusInternalServerError)
@@ -2510,3 +2691,2863 @@ func (g *Gorilla) swagGETNodeNFDTags(w http.ResponseWriter, r *http.Request) {
 	}
 	fmt.Fprintf(w, "\n")
 }
+
+// Used for GET /nfd/features endpoint
+func (g *Gorilla) swagGETNfdFeatures(w http.ResponseWriter, r *http.Request) {
+	// Load the controller to access the persistence
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+
+	// Fetch the NFD features of all nodes from persistence
+	fleet, err := getFleetNfdFeatures(r.Context(), ctrl.PersistenceService)
+	if err != nil {
+		log.Errf("Error reading NFD features: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	// Marshal the response object to JSON
+	featuresJSON, err := json.Marshal(aggregateNfdFeatures(fleet))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err = w.Write(featuresJSON); err != nil {
+		log.Errf("Error writing response: %v", err)
+	}
+}
+
+// Used for GET /operations endpoint
+func (g *Gorilla) swagGETOperations(w http.ResponseWriter, r *http.Request) {
+	// Load the controller to access the persistence
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+
+	// Fetch the operations from persistence, optionally for a single node
+	var (
+		persisted []cce.Persistable
+		err       error
+	)
+	if nodeID := r.URL.Query().Get("node_id"); nodeID != "" {
+		persisted, err = ctrl.PersistenceService.Filter(
+			r.Context(),
+			&cce.Operation{},
+			[]cce.Filter{
+				{
+					Field: "node_id",
+					Value: nodeID,
+				},
+			})
+	} else {
+		persisted, err = ctrl.PersistenceService.ReadAll(r.Context(), &cce.Operation{})
+	}
+	if err != nil {
+		log.Errf("Error reading operations: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	// Construct the response object
+	ops := swagger.OperationList{Operations: []swagger.OperationSummary{}}
+	for _, op := range persisted {
+		ops.Operations = append(ops.Operations, toOperationSummary(op.(*cce.Operation)))
+	}
+
+	// Marshal the response object to JSON
+	opsJSON, err := json.Marshal(ops)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err = w.Write(opsJSON); err != nil {
+		log.Errf("Error writing response: %v", err)
+	}
+}
+
+// Used for GET /operations/{operation_id} endpoint
+func (g *Gorilla) swagGETOperationByID(w http.ResponseWriter, r *http.Request) {
+	// Load the controller to access the persistence
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+
+	// Fetch the entity from persistence and check if it's there
+	persisted, err := ctrl.PersistenceService.Read(r.Context(), mux.Vars(r)["operation_id"], &cce.Operation{})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if persisted == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	op := persisted.(*cce.Operation)
+
+	// Construct the response object
+	opDetail := swagger.OperationDetail{
+		OperationSummary: toOperationSummary(op),
+		Attempts:         op.Attempts,
+		Progress:         op.Progress(),
+		Error:            op.Error,
+		Transitions:      op.Transitions,
+		CreatedAt:        op.CreatedAt,
+		UpdatedAt:        op.UpdatedAt,
+	}
+
+	// Marshal the response object to JSON
+	opJSON, err := json.Marshal(opDetail)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err = w.Write(opJSON); err != nil {
+		log.Errf("Error writing response: %v", err)
+	}
+}
+
+// Used for GET /nodes/{node_id}/drift endpoint
+func (g *Gorilla) swagGETNodeDrift(w http.ResponseWriter, r *http.Request) {
+	// Load the controller to access the persistence
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+
+	// Fetch the node from persistence and check if it's there
+	node, err := ctrl.PersistenceService.Read(r.Context(), mux.Vars(r)["node_id"], &cce.Node{})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if node == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	// Reconcile the node now if requested, otherwise report the last result
+	var drift *cce.NodeDrift
+	if r.URL.Query().Get("refresh") == "true" {
+		if drift, err = g.reconciler.reconcile(r.Context(), node.(*cce.Node)); err != nil {
+			log.Errf("Error reconciling node: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			_, err = w.Write([]byte(err.Error()))
+			if err != nil {
+				log.Errf("Error writing response: %v", err)
+			}
+			return
+		}
+	} else {
+		var persisted []cce.Persistable
+		if persisted, err = ctrl.PersistenceService.Filter(
+			r.Context(),
+			&cce.NodeDrift{},
+			[]cce.Filter{
+				{
+					Field: "node_id",
+					Value: node.GetID(),
+				},
+			},
+		); err != nil {
+			log.Errf("Error reading node_drifts: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if len(persisted) != 0 {
+			drift = persisted[0].(*cce.NodeDrift)
+		}
+	}
+
+	// Construct the response object
+	driftDetail := swagger.NodeDriftDetail{
+		NodeID: node.GetID(),
+		Items:  []*cce.DriftItem{},
+	}
+	if drift != nil {
+		driftDetail.CheckedAt = &drift.CheckedAt
+		driftDetail.InSync = len(drift.Items) == 0
+		driftDetail.Items = append(driftDetail.Items, drift.Items...)
+	}
+
+	// Marshal the response object to JSON
+	driftJSON, err := json.Marshal(driftDetail)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err = w.Write(driftJSON); err != nil {
+		log.Errf("Error writing response: %v", err)
+	}
+}
+
+// Used for GET /orphans endpoint
+func (g *Gorilla) swagGETOrphans(w http.ResponseWriter, r *http.Request) {
+	// Reconcile now if requested, otherwise report the last result
+	report := g.orphans.lastReport()
+	if r.URL.Query().Get("refresh") == "true" {
+		var err error
+		if report, err = g.orphans.reconcileAll(r.Context()); err != nil {
+			log.Errf("Error reconciling Kubernetes objects of apps: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			_, err = w.Write([]byte(err.Error()))
+			if err != nil {
+				log.Errf("Error writing response: %v", err)
+			}
+			return
+		}
+	}
+	if report == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	// Marshal the response object to JSON
+	reportJSON, err := json.Marshal(report)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err = w.Write(reportJSON); err != nil {
+		log.Errf("Error writing response: %v", err)
+	}
+}
+
+// Used for GET /nodes/{node_id}/queue endpoint
+func (g *Gorilla) swagGETNodeQueue(w http.ResponseWriter, r *http.Request) {
+	// Load the controller to access the persistence
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+
+	// Fetch the node from persistence and check if it's there
+	node, err := ctrl.PersistenceService.Read(r.Context(), mux.Vars(r)["node_id"], &cce.Node{})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if node == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	// Fetch the queued operations in the order they will be replayed, then
+	// the operations deferred to a maintenance window
+	queued, err := g.operations.queuedOperations(r.Context(), node.GetID())
+	if err != nil {
+		log.Errf("Error reading queued operations: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	deferred, err := g.operations.deferredOperations(r.Context(), node.GetID())
+	if err != nil {
+		log.Errf("Error reading deferred operations: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	// Construct the response object
+	ops := swagger.OperationList{Operations: []swagger.OperationSummary{}}
+	for _, op := range append(queued, deferred...) {
+		ops.Operations = append(ops.Operations, toOperationSummary(op))
+	}
+
+	// Marshal the response object to JSON
+	opsJSON, err := json.Marshal(ops)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err = w.Write(opsJSON); err != nil {
+		log.Errf("Error writing response: %v", err)
+	}
+}
+
+// Used for DELETE /nodes/{node_id}/queue/{operation_id} endpoint
+func (g *Gorilla) swagDELETENodeQueuedOperation(w http.ResponseWriter, r *http.Request) {
+	// Load the controller to access the persistence
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+
+	// Fetch the operation from persistence and check it belongs to the node
+	persisted, err := ctrl.PersistenceService.Read(r.Context(), mux.Vars(r)["operation_id"], &cce.Operation{})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if persisted == nil || persisted.(*cce.Operation).NodeID != mux.Vars(r)["node_id"] {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	// Cancel the operation
+	if statusCode, err := g.operations.cancel(r.Context(), persisted.(*cce.Operation)); err != nil {
+		log.Errf("Error canceling operation: %v", err)
+		w.WriteHeader(statusCode)
+		_, err = w.Write([]byte(err.Error()))
+		if err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Used for POST /nodes/{node_id}/decommission endpoint
+func (g *Gorilla) swagPOSTNodeDecommission(w http.ResponseWriter, r *http.Request) {
+	// Load the controller to access the persistence
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+
+	// Fetch the node from persistence and check if it's there
+	node, err := ctrl.PersistenceService.Read(r.Context(), mux.Vars(r)["node_id"], &cce.Node{})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if node == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	// Check that the node is not being decommissioned already
+	if statusCode, err := checkDecommission(r.Context(), ctrl.PersistenceService, node.GetID()); err != nil {
+		log.Errf("Error running DB logic: %v", err)
+		w.WriteHeader(statusCode)
+		_, err = w.Write([]byte(err.Error()))
+		if err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
+		return
+	}
+
+	// Cancel the operations queued for the node as they will never be needed
+	queued, err := g.operations.queuedOperations(r.Context(), node.GetID())
+	if err != nil {
+		log.Errf("Error reading queued operations: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	for _, op := range queued {
+		if _, err = g.operations.cancel(r.Context(), op); err != nil {
+			log.Errf("Error canceling operation: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	}
+
+	// Decommission the node asynchronously
+	op := &cce.Operation{
+		Type:   cce.OperationTypeDecommission,
+		NodeID: node.GetID(),
+	}
+	if err = g.operations.submit(r.Context(), op); err != nil {
+		log.Errf("Error submitting operation: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	writeOperationAccepted(w, op)
+}
+
+// Used for GET /nodes/{node_id}/zones endpoint
+func (g *Gorilla) swagGETNodeZones(w http.ResponseWriter, r *http.Request) {
+	// Load the controller to access the persistence
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+
+	// Fetch the node from persistence and check if it's there
+	node, err := ctrl.PersistenceService.Read(r.Context(), mux.Vars(r)["node_id"], &cce.Node{})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if node == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	// Get the zones from the node
+	nodeZones, err := handleGetNodesZones(r.Context(), ctrl.PersistenceService, node)
+	if err != nil {
+		log.Errf("Error getting zones: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	// Construct the response object
+	zones := swagger.ZoneList{Zones: []swagger.ZoneSummary{}}
+	for _, zone := range nodeZones {
+		zones.Zones = append(zones.Zones, swagger.ZoneSummary{
+			ID:          zone.Id,
+			Description: zone.Description,
+		})
+	}
+
+	// Marshal the response object to JSON
+	zonesJSON, err := json.Marshal(zones)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err = w.Write(zonesJSON); err != nil {
+		log.Errf("Error writing response: %v", err)
+	}
+}
+
+// Used for POST /nodes/{node_id}/zones endpoint
+func (g *Gorilla) swagPOSTNodeZones(w http.ResponseWriter, r *http.Request) {
+	// Load the controller to access the persistence and the payload
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+	body := r.Context().Value(contextKey("body")).([]byte)
+
+	// Unmarshal the payload
+	var requested swagger.ZoneDetail
+	if err := json.Unmarshal(body, &requested); err != nil {
+		log.Errf("Error unmarshaling json: %v", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	// Fetch the node from persistence and check if it's there
+	node, err := ctrl.PersistenceService.Read(r.Context(), mux.Vars(r)["node_id"], &cce.Node{})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if node == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	// Convert it to a persistable object
+	zone := &cce.NodeZone{
+		ID:          uuid.New(),
+		NodeID:      node.GetID(),
+		ZoneID:      requested.ID,
+		Description: requested.Description,
+	}
+
+	// Validate the object
+	if err = zone.Validate(); err != nil {
+		log.Debugf("Validation failed for %#v: %v", zone, err)
+		w.WriteHeader(http.StatusBadRequest)
+		_, err = w.Write([]byte(fmt.Sprintf("Validation failed: %v", err)))
+		if err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
+		return
+	}
+
+	// Check that the zone is not defined yet
+	if statusCode, err := checkDBCreateNodesZones(r.Context(), ctrl.PersistenceService, zone); err != nil {
+		log.Errf("Error running DB logic: %v", err)
+		w.WriteHeader(statusCode)
+		_, err = w.Write([]byte(err.Error()))
+		if err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
+		return
+	}
+
+	// Create the zone on the node
+	if err = handleCreateNodesZones(r.Context(), ctrl.PersistenceService, zone); err != nil {
+		log.Errf("Error creating zone: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	// Persist the object
+	if err = ctrl.PersistenceService.Create(r.Context(), zone); err != nil {
+		log.Errf("Error creating entity: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	// Marshal the response object to JSON
+	idJSON, err := json.Marshal(swagger.BaseResource{ID: zone.ZoneID})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if _, err = w.Write(idJSON); err != nil {
+		log.Errf("Error writing response: %v", err)
+	}
+}
+
+// findNodeZone returns the persisted zone of a node or nil if the zone is not
+// defined.
+func findNodeZone(ctx context.Context, ps cce.PersistenceService, nodeID, zoneID string) (*cce.NodeZone, error) {
+	zones, err := ps.Filter(
+		ctx,
+		&cce.NodeZone{},
+		[]cce.Filter{
+			{
+				Field: "node_id",
+				Value: nodeID,
+			},
+			{
+				Field: "zone_id",
+				Value: zoneID,
+			},
+		})
+	if err != nil {
+		return nil, err
+	}
+	if len(zones) == 0 {
+		return nil, nil
+	}
+
+	return zones[0].(*cce.NodeZone), nil
+}
+
+// Used for GET /nodes/{node_id}/zones/{zone_id} endpoint
+func (g *Gorilla) swagGETNodeZoneByID(w http.ResponseWriter, r *http.Request) {
+	// Load the controller to access the persistence
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+
+	// Fetch the zone from persistence and check if it's there
+	zone, err := findNodeZone(r.Context(), ctrl.PersistenceService, mux.Vars(r)["node_id"], mux.Vars(r)["zone_id"])
+	if err != nil {
+		log.Errf("Error reading nodes_zones: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if zone == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	// Marshal the response object to JSON
+	zoneJSON, err := json.Marshal(swagger.ZoneDetail{
+		ZoneSummary: swagger.ZoneSummary{
+			ID:          zone.ZoneID,
+			Description: zone.Description,
+		},
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err = w.Write(zoneJSON); err != nil {
+		log.Errf("Error writing response: %v", err)
+	}
+}
+
+// Used for PATCH /nodes/{node_id}/zones/{zone_id} endpoint
+func (g *Gorilla) swagPATCHNodeZoneByID(w http.ResponseWriter, r *http.Request) {
+	// Load the controller to access the persistence and the payload
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+	body := r.Context().Value(contextKey("body")).([]byte)
+
+	// Unmarshal the payload
+	var requested swagger.ZoneDetail
+	if err := json.Unmarshal(body, &requested); err != nil {
+		log.Errf("Error unmarshaling json: %v", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	// Fetch the zone from persistence and check if it's there
+	zone, err := findNodeZone(r.Context(), ctrl.PersistenceService, mux.Vars(r)["node_id"], mux.Vars(r)["zone_id"])
+	if err != nil {
+		log.Errf("Error reading nodes_zones: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if zone == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	// The zone ID identifies the zone on the node and cannot be changed
+	if requested.ID != "" && requested.ID != zone.ZoneID {
+		w.WriteHeader(http.StatusBadRequest)
+		_, err = w.Write([]byte("Validation failed: id cannot be changed"))
+		if err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
+		return
+	}
+	zone.Description = requested.Description
+
+	// Update the zone on the node
+	if err = handleUpdateNodesZones(r.Context(), ctrl.PersistenceService, zone); err != nil {
+		log.Errf("Error updating zone: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	// Persist the object
+	if err = ctrl.PersistenceService.BulkUpdate(r.Context(), []cce.Persistable{zone}); err != nil {
+		log.Errf("Error updating entities: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// Used for DELETE /nodes/{node_id}/zones/{zone_id} endpoint
+func (g *Gorilla) swagDELETENodeZoneByID(w http.ResponseWriter, r *http.Request) {
+	// Load the controller to access the persistence
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+
+	// Fetch the zone from persistence and check if it's there
+	zone, err := findNodeZone(r.Context(), ctrl.PersistenceService, mux.Vars(r)["node_id"], mux.Vars(r)["zone_id"])
+	if err != nil {
+		log.Errf("Error reading nodes_zones: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if zone == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	// Check that no network interface is assigned to the zone
+	if statusCode, err := checkDBDeleteNodesZones(
+		r.Context(), ctrl.PersistenceService, zone.NodeID, zone.ZoneID,
+	); err != nil {
+		log.Errf("Error running DB logic: %v", err)
+		w.WriteHeader(statusCode)
+		_, err = w.Write([]byte(err.Error()))
+		if err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
+		return
+	}
+
+	// Delete the zone from the node
+	if err = handleDeleteNodesZones(r.Context(), ctrl.PersistenceService, zone); err != nil {
+		log.Errf("Error deleting zone: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	// Delete the resource
+	if _, err = ctrl.PersistenceService.Delete(r.Context(), zone.ID, &cce.NodeZone{}); err != nil {
+		log.Errf("Error deleting entity: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Used for GET /nodes/{node_id}/ports endpoint
+func (g *Gorilla) swagGETNodePorts(w http.ResponseWriter, r *http.Request) {
+	// Load the controller to access the persistence
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+
+	// Fetch the node from persistence and check if it's there
+	node, err := ctrl.PersistenceService.Read(r.Context(), mux.Vars(r)["node_id"], &cce.Node{})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if node == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	// Get the ports from the node
+	nodePorts, err := handleGetNodesPorts(r.Context(), ctrl.PersistenceService, node)
+	if err != nil {
+		log.Errf("Error getting ports: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	// Construct the response object
+	ports := swagger.PortList{Ports: []swagger.PortSummary{}}
+	for _, port := range nodePorts {
+		ports.Ports = append(ports.Ports, swagger.PortSummary{
+			PCI:        port.Pci,
+			Driver:     fromPBPortDriver(port.Driver),
+			Bridge:     port.Bridge,
+			MACAddress: port.MacAddress,
+		})
+	}
+
+	// Marshal the response object to JSON
+	portsJSON, err := json.Marshal(ports)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err = w.Write(portsJSON); err != nil {
+		log.Errf("Error writing response: %v", err)
+	}
+}
+
+// Used for POST /nodes/{node_id}/ports endpoint
+func (g *Gorilla) swagPOSTNodePorts(w http.ResponseWriter, r *http.Request) {
+	// Load the controller to access the persistence and the payload
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+	body := r.Context().Value(contextKey("body")).([]byte)
+
+	// Unmarshal the payload
+	var requested swagger.PortList
+	if err := json.Unmarshal(body, &requested); err != nil {
+		log.Errf("Error unmarshaling json: %v", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	// Fetch the node from persistence and check if it's there
+	node, err := ctrl.PersistenceService.Read(r.Context(), mux.Vars(r)["node_id"], &cce.Node{})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if node == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	// Convert them to persistable objects and validate them
+	var ports []*cce.NodePort
+	for _, requestedPort := range requested.Ports {
+		port := &cce.NodePort{
+			ID:     uuid.New(),
+			NodeID: node.GetID(),
+			PCI:    requestedPort.PCI,
+			Bridge: requestedPort.Bridge,
+			Driver: requestedPort.Driver,
+		}
+		if port.Bridge == "" {
+			port.Bridge = defaultOVSBridge
+		}
+
+		if err = port.Validate(); err != nil {
+			log.Debugf("Validation failed for %#v: %v", port, err)
+			w.WriteHeader(http.StatusBadRequest)
+			_, err = w.Write([]byte(fmt.Sprintf("Validation failed: %v", err)))
+			if err != nil {
+				log.Errf("Error writing response: %v", err)
+			}
+			return
+		}
+		ports = append(ports, port)
+	}
+	if len(ports) == 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		_, err = w.Write([]byte("Validation failed: ports cannot be empty"))
+		if err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
+		return
+	}
+
+	// Check that the ports exist on the node
+	if statusCode, err := checkNodePortsExist(r.Context(), ctrl.PersistenceService, node, ports); err != nil {
+		log.Errf("Error checking ports: %v", err)
+		w.WriteHeader(statusCode)
+		_, err = w.Write([]byte(err.Error()))
+		if err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
+		return
+	}
+
+	// Attach the ports and record the bridge membership
+	if err = handleAttachNodesPorts(r.Context(), ctrl.PersistenceService, node.(*cce.Node), ports); err != nil {
+		log.Errf("Error attaching ports: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	for _, port := range ports {
+		audit(r, "attached port %s of node %s to bridge %s with driver %s",
+			port.PCI, port.NodeID, port.Bridge, port.Driver)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Used for DELETE /nodes/{node_id}/ports/{pci} endpoint
+func (g *Gorilla) swagDELETENodePortByPCI(w http.ResponseWriter, r *http.Request) {
+	// Load the controller to access the persistence
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+
+	// Fetch the node from persistence and check if it's there
+	node, err := ctrl.PersistenceService.Read(r.Context(), mux.Vars(r)["node_id"], &cce.Node{})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if node == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	// Check that the port exists on the node
+	pci := mux.Vars(r)["pci"]
+	if statusCode, err := checkNodePortsExist(
+		r.Context(), ctrl.PersistenceService, node, []*cce.NodePort{{PCI: pci}},
+	); err != nil {
+		log.Errf("Error checking ports: %v", err)
+		if statusCode == http.StatusUnprocessableEntity {
+			statusCode = http.StatusNotFound
+		}
+		w.WriteHeader(statusCode)
+		return
+	}
+
+	// Detach the port and forget its bridge membership
+	if err = handleDetachNodesPorts(r.Context(), ctrl.PersistenceService, node.(*cce.Node), pci); err != nil {
+		log.Errf("Error detaching port: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	audit(r, "detached port %s of node %s", pci, node.GetID())
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// findNodePort returns the persisted bridge membership of a node's port or nil
+// if the controller did not attach the port.
+func findNodePort(ctx context.Context, ps cce.PersistenceService, nodeID, pci string) (*cce.NodePort, error) {
+	ports, err := ps.Filter(
+		ctx,
+		&cce.NodePort{},
+		[]cce.Filter{
+			{
+				Field: "node_id",
+				Value: nodeID,
+			},
+			{
+				Field: "pci",
+				Value: pci,
+			},
+		})
+	if err != nil {
+		return nil, err
+	}
+	if len(ports) == 0 {
+		return nil, nil
+	}
+
+	return ports[0].(*cce.NodePort), nil
+}
+
+// checkNodePortsExist returns an error if any of the ports is not reported by
+// the node.
+func checkNodePortsExist(
+	ctx context.Context,
+	ps cce.PersistenceService,
+	node cce.Persistable,
+	ports []*cce.NodePort,
+) (statusCode int, err error) {
+	nodePorts, err := handleGetNodesPorts(ctx, ps, node)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	pcis := make(map[string]bool)
+	for _, port := range nodePorts {
+		pcis[port.Pci] = true
+	}
+	for _, port := range ports {
+		if !pcis[port.PCI] {
+			return http.StatusUnprocessableEntity, fmt.Errorf(
+				"port %s not found on node %s", port.PCI, node.GetID())
+		}
+	}
+
+	return 0, nil
+}
+
+// Used for PATCH /nodes/{node_id}/capacity endpoint
+func (g *Gorilla) swagPATCHNodeCapacity(w http.ResponseWriter, r *http.Request) {
+	// Load the controller to access the persistence and the payload
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+	body := r.Context().Value(contextKey("body")).([]byte)
+
+	// Unmarshal the payload
+	var resources swagger.NodeResources
+	if err := json.Unmarshal(body, &resources); err != nil {
+		log.Errf("Error unmarshaling json: %v", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	// Fetch the node from persistence and check if it's there
+	node, err := ctrl.PersistenceService.Read(r.Context(), mux.Vars(r)["node_id"], &cce.Node{})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if node == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	// Convert it to a persistable object, replacing the capacity set before
+	capacity, err := findNodeCapacity(r.Context(), ctrl.PersistenceService, node.GetID())
+	if err != nil {
+		log.Errf("Error finding node capacity: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	exists := capacity != nil
+	if !exists {
+		capacity = &cce.NodeCapacity{
+			ID:     uuid.New(),
+			NodeID: node.GetID(),
+		}
+	}
+	capacity.Cores = resources.Cores
+	capacity.Memory = resources.Memory
+	capacity.Hugepages = resources.Hugepages
+
+	// Validate the object
+	if err = capacity.Validate(); err != nil {
+		log.Debugf("Validation failed for %#v: %v", capacity, err)
+		w.WriteHeader(http.StatusBadRequest)
+		_, err = w.Write([]byte(fmt.Sprintf("Validation failed: %v", err)))
+		if err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
+		return
+	}
+
+	// Persist the object
+	if exists {
+		err = ctrl.PersistenceService.BulkUpdate(r.Context(), []cce.Persistable{capacity})
+	} else {
+		err = ctrl.PersistenceService.Create(r.Context(), capacity)
+	}
+	if err != nil {
+		log.Errf("Error persisting node capacity: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	audit(r, "set capacity of node %s to %d cores, %d MB memory and %d MB hugepages",
+		capacity.NodeID, capacity.Cores, capacity.Memory, capacity.Hugepages)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Used for DELETE /nodes/{node_id}/capacity endpoint
+func (g *Gorilla) swagDELETENodeCapacity(w http.ResponseWriter, r *http.Request) {
+	// Load the controller to access the persistence
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+
+	// Fetch the capacity from persistence and check if it's there
+	capacity, err := findNodeCapacity(r.Context(), ctrl.PersistenceService, mux.Vars(r)["node_id"])
+	if err != nil {
+		log.Errf("Error finding node capacity: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if capacity == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	// Delete the capacity so the node's reported resources are used again
+	if _, err = ctrl.PersistenceService.Delete(r.Context(), capacity.ID, &cce.NodeCapacity{}); err != nil {
+		log.Errf("Error deleting node capacity: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	audit(r, "cleared capacity of node %s", capacity.NodeID)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Used for POST /apps/{app_id}/upgrade endpoint
+func (g *Gorilla) swagPOSTAppUpgrade(w http.ResponseWriter, r *http.Request) {
+	// Load the controller to access the persistence and the payload
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+	body := r.Context().Value(contextKey("body")).([]byte)
+
+	// Unmarshal the payload
+	var req swagger.AppUpgradeRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		log.Errf("Error unmarshaling json: %v", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	// Validate the request
+	if err := validateAppUpgradeRequest(&req); err != nil {
+		log.Debugf("Validation failed for %#v: %v", req, err)
+		w.WriteHeader(http.StatusBadRequest)
+		_, err = w.Write([]byte(fmt.Sprintf("Validation failed: %v", err)))
+		if err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
+		return
+	}
+
+	// Fetch the app from persistence and check if it's there
+	app, err := ctrl.PersistenceService.Read(r.Context(), mux.Vars(r)["app_id"], &cce.App{})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if app == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	// Find the nodes to upgrade
+	upgrade, statusCode, err := newAppUpgrade(r.Context(), ctrl.PersistenceService, app.(*cce.App), &req)
+	if err != nil {
+		log.Errf("Error running DB logic: %v", err)
+		w.WriteHeader(statusCode)
+		_, err = w.Write([]byte(err.Error()))
+		if err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
+		return
+	}
+
+	// Roll the upgrade out asynchronously
+	if err = g.operations.startUpgrade(r.Context(), upgrade); err != nil {
+		log.Errf("Error starting upgrade: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	audit(r, "upgrading app %s on %d node(s) to version %s in batches of %d",
+		upgrade.AppID, len(upgrade.Nodes), req.Version, upgrade.BatchSize)
+
+	// Marshal the response object to JSON
+	upgradeJSON, err := json.Marshal(toAppUpgradeSummary(upgrade))
+	if err != nil {
+		log.Errf("Error marshaling response: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", upgradeURL(upgrade.ID))
+	w.WriteHeader(http.StatusAccepted)
+	if _, err = w.Write(upgradeJSON); err != nil {
+		log.Errf("Error writing response: %v", err)
+	}
+}
+
+// Used for GET /upgrades/{upgrade_id} endpoint
+func (g *Gorilla) swagGETUpgradeByID(w http.ResponseWriter, r *http.Request) {
+	// Load the controller to access the persistence
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+
+	// Fetch the entity from persistence and check if it's there
+	persisted, err := ctrl.PersistenceService.Read(r.Context(), mux.Vars(r)["upgrade_id"], &cce.AppUpgrade{})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if persisted == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	upgrade := persisted.(*cce.AppUpgrade)
+
+	// Construct the response object
+	upgradeDetail := swagger.AppUpgradeDetail{
+		AppUpgradeSummary: toAppUpgradeSummary(upgrade),
+		BatchSize:         upgrade.BatchSize,
+		Error:             upgrade.Error,
+		Nodes:             upgrade.Nodes,
+		CreatedAt:         upgrade.CreatedAt,
+		UpdatedAt:         upgrade.UpdatedAt,
+	}
+
+	// Marshal the response object to JSON
+	upgradeJSON, err := json.Marshal(upgradeDetail)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err = w.Write(upgradeJSON); err != nil {
+		log.Errf("Error writing response: %v", err)
+	}
+}
+
+// Used for GET /registries endpoint
+func (g *Gorilla) swagGETRegistries(w http.ResponseWriter, r *http.Request) {
+	// Load the controller to access the persistence
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+
+	// Fetch the registries from persistence
+	persisted, err := ctrl.PersistenceService.ReadAll(r.Context(), &cce.Registry{})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	// Construct the response object
+	registries := swagger.RegistryList{Registries: []swagger.RegistrySummary{}}
+	for _, e := range persisted {
+		registries.Registries = append(registries.Registries, swagger.RegistrySummary{
+			ID:   e.(*cce.Registry).ID,
+			Host: e.(*cce.Registry).Host,
+		})
+	}
+
+	// Marshal the response object to JSON
+	registriesJSON, err := json.Marshal(registries)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err = w.Write(registriesJSON); err != nil {
+		log.Errf("Error writing response: %v", err)
+	}
+}
+
+// Used for POST /registries endpoint
+func (g *Gorilla) swagPOSTRegistries(w http.ResponseWriter, r *http.Request) {
+	g.registriesHandler.create(w, r)
+}
+
+// Used for GET /registries/{registry_id} endpoint
+func (g *Gorilla) swagGETRegistryByID(w http.ResponseWriter, r *http.Request) {
+	// Load the controller to access the persistence
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+
+	// Fetch the entity from persistence and check if it's there
+	persisted, err := ctrl.PersistenceService.Read(r.Context(), mux.Vars(r)["registry_id"], &cce.Registry{})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if persisted == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	// Construct the response object, without the password
+	registry := swagger.RegistryDetail{
+		RegistrySummary: swagger.RegistrySummary{
+			ID:   persisted.(*cce.Registry).ID,
+			Host: persisted.(*cce.Registry).Host,
+		},
+		Username: persisted.(*cce.Registry).Username,
+	}
+
+	// Marshal the response object to JSON
+	registryJSON, err := json.Marshal(registry)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err = w.Write(registryJSON); err != nil {
+		log.Errf("Error writing response: %v", err)
+	}
+}
+
+// Used for PATCH /registries/{registry_id} endpoint
+func (g *Gorilla) swagPATCHRegistryByID(w http.ResponseWriter, r *http.Request) {
+	// Load the controller to access the persistence and the payload
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+	body := r.Context().Value(contextKey("body")).([]byte)
+
+	// Unmarshal the payload
+	registry := swagger.RegistryDetail{}
+	if err := json.Unmarshal(body, &registry); err != nil {
+		log.Errf("Error unmarshaling json: %v", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	// Fetch the entity from persistence and check if it's there
+	persisted, err := ctrl.PersistenceService.Read(r.Context(), mux.Vars(r)["registry_id"], &cce.Registry{})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if persisted == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	// Convert it to a persistable object. The password is never returned, so
+	// the persisted one is kept unless a new one is given.
+	updated := cce.Registry{
+		ID:       persisted.GetID(),
+		Host:     registry.Host,
+		Username: registry.Username,
+		Password: registry.Password,
+	}
+	if updated.Password == "" {
+		updated.Password = persisted.(*cce.Registry).Password
+	}
+
+	// Validate the object
+	if err = updated.Validate(); err != nil {
+		log.Debugf("Validation failed for %v: %v", &updated, err)
+		w.WriteHeader(http.StatusBadRequest)
+		_, err = w.Write([]byte(fmt.Sprintf("Validation failed: %v", err)))
+		if err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
+		return
+	}
+	if statusCode, err := checkDBCreateRegistries(r.Context(), ctrl.PersistenceService, &updated); err != nil {
+		log.Errf("Error running DB logic: %v", err)
+		w.WriteHeader(statusCode)
+		_, err = w.Write([]byte(err.Error()))
+		if err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
+		return
+	}
+
+	// Persist the object
+	if err = ctrl.PersistenceService.BulkUpdate(r.Context(), []cce.Persistable{&updated}); err != nil {
+		log.Errf("Error updating entities: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// Used for DELETE /registries/{registry_id} endpoint. The pull secrets created
+// from the registry are kept until their namespace is deleted.
+func (g *Gorilla) swagDELETERegistryByID(w http.ResponseWriter, r *http.Request) {
+	// Load the controller to access the persistence
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+
+	// Fetch the entity from persistence and check if it's there
+	persisted, err := ctrl.PersistenceService.Read(r.Context(), mux.Vars(r)["registry_id"], &cce.Registry{})
+	if err != nil {
+		log.Errf("Error reading entity: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if persisted == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	ok, err := ctrl.PersistenceService.Delete(r.Context(), mux.Vars(r)["registry_id"], &cce.Registry{})
+	if err != nil {
+		log.Errf("Error deleting entity: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	// we just fetched the entity, so if !ok then something went wrong
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// Used for GET /networks endpoint
+func (g *Gorilla) swagGETNetworks(w http.ResponseWriter, r *http.Request) {
+	// Load the controller to access the persistence
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+
+	// Fetch the networks from persistence
+	persisted, err := ctrl.PersistenceService.ReadAll(r.Context(), &cce.Network{})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	// Construct the response object
+	networks := swagger.NetworkList{Networks: []swagger.NetworkSummary{}}
+	for _, e := range persisted {
+		networks.Networks = append(networks.Networks, swagger.NetworkSummary{
+			ID:   e.(*cce.Network).ID,
+			Name: e.(*cce.Network).Name,
+		})
+	}
+
+	// Marshal the response object to JSON
+	networksJSON, err := json.Marshal(networks)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err = w.Write(networksJSON); err != nil {
+		log.Errf("Error writing response: %v", err)
+	}
+}
+
+// Used for POST /networks endpoint
+func (g *Gorilla) swagPOSTNetworks(w http.ResponseWriter, r *http.Request) {
+	g.networksHandler.create(w, r)
+}
+
+// Used for GET /networks/{network_id} endpoint
+func (g *Gorilla) swagGETNetworkByID(w http.ResponseWriter, r *http.Request) {
+	// Load the controller to access the persistence
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+
+	// Fetch the entity from persistence and check if it's there
+	persisted, err := ctrl.PersistenceService.Read(r.Context(), mux.Vars(r)["network_id"], &cce.Network{})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if persisted == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	// Construct the response object
+	network := swagger.NetworkDetail{
+		NetworkSummary: swagger.NetworkSummary{
+			ID:   persisted.(*cce.Network).ID,
+			Name: persisted.(*cce.Network).Name,
+		},
+		CNIConfig:     persisted.(*cce.Network).CNIConfig,
+		InterfaceName: persisted.(*cce.Network).InterfaceName,
+		Args:          persisted.(*cce.Network).Args,
+	}
+
+	// Marshal the response object to JSON
+	networkJSON, err := json.Marshal(network)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err = w.Write(networkJSON); err != nil {
+		log.Errf("Error writing response: %v", err)
+	}
+}
+
+// Used for PATCH /networks/{network_id} endpoint. Apps attached to the network
+// use the updated network when they are next deployed.
+func (g *Gorilla) swagPATCHNetworkByID(w http.ResponseWriter, r *http.Request) {
+	// Load the controller to access the persistence and the payload
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+	body := r.Context().Value(contextKey("body")).([]byte)
+
+	// Unmarshal the payload
+	network := swagger.NetworkDetail{}
+	if err := json.Unmarshal(body, &network); err != nil {
+		log.Errf("Error unmarshaling json: %v", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	// Fetch the entity from persistence and check if it's there
+	persisted, err := ctrl.PersistenceService.Read(r.Context(), mux.Vars(r)["network_id"], &cce.Network{})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if persisted == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	// Convert it to a persistable object
+	updated := cce.Network{
+		ID:            persisted.GetID(),
+		Name:          network.Name,
+		CNIConfig:     network.CNIConfig,
+		InterfaceName: network.InterfaceName,
+		Args:          network.Args,
+	}
+
+	// Validate the object
+	if err = updated.Validate(); err != nil {
+		log.Debugf("Validation failed for %v: %v", &updated, err)
+		w.WriteHeader(http.StatusBadRequest)
+		_, err = w.Write([]byte(fmt.Sprintf("Validation failed: %v", err)))
+		if err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
+		return
+	}
+	if statusCode, err := checkDBCreateNetworks(r.Context(), ctrl.PersistenceService, &updated); err != nil {
+		log.Errf("Error running DB logic: %v", err)
+		w.WriteHeader(statusCode)
+		_, err = w.Write([]byte(err.Error()))
+		if err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
+		return
+	}
+
+	// Persist the object
+	if err = ctrl.PersistenceService.BulkUpdate(r.Context(), []cce.Persistable{&updated}); err != nil {
+		log.Errf("Error updating entities: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// Used for DELETE /networks/{network_id} endpoint
+func (g *Gorilla) swagDELETENetworkByID(w http.ResponseWriter, r *http.Request) {
+	// Load the controller to access the persistence
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+
+	// Check that we can delete the entity
+	if statusCode, err := checkDBDeleteNetworks(
+		r.Context(),
+		ctrl.PersistenceService,
+		mux.Vars(r)["network_id"]); err != nil {
+		log.Errf("Error running DB logic: %v", err)
+		w.WriteHeader(statusCode)
+		_, err = w.Write([]byte(err.Error()))
+		if err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
+		return
+	}
+
+	// Fetch the entity from persistence and check if it's there
+	persisted, err := ctrl.PersistenceService.Read(r.Context(), mux.Vars(r)["network_id"], &cce.Network{})
+	if err != nil {
+		log.Errf("Error reading entity: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if persisted == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	ok, err := ctrl.PersistenceService.Delete(r.Context(), mux.Vars(r)["network_id"], &cce.Network{})
+	if err != nil {
+		log.Errf("Error deleting entity: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	// we just fetched the entity, so if !ok then something went wrong
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// findNodeMaintenanceWindow returns the persisted maintenance window of a node
+// or nil if the node has no such window.
+func findNodeMaintenanceWindow(
+	ctx context.Context,
+	ps cce.PersistenceService,
+	nodeID string,
+	windowID string,
+) (*cce.NodeMaintenanceWindow, error) {
+	e, err := ps.Read(ctx, windowID, &cce.NodeMaintenanceWindow{})
+	if err != nil {
+		return nil, err
+	}
+	if e == nil || e.(*cce.NodeMaintenanceWindow).NodeID != nodeID {
+		return nil, nil
+	}
+
+	return e.(*cce.NodeMaintenanceWindow), nil
+}
+
+func toMaintenanceWindowSummary(window *cce.NodeMaintenanceWindow, now time.Time) swagger.MaintenanceWindowSummary {
+	return swagger.MaintenanceWindowSummary{
+		ID:       window.ID,
+		Cron:     window.Cron,
+		TimeZone: window.TimeZone,
+		Duration: window.Duration,
+		Open:     window.OpenAt(now),
+	}
+}
+
+// Used for GET /nodes/{node_id}/maintenance_windows endpoint
+func (g *Gorilla) swagGETNodeMaintenanceWindows(w http.ResponseWriter, r *http.Request) {
+	// Load the controller to access the persistence
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+
+	// Fetch the node from persistence and check if it's there
+	node, err := ctrl.PersistenceService.Read(r.Context(), mux.Vars(r)["node_id"], &cce.Node{})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if node == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	// Fetch the maintenance windows of the node
+	persisted, err := maintenanceWindows(r.Context(), ctrl.PersistenceService, node.GetID())
+	if err != nil {
+		log.Errf("Error reading maintenance windows: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	// Construct the response object
+	now := time.Now()
+	windows := swagger.MaintenanceWindowList{MaintenanceWindows: []swagger.MaintenanceWindowSummary{}}
+	for _, window := range persisted {
+		windows.MaintenanceWindows = append(windows.MaintenanceWindows, toMaintenanceWindowSummary(window, now))
+	}
+
+	// Marshal the response object to JSON
+	windowsJSON, err := json.Marshal(windows)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err = w.Write(windowsJSON); err != nil {
+		log.Errf("Error writing response: %v", err)
+	}
+}
+
+// Used for POST /nodes/{node_id}/maintenance_windows endpoint
+func (g *Gorilla) swagPOSTNodeMaintenanceWindows(w http.ResponseWriter, r *http.Request) {
+	// Load the controller to access the persistence and the payload
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+	body := r.Context().Value(contextKey("body")).([]byte)
+
+	// Unmarshal the payload
+	var requested swagger.MaintenanceWindowDetail
+	if err := json.Unmarshal(body, &requested); err != nil {
+		log.Errf("Error unmarshaling json: %v", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	// Fetch the node from persistence and check if it's there
+	node, err := ctrl.PersistenceService.Read(r.Context(), mux.Vars(r)["node_id"], &cce.Node{})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if node == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	if requested.ID != "" {
+		w.WriteHeader(http.StatusBadRequest)
+		_, err = w.Write([]byte("Validation failed: id cannot be specified in POST request"))
+		if err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
+		return
+	}
+
+	// Convert it to a persistable object
+	window := &cce.NodeMaintenanceWindow{
+		ID:       uuid.New(),
+		NodeID:   node.GetID(),
+		Cron:     requested.Cron,
+		TimeZone: requested.TimeZone,
+		Duration: requested.Duration,
+	}
+
+	// Validate the object
+	if err = window.Validate(); err != nil {
+		log.Debugf("Validation failed for %#v: %v", window, err)
+		w.WriteHeader(http.StatusBadRequest)
+		_, err = w.Write([]byte(fmt.Sprintf("Validation failed: %v", err)))
+		if err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
+		return
+	}
+
+	// Persist the object
+	if err = ctrl.PersistenceService.Create(r.Context(), window); err != nil {
+		log.Errf("Error creating entity: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	// Marshal the response object to JSON
+	idJSON, err := json.Marshal(swagger.BaseResource{ID: window.ID})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if _, err = w.Write(idJSON); err != nil {
+		log.Errf("Error writing response: %v", err)
+	}
+}
+
+// Used for GET /nodes/{node_id}/maintenance_windows/{window_id} endpoint
+func (g *Gorilla) swagGETNodeMaintenanceWindowByID(w http.ResponseWriter, r *http.Request) {
+	// Load the controller to access the persistence
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+
+	// Fetch the window from persistence and check if it's there
+	window, err := findNodeMaintenanceWindow(
+		r.Context(), ctrl.PersistenceService, mux.Vars(r)["node_id"], mux.Vars(r)["window_id"])
+	if err != nil {
+		log.Errf("Error reading nodes_maintenance_windows: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if window == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	// Construct the response object
+	now := time.Now()
+	detail := swagger.MaintenanceWindowDetail{
+		MaintenanceWindowSummary: toMaintenanceWindowSummary(window, now),
+	}
+	if next := window.NextOpen(now); !next.IsZero() {
+		detail.NextOpenAt = &next
+	}
+
+	// Marshal the response object to JSON
+	windowJSON, err := json.Marshal(detail)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err = w.Write(windowJSON); err != nil {
+		log.Errf("Error writing response: %v", err)
+	}
+}
+
+// Used for PATCH /nodes/{node_id}/maintenance_windows/{window_id} endpoint.
+// Operations deferred to the window are executed when the updated window
+// opens.
+func (g *Gorilla) swagPATCHNodeMaintenanceWindowByID(w http.ResponseWriter, r *http.Request) {
+	// Load the controller to access the persistence and the payload
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+	body := r.Context().Value(contextKey("body")).([]byte)
+
+	// Unmarshal the payload
+	var requested swagger.MaintenanceWindowDetail
+	if err := json.Unmarshal(body, &requested); err != nil {
+		log.Errf("Error unmarshaling json: %v", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	// Fetch the window from persistence and check if it's there
+	window, err := findNodeMaintenanceWindow(
+		r.Context(), ctrl.PersistenceService, mux.Vars(r)["node_id"], mux.Vars(r)["window_id"])
+	if err != nil {
+		log.Errf("Error reading nodes_maintenance_windows: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if window == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	// Convert it to a persistable object
+	updated := cce.NodeMaintenanceWindow{
+		ID:       window.ID,
+		NodeID:   window.NodeID,
+		Cron:     requested.Cron,
+		TimeZone: requested.TimeZone,
+		Duration: requested.Duration,
+	}
+
+	// Validate the object
+	if err = updated.Validate(); err != nil {
+		log.Debugf("Validation failed for %v: %v", &updated, err)
+		w.WriteHeader(http.StatusBadRequest)
+		_, err = w.Write([]byte(fmt.Sprintf("Validation failed: %v", err)))
+		if err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
+		return
+	}
+
+	// Persist the object
+	if err = ctrl.PersistenceService.BulkUpdate(r.Context(), []cce.Persistable{&updated}); err != nil {
+		log.Errf("Error updating entities: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// Used for DELETE /nodes/{node_id}/maintenance_windows/{window_id} endpoint.
+// Operations deferred for a node without windows are executed at once.
+func (g *Gorilla) swagDELETENodeMaintenanceWindowByID(w http.ResponseWriter, r *http.Request) {
+	// Load the controller to access the persistence
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+
+	// Fetch the window from persistence and check if it's there
+	window, err := findNodeMaintenanceWindow(
+		r.Context(), ctrl.PersistenceService, mux.Vars(r)["node_id"], mux.Vars(r)["window_id"])
+	if err != nil {
+		log.Errf("Error reading nodes_maintenance_windows: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if window == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	ok, err := ctrl.PersistenceService.Delete(r.Context(), window.ID, &cce.NodeMaintenanceWindow{})
+	if err != nil {
+		log.Errf("Error deleting entity: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	// we just fetched the entity, so if !ok then something went wrong
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// Used for GET /schedules endpoint
+func (g *Gorilla) swagGETSchedules(w http.ResponseWriter, r *http.Request) {
+	// Load the controller to access the persistence
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+
+	// Fetch the schedules from persistence
+	persisted, err := ctrl.PersistenceService.ReadAll(r.Context(), &cce.Schedule{})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	// Construct the response object
+	schedules := swagger.ScheduleList{Schedules: []swagger.ScheduleSummary{}}
+	for _, e := range persisted {
+		schedules.Schedules = append(schedules.Schedules, swagger.ScheduleSummary{
+			ID:     e.(*cce.Schedule).ID,
+			NodeID: e.(*cce.Schedule).NodeID,
+			AppID:  e.(*cce.Schedule).AppID,
+			Cmd:    e.(*cce.Schedule).Cmd,
+			Cron:   e.(*cce.Schedule).Cron,
+		})
+	}
+
+	// Marshal the response object to JSON
+	schedulesJSON, err := json.Marshal(schedules)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err = w.Write(schedulesJSON); err != nil {
+		log.Errf("Error writing response: %v", err)
+	}
+}
+
+// Used for POST /schedules endpoint
+func (g *Gorilla) swagPOSTSchedules(w http.ResponseWriter, r *http.Request) {
+	g.schedulesHandler.create(w, r)
+}
+
+// Used for GET /schedules/{schedule_id} endpoint
+func (g *Gorilla) swagGETScheduleByID(w http.ResponseWriter, r *http.Request) {
+	// Load the controller to access the persistence
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+
+	// Fetch the entity from persistence and check if it's there
+	persisted, err := ctrl.PersistenceService.Read(r.Context(), mux.Vars(r)["schedule_id"], &cce.Schedule{})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if persisted == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	s := persisted.(*cce.Schedule)
+
+	// Construct the response object
+	schedule := swagger.ScheduleDetail{
+		ScheduleSummary: swagger.ScheduleSummary{
+			ID:     s.ID,
+			NodeID: s.NodeID,
+			AppID:  s.AppID,
+			Cmd:    s.Cmd,
+			Cron:   s.Cron,
+		},
+		TimeZone:        s.TimeZone,
+		LastRunAt:       s.LastRunAt,
+		LastOperationID: s.LastOperationID,
+	}
+	if cron, err := s.CronSchedule(); err == nil {
+		if next := cron.Next(time.Now()); !next.IsZero() {
+			schedule.NextRunAt = &next
+		}
+	}
+
+	// Marshal the response object to JSON
+	scheduleJSON, err := json.Marshal(schedule)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err = w.Write(scheduleJSON); err != nil {
+		log.Errf("Error writing response: %v", err)
+	}
+}
+
+// Used for PATCH /schedules/{schedule_id} endpoint
+func (g *Gorilla) swagPATCHScheduleByID(w http.ResponseWriter, r *http.Request) {
+	// Load the controller to access the persistence and the payload
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+	body := r.Context().Value(contextKey("body")).([]byte)
+
+	// Unmarshal the payload
+	schedule := swagger.ScheduleDetail{}
+	if err := json.Unmarshal(body, &schedule); err != nil {
+		log.Errf("Error unmarshaling json: %v", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	// Fetch the entity from persistence and check if it's there
+	persisted, err := ctrl.PersistenceService.Read(r.Context(), mux.Vars(r)["schedule_id"], &cce.Schedule{})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if persisted == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	// Convert it to a persistable object, keeping the commands issued
+	updated := cce.Schedule{
+		ID:              persisted.GetID(),
+		NodeID:          schedule.NodeID,
+		AppID:           schedule.AppID,
+		Cmd:             schedule.Cmd,
+		Cron:            schedule.Cron,
+		TimeZone:        schedule.TimeZone,
+		LastRunAt:       persisted.(*cce.Schedule).LastRunAt,
+		LastOperationID: persisted.(*cce.Schedule).LastOperationID,
+	}
+
+	// Validate the object
+	if err = updated.Validate(); err != nil {
+		log.Debugf("Validation failed for %v: %v", &updated, err)
+		w.WriteHeader(http.StatusBadRequest)
+		_, err = w.Write([]byte(fmt.Sprintf("Validation failed: %v", err)))
+		if err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
+		return
+	}
+	if statusCode, err := checkDBCreateSchedules(r.Context(), ctrl.PersistenceService, &updated); err != nil {
+		log.Errf("Error running DB logic: %v", err)
+		w.WriteHeader(statusCode)
+		_, err = w.Write([]byte(err.Error()))
+		if err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
+		return
+	}
+
+	// Persist the object
+	if err = ctrl.PersistenceService.BulkUpdate(r.Context(), []cce.Persistable{&updated}); err != nil {
+		log.Errf("Error updating entities: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// Used for DELETE /schedules/{schedule_id} endpoint
+func (g *Gorilla) swagDELETEScheduleByID(w http.ResponseWriter, r *http.Request) {
+	// Load the controller to access the persistence
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+
+	// Fetch the entity from persistence and check if it's there
+	persisted, err := ctrl.PersistenceService.Read(r.Context(), mux.Vars(r)["schedule_id"], &cce.Schedule{})
+	if err != nil {
+		log.Errf("Error reading entity: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if persisted == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	ok, err := ctrl.PersistenceService.Delete(r.Context(), mux.Vars(r)["schedule_id"], &cce.Schedule{})
+	if err != nil {
+		log.Errf("Error deleting entity: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	// we just fetched the entity, so if !ok then something went wrong
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// Used for GET /app_bundles endpoint
+func (g *Gorilla) swagGETAppBundles(w http.ResponseWriter, r *http.Request) {
+	// Load the controller to access the persistence
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+
+	// Fetch the bundles from persistence
+	persisted, err := ctrl.PersistenceService.ReadAll(r.Context(), &cce.AppBundle{})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	// Construct the response object
+	bundles := swagger.AppBundleList{AppBundles: []swagger.AppBundleSummary{}}
+	for _, e := range persisted {
+		bundles.AppBundles = append(bundles.AppBundles, swagger.AppBundleSummary{
+			ID:          e.(*cce.AppBundle).ID,
+			Name:        e.(*cce.AppBundle).Name,
+			Description: e.(*cce.AppBundle).Description,
+		})
+	}
+
+	// Marshal the response object to JSON
+	bundlesJSON, err := json.Marshal(bundles)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err = w.Write(bundlesJSON); err != nil {
+		log.Errf("Error writing response: %v", err)
+	}
+}
+
+// Used for POST /app_bundles endpoint
+func (g *Gorilla) swagPOSTAppBundles(w http.ResponseWriter, r *http.Request) {
+	g.appBundlesHandler.create(w, r)
+}
+
+// Used for GET /app_bundles/{bundle_id} endpoint
+func (g *Gorilla) swagGETAppBundleByID(w http.ResponseWriter, r *http.Request) {
+	// Load the controller to access the persistence
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+
+	// Fetch the entity from persistence and check if it's there
+	persisted, err := ctrl.PersistenceService.Read(r.Context(), mux.Vars(r)["bundle_id"], &cce.AppBundle{})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if persisted == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	// Construct the response object
+	bundle := persisted.(*cce.AppBundle)
+	detail := swagger.AppBundleDetail{
+		AppBundleSummary: swagger.AppBundleSummary{
+			ID:          bundle.ID,
+			Name:        bundle.Name,
+			Description: bundle.Description,
+		},
+		Members: []swagger.AppBundleMember{},
+	}
+	for _, m := range bundle.Members {
+		detail.Members = append(detail.Members, swagger.AppBundleMember{
+			AppID:     m.AppID,
+			DependsOn: m.DependsOn,
+		})
+	}
+
+	// Marshal the response object to JSON
+	bundleJSON, err := json.Marshal(detail)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err = w.Write(bundleJSON); err != nil {
+		log.Errf("Error writing response: %v", err)
+	}
+}
+
+// Used for PATCH /app_bundles/{bundle_id} endpoint. Bundles deployed to a node
+// cannot be updated, as their members would no longer match the node.
+func (g *Gorilla) swagPATCHAppBundleByID(w http.ResponseWriter, r *http.Request) {
+	// Load the controller to access the persistence and the payload
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+	body := r.Context().Value(contextKey("body")).([]byte)
+
+	// Unmarshal the payload
+	bundle := swagger.AppBundleDetail{}
+	if err := json.Unmarshal(body, &bundle); err != nil {
+		log.Errf("Error unmarshaling json: %v", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	// Fetch the entity from persistence and check if it's there
+	persisted, err := ctrl.PersistenceService.Read(r.Context(), mux.Vars(r)["bundle_id"], &cce.AppBundle{})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if persisted == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	// Convert it to a persistable object
+	updated := cce.AppBundle{
+		ID:          persisted.GetID(),
+		Name:        bundle.Name,
+		Description: bundle.Description,
+	}
+	for _, m := range bundle.Members {
+		updated.Members = append(updated.Members, cce.AppBundleMember{
+			AppID:     m.AppID,
+			DependsOn: m.DependsOn,
+		})
+	}
+
+	// Validate the object
+	if err = updated.Validate(); err != nil {
+		log.Debugf("Validation failed for %v: %v", &updated, err)
+		w.WriteHeader(http.StatusBadRequest)
+		_, err = w.Write([]byte(fmt.Sprintf("Validation failed: %v", err)))
+		if err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
+		return
+	}
+	if statusCode, err := checkDBCreateAppBundles(r.Context(), ctrl.PersistenceService, &updated); err != nil {
+		log.Errf("Error running DB logic: %v", err)
+		w.WriteHeader(statusCode)
+		_, err = w.Write([]byte(err.Error()))
+		if err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
+		return
+	}
+	nodeBundles, err := ctrl.PersistenceService.Filter(
+		r.Context(),
+		&cce.NodeAppBundle{},
+		[]cce.Filter{
+			{
+				Field: "bundle_id",
+				Value: updated.ID,
+			},
+		})
+	if err != nil {
+		log.Errf("Error filtering nodes_app_bundles: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if len(nodeBundles) != 0 {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, err = w.Write([]byte(fmt.Sprintf(
+			"cannot update bundle_id %s: deployed to node_id %s",
+			updated.ID, nodeBundles[0].(*cce.NodeAppBundle).NodeID)))
+		if err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
+		return
+	}
+	if statusCode, err := checkPendingBundleUpdate(r.Context(), ctrl.PersistenceService, updated.ID); err != nil {
+		log.Errf("Error checking pending operations: %v", err)
+		w.WriteHeader(statusCode)
+		_, err = w.Write([]byte(err.Error()))
+		if err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
+		return
+	}
+
+	// Persist the object
+	if err = ctrl.PersistenceService.BulkUpdate(r.Context(), []cce.Persistable{&updated}); err != nil {
+		log.Errf("Error updating entities: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// Used for DELETE /app_bundles/{bundle_id} endpoint
+func (g *Gorilla) swagDELETEAppBundleByID(w http.ResponseWriter, r *http.Request) {
+	// Load the controller to access the persistence
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+
+	// Check that we can delete the entity
+	if statusCode, err := checkDBDeleteAppBundles(
+		r.Context(),
+		ctrl.PersistenceService,
+		mux.Vars(r)["bundle_id"]); err != nil {
+		log.Errf("Error running DB logic: %v", err)
+		w.WriteHeader(statusCode)
+		_, err = w.Write([]byte(err.Error()))
+		if err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
+		return
+	}
+
+	// Fetch the entity from persistence and check if it's there
+	persisted, err := ctrl.PersistenceService.Read(r.Context(), mux.Vars(r)["bundle_id"], &cce.AppBundle{})
+	if err != nil {
+		log.Errf("Error reading entity: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if persisted == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	ok, err := ctrl.PersistenceService.Delete(r.Context(), mux.Vars(r)["bundle_id"], &cce.AppBundle{})
+	if err != nil {
+		log.Errf("Error deleting entity: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	// we just fetched the entity, so if !ok then something went wrong
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// Used for GET /nodes/{node_id}/app_bundles endpoint
+func (g *Gorilla) swagGETNodeAppBundles(w http.ResponseWriter, r *http.Request) {
+	// Load the controller to access the persistence
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+
+	// Fetch the node from persistence and check if it's there
+	node, err := ctrl.PersistenceService.Read(r.Context(), mux.Vars(r)["node_id"], &cce.Node{})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if node == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	nodeBundles, err := ctrl.PersistenceService.Filter(
+		r.Context(),
+		&cce.NodeAppBundle{},
+		[]cce.Filter{
+			{
+				Field: "node_id",
+				Value: node.GetID(),
+			},
+		})
+	if err != nil {
+		log.Errf("Error filtering nodes_app_bundles: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	// Construct the response object, the status of a bundle is aggregated
+	// from the statuses of its members
+	bundles := swagger.NodeAppBundleList{NodeAppBundles: []swagger.NodeAppBundleSummary{}}
+	for _, e := range nodeBundles {
+		bundle, _, err := readBundle(r.Context(), ctrl.PersistenceService, e.(*cce.NodeAppBundle).BundleID)
+		if err != nil {
+			log.Errf("Error reading bundle: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		detail, err := nodeAppBundleDetail(r.Context(), ctrl.PersistenceService, node.GetID(), bundle)
+		if err != nil {
+			log.Errf("Error getting status of bundle %s: %v", bundle.ID, err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		bundles.NodeAppBundles = append(bundles.NodeAppBundles, detail.NodeAppBundleSummary)
+	}
+
+	// Marshal the response object to JSON
+	bundlesJSON, err := json.Marshal(bundles)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err = w.Write(bundlesJSON); err != nil {
+		log.Errf("Error writing response: %v", err)
+	}
+}
+
+// Used for POST /nodes/{node_id}/app_bundles endpoint. The members of the
+// bundle are deployed and started asynchronously, each after the apps it
+// depends on.
+func (g *Gorilla) swagPOSTNodeAppBundle(w http.ResponseWriter, r *http.Request) { //nolint:gocyclo
+	// Load the controller to access the persistence and the payload
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+	body := r.Context().Value(contextKey("body")).([]byte)
+
+	// Unmarshal the payload
+	var baseResource swagger.BaseResource
+	if err := json.Unmarshal(body, &baseResource); err != nil {
+		log.Errf("Error unmarshaling json: %v", err)
+		w.WriteHeader(http.StatusBadRequest)
+		_, err = w.Write([]byte(fmt.Sprintf("Error unmarshaling json: %v", err)))
+		if err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
+		return
+	}
+
+	// Fetch the node from persistence and check if it's there
+	node, err := ctrl.PersistenceService.Read(r.Context(), mux.Vars(r)["node_id"], &cce.Node{})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if node == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	// Validate the association
+	nodeBundle := cce.NodeAppBundle{
+		ID:       uuid.New(),
+		NodeID:   node.GetID(),
+		BundleID: baseResource.ID,
+	}
+	if err = nodeBundle.Validate(); err != nil {
+		log.Debugf("Validation failed for %v: %v", &nodeBundle, err)
+		w.WriteHeader(http.StatusBadRequest)
+		_, err = w.Write([]byte(fmt.Sprintf("Validation failed: %v", err)))
+		if err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
+		return
+	}
+
+	// Fetch the bundle from persistence and check if it's there
+	persisted, err := ctrl.PersistenceService.Read(r.Context(), nodeBundle.BundleID, &cce.AppBundle{})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if persisted == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	bundle := persisted.(*cce.AppBundle)
+
+	// Check that the bundle and its members are not deployed to the node
+	// already and that the node can run every member
+	existing, err := findNodeAppBundle(r.Context(), ctrl.PersistenceService, nodeBundle.NodeID, bundle.ID)
+	if err != nil {
+		log.Errf("Error finding node app bundle: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if existing != nil {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, err = w.Write([]byte(fmt.Sprintf(
+			"duplicate record in nodes_app_bundles detected for node_id %s and bundle_id %s",
+			nodeBundle.NodeID, bundle.ID)))
+		if err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
+		return
+	}
+	if statusCode, err := checkDBCreateNodesAppBundles(
+		r.Context(), ctrl.PersistenceService, nodeBundle.NodeID, bundle,
+	); err != nil {
+		log.Errf("Error checking node app bundle: %v", err)
+		w.WriteHeader(statusCode)
+		_, err = w.Write([]byte(err.Error()))
+		if err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
+		return
+	}
+
+	// Check that no other operation is in progress for the bundle or its
+	// members
+	if statusCode, err := checkPendingBundleOperations(
+		r.Context(), ctrl.PersistenceService, nodeBundle.NodeID, bundle,
+	); err != nil {
+		log.Errf("Error checking pending operations: %v", err)
+		w.WriteHeader(statusCode)
+		_, err = w.Write([]byte(err.Error()))
+		if err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
+		return
+	}
+
+	// Deploy the bundle to the node asynchronously, the node app bundle is
+	// persisted once the deployment starts
+	op := &cce.Operation{
+		Type:    cce.OperationTypeDeployBundle,
+		NodeID:  nodeBundle.NodeID,
+		Payload: toBundlePayload(bundle.ID),
+	}
+	if err := g.operations.submit(r.Context(), op); err != nil {
+		log.Errf("Error submitting operation: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	audit(r, "deploying bundle %s to node %s", bundle.ID, nodeBundle.NodeID)
+
+	writeOperationAccepted(w, op)
+}
+
+// Used for GET /nodes/{node_id}/app_bundles/{bundle_id} endpoint
+func (g *Gorilla) swagGETNodeAppBundleByID(w http.ResponseWriter, r *http.Request) {
+	// Load the controller to access the persistence
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+
+	// Fetch the entity from persistence and check if it's there
+	nodeBundle, err := findNodeAppBundle(
+		r.Context(), ctrl.PersistenceService, mux.Vars(r)["node_id"], mux.Vars(r)["bundle_id"])
+	if err != nil {
+		log.Errf("Error finding node app bundle: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if nodeBundle == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	// Construct the response object
+	bundle, _, err := readBundle(r.Context(), ctrl.PersistenceService, nodeBundle.BundleID)
+	if err != nil {
+		log.Errf("Error reading bundle: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	detail, err := nodeAppBundleDetail(r.Context(), ctrl.PersistenceService, nodeBundle.NodeID, bundle)
+	if err != nil {
+		log.Errf("Error getting status of bundle %s: %v", bundle.ID, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	// Marshal the response object to JSON
+	detailJSON, err := json.Marshal(detail)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err = w.Write(detailJSON); err != nil {
+		log.Errf("Error writing response: %v", err)
+	}
+}
+
+// Used for PATCH /nodes/{node_id}/app_bundles/{bundle_id} endpoint. The
+// command starts the members of the bundle in dependency order, stops them in
+// reverse order or restarts them.
+func (g *Gorilla) swagPATCHNodeAppBundleByID(w http.ResponseWriter, r *http.Request) {
+	// Load the controller to access the persistence and the payload
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+	body := r.Context().Value(contextKey("body")).([]byte)
+
+	// Unmarshal the payload
+	detail := swagger.NodeAppBundleDetail{}
+	if err := json.Unmarshal(body, &detail); err != nil {
+		log.Errf("Error unmarshaling json: %v", err)
+		w.WriteHeader(http.StatusBadRequest)
+		_, err = w.Write([]byte(fmt.Sprintf("Error unmarshaling json: %v", err)))
+		if err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
+		return
+	}
+
+	// Fetch the entity from persistence and check if it's there
+	nodeBundle, err := findNodeAppBundle(
+		r.Context(), ctrl.PersistenceService, mux.Vars(r)["node_id"], mux.Vars(r)["bundle_id"])
+	if err != nil {
+		log.Errf("Error finding node app bundle: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if nodeBundle == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	// Validate the command
+	opType, ok := bundleCommands[detail.Command]
+	if !ok {
+		w.WriteHeader(http.StatusBadRequest)
+		_, err = w.Write([]byte(fmt.Sprintf("Validation failed: command %q is invalid", detail.Command)))
+		if err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
+		return
+	}
+
+	// Check that no other operation is in progress for the bundle or its
+	// members
+	bundle, _, err := readBundle(r.Context(), ctrl.PersistenceService, nodeBundle.BundleID)
+	if err != nil {
+		log.Errf("Error reading bundle: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if statusCode, err := checkPendingBundleOperations(
+		r.Context(), ctrl.PersistenceService, nodeBundle.NodeID, bundle,
+	); err != nil {
+		log.Errf("Error checking pending operations: %v", err)
+		w.WriteHeader(statusCode)
+		_, err = w.Write([]byte(err.Error()))
+		if err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
+		return
+	}
+
+	// Send the lifecycle command to the members asynchronously
+	op := &cce.Operation{
+		Type:    opType,
+		NodeID:  nodeBundle.NodeID,
+		Payload: toBundlePayload(bundle.ID),
+	}
+	if err := g.operations.submit(r.Context(), op); err != nil {
+		log.Errf("Error submitting operation: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	audit(r, "sending %s to bundle %s on node %s", detail.Command, bundle.ID, nodeBundle.NodeID)
+
+	writeOperationAccepted(w, op)
+}
+
+// Used for DELETE /nodes/{node_id}/app_bundles/{bundle_id} endpoint. The
+// members of the bundle are stopped and undeployed in reverse dependency
+// order.
+func (g *Gorilla) swagDELETENodeAppBundleByID(w http.ResponseWriter, r *http.Request) {
+	// Load the controller to access the persistence
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+
+	// Fetch the entity from persistence and check if it's there
+	nodeBundle, err := findNodeAppBundle(
+		r.Context(), ctrl.PersistenceService, mux.Vars(r)["node_id"], mux.Vars(r)["bundle_id"])
+	if err != nil {
+		log.Errf("Error finding node app bundle: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if nodeBundle == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	bundle, _, err := readBundle(r.Context(), ctrl.PersistenceService, nodeBundle.BundleID)
+	if err != nil {
+		log.Errf("Error reading bundle: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	// Check that we can delete the members
+	for _, m := range bundle.Members {
+		nodeApps, err := ctrl.PersistenceService.Filter(
+			r.Context(),
+			&cce.NodeApp{},
+			[]cce.Filter{
+				{
+					Field: "node_id",
+					Value: nodeBundle.NodeID,
+				},
+				{
+					Field: "app_id",
+					Value: m.AppID,
+				},
+			})
+		if err != nil {
+			log.Errf("Error filtering node_apps: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if len(nodeApps) == 0 {
+			continue
+		}
+		if statusCode, err := checkDBDeleteNodesApps(
+			r.Context(), ctrl.PersistenceService, nodeApps[0].GetID(),
+		); err != nil {
+			log.Errf("Error running DB logic: %v", err)
+			w.WriteHeader(statusCode)
+			_, err = w.Write([]byte(
+				fmt.Sprintf("cannot delete app %s: record in use in nodes_apps_traffic_policies", m.AppID)))
+			if err != nil {
+				log.Errf("Error writing response: %v", err)
+			}
+			return
+		}
+	}
+
+	// Check that no other operation is in progress for the bundle or its
+	// members
+	if statusCode, err := checkPendingBundleOperations(
+		r.Context(), ctrl.PersistenceService, nodeBundle.NodeID, bundle,
+	); err != nil {
+		log.Errf("Error checking pending operations: %v", err)
+		w.WriteHeader(statusCode)
+		_, err = w.Write([]byte(err.Error()))
+		if err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
+		return
+	}
+
+	// Delete the bundle from the node asynchronously, the node app bundle is
+	// deleted once its members are removed
+	op := &cce.Operation{
+		Type:    cce.OperationTypeUndeployBundle,
+		NodeID:  nodeBundle.NodeID,
+		Payload: toBundlePayload(bundle.ID),
+	}
+	if err = g.operations.submit(r.Context(), op); err != nil {
+		log.Errf("Error submitting operation: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	audit(r, "undeploying bundle %s from node %s", bundle.ID, nodeBundle.NodeID)
+
+	writeOperationAccepted(w, op)
+}
+
+// Used for GET /app_templates endpoint
+func (g *Gorilla) swagGETAppTemplates(w http.ResponseWriter, r *http.Request) {
+	// Load the controller to access the persistence
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+
+	// Fetch the templates from persistence
+	persisted, err := ctrl.PersistenceService.ReadAll(r.Context(), &cce.AppTemplate{})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	// Construct the response object
+	templates := swagger.AppTemplateList{AppTemplates: []swagger.AppTemplateSummary{}}
+	for _, e := range persisted {
+		templates.AppTemplates = append(templates.AppTemplates, swagger.AppTemplateSummary{
+			ID:      e.(*cce.AppTemplate).ID,
+			Name:    e.(*cce.AppTemplate).Name,
+			Version: e.(*cce.AppTemplate).Version,
+		})
+	}
+
+	// Marshal the response object to JSON
+	templatesJSON, err := json.Marshal(templates)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err = w.Write(templatesJSON); err != nil {
+		log.Errf("Error writing response: %v", err)
+	}
+}
+
+// toAppTemplate converts a template of the API to a persistable object.
+func toAppTemplate(id string, version int, template *swagger.AppTemplateDetail) *cce.AppTemplate {
+	t := &cce.AppTemplate{
+		ID:          id,
+		Name:        template.Name,
+		Description: template.Description,
+		Version:     version,
+		Spec:        template.Spec,
+	}
+	for _, p := range template.Parameters {
+		t.Parameters = append(t.Parameters, cce.AppTemplateParameter{
+			Name:        p.Name,
+			Type:        p.Type,
+			Default:     p.Default,
+			Description: p.Description,
+		})
+	}
+	return t
+}
+
+// Used for POST /app_templates endpoint. The first version of a template is 1.
+func (g *Gorilla) swagPOSTAppTemplates(w http.ResponseWriter, r *http.Request) {
+	// Load the controller to access the persistence and the payload
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+	body := r.Context().Value(contextKey("body")).([]byte)
+
+	// Unmarshal the payload
+	template := swagger.AppTemplateDetail{}
+	if err := json.Unmarshal(body, &template); err != nil {
+		log.Errf("Error unmarshaling json: %v", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if template.ID != "" {
+		w.WriteHeader(http.StatusBadRequest)
+		if _, err := w.Write([]byte("Validation failed: id cannot be specified in POST request")); err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
+		return
+	}
+
+	// Convert it to a persistable object and validate it
+	created := toAppTemplate(uuid.New(), 1, &template)
+	if err := created.Validate(); err != nil {
+		log.Debugf("Validation failed for %v: %v", created, err)
+		w.WriteHeader(http.StatusBadRequest)
+		if _, err = w.Write([]byte(fmt.Sprintf("Validation failed: %v", err))); err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
+		return
+	}
+
+	// Persist the object
+	if err := ctrl.PersistenceService.Create(r.Context(), created); err != nil {
+		log.Errf("Error creating entity: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if _, err := w.Write([]byte(fmt.Sprintf(`{"id":"%s"}`, created.ID))); err != nil {
+		log.Errf("Error writing response: %v", err)
+	}
+}
+
+// Used for GET /app_templates/{template_id} endpoint
+func (g *Gorilla) swagGETAppTemplateByID(w http.ResponseWriter, r *http.Request) {
+	// Load the controller to access the persistence
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+
+	// Fetch the entity from persistence and check if it's there
+	persisted, err := ctrl.PersistenceService.Read(r.Context(), mux.Vars(r)["template_id"], &cce.AppTemplate{})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if persisted == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	// Construct the response object
+	t := persisted.(*cce.AppTemplate)
+	template := swagger.AppTemplateDetail{
+		AppTemplateSummary: swagger.AppTemplateSummary{
+			ID:      t.ID,
+			Name:    t.Name,
+			Version: t.Version,
+		},
+		Description: t.Description,
+		Spec:        t.Spec,
+	}
+	for _, p := range t.Parameters {
+		template.Parameters = append(template.Parameters, swagger.AppTemplateParameter{
+			Name:        p.Name,
+			Type:        p.Type,
+			Default:     p.Default,
+			Description: p.Description,
+		})
+	}
+
+	// Marshal the response object to JSON
+	templateJSON, err := json.Marshal(template)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err = w.Write(templateJSON); err != nil {
+		log.Errf("Error writing response: %v", err)
+	}
+}
+
+// Used for PATCH /app_templates/{template_id} endpoint. Each update is a new
+// version of the template; apps already instantiated from it are unchanged.
+func (g *Gorilla) swagPATCHAppTemplateByID(w http.ResponseWriter, r *http.Request) {
+	// Load the controller to access the persistence and the payload
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+	body := r.Context().Value(contextKey("body")).([]byte)
+
+	// Unmarshal the payload
+	template := swagger.AppTemplateDetail{}
+	if err := json.Unmarshal(body, &template); err != nil {
+		log.Errf("Error unmarshaling json: %v", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	// Fetch the entity from persistence and check if it's there
+	persisted, err := ctrl.PersistenceService.Read(r.Context(), mux.Vars(r)["template_id"], &cce.AppTemplate{})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if persisted == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	// Convert it to a persistable object and validate it
+	updated := toAppTemplate(persisted.GetID(), persisted.(*cce.AppTemplate).Version+1, &template)
+	if err = updated.Validate(); err != nil {
+		log.Debugf("Validation failed for %v: %v", updated, err)
+		w.WriteHeader(http.StatusBadRequest)
+		_, err = w.Write([]byte(fmt.Sprintf("Validation failed: %v", err)))
+		if err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
+		return
+	}
+
+	// Persist the object
+	if err = ctrl.PersistenceService.BulkUpdate(r.Context(), []cce.Persistable{updated}); err != nil {
+		log.Errf("Error updating entities: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// Used for DELETE /app_templates/{template_id} endpoint
+func (g *Gorilla) swagDELETEAppTemplateByID(w http.ResponseWriter, r *http.Request) {
+	// Load the controller to access the persistence
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+
+	// Check that we can delete the entity
+	if statusCode, err := checkDBDeleteAppTemplates(
+		r.Context(),
+		ctrl.PersistenceService,
+		mux.Vars(r)["template_id"]); err != nil {
+		log.Errf("Error running DB logic: %v", err)
+		w.WriteHeader(statusCode)
+		_, err = w.Write([]byte(err.Error()))
+		if err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
+		return
+	}
+
+	// Fetch the entity from persistence and check if it's there
+	persisted, err := ctrl.PersistenceService.Read(r.Context(), mux.Vars(r)["template_id"], &cce.AppTemplate{})
+	if err != nil {
+		log.Errf("Error reading entity: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if persisted == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	ok, err := ctrl.PersistenceService.Delete(r.Context(), mux.Vars(r)["template_id"], &cce.AppTemplate{})
+	if err != nil {
+		log.Errf("Error deleting entity: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	// we just fetched the entity, so if !ok then something went wrong
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// postNodeAppFromTemplate instantiates a template with the values of its
+// parameters for a node and deploys the resulting app to the node. The app is
+// persisted like any other app and deleted when it is undeployed.
+func (g *Gorilla) postNodeAppFromTemplate( //nolint:gocyclo
+	w http.ResponseWriter,
+	r *http.Request,
+	req *swagger.NodeAppTemplateReq,
+) {
+	// Load the controller to access the persistence
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+
+	// Fetch the node and the template from persistence and check they're there
+	node, err := ctrl.PersistenceService.Read(r.Context(), mux.Vars(r)["node_id"], &cce.Node{})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if node == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	persisted, err := ctrl.PersistenceService.Read(r.Context(), req.TemplateID, &cce.AppTemplate{})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if persisted == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	template := persisted.(*cce.AppTemplate)
+
+	// Instantiate the template, which validates the app
+	app, parameters, err := template.Instantiate(req.Parameters)
+	if err != nil {
+		log.Debugf("Instantiation of template %s failed: %v", template.ID, err)
+		w.WriteHeader(http.StatusBadRequest)
+		_, err = w.Write([]byte(fmt.Sprintf("Validation failed: %v", err)))
+		if err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
+		return
+	}
+
+	// Check that the app can be created and run on the node
+	writeErr := func(statusCode int, err error) {
+		log.Errf("Error checking app of template %s: %v", template.ID, err)
+		w.WriteHeader(statusCode)
+		if _, err = w.Write([]byte(err.Error())); err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
+	}
+	if statusCode, err := checkDBCreateApps(r.Context(), ctrl.PersistenceService, app); err != nil {
+		writeErr(statusCode, err)
+		return
+	}
+	if err = checkOrchestrationSupport(r.Context(), app); err != nil {
+		writeErr(http.StatusUnprocessableEntity, err)
+		return
+	}
+	if statusCode, err := checkOvercommit(r.Context(), ctrl.PersistenceService, node.GetID(), app); err != nil {
+		writeErr(statusCode, err)
+		return
+	}
+	features, err := getNfdFeatures(r.Context(), node.GetID())
+	if err != nil {
+		log.Errf("postNodeAppFromTemplate(): getNfdFeatures() failed: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(w, "error: %v\n", err)
+		return
+	}
+	if err = app.EPAValidate(features); err != nil {
+		writeErr(http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	// Persist the app, then deploy it to the node asynchronously. The node
+	// app records the template version and parameters once it is deployed.
+	if err = ctrl.PersistenceService.Create(r.Context(), app); err != nil {
+		log.Errf("Error creating app: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	op := &cce.Operation{
+		Type:    cce.OperationTypeDeploy,
+		NodeID:  node.GetID(),
+		AppID:   app.ID,
+		Payload: toTemplatePayload(template, parameters),
+	}
+	if err = g.operations.submit(r.Context(), op); err != nil {
+		log.Errf("Error submitting operation: %v", err)
+		if _, err = ctrl.PersistenceService.Delete(r.Context(), app.ID, &cce.App{}); err != nil {
+			log.Errf("Error deleting app %s: %v", app.ID, err)
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	audit(r, "deploying app %s instantiated from version %d of template %s to node %s",
+		app.ID, template.Version, template.ID, node.GetID())
+
+	writeOperationAccepted(w, op)
+}
@@ -0,0 +1,85 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package gorilla
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	cce "github.com/open-ness/edgecontroller"
+	"github.com/pkg/errors"
+)
+
+// templatePayload is the payload of deploy operations of apps instantiated
+// from a template.
+type templatePayload struct {
+	TemplateID      string                     `json:"template_id"`
+	TemplateVersion int                        `json:"template_version"`
+	Parameters      map[string]json.RawMessage `json:"parameters,omitempty"`
+}
+
+func toTemplatePayload(template *cce.AppTemplate, parameters map[string]json.RawMessage) json.RawMessage {
+	payload, _ := json.Marshal(templatePayload{
+		TemplateID:      template.ID,
+		TemplateVersion: template.Version,
+		Parameters:      parameters,
+	})
+	return payload
+}
+
+// setNodeAppTemplate records on a node app the template, if any, that the
+// app of a deploy operation was instantiated from.
+func setNodeAppTemplate(nodeApp *cce.NodeApp, op *cce.Operation) error {
+	if len(op.Payload) == 0 {
+		return nil
+	}
+
+	var payload templatePayload
+	if err := json.Unmarshal(op.Payload, &payload); err != nil {
+		return errors.Wrap(err, "error unmarshaling payload")
+	}
+	nodeApp.TemplateID = payload.TemplateID
+	nodeApp.TemplateVersion = payload.TemplateVersion
+	nodeApp.TemplateParameters = payload.Parameters
+
+	return nil
+}
+
+// deleteTemplateApp deletes the app of an undeployed node app if it was
+// instantiated from a template, as it was created for that node app only.
+// The app is kept if it is still referenced.
+func deleteTemplateApp(ctx context.Context, ps cce.PersistenceService, nodeApp *cce.NodeApp) error {
+	if nodeApp.TemplateID == "" {
+		return nil
+	}
+
+	if statusCode, err := checkDBDeleteApps(ctx, ps, nodeApp.AppID); err != nil {
+		if statusCode == http.StatusInternalServerError {
+			return err
+		}
+		log.Infof("Keeping app %s instantiated from template %s: %v", nodeApp.AppID, nodeApp.TemplateID, err)
+		return nil
+	}
+	if _, err := ps.Delete(ctx, nodeApp.AppID, &cce.App{}); err != nil {
+		return errors.Wrap(err, "error deleting app instantiated from template")
+	}
+
+	return nil
+}
+
+// abandonTemplateApp deletes the app of a deploy operation that failed or was
+// canceled if the app was instantiated from a template, as no node app will
+// ever reference it.
+func abandonTemplateApp(ctx context.Context, ps cce.PersistenceService, op *cce.Operation) error {
+	if op.Type != cce.OperationTypeDeploy || len(op.Payload) == 0 {
+		return nil
+	}
+
+	nodeApp := &cce.NodeApp{NodeID: op.NodeID, AppID: op.AppID}
+	if err := setNodeAppTemplate(nodeApp, op); err != nil {
+		return err
+	}
+	return deleteTemplateApp(ctx, ps, nodeApp)
+}
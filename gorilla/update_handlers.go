@@ -8,6 +8,8 @@ import (
 	"net/http"
 
 	cce "github.com/open-ness/edgecontroller"
+	elapb "github.com/open-ness/edgecontroller/pb/ela"
+	"github.com/open-ness/edgecontroller/uuid"
 	"github.com/pkg/errors"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -87,13 +89,13 @@ func handleUpdateNodesApps( //nolint: gocyclo
 	case cce.OrchestrationModeKubernetes, cce.OrchestrationModeKubernetesOVN:
 		switch e.(*cce.NodeAppReq).Cmd {
 		case "start":
-			err = ctrl.KubernetesClient.Start(ctx,
+			err = ctrl.KubernetesClient.Start(ctx, e.(*cce.NodeAppReq).NodeApp.Tenant,
 				e.(*cce.NodeAppReq).NodeApp.NodeID, e.(*cce.NodeAppReq).NodeApp.AppID)
 		case "stop":
-			err = ctrl.KubernetesClient.Stop(ctx,
+			err = ctrl.KubernetesClient.Stop(ctx, e.(*cce.NodeAppReq).NodeApp.Tenant,
 				e.(*cce.NodeAppReq).NodeApp.NodeID, e.(*cce.NodeAppReq).NodeApp.AppID)
 		case "restart":
-			err = ctrl.KubernetesClient.Restart(ctx,
+			err = ctrl.KubernetesClient.Restart(ctx, e.(*cce.NodeAppReq).NodeApp.Tenant,
 				e.(*cce.NodeAppReq).NodeApp.NodeID, e.(*cce.NodeAppReq).NodeApp.AppID)
 		}
 		if err != nil {
@@ -103,3 +105,151 @@ func handleUpdateNodesApps( //nolint: gocyclo
 
 	return 0, nil
 }
+
+func handleUpdateNodesInterfacesPolicy(
+	ctx context.Context,
+	ps cce.PersistenceService,
+	nodeID string,
+	interfaceID string,
+	policyID string,
+) (statusCode int, err error) {
+	// Construct the update object to dial to the node, an empty policy ID
+	// sets no policy
+	requested := cce.NodeReq{
+		Node: cce.Node{
+			ID: nodeID,
+		},
+		TrafficPolicies: []cce.NetworkInterfaceTrafficPolicy{
+			{
+				NetworkInterfaceID: interfaceID,
+				TrafficPolicyID:    policyID,
+			},
+		},
+	}
+
+	// Update the remote node
+	if code, err := handleUpdateNodes(ctx, ps, &requested); code != 0 {
+		return code, err
+	}
+
+	// Filter nodes_interfaces_traffic_policies to see if a record already exists
+	nodeIfacePolicy, err := ps.Filter(
+		ctx,
+		&cce.NodeInterfaceTrafficPolicy{},
+		[]cce.Filter{
+			{
+				Field: "network_interface_id",
+				Value: interfaceID,
+			},
+		})
+	if err != nil {
+		return http.StatusInternalServerError,
+			errors.Wrap(err, "error reading nodes_interfaces_traffic_policies")
+	}
+
+	// If it exists, delete it
+	if len(nodeIfacePolicy) == 1 {
+		ok, err := ps.Delete(ctx, nodeIfacePolicy[0].GetID(), &cce.NodeInterfaceTrafficPolicy{})
+		if err != nil {
+			return http.StatusInternalServerError,
+				errors.Wrap(err, "error deleting from nodes_interfaces_traffic_policies")
+		}
+		if !ok {
+			return http.StatusInternalServerError,
+				errors.New("did not delete 1 record from nodes_interfaces_traffic_policies")
+		}
+	}
+
+	if policyID == "" {
+		return 0, nil
+	}
+
+	// Persist the new association
+	persisted := &cce.NodeInterfaceTrafficPolicy{
+		ID:                 uuid.New(),
+		NodeID:             nodeID,
+		NetworkInterfaceID: interfaceID,
+		TrafficPolicyID:    policyID,
+	}
+	if err := ps.Create(ctx, persisted); err != nil {
+		return http.StatusInternalServerError, errors.Wrap(err, "error creating entity")
+	}
+
+	return 0, nil
+}
+
+func handleUpdateNodesAppsPolicy(
+	ctx context.Context,
+	ps cce.PersistenceService,
+	nodeApp *cce.NodeApp,
+	policy *cce.TrafficPolicy,
+) error {
+	ctrl := getController(ctx)
+	nodePort := ctrl.ELAPort
+	if nodePort == "" {
+		nodePort = defaultELAPort
+	}
+	nodeCC, err := connectNode(ctx, ps, nodeApp, nodePort, ctrl.EdgeNodeCreds)
+	if err != nil {
+		return errors.Wrap(err, "error connecting to node")
+	}
+	defer disconnectNode(nodeCC)
+
+	// Make gRPC call to node to set the policy
+	if err = nodeCC.AppPolicySvcCli.Set(ctx, nodeApp.AppID, policy); err != nil {
+		return errors.Wrap(err, "error setting policy")
+	}
+
+	// Filter nodes_apps_traffic_policies to see if a record already exists
+	nodeAppPolicies, err := ps.Filter(
+		ctx,
+		&cce.NodeAppTrafficPolicy{},
+		[]cce.Filter{
+			{
+				Field: "nodes_apps_id",
+				Value: nodeApp.ID,
+			},
+		})
+	if err != nil {
+		return errors.Wrap(err, "error reading nodes_apps_traffic_policies")
+	}
+
+	// If it exists, delete it
+	if len(nodeAppPolicies) == 1 {
+		ok, err := ps.Delete(ctx, nodeAppPolicies[0].GetID(), &cce.NodeAppTrafficPolicy{})
+		if err != nil {
+			return errors.Wrap(err, "error deleting from nodes_apps_traffic_policies")
+		}
+		if !ok {
+			return errors.New("did not delete 1 record from nodes_apps_traffic_policies")
+		}
+	}
+
+	// Persist the new association
+	persisted := &cce.NodeAppTrafficPolicy{
+		ID:              uuid.New(),
+		NodeAppID:       nodeApp.ID,
+		TrafficPolicyID: policy.ID,
+	}
+
+	return errors.Wrap(ps.Create(ctx, persisted), "error creating entity")
+}
+
+func handleUpdateNodesZones(ctx context.Context, ps cce.PersistenceService, zone *cce.NodeZone) error {
+	ctrl := getController(ctx)
+	nodePort := ctrl.ELAPort
+	if nodePort == "" {
+		nodePort = defaultELAPort
+	}
+
+	nodeCC, err := connectNode(ctx, ps, zone, nodePort, ctrl.EdgeNodeCreds)
+	if err != nil {
+		return err
+	}
+	defer disconnectNode(nodeCC)
+
+	return nodeCC.ZoneSvcCli.Update(ctx, &elapb.NetworkZone{
+		Id:          zone.ZoneID,
+		Description: zone.Description,
+	})
+}
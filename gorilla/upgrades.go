@@ -0,0 +1,549 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package gorilla
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	cce "github.com/open-ness/edgecontroller"
+	"github.com/open-ness/edgecontroller/swagger"
+	"github.com/open-ness/edgecontroller/uuid"
+	"github.com/pkg/errors"
+)
+
+// upgradePollInterval is how often the operations of a batch are checked for
+// completion.
+const upgradePollInterval = time.Second
+
+// upgradePayload is the payload of upgrade operations.
+type upgradePayload struct {
+	AppID string `json:"app_id"`
+}
+
+func toUpgradePayload(appID string) json.RawMessage {
+	payload, _ := json.Marshal(upgradePayload{AppID: appID})
+	return payload
+}
+
+// validateAppUpgradeRequest validates an upgrade request. Nodes are upgraded
+// one at a time unless a batch size is requested.
+func validateAppUpgradeRequest(req *swagger.AppUpgradeRequest) error {
+	if req.Version == "" {
+		return errors.New("version cannot be empty")
+	}
+	if req.BatchSize == 0 {
+		req.BatchSize = 1
+	}
+	if req.BatchSize < 1 {
+		return errors.New("batch_size must be at least 1")
+	}
+
+	return nil
+}
+
+// getAppVersions returns all versions of the app with the given identity,
+// including the first version.
+func getAppVersions(ctx context.Context, ps cce.PersistenceService, identity string) ([]*cce.App, error) {
+	apps, err := ps.ReadAll(ctx, &cce.App{})
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading apps")
+	}
+
+	var versions []*cce.App
+	for _, e := range apps {
+		if e.(*cce.App).Identity() == identity {
+			versions = append(versions, e.(*cce.App))
+		}
+	}
+
+	return versions, nil
+}
+
+// newAppUpgrade prepares the upgrade of all nodes running a version of the
+// app to the requested version. The nodes are ordered by ID so that batches
+// are predictable.
+func newAppUpgrade(
+	ctx context.Context,
+	ps cce.PersistenceService,
+	app *cce.App,
+	req *swagger.AppUpgradeRequest,
+) (upgrade *cce.AppUpgrade, statusCode int, err error) {
+	versions, err := getAppVersions(ctx, ps, app.Identity())
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	isVersion := make(map[string]bool)
+	var target *cce.App
+	for _, version := range versions {
+		isVersion[version.ID] = true
+		if version.Version == req.Version {
+			target = version
+		}
+	}
+	if target == nil {
+		return nil, http.StatusUnprocessableEntity, fmt.Errorf(
+			"version %s of app %s not found", req.Version, app.Identity())
+	}
+	if err = checkOrchestrationSupport(ctx, target); err != nil {
+		return nil, http.StatusUnprocessableEntity, err
+	}
+	if err = checkAppSignature(ctx, target); err != nil {
+		return nil, http.StatusUnprocessableEntity, err
+	}
+
+	upgrades, err := ps.Filter(
+		ctx,
+		&cce.AppUpgrade{},
+		[]cce.Filter{
+			{
+				Field: "app_id",
+				Value: app.Identity(),
+			},
+		})
+	if err != nil {
+		return nil, http.StatusInternalServerError, errors.Wrap(err, "error filtering apps_upgrades")
+	}
+	for _, e := range upgrades {
+		if !e.(*cce.AppUpgrade).Done() {
+			return nil, http.StatusUnprocessableEntity, fmt.Errorf(
+				"upgrade %s is %s for app %s", e.GetID(), e.(*cce.AppUpgrade).State, app.Identity())
+		}
+	}
+
+	nodeApps, err := ps.ReadAll(ctx, &cce.NodeApp{})
+	if err != nil {
+		return nil, http.StatusInternalServerError, errors.Wrap(err, "error reading nodes_apps")
+	}
+	upgrade = &cce.AppUpgrade{
+		ID:          uuid.New(),
+		AppID:       app.Identity(),
+		TargetAppID: target.ID,
+		BatchSize:   req.BatchSize,
+		State:       cce.UpgradeStateRunning,
+		Nodes:       []*cce.AppUpgradeNode{},
+	}
+	for _, e := range nodeApps {
+		nodeApp := e.(*cce.NodeApp)
+		if !isVersion[nodeApp.RunningAppID()] || nodeApp.RunningAppID() == target.ID {
+			continue
+		}
+		// rejected early here, checked again when the node's batch is
+		// submitted, see submitUpgradeNode
+		if statusCode, err = checkPendingOperations(ctx, ps, nodeApp.NodeID, nodeApp.AppID); err != nil {
+			return nil, statusCode, err
+		}
+		upgrade.Nodes = append(upgrade.Nodes, &cce.AppUpgradeNode{
+			NodeID:    nodeApp.NodeID,
+			AppID:     nodeApp.AppID,
+			FromAppID: nodeApp.RunningAppID(),
+			State:     cce.UpgradeNodeStatePending,
+		})
+	}
+	if len(upgrade.Nodes) == 0 {
+		return nil, http.StatusUnprocessableEntity, fmt.Errorf(
+			"no node runs a version of app %s other than %s", app.Identity(), req.Version)
+	}
+	sort.Slice(upgrade.Nodes, func(i, j int) bool {
+		return upgrade.Nodes[i].NodeID < upgrade.Nodes[j].NodeID
+	})
+
+	return upgrade, 0, nil
+}
+
+// startUpgrade persists a new upgrade and hands it to the pool.
+func (p *operationPool) startUpgrade(ctx context.Context, upgrade *cce.AppUpgrade) error {
+	upgrade.CreatedAt = time.Now().UTC()
+	upgrade.UpdatedAt = upgrade.CreatedAt
+
+	if err := upgrade.Validate(); err != nil {
+		return errors.Wrap(err, "invalid upgrade")
+	}
+	if err := p.controller.PersistenceService.Create(ctx, upgrade); err != nil {
+		return errors.Wrap(err, "error persisting upgrade")
+	}
+
+	go func() { p.upgrades <- upgrade.ID }()
+
+	return nil
+}
+
+// resumeUpgrades continues the upgrades interrupted by a controller restart.
+func (p *operationPool) resumeUpgrades(ctx context.Context) {
+	for _, state := range []string{cce.UpgradeStateRunning, cce.UpgradeStateRollingBack} {
+		upgrades, err := p.controller.PersistenceService.Filter(
+			ctx,
+			&cce.AppUpgrade{},
+			[]cce.Filter{
+				{
+					Field: "state",
+					Value: state,
+				},
+			})
+		if err != nil {
+			log.Errf("Error loading %s upgrades: %v", state, err)
+			continue
+		}
+		for _, upgrade := range upgrades {
+			go p.rollOut(ctx, upgrade.GetID())
+		}
+	}
+}
+
+// rollOut upgrades the nodes of an upgrade batch by batch and rolls all of
+// them back if a batch fails. The progress is persisted after every step so
+// that an upgrade interrupted by a controller restart can be resumed.
+func (p *operationPool) rollOut(ctx context.Context, id string) {
+	e, err := p.controller.PersistenceService.Read(ctx, id, &cce.AppUpgrade{})
+	if err != nil {
+		log.Errf("Error loading upgrade %s: %v", id, err)
+		return
+	}
+	if e == nil {
+		log.Errf("Upgrade %s not found", id)
+		return
+	}
+	upgrade := e.(*cce.AppUpgrade)
+
+	if upgrade.State == cce.UpgradeStateRunning {
+		err = p.upgradeBatches(ctx, upgrade)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			log.Errf("Upgrade %s of app %s failed, rolling back: %v", upgrade.ID, upgrade.AppID, err)
+			upgrade.Error = err.Error()
+			upgrade.State = cce.UpgradeStateRollingBack
+		} else {
+			upgrade.State = cce.UpgradeStateSucceeded
+		}
+		if err = p.updateUpgrade(ctx, upgrade); err != nil {
+			log.Errf("Error updating upgrade %s: %v", upgrade.ID, err)
+			return
+		}
+	}
+
+	if upgrade.State == cce.UpgradeStateRollingBack {
+		err = p.rollBack(ctx, upgrade)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			log.Errf("Rollback of upgrade %s of app %s failed: %v", upgrade.ID, upgrade.AppID, err)
+			upgrade.Error = fmt.Sprintf("%s; rollback failed: %v", upgrade.Error, err)
+			upgrade.State = cce.UpgradeStateFailed
+		} else {
+			upgrade.State = cce.UpgradeStateRolledBack
+		}
+		if err = p.updateUpgrade(ctx, upgrade); err != nil {
+			log.Errf("Error updating upgrade %s: %v", upgrade.ID, err)
+			return
+		}
+	}
+
+	log.Infof("Upgrade %s of app %s %s", upgrade.ID, upgrade.AppID, upgrade.State)
+}
+
+// upgradeBatches upgrades the nodes that are still pending one batch at a
+// time. The health of the app on each node is checked once its batch has been
+// upgraded.
+func (p *operationPool) upgradeBatches(ctx context.Context, upgrade *cce.AppUpgrade) error {
+	for start := 0; start < len(upgrade.Nodes); start += upgrade.BatchSize {
+		end := start + upgrade.BatchSize
+		if end > len(upgrade.Nodes) {
+			end = len(upgrade.Nodes)
+		}
+		batch := upgrade.Nodes[start:end]
+
+		// the nodes of the batch submitted before a node that could not be
+		// are awaited, so that they are not rolled back while upgrading
+		var submitErr error
+		for _, n := range batch {
+			if n.State != cce.UpgradeNodeStatePending {
+				continue
+			}
+			if submitErr = p.submitUpgradeNode(
+				ctx, upgrade, n, upgrade.TargetAppID, cce.UpgradeNodeStateUpgrading); submitErr != nil {
+				break
+			}
+		}
+		if err := p.awaitUpgradeNodes(ctx, upgrade, cce.UpgradeNodeStateUpgrading, cce.UpgradeNodeStateUpgraded); err != nil {
+			return err
+		}
+		if submitErr != nil {
+			return submitErr
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(cce.UpgradeHealthCheckDelay):
+		}
+
+		for _, n := range batch {
+			if err := checkUpgradeNodeHealth(ctx, p.controller.PersistenceService, n); err != nil {
+				n.State = cce.UpgradeNodeStateFailed
+				n.Error = err.Error()
+				if updateErr := p.updateUpgrade(ctx, upgrade); updateErr != nil {
+					log.Errf("Error updating upgrade %s: %v", upgrade.ID, updateErr)
+				}
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// rollBack returns every node the upgrade touched to the version it ran
+// before. Nodes that failed to upgrade are rolled back as well since they may
+// have been upgraded partially.
+func (p *operationPool) rollBack(ctx context.Context, upgrade *cce.AppUpgrade) error {
+	for _, n := range upgrade.Nodes {
+		switch n.State {
+		case cce.UpgradeNodeStateUpgrading, cce.UpgradeNodeStateUpgraded, cce.UpgradeNodeStateFailed:
+			if err := p.submitUpgradeNode(ctx, upgrade, n, n.FromAppID, cce.UpgradeNodeStateRollingBack); err != nil {
+				return err
+			}
+		}
+	}
+
+	return p.awaitUpgradeNodes(ctx, upgrade, cce.UpgradeNodeStateRollingBack, cce.UpgradeNodeStateRolledBack)
+}
+
+// submitUpgradeNode submits the operation that moves a node to the given
+// version of the app. Like a request changing the node app, it fails if
+// another operation of the node app has not finished yet.
+func (p *operationPool) submitUpgradeNode(
+	ctx context.Context,
+	upgrade *cce.AppUpgrade,
+	n *cce.AppUpgradeNode,
+	appID string,
+	state string,
+) error {
+	op := &cce.Operation{
+		Type:    cce.OperationTypeUpgrade,
+		NodeID:  n.NodeID,
+		AppID:   n.AppID,
+		Payload: toUpgradePayload(appID),
+	}
+	if _, err := p.submitNodeApp(ctx, op, true); err != nil {
+		return errors.Wrapf(err, "error submitting upgrade of node %s", n.NodeID)
+	}
+
+	n.State = state
+	n.OperationID = op.ID
+	n.Error = ""
+
+	return p.updateUpgrade(ctx, upgrade)
+}
+
+// awaitUpgradeNodes waits for the operations of the nodes in the given state
+// to finish and moves the nodes whose operation succeeded to the done state.
+// Operations queued for an unreachable node are canceled so that they do not
+// change the node after the upgrade has moved on.
+func (p *operationPool) awaitUpgradeNodes(
+	ctx context.Context,
+	upgrade *cce.AppUpgrade,
+	state string,
+	done string,
+) error {
+	var failed error
+	for _, n := range upgrade.Nodes {
+		if n.State != state {
+			continue
+		}
+
+		op, err := p.await(ctx, n.OperationID)
+		if err != nil {
+			return err
+		}
+		if op.State == cce.OperationStateQueued {
+			if _, err = p.cancel(ctx, op); err != nil {
+				return err
+			}
+		}
+
+		if op.State == cce.OperationStateSucceeded {
+			n.State = done
+			continue
+		}
+		n.State = cce.UpgradeNodeStateFailed
+		n.Error = fmt.Sprintf("operation %s is %s: %s", op.ID, op.State, op.Error)
+		if failed == nil {
+			failed = fmt.Errorf("node %s: %s", n.NodeID, n.Error)
+		}
+	}
+
+	if err := p.updateUpgrade(ctx, upgrade); err != nil {
+		return err
+	}
+
+	return failed
+}
+
+// await waits until an operation is done or queued for an unreachable node.
+func (p *operationPool) await(ctx context.Context, id string) (*cce.Operation, error) {
+	ticker := time.NewTicker(upgradePollInterval)
+	defer ticker.Stop()
+
+	for {
+		e, err := p.controller.PersistenceService.Read(ctx, id, &cce.Operation{})
+		if err != nil {
+			return nil, errors.Wrap(err, "error reading operation")
+		}
+		if e == nil {
+			return nil, fmt.Errorf("operation %s not found", id)
+		}
+		op := e.(*cce.Operation)
+		if op.Done() || op.State == cce.OperationStateQueued {
+			return op, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *operationPool) updateUpgrade(ctx context.Context, upgrade *cce.AppUpgrade) error {
+	upgrade.UpdatedAt = time.Now().UTC()
+	return p.controller.PersistenceService.BulkUpdate(ctx, []cce.Persistable{upgrade})
+}
+
+// checkUpgradeNodeHealth returns an error if the app on an upgraded node
+// reports an error or fails its health probes.
+func checkUpgradeNodeHealth(ctx context.Context, ps cce.PersistenceService, n *cce.AppUpgradeNode) error {
+	nodeApp, err := findNodeApp(ctx, ps, &cce.Operation{NodeID: n.NodeID, AppID: n.AppID})
+	if err != nil {
+		return err
+	}
+
+	resp, err := handleGetNodesApps(ctx, ps, nodeApp)
+	if err != nil {
+		return errors.Wrapf(err, "node %s: error getting app status", n.NodeID)
+	}
+	switch status := resp.(*cce.NodeAppResp).Status; status {
+	case cce.Error.String(), cce.Unhealthy.String():
+		return fmt.Errorf("node %s: app is %s after the upgrade", n.NodeID, status)
+	}
+
+	return nil
+}
+
+// runUpgradeOperation moves the app deployed to a node to the version in the
+// operation's payload.
+func runUpgradeOperation(ctx context.Context, ps cce.PersistenceService, op *cce.Operation) error {
+	var payload upgradePayload
+	if err := json.Unmarshal(op.Payload, &payload); err != nil {
+		return permanent(errors.Wrap(err, "error unmarshaling payload"))
+	}
+
+	nodeApp, err := findNodeApp(ctx, ps, op)
+	if err != nil {
+		return err
+	}
+	// a previous attempt may have upgraded the app already
+	if nodeApp.RunningAppID() == payload.AppID {
+		return nil
+	}
+
+	target, err := ps.Read(ctx, payload.AppID, &cce.App{})
+	if err != nil {
+		return errors.Wrap(err, "error reading app")
+	}
+	if target == nil {
+		return permanent(fmt.Errorf("app %s not found", payload.AppID))
+	}
+
+	if err = reportProgress(ctx, ps, op, fmt.Sprintf("switching to version %s", target.(*cce.App).Version)); err != nil {
+		return err
+	}
+	if err = handleUpgradeNodesApps(ctx, ps, nodeApp, target.(*cce.App)); err != nil {
+		return err
+	}
+
+	nodeApp.VersionID = target.GetID()
+	if nodeApp.VersionID == nodeApp.AppID {
+		nodeApp.VersionID = ""
+	}
+	if err = ps.BulkUpdate(ctx, []cce.Persistable{nodeApp}); err != nil {
+		return errors.Wrap(err, "error updating nodes_apps")
+	}
+
+	return nil
+}
+
+// handleUpgradeNodesApps replaces the version of an app running on a node. The
+// node redeploys the app in place under the ID it was deployed with. With
+// Kubernetes the node loads the image of the version under the version's ID
+// and the image of the app's deployment is replaced, while the image the app
+// was deployed with is kept until the app is undeployed.
+func handleUpgradeNodesApps(
+	ctx context.Context,
+	ps cce.PersistenceService,
+	nodeApp *cce.NodeApp,
+	target *cce.App,
+) error {
+	ctrl := getController(ctx)
+	nodePort := ctrl.EVAPort
+	if nodePort == "" {
+		nodePort = defaultEVAPort
+	}
+	nodeCC, err := connectNode(ctx, ps, nodeApp, nodePort, ctrl.EdgeNodeCreds)
+	if err != nil {
+		return errors.Wrap(err, "error connecting to node")
+	}
+	defer disconnectNode(nodeCC)
+
+	if ctrl.OrchestrationMode == cce.OrchestrationModeNative {
+		redeployed := *target
+		redeployed.ID = nodeApp.AppID
+		networks, err := getAppNetworks(ctx, ps, &redeployed)
+		if err != nil {
+			return err
+		}
+		return nodeCC.AppDeploySvcCli.Redeploy(ctx, &redeployed, networks...)
+	}
+
+	if target.ID != nodeApp.AppID && target.ImageRef() == "" {
+		if err = nodeCC.AppDeploySvcCli.Deploy(ctx, target); err != nil && !isAlreadyExists(err) {
+			return err
+		}
+	}
+	k8sApp, err := toK8SNodeApp(ctx, ps, nodeApp, target)
+	if err != nil {
+		return err
+	}
+	if err = ctrl.KubernetesClient.UpdateImage(ctx, nodeApp.NodeID, k8sApp); err != nil {
+		return err
+	}
+	if nodeApp.VersionID != "" {
+		if err = nodeCC.AppDeploySvcCli.Undeploy(ctx, nodeApp.VersionID); err != nil && !isNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func upgradeURL(id string) string {
+	return "/upgrades/" + id
+}
+
+func toAppUpgradeSummary(upgrade *cce.AppUpgrade) swagger.AppUpgradeSummary {
+	return swagger.AppUpgradeSummary{
+		ID:          upgrade.ID,
+		AppID:       upgrade.AppID,
+		TargetAppID: upgrade.TargetAppID,
+		State:       upgrade.State,
+		URL:         upgradeURL(upgrade.ID),
+	}
+}
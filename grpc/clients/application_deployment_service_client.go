@@ -6,7 +6,7 @@ package clients
 import (
 	"context"
 	"encoding/json"
-	"io/ioutil"
+	"strings"
 
 	cce "github.com/open-ness/edgecontroller"
 	"github.com/open-ness/edgecontroller/grpc"
@@ -14,11 +14,6 @@ import (
 	"github.com/pkg/errors"
 )
 
-const (
-	cniConfPath = "/artifacts/cni/cni.conf"
-	cniArgsPath = "/artifacts/cni/cni_args.json"
-)
-
 // ApplicationDeploymentServiceClient wraps the PB client.
 type ApplicationDeploymentServiceClient struct {
 	PBCli evapb.ApplicationDeploymentServiceClient
@@ -33,19 +28,24 @@ func NewApplicationDeploymentServiceClient(
 	}
 }
 
-// Deploy deploys an application. Depending on the type of the application,
+// Deploy deploys an application attached to the given networks, which must be
+// the networks of the application. Depending on the type of the application,
 // either DeployContainer or DeployVM is called on the gRPC service.
 func (c *ApplicationDeploymentServiceClient) Deploy(
 	ctx context.Context,
 	app *cce.App,
+	networks ...*cce.Network,
 ) error {
-	var err error
+	cniConf, err := toPBCNIConf(app.ID, networks)
+	if err != nil {
+		return errors.Wrap(err, "error deploying application")
+	}
 
 	switch app.Type {
 	case "container":
-		_, err = c.PBCli.DeployContainer(ctx, toPBApp(app))
+		_, err = c.PBCli.DeployContainer(ctx, toPBApp(app, cniConf))
 	case "vm":
-		_, err = c.PBCli.DeployVM(ctx, toPBApp(app))
+		_, err = c.PBCli.DeployVM(ctx, toPBApp(app, cniConf))
 	}
 
 	if err != nil {
@@ -55,38 +55,27 @@ func (c *ApplicationDeploymentServiceClient) Deploy(
 	return nil
 }
 
-// TODO: Find better place for this function
-func getCNIConf() (*evapb.CNIConfiguration, error) {
-	cniConf, err := ioutil.ReadFile(cniConfPath)
-	if err != nil {
-		return nil, errors.Wrapf(err, "failed to read file with CNI configuration: %s", cniConfPath)
-	}
-
-	cniArgsContents, err := ioutil.ReadFile(cniArgsPath)
-	if err != nil {
-		return nil, errors.Wrapf(err, "failed to read file with CNI args: %s", cniArgsPath)
-	}
-
-	var cniArgs struct {
-		InterfaceName string
-		Path          string
-		ExtraArgs     string
-	}
-
-	err = json.Unmarshal(cniArgsContents, &cniArgs)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to unmarshal CNI args")
+// toPBCNIConf returns the CNI configuration of the network an application is
+// attached to, or nil if it has none. The node attaches an application to a
+// single network, identifying it with an appID arg.
+func toPBCNIConf(appID string, networks []*cce.Network) (*evapb.CNIConfiguration, error) {
+	switch len(networks) {
+	case 0:
+		return nil, nil
+	case 1:
+	default:
+		return nil, errors.Errorf("%d networks requested, the node supports a single network per app",
+			len(networks))
 	}
 
 	return &evapb.CNIConfiguration{
-		CniConfig:     string(cniConf),
-		InterfaceName: cniArgs.InterfaceName,
-		Path:          cniArgs.Path,
-		Args:          cniArgs.ExtraArgs,
+		CniConfig:     string(networks[0].CNIConfig),
+		InterfaceName: networks[0].InterfaceName,
+		Args:          strings.TrimPrefix(networks[0].Args+";appID="+appID, ";"),
 	}, nil
 }
 
-func toPBApp(app *cce.App) *evapb.Application {
+func toPBApp(app *cce.App, cniConf *evapb.CNIConfiguration) *evapb.Application {
 	var ports []*evapb.PortProto
 	for _, pp := range app.Ports {
 		// If the protocol is "all", make it empty in the protobuf
@@ -122,23 +111,62 @@ func toPBApp(app *cce.App) *evapb.Application {
 			},
 		},
 		EACJsonBlob: string(tmp),
+		Command:     app.Command,
+		Args:        app.Args,
+
+		LivenessProbe:  toPBProbe(app.LivenessProbe),
+		ReadinessProbe: toPBProbe(app.ReadinessProbe),
+		RestartPolicy:  app.RestartPolicy,
+		CniConf:        cniConf,
+		Digest:         app.ImageDigest(),
 	}
-
-	cniConf, err := getCNIConf()
-	if err == nil {
-		pb.CniConf = cniConf
-		pb.CniConf.Args = pb.CniConf.Args + ";appID=" + app.ID
+	for _, env := range app.Env {
+		pb.Env = append(pb.Env, &evapb.EnvVar{Name: env.Name, Value: env.Value})
+	}
+	for _, v := range app.Volumes {
+		pb.Volumes = append(pb.Volumes, &evapb.VolumeMount{
+			Name:      v.Name,
+			MountPath: v.MountPath,
+			HostPath:  v.HostPath,
+			ReadOnly:  v.ReadOnly,
+		})
+	}
+	for _, f := range app.ConfigFiles {
+		pb.ConfigFiles = append(pb.ConfigFiles, &evapb.ConfigFile{Path: f.Path, Content: f.Content})
 	}
 
 	return &pb
 }
 
-// Redeploy redeploys an application.
+func toPBProbe(probe *cce.Probe) *evapb.Probe {
+	if probe == nil {
+		return nil
+	}
+
+	return &evapb.Probe{
+		Type:                probe.Type,
+		Path:                probe.Path,
+		Port:                probe.Port,
+		Command:             probe.Command,
+		InitialDelaySeconds: int32(probe.InitialDelaySeconds),
+		PeriodSeconds:       int32(probe.PeriodSeconds),
+		TimeoutSeconds:      int32(probe.TimeoutSeconds),
+		FailureThreshold:    int32(probe.FailureThreshold),
+	}
+}
+
+// Redeploy redeploys an application attached to the given networks.
 func (c *ApplicationDeploymentServiceClient) Redeploy(
 	ctx context.Context,
 	app *cce.App,
+	networks ...*cce.Network,
 ) error {
-	_, err := c.PBCli.Redeploy(ctx, toPBApp(app))
+	cniConf, err := toPBCNIConf(app.ID, networks)
+	if err != nil {
+		return errors.Wrap(err, "error redeploying application")
+	}
+
+	_, err = c.PBCli.Redeploy(ctx, toPBApp(app, cniConf))
 
 	if err != nil {
 		return errors.Wrap(err, "error redeploying application")
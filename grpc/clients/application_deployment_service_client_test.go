@@ -4,6 +4,8 @@
 package clients_test
 
 import (
+	"encoding/json"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	cce "github.com/open-ness/edgecontroller"
@@ -74,7 +76,35 @@ var _ = Describe("Application Deployment Service Client", func() {
 			})
 		})
 
-		Describe("Errors", func() {})
+		Describe("Errors", func() {
+			It("Should return an error if the app is attached to several networks", func() {
+				network := &cce.Network{
+					ID:        uuid.New(),
+					Name:      "dataplane",
+					CNIConfig: json.RawMessage(`{"cniVersion":"0.3.1","type":"macvlan"}`),
+				}
+
+				By("Deploying an application attached to two networks")
+				err := appDeploySvcCli.Deploy(
+					ctx,
+					&cce.App{
+						ID:      uuid.New(),
+						Type:    "container",
+						Name:    "test_container_app",
+						Vendor:  "test_vendor",
+						Version: "latest",
+						Cores:   4,
+						Memory:  4096,
+						Source:  "http://path/to/file.zip",
+					},
+					network,
+					network)
+
+				By("Verifying the error")
+				Expect(err).To(MatchError("error deploying application: " +
+					"2 networks requested, the node supports a single network per app"))
+			})
+		})
 	})
 
 	Describe("GetStatus", func() {
@@ -5,6 +5,7 @@ package clients
 
 import (
 	"context"
+	"io"
 
 	cce "github.com/open-ness/edgecontroller"
 	"github.com/open-ness/edgecontroller/grpc"
@@ -98,3 +99,59 @@ func (c *ApplicationLifecycleServiceClient) GetStatus(
 
 	return fromPBLifecycleStatus(pbStatus), nil
 }
+
+// GetLogs streams an application's logs. The stream ends when the context is
+// canceled or the reader is closed.
+func (c *ApplicationLifecycleServiceClient) GetLogs(
+	ctx context.Context,
+	id string,
+	opts cce.NodeAppLogOptions,
+) (io.ReadCloser, error) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	req := &evapb.LogRequest{
+		Id:     id,
+		Follow: opts.Follow,
+		Tail:   opts.Tail,
+	}
+	if !opts.Since.IsZero() {
+		req.Since = opts.Since.Unix()
+	}
+
+	stream, err := c.PBCli.GetLogs(ctx, req)
+	if err != nil {
+		cancel()
+		return nil, errors.Wrap(err, "error retrieving application logs")
+	}
+
+	return &logReader{stream: stream, cancel: cancel}, nil
+}
+
+// logReader reads the chunks of a log stream.
+type logReader struct {
+	stream evapb.ApplicationLifecycleService_GetLogsClient
+	cancel context.CancelFunc
+	buf    []byte
+}
+
+func (r *logReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		chunk, err := r.stream.Recv()
+		if err != nil {
+			if err != io.EOF {
+				err = errors.Wrap(err, "error retrieving application logs")
+			}
+			return 0, err
+		}
+		r.buf = chunk.Data
+	}
+
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+func (r *logReader) Close() error {
+	r.cancel()
+	return nil
+}
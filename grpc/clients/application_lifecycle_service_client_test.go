@@ -4,6 +4,10 @@
 package clients_test
 
 import (
+	"fmt"
+	"io/ioutil"
+	"time"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	cce "github.com/open-ness/edgecontroller"
@@ -222,4 +226,66 @@ var _ = Describe("Application Lifecycle Service Client", func() {
 			})
 		})
 	})
+
+	Describe("GetLogs", func() {
+		Describe("Success", func() {
+			It("Should get the logs of applications", func() {
+				By("Starting and stopping the container application")
+				Expect(appLifeSvcCli.Start(ctx, containerAppID)).To(Succeed())
+				Expect(appLifeSvcCli.Stop(ctx, containerAppID)).To(Succeed())
+
+				By("Getting the logs of the container application")
+				logs, err := appLifeSvcCli.GetLogs(ctx, containerAppID, cce.NodeAppLogOptions{})
+				Expect(err).ToNot(HaveOccurred())
+				defer logs.Close()
+
+				By("Verifying the logs")
+				Expect(ioutil.ReadAll(logs)).To(Equal([]byte(fmt.Sprintf(
+					"Application %[1]s deployed\nApplication %[1]s started\nApplication %[1]s stopped\n",
+					containerAppID))))
+			})
+
+			It("Should get the last lines of the logs of applications", func() {
+				By("Starting and stopping the container application")
+				Expect(appLifeSvcCli.Start(ctx, containerAppID)).To(Succeed())
+				Expect(appLifeSvcCli.Stop(ctx, containerAppID)).To(Succeed())
+
+				By("Getting the last line of the logs of the container application")
+				logs, err := appLifeSvcCli.GetLogs(ctx, containerAppID, cce.NodeAppLogOptions{Tail: 1})
+				Expect(err).ToNot(HaveOccurred())
+				defer logs.Close()
+
+				By("Verifying the logs")
+				Expect(ioutil.ReadAll(logs)).To(Equal([]byte(fmt.Sprintf(
+					"Application %s stopped\n", containerAppID))))
+			})
+
+			It("Should get no logs written after since", func() {
+				By("Getting the logs of the container application from a minute on")
+				logs, err := appLifeSvcCli.GetLogs(ctx, containerAppID, cce.NodeAppLogOptions{
+					Since: time.Now().Add(time.Minute),
+				})
+				Expect(err).ToNot(HaveOccurred())
+				defer logs.Close()
+
+				By("Verifying the logs are empty")
+				Expect(ioutil.ReadAll(logs)).To(BeEmpty())
+			})
+		})
+
+		Describe("Errors", func() {
+			It("Should return an error if the application does not exist", func() {
+				By("Getting the logs of a nonexistent application")
+				id := uuid.New()
+				logs, err := appLifeSvcCli.GetLogs(ctx, id, cce.NodeAppLogOptions{})
+				Expect(err).ToNot(HaveOccurred())
+				defer logs.Close()
+
+				By("Verifying a NotFound response once the logs are read")
+				_, err = ioutil.ReadAll(logs)
+				Expect(errors.Cause(err)).To(Equal(
+					status.Errorf(codes.NotFound, "Application %s not found", id)))
+			})
+		})
+	})
 })
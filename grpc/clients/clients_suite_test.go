@@ -52,6 +52,11 @@ var (
 			MockNode: mockNode,
 		},
 	}
+	portSvcCli = &gclients.PortServiceClient{
+		PBCli: &ctrlgmock.MockPBPortServiceClient{
+			MockNode: mockNode,
+		},
+	}
 )
 
 func TestApplicationClient(t *testing.T) {
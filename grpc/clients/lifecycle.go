@@ -26,6 +26,8 @@ func fromPBLifecycleStatus(status *evapb.LifecycleStatus) cce.LifecycleStatus {
 		return cce.Stopped
 	case evapb.LifecycleStatus_ERROR:
 		return cce.Error
+	case evapb.LifecycleStatus_UNHEALTHY:
+		return cce.Unhealthy
 	default:
 		return cce.Unknown
 	}
@@ -0,0 +1,75 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package clients
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/open-ness/edgecontroller/grpc"
+	ifsvcpb "github.com/open-ness/edgecontroller/pb/interfaceservice"
+	"github.com/pkg/errors"
+)
+
+// PortServiceClient wraps the PB client of the node's interface service,
+// which attaches network interfaces to OVS bridges.
+type PortServiceClient struct {
+	PBCli ifsvcpb.InterfaceServiceClient
+}
+
+// NewPortServiceClient creates a new client.
+func NewPortServiceClient(conn *grpc.ClientConn) *PortServiceClient {
+	return &PortServiceClient{
+		conn.NewPortServiceClient(),
+	}
+}
+
+// GetAll retrieves all ports of the node.
+func (c *PortServiceClient) GetAll(
+	ctx context.Context,
+) ([]*ifsvcpb.Port, error) {
+	ports, err := c.PBCli.Get(ctx, &empty.Empty{})
+
+	if err != nil {
+		return nil, errors.Wrap(err, "error retrieving all ports")
+	}
+
+	return ports.Ports, nil
+}
+
+// Attach attaches ports to the bridges and with the drivers they specify.
+func (c *PortServiceClient) Attach(
+	ctx context.Context,
+	ports []*ifsvcpb.Port,
+) error {
+	_, err := c.PBCli.Attach(
+		ctx,
+		&ifsvcpb.Ports{
+			Ports: ports,
+		})
+
+	if err != nil {
+		return errors.Wrap(err, "error attaching ports")
+	}
+
+	return nil
+}
+
+// Detach detaches ports from their bridges.
+func (c *PortServiceClient) Detach(
+	ctx context.Context,
+	ports []*ifsvcpb.Port,
+) error {
+	_, err := c.PBCli.Detach(
+		ctx,
+		&ifsvcpb.Ports{
+			Ports: ports,
+		})
+
+	if err != nil {
+		return errors.Wrap(err, "error detaching ports")
+	}
+
+	return nil
+}
@@ -0,0 +1,170 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package clients_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	ifsvcpb "github.com/open-ness/edgecontroller/pb/interfaceservice"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var _ = Describe("Port Service Client", func() {
+	BeforeEach(func() {
+		By("Resetting the node")
+		mockNode.Reset()
+	})
+
+	Describe("GetAll", func() {
+		Describe("Success", func() {
+			It("Should get all ports", func() {
+				By("Getting all ports")
+				ports, err := portSvcCli.GetAll(ctx)
+
+				By("Verifying the response contains both ports")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(ports).To(Equal(
+					[]*ifsvcpb.Port{
+						{
+							Pci:        "0000:00:00.0",
+							Driver:     ifsvcpb.Port_NONE,
+							MacAddress: "mac0",
+						},
+						{
+							Pci:        "0000:00:00.1",
+							Driver:     ifsvcpb.Port_NONE,
+							MacAddress: "mac1",
+						},
+					},
+				))
+			})
+		})
+
+		Describe("Errors", func() {})
+	})
+
+	Describe("Attach", func() {
+		Describe("Success", func() {
+			It("Should attach ports", func() {
+				By("Attaching the second port")
+				err := portSvcCli.Attach(
+					ctx,
+					[]*ifsvcpb.Port{
+						{
+							Pci:    "0000:00:00.1",
+							Bridge: "br-local",
+							Driver: ifsvcpb.Port_USERSPACE,
+						},
+					},
+				)
+
+				By("Verifying a success response")
+				Expect(err).ToNot(HaveOccurred())
+
+				By("Getting all ports")
+				ports, err := portSvcCli.GetAll(ctx)
+
+				By("Verifying the second port is attached")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(ports[1]).To(Equal(
+					&ifsvcpb.Port{
+						Pci:        "0000:00:00.1",
+						Bridge:     "br-local",
+						Driver:     ifsvcpb.Port_USERSPACE,
+						MacAddress: "mac1",
+					},
+				))
+			})
+		})
+
+		Describe("Errors", func() {
+			It("Should return an error if the port does not exist", func() {
+				By("Passing a nonexistent PCI address")
+				err := portSvcCli.Attach(
+					ctx,
+					[]*ifsvcpb.Port{
+						{
+							Pci:    "0000:00:00.9",
+							Bridge: "br-local",
+							Driver: ifsvcpb.Port_KERNEL,
+						},
+					},
+				)
+
+				By("Verifying a NotFound response")
+				Expect(err).To(HaveOccurred())
+				Expect(errors.Cause(err)).To(Equal(
+					status.Errorf(codes.NotFound,
+						"Port %s not found", "0000:00:00.9")))
+			})
+		})
+	})
+
+	Describe("Detach", func() {
+		Describe("Success", func() {
+			It("Should detach ports", func() {
+				By("Attaching the first port")
+				err := portSvcCli.Attach(
+					ctx,
+					[]*ifsvcpb.Port{
+						{
+							Pci:    "0000:00:00.0",
+							Bridge: "br-local",
+							Driver: ifsvcpb.Port_KERNEL,
+						},
+					},
+				)
+				Expect(err).ToNot(HaveOccurred())
+
+				By("Detaching the first port")
+				err = portSvcCli.Detach(
+					ctx,
+					[]*ifsvcpb.Port{
+						{
+							Pci: "0000:00:00.0",
+						},
+					},
+				)
+
+				By("Verifying a success response")
+				Expect(err).ToNot(HaveOccurred())
+
+				By("Getting all ports")
+				ports, err := portSvcCli.GetAll(ctx)
+
+				By("Verifying the first port is detached")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(ports[0]).To(Equal(
+					&ifsvcpb.Port{
+						Pci:        "0000:00:00.0",
+						Driver:     ifsvcpb.Port_NONE,
+						MacAddress: "mac0",
+					},
+				))
+			})
+		})
+
+		Describe("Errors", func() {
+			It("Should return an error if the port does not exist", func() {
+				By("Passing a nonexistent PCI address")
+				err := portSvcCli.Detach(
+					ctx,
+					[]*ifsvcpb.Port{
+						{
+							Pci: "0000:00:00.9",
+						},
+					},
+				)
+
+				By("Verifying a NotFound response")
+				Expect(err).To(HaveOccurred())
+				Expect(errors.Cause(err)).To(Equal(
+					status.Errorf(codes.NotFound,
+						"Port %s not found", "0000:00:00.9")))
+			})
+		})
+	})
+})
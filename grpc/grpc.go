@@ -15,6 +15,7 @@ import (
 	logger "github.com/open-ness/common/log"
 	elapb "github.com/open-ness/edgecontroller/pb/ela"
 	evapb "github.com/open-ness/edgecontroller/pb/eva"
+	ifsvcpb "github.com/open-ness/edgecontroller/pb/interfaceservice"
 )
 
 var log = logger.DefaultLogger.WithField("pkg", "grpc")
@@ -82,3 +83,8 @@ func (c *ClientConn) NewZoneServiceClient() elapb.ZoneServiceClient {
 func (c *ClientConn) NewDNSServiceClient() elapb.DNSServiceClient {
 	return elapb.NewDNSServiceClient(c.conn)
 }
+
+// NewPortServiceClient wraps the pb function.
+func (c *ClientConn) NewPortServiceClient() ifsvcpb.InterfaceServiceClient {
+	return ifsvcpb.NewInterfaceServiceClient(c.conn)
+}
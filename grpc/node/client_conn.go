@@ -28,6 +28,7 @@ type ClientConn struct {
 	IfaceSvcCli       *gclients.InterfaceServiceClient
 	DNSSvcCli         *gclients.DNSServiceClient
 	ZoneSvcCli        *gclients.ZoneServiceClient
+	PortSvcCli        *gclients.PortServiceClient
 }
 
 // Connect connects to a node via grpc.Dial.
@@ -55,7 +56,8 @@ func (cc *ClientConn) Connect(ctx context.Context) error {
 		cc.DNSSvcCli = gclients.NewDNSServiceClient(cc.conn)
 		cc.IfaceSvcCli = gclients.NewInterfaceServiceClient(cc.conn)
 
-		cc.ZoneSvcCli = gclients.NewZoneServiceClient(cc.conn) // XXX unimplemented?
+		cc.ZoneSvcCli = gclients.NewZoneServiceClient(cc.conn)
+		cc.PortSvcCli = gclients.NewPortServiceClient(cc.conn)
 	}
 
 	return err
@@ -54,40 +54,46 @@ type Server struct {
 func NewServer(controller *cce.Controller, conf *tls.Config) *Server {
 	s := &Server{
 		controller: controller,
-		grpc: grpc.NewServer(
-			grpc.Creds(credentials.NewTLS(conf)),
-			grpc.UnaryInterceptor(
-				func(
-					ctx context.Context,
-					req interface{},
-					info *grpc.UnaryServerInfo,
-					handler grpc.UnaryHandler,
-				) (resp interface{}, err error) {
-					// apply checkAuth middleware
-					if err := checkAuth(ctx,
-						info.FullMethod); err != nil {
-						return nil, err
-					}
-					return handler(ctx, req)
-				},
-			),
-			grpc.StreamInterceptor(
-				func(
-					srv interface{},
-					ss grpc.ServerStream,
-					info *grpc.StreamServerInfo,
-					handler grpc.StreamHandler,
-				) error {
-					// apply checkAuth middleware
-					if err := checkAuth(ss.Context(),
-						info.FullMethod); err != nil {
-						return err
-					}
-					return handler(srv, ss)
-				},
-			),
-		),
 	}
+	s.grpc = grpc.NewServer(
+		grpc.Creds(credentials.NewTLS(conf)),
+		grpc.UnaryInterceptor(
+			func(
+				ctx context.Context,
+				req interface{},
+				info *grpc.UnaryServerInfo,
+				handler grpc.UnaryHandler,
+			) (resp interface{}, err error) {
+				// apply checkAuth middleware
+				if err := checkAuth(ctx,
+					info.FullMethod); err != nil {
+					return nil, err
+				}
+				if err := s.checkRevoked(ctx); err != nil {
+					return nil, err
+				}
+				return handler(ctx, req)
+			},
+		),
+		grpc.StreamInterceptor(
+			func(
+				srv interface{},
+				ss grpc.ServerStream,
+				info *grpc.StreamServerInfo,
+				handler grpc.StreamHandler,
+			) error {
+				// apply checkAuth middleware
+				if err := checkAuth(ss.Context(),
+					info.FullMethod); err != nil {
+					return err
+				}
+				if err := s.checkRevoked(ss.Context()); err != nil {
+					return err
+				}
+				return handler(srv, ss)
+			},
+		),
+	)
 
 	authpb.RegisterAuthServiceServer(s.grpc, s)
 	evapb.RegisterControllerVirtualizationAgentServer(s.grpc, s)
@@ -121,6 +127,41 @@ func checkAuth(ctx context.Context, method string) error {
 	}
 }
 
+// checkRevoked rejects nodes whose credentials were revoked when the node was
+// decommissioned. Only connections made with the post-enrollment config are
+// checked. The credentials of a node are read once and then looked up in the
+// revocation cache of the controller.
+func (s *Server) checkRevoked(ctx context.Context) error {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return fmt.Errorf("expected peer info in gRPC context")
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || tlsInfo.State.ServerName != SNI {
+		return nil
+	}
+
+	nodeID, err := getNodeID(ctx)
+	if err != nil {
+		return err
+	}
+	revoked, ok := s.controller.Revocations.Lookup(nodeID)
+	if !ok {
+		creds, err := s.controller.PersistenceService.Read(ctx, nodeID, &cce.Credentials{})
+		if err != nil {
+			log.Errf("error reading credentials of node %s: %v", nodeID, err)
+			return status.Error(codes.Internal, "unable to read credentials")
+		}
+		revoked = creds == nil
+		s.controller.Revocations.Set(nodeID, revoked)
+	}
+	if revoked {
+		return status.Errorf(codes.Unauthenticated, "credentials of node %s were revoked", nodeID)
+	}
+
+	return nil
+}
+
 // Serve wraps grpc.Server.Serve.
 func (s *Server) Serve(lis net.Listener) error {
 	return s.grpc.Serve(lis)
@@ -231,6 +272,7 @@ func (s *Server) RequestCredentials(ctx context.Context, id *authpb.Identity) (
 		log.Errf("Failed to store Node credentials: %v", err)
 		return nil, status.Error(codes.Internal, "unable to store credentials")
 	}
+	s.controller.Revocations.Set(node.ID, false)
 
 	// Get the Node's IP address
 	p, ok := peer.FromContext(ctx)
@@ -18,10 +18,16 @@ type JWSTokenIssuer struct {
 	KeyAlgorithm string
 }
 
-// Issue issues a new JWT token signed with the authority key and valid for one
-// day. The signed JWT token is returned in the RFC 7519 compact serialization
-// format.
-func (s *JWSTokenIssuer) Issue() (string, error) {
+// roleClaims are the private claims of a token, granting the role of the user
+// it was issued to.
+type roleClaims struct {
+	Role string `json:"role"`
+}
+
+// Issue issues a new JWT token granting the role, signed with the authority
+// key and valid for one day. The signed JWT token is returned in the RFC 7519
+// compact serialization format.
+func (s *JWSTokenIssuer) Issue(role string) (string, error) {
 	signer, err := jose.NewSigner(
 		jose.SigningKey{
 			Key:       s.Key,
@@ -36,28 +42,38 @@ func (s *JWSTokenIssuer) Issue() (string, error) {
 		Expiry: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)), // 1 day
 	}
 
-	return jwt.Signed(signer).Claims(claims).CompactSerialize()
+	return jwt.Signed(signer).Claims(claims).Claims(roleClaims{Role: role}).CompactSerialize()
 }
 
 // Validate validates the JWT token was signed with the authority key and has
-// not yet expired. The signed JWT token is expected to be in the RFC 7519
-// compact serialization format.
-func (s *JWSTokenIssuer) Validate(t string) error {
+// not yet expired and returns the role it grants. The signed JWT token is
+// expected to be in the RFC 7519 compact serialization format.
+func (s *JWSTokenIssuer) Validate(t string) (string, error) {
 	token, err := jwt.ParseSigned(t)
 	if err != nil {
-		return errors.Wrap(err, "unable to parse token")
+		return "", errors.Wrap(err, "unable to parse token")
 	}
 
 	key, ok := s.Key.(crypto.Signer)
 	if !ok {
-		return errors.Wrap(err, "invalid signing key")
+		return "", errors.Wrap(err, "invalid signing key")
 	}
 
-	var claims jwt.Claims
-	err = token.Claims(key.Public(), &claims)
+	var (
+		claims jwt.Claims
+		role   roleClaims
+	)
+	err = token.Claims(key.Public(), &claims, &role)
 	if err != nil {
-		return errors.Wrap(err, "unable to deserialize token claims")
+		return "", errors.Wrap(err, "unable to deserialize token claims")
+	}
+
+	if err = claims.Validate(jwt.Expected{Time: time.Now()}); err != nil {
+		return "", err
+	}
+	if role.Role == "" {
+		return "", errors.New("token grants no role")
 	}
 
-	return claims.Validate(jwt.Expected{Time: time.Now()})
+	return role.Role, nil
 }
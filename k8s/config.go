@@ -0,0 +1,55 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package k8s
+
+import (
+	"github.com/pkg/errors"
+	restClient "k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// restConfig returns the configuration of the connection to the Kubernetes
+// server. It is loaded from the kubeconfig if KubeConfig or KubeContext is
+// set, and from the service account of the pod if Host is not set and the
+// controller runs in a cluster. Otherwise it is built from Host and the
+// certificate files.
+//
+// Exec-based credentials of a kubeconfig are refreshed by the transport when
+// they expire or are rejected by the server.
+func (ks *Client) restConfig() (*restClient.Config, error) {
+	if ks.KubeConfig != "" || ks.KubeContext != "" {
+		rules := clientcmd.NewDefaultClientConfigLoadingRules()
+		rules.ExplicitPath = ks.KubeConfig
+		config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+			rules,
+			&clientcmd.ConfigOverrides{CurrentContext: ks.KubeContext},
+		).ClientConfig()
+		if err != nil {
+			return nil, errors.Wrap(err, "error loading kubeconfig")
+		}
+		return config, nil
+	}
+
+	if ks.Host == "" {
+		config, err := restClient.InClusterConfig()
+		switch {
+		case err == nil:
+			return config, nil
+		case err != restClient.ErrNotInCluster:
+			return nil, errors.Wrap(err, "error loading in-cluster configuration")
+		}
+	}
+
+	return &restClient.Config{
+		Host:     ks.Host,
+		APIPath:  ks.APIPath,
+		Username: ks.Username,
+		TLSClientConfig: restClient.TLSClientConfig{
+			Insecure: false,
+			CertFile: ks.CertFile,
+			KeyFile:  ks.KeyFile,
+			CAFile:   ks.CAFile,
+		},
+	}, nil
+}
@@ -27,4 +27,7 @@ const (
 
 	// Error means error occurred
 	Error LifecycleStatus = "error"
+
+	// Unhealthy means the pod is running but failing its health probes
+	Unhealthy LifecycleStatus = "unhealthy"
 )
@@ -0,0 +1,316 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	apiV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	appsListers "k8s.io/client-go/listers/apps/v1"
+	coreListers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// maxStatusEvents is the number of status transitions kept per app.
+const maxStatusEvents = 20
+
+// AppStatus is the lifecycle status of an app on a node. Reason and Message
+// explain it, e.g. ImagePullBackOff, OOMKilled or CrashLoopBackOff.
+type AppStatus struct {
+	Status  LifecycleStatus
+	Reason  string
+	Message string
+}
+
+// StatusEvent is a transition of the status of an app on a node.
+type StatusEvent struct {
+	AppStatus
+	Time time.Time
+}
+
+// appKey identifies the deployment of an app on a node.
+type appKey struct {
+	namespace string
+	nodeID    string
+	appID     string
+}
+
+type appState struct {
+	AppStatus
+	events []StatusEvent
+	// the app is deployed as a VirtualMachine
+	vm bool
+}
+
+// statusCache caches the statuses of the apps, kept up to date by shared
+// informers on the pods and deployments and on the VirtualMachines of apps.
+type statusCache struct {
+	pods        coreListers.PodLister
+	deployments appsListers.DeploymentLister
+
+	mu   sync.RWMutex
+	apps map[appKey]*appState
+}
+
+// WatchStatus caches the statuses of apps and records their transitions
+// until the context is done. While it runs, Status, AppStatus and
+// StatusEvents are served from the cache of its shared informers instead of
+// the API server. VirtualMachines are only watched if KubeVirt is installed.
+// The informers resync the cache with the API server every resync period.
+func (ks *Client) WatchStatus(ctx context.Context, resync time.Duration) error {
+	ks.connectOnce.Do(ks.init)
+	if ks.err != nil {
+		return ks.err
+	}
+
+	factory := informers.NewSharedInformerFactoryWithOptions(ks.clientSet, resync,
+		informers.WithTweakListOptions(func(options *metaV1.ListOptions) {
+			options.LabelSelector = appIDLabelKey + "," + nodeIDLabelKey
+		}))
+	podInformer := factory.Core().V1().Pods()
+	deploymentInformer := factory.Apps().V1().Deployments()
+	sc := &statusCache{
+		pods:        podInformer.Lister(),
+		deployments: deploymentInformer.Lister(),
+		apps:        make(map[appKey]*appState),
+	}
+	handler := cache.ResourceEventHandlerFuncs{
+		AddFunc:    sc.refresh,
+		UpdateFunc: func(_, obj interface{}) { sc.refresh(obj) },
+		DeleteFunc: sc.refresh,
+	}
+	podInformer.Informer().AddEventHandler(handler)
+	deploymentInformer.Informer().AddEventHandler(handler)
+
+	kubevirt, err := ks.kubevirtInstalled()
+	if err != nil {
+		return errors.Wrap(err, "watch status")
+	}
+	var vmInformer cache.SharedIndexInformer
+	if kubevirt {
+		vmInformer = cache.NewSharedIndexInformer(ks.vmListWatch(), &virtualMachine{}, resync, cache.Indexers{})
+		vmInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc:    sc.refreshVM,
+			UpdateFunc: func(_, obj interface{}) { sc.refreshVM(obj) },
+			DeleteFunc: sc.removeVM,
+		})
+		go vmInformer.Run(ctx.Done())
+	}
+
+	factory.Start(ctx.Done())
+	for informer, ok := range factory.WaitForCacheSync(ctx.Done()) {
+		if !ok {
+			return errors.Errorf("watch status: error syncing cache of %v", informer)
+		}
+	}
+	if vmInformer != nil && !cache.WaitForCacheSync(ctx.Done(), vmInformer.HasSynced) {
+		return errors.New("watch status: error syncing cache of virtual machines")
+	}
+
+	ks.cacheMu.Lock()
+	ks.statusCache = sc
+	ks.cacheMu.Unlock()
+
+	<-ctx.Done()
+
+	ks.cacheMu.Lock()
+	ks.statusCache = nil
+	ks.cacheMu.Unlock()
+	return ctx.Err()
+}
+
+// StatusEvents returns the recorded status transitions of an app on a node,
+// oldest first. Transitions are only recorded while WatchStatus runs.
+func (ks *Client) StatusEvents(ctx context.Context, tenant, nodeID, appID string) []StatusEvent {
+	sc := ks.getStatusCache()
+	if sc == nil {
+		return nil
+	}
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+	state, ok := sc.apps[appKey{tenantNamespace(tenant), nodeID, appID}]
+	if !ok {
+		return nil
+	}
+	return append([]StatusEvent(nil), state.events...)
+}
+
+func (ks *Client) getStatusCache() *statusCache {
+	ks.cacheMu.RLock()
+	defer ks.cacheMu.RUnlock()
+	return ks.statusCache
+}
+
+// status returns the cached status of an app. An app that is in neither a
+// deployment nor a VirtualMachine is not in the cache.
+func (sc *statusCache) status(key appKey) (AppStatus, error) {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+	state, ok := sc.apps[key]
+	if !ok {
+		return AppStatus{Status: Error}, errors.New("deployment not found")
+	}
+	return state.AppStatus, nil
+}
+
+// refresh updates the status of the app of a pod or deployment. The listers
+// already hold the object, so an app is complete in the cache once the last
+// of its objects is seen.
+func (sc *statusCache) refresh(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	meta, ok := obj.(metaV1.Object)
+	if !ok {
+		return
+	}
+	sc.update(appKey{
+		namespace: meta.GetNamespace(),
+		nodeID:    meta.GetLabels()[nodeIDLabelKey],
+		appID:     meta.GetLabels()[appIDLabelKey],
+	})
+}
+
+// update reads the status of an app from the listers and records it if it
+// changed. Apps without a deployment, other than VirtualMachines, are removed
+// from the cache.
+func (sc *statusCache) update(key appKey) {
+	selector := labels.SelectorFromSet(labels.Set{
+		appIDLabelKey:  key.appID,
+		nodeIDLabelKey: key.nodeID,
+	})
+	deployments, err := sc.deployments.Deployments(key.namespace).List(selector)
+	if err != nil {
+		return
+	}
+	pods, err := sc.pods.Pods(key.namespace).List(selector)
+	if err != nil {
+		return
+	}
+
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	if len(deployments) == 0 {
+		// the pods of a VirtualMachine carry the labels of its app too
+		if state, ok := sc.apps[key]; ok && !state.vm {
+			delete(sc.apps, key)
+		}
+		return
+	}
+	sc.record(key, getAppStatus(pods), false)
+}
+
+// refreshVM records the status of the app of a VirtualMachine.
+func (sc *statusCache) refreshVM(obj interface{}) {
+	vm, ok := obj.(*virtualMachine)
+	if !ok {
+		return
+	}
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	sc.record(vmAppKey(vm), AppStatus{Status: getVMStatus(vm)}, true)
+}
+
+// removeVM removes the app of a deleted VirtualMachine from the cache.
+func (sc *statusCache) removeVM(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	vm, ok := obj.(*virtualMachine)
+	if !ok {
+		return
+	}
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	delete(sc.apps, vmAppKey(vm))
+}
+
+func vmAppKey(vm *virtualMachine) appKey {
+	return appKey{
+		namespace: vm.Namespace,
+		nodeID:    vm.Labels[nodeIDLabelKey],
+		appID:     vm.Labels[appIDLabelKey],
+	}
+}
+
+// record records the status of an app if it changed. The caller holds the
+// lock of the cache.
+func (sc *statusCache) record(key appKey, status AppStatus, vm bool) {
+	state, ok := sc.apps[key]
+	if !ok {
+		state = &appState{}
+		sc.apps[key] = state
+	}
+	state.vm = vm
+	if ok && state.Status == status.Status && state.Reason == status.Reason {
+		return
+	}
+	state.AppStatus = status
+	state.events = append(state.events, StatusEvent{AppStatus: status, Time: time.Now()})
+	if len(state.events) > maxStatusEvents {
+		state.events = state.events[len(state.events)-maxStatusEvents:]
+	}
+}
+
+// getAppStatus aggregates the statuses of the pods of an app's deployment.
+// The status of a pod that is not terminating wins and is explained by the
+// reason of the pod.
+func getAppStatus(pods []*apiV1.Pod) AppStatus {
+	if len(pods) == 0 {
+		// Deployment exists, but no pod is running
+		return AppStatus{Status: Deployed}
+	}
+
+	var pod, terminating *apiV1.Pod
+	status := Unknown
+	for _, p := range pods {
+		if getPodStatus(*p) == Terminating {
+			terminating = p
+			continue
+		}
+		pod, status = p, getPodStatus(*p)
+	}
+	if pod == nil || (status == Unknown && terminating != nil) {
+		return AppStatus{Status: Terminating}
+	}
+
+	reason, message := getPodReason(pod)
+	return AppStatus{Status: status, Reason: reason, Message: message}
+}
+
+// getPodReason returns why a pod is in its status: the reason a container
+// waits or was terminated, or the reason of the pod or of its unmet
+// conditions.
+func getPodReason(pod *apiV1.Pod) (reason, message string) {
+	for _, cStatus := range pod.Status.ContainerStatuses {
+		state := cStatus.State
+		switch {
+		case state.Waiting != nil && state.Waiting.Reason != "":
+			message = state.Waiting.Message
+			// e.g. a container killed for running out of memory
+			if last := cStatus.LastTerminationState.Terminated; last != nil && last.Reason != "" {
+				message = fmt.Sprintf("last terminated: %s (exit code %d)", last.Reason, last.ExitCode)
+			}
+			return state.Waiting.Reason, message
+		case state.Terminated != nil && state.Terminated.Reason != "":
+			return state.Terminated.Reason, state.Terminated.Message
+		}
+	}
+	if pod.Status.Reason != "" {
+		return pod.Status.Reason, pod.Status.Message
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Status == apiV1.ConditionFalse && condition.Reason != "" {
+			return condition.Reason, condition.Message
+		}
+	}
+	return "", ""
+}
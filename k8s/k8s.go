@@ -5,6 +5,7 @@ package k8s
 
 import (
 	"context"
+	"crypto/sha256"
 	"fmt"
 	"strings"
 	"sync"
@@ -15,20 +16,99 @@ import (
 	autoscalingV1 "k8s.io/api/autoscaling/v1"
 	apiV1 "k8s.io/api/core/v1"
 	networkingV1 "k8s.io/api/networking/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/kubernetes"
-	restClient "k8s.io/client-go/rest"
 )
 
 // App contains the information for deploying an application with
 // Kubernetes.
 type App struct {
-	ID     string
-	Cores  int
-	Memory int // in MB
-	Image  string
-	Ports  []*PortProto
+	ID        string
+	Tenant    string // the app is deployed to the tenant's namespace
+	VM        bool   // the app is a KubeVirt VirtualMachine instead of a Deployment
+	Cores     int
+	Memory    int // in MB
+	Hugepages int // in MB
+	Image     string
+	Ports     []*PortProto
+	Networks  []Network // attached through Multus
+
+	// ImagePullPolicy overrides the pull policy of the client for the app
+	ImagePullPolicy apiV1.PullPolicy
+	// PullSecret is the login to the registry of the image, if it needs one
+	PullSecret *RegistryCredential
+
+	Env         []EnvVar
+	Command     []string
+	Args        []string
+	Volumes     []Volume
+	ConfigFiles []ConfigFile
+
+	LivenessProbe  *Probe
+	ReadinessProbe *Probe
+	// RestartPolicy must be empty or "always", which is the only policy of
+	// the pods of a deployment
+	RestartPolicy string
+}
+
+// Probe checks the health of the app's container with an HTTP GET ("http"),
+// a TCP connection ("tcp") or a command run in it ("exec").
+type Probe struct {
+	Type                string
+	Path                string
+	Port                int32
+	Command             []string
+	InitialDelaySeconds int32
+	PeriodSeconds       int32
+	TimeoutSeconds      int32
+	FailureThreshold    int32
+}
+
+// EnvVar is an environment variable set in the app's container.
+type EnvVar struct {
+	Name  string
+	Value string
+}
+
+// Volume is a directory of the node mounted in the app's container.
+type Volume struct {
+	Name      string
+	MountPath string
+	HostPath  string
+	ReadOnly  bool
+}
+
+// ConfigFile is a file mounted in the app's container from a ConfigMap.
+type ConfigFile struct {
+	Path    string
+	Content string
+}
+
+// Resources are the resources of a node that pods can request.
+type Resources struct {
+	Cores     int
+	Memory    int // in MB
+	Hugepages int // in MB
+}
+
+// ServiceAddress is the stable address of an app on a node, served by the
+// Service in front of the app's pod.
+type ServiceAddress struct {
+	Name      string
+	Type      string
+	ClusterIP string
+	Ports     []ServicePort
+}
+
+// ServicePort is a port of a Service. NodePort is set for NodePort Services
+// only.
+type ServicePort struct {
+	Port     int32
+	NodePort int32
+	Protocol string
 }
 
 // PortProto is a port and protocol tuple
@@ -38,10 +118,17 @@ type PortProto struct {
 }
 
 const (
+	// Name of the resource of hugepages backed by 2MiB pages
+	hugepages2MiResource = apiV1.ResourceName(apiV1.ResourceHugePagesPrefix + "2Mi")
+
 	// Key for the label attached to a k8s pod or k8s node containing the Node ID
 	nodeIDLabelKey = "node-id"
 	// Key for the label attached to a k8s pod containing the App ID
 	appIDLabelKey = "app-id"
+
+	// Name of the pod volume of the ConfigMap holding the app's config files,
+	// which apps cannot give their own volumes
+	configFilesVolumeName = "config-files"
 )
 
 // Client abstracts calls to k8s master API
@@ -61,19 +148,39 @@ type Client struct {
 	KeyFile  string
 	CAFile   string
 
+	// KubeConfig is the path of a kubeconfig and KubeContext the context of
+	// it to use. If either is set, the kubeconfig is used instead of Host and
+	// the authentication info, with the default loading rules filling in
+	// the other. If neither is set nor Host, the service account of the pod
+	// is used when the controller runs in a cluster.
+	KubeConfig  string
+	KubeContext string
+
 	// ImagePullPolicy specifies container retrieval policy. If not provided,
 	// PullNever policy will be used. This field is intended for overriding default
 	// policy for testing.
 	ImagePullPolicy apiV1.PullPolicy
 
+	// ServiceType is the type of the Service created for each deployed app,
+	// either ClusterIP or NodePort. If not provided, ClusterIP is used.
+	ServiceType apiV1.ServiceType
+
+	// TenantQuota limits the resources of all apps of a tenant. A zero field
+	// leaves the resource unlimited.
+	TenantQuota Resources
+
 	// NewClientSet creates a new Kubernetes clientset interface. If it is nil,
-	// a REST client with TLS will be used. This field is intended for use
-	// mocking an external connection.
+	// a REST client configured by the fields above will be used. This field
+	// is intended for use mocking an external connection.
 	NewClientSet func() (kubernetes.Interface, error)
 
 	connectOnce sync.Once
 	clientSet   kubernetes.Interface
 	err         error
+
+	// statusCache is set while WatchStatus runs
+	cacheMu     sync.RWMutex
+	statusCache *statusCache
 }
 
 // Ping checks the connection to the Kubernetes server.
@@ -90,29 +197,30 @@ func (ks *Client) init() {
 	if ks.ImagePullPolicy == "" {
 		ks.ImagePullPolicy = apiV1.PullNever
 	}
+	if ks.ServiceType == "" {
+		ks.ServiceType = apiV1.ServiceTypeClusterIP
+	}
+	if ks.ServiceType != apiV1.ServiceTypeClusterIP && ks.ServiceType != apiV1.ServiceTypeNodePort {
+		ks.err = errors.Errorf("unsupported service type %s", ks.ServiceType)
+		return
+	}
 
 	csCreate := ks.NewClientSet
 	if csCreate == nil {
 		csCreate = func() (kubernetes.Interface, error) {
-			return kubernetes.NewForConfig(
-				&restClient.Config{
-					Host:     ks.Host,
-					APIPath:  ks.APIPath,
-					Username: ks.Username,
-					TLSClientConfig: restClient.TLSClientConfig{
-						Insecure: false,
-						CertFile: ks.CertFile,
-						KeyFile:  ks.KeyFile,
-						CAFile:   ks.CAFile,
-					},
-				},
-			)
+			config, err := ks.restConfig()
+			if err != nil {
+				return nil, err
+			}
+			return kubernetes.NewForConfig(config)
 		}
 	}
 	ks.clientSet, ks.err = csCreate()
 }
 
-// Deploy creates a kubernetes deployment
+// Deploy creates a kubernetes deployment, or a KubeVirt virtual machine for
+// VM apps. Objects of the app that already exist are kept, so a failed deploy
+// can be retried.
 func (ks *Client) Deploy(ctx context.Context, nodeID string, app App) error {
 	ks.connectOnce.Do(ks.init)
 	if ks.err != nil {
@@ -129,14 +237,15 @@ func (ks *Client) Deploy(ctx context.Context, nodeID string, app App) error {
 	return nil
 }
 
-// Undeploy cascade deletes a kubernetes deployment
-func (ks *Client) Undeploy(ctx context.Context, nodeID, appID string) error {
+// Undeploy cascade deletes a kubernetes deployment or virtual machine
+func (ks *Client) Undeploy(ctx context.Context, tenant, nodeID, appID string) error {
 	ks.connectOnce.Do(ks.init)
 	if ks.err != nil {
 		return ks.err
 	}
+	namespace := tenantNamespace(tenant)
 	// make the deployment to the correct node
-	if err := ks.undeploy(nodeID, appID); err != nil {
+	if err := ks.undeploy(namespace, nodeID, appID); err != nil {
 		return errors.Wrap(err, "undeploy: un-deployment error")
 	}
 	return nil
@@ -158,29 +267,131 @@ func (ks *Client) checkNode(nodeID string) error {
 	return nil
 }
 
+// NodeAllocatable returns the resources of a node that pods can request.
+func (ks *Client) NodeAllocatable(ctx context.Context, nodeID string) (*Resources, error) {
+	ks.connectOnce.Do(ks.init)
+	if ks.err != nil {
+		return nil, ks.err
+	}
+
+	nodeList, err := ks.clientSet.CoreV1().Nodes().List(
+		metaV1.ListOptions{
+			LabelSelector: fmt.Sprintf("%s=%s", nodeIDLabelKey, nodeID),
+		},
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "get kubernetes node list error")
+	}
+	if len(nodeList.Items) != 1 {
+		return nil, errors.Errorf("%d kubernetes nodes labeled with node id %s", len(nodeList.Items), nodeID)
+	}
+
+	allocatable := nodeList.Items[0].Status.Allocatable
+	resources := &Resources{
+		Cores:  int(allocatable.Cpu().MilliValue() / 1000),
+		Memory: int(allocatable.Memory().Value() / (1024 * 1024)),
+	}
+	for name, quantity := range allocatable {
+		if strings.HasPrefix(string(name), apiV1.ResourceHugePagesPrefix) {
+			resources.Hugepages += int(quantity.Value() / (1024 * 1024))
+		}
+	}
+
+	return resources, nil
+}
+
 // create a kubernetes deployment
 func (ks *Client) deploy(nodeID string, app App) error {
-	protoConverter := map[string]apiV1.Protocol{
-		"tcp":  apiV1.ProtocolTCP,
-		"udp":  apiV1.ProtocolUDP,
-		"sctp": apiV1.ProtocolSCTP,
+	namespace := tenantNamespace(app.Tenant)
+	if err := ks.ensureTenant(namespace, app.Tenant); err != nil {
+		return err
+	}
+	if app.VM {
+		return ks.deployVM(namespace, nodeID, app)
 	}
 
-	var ports []apiV1.ContainerPort
-	for _, portProt := range app.Ports {
-		proto, ok := protoConverter[portProt.Protocol]
-		if !ok {
-			return errors.New("unsupported protocol for kubernetes error")
-		}
-		ports = append(ports, apiV1.ContainerPort{
-			ContainerPort: portProt.Port,
-			Protocol:      proto,
-		})
+	ports, err := toContainerPorts(app.Ports)
+	if err != nil {
+		return err
+	}
+
+	limits := apiV1.ResourceList{
+		// CPU, in cores. (500m = .5 cores)
+		apiV1.ResourceCPU: *resource.NewQuantity(
+			int64(app.Cores),
+			resource.DecimalSI,
+		),
+
+		// Memory, in bytes. (500Gi = 500GiB = 500 * 1024 * 1024 * 1024)
+		apiV1.ResourceMemory: *resource.NewQuantity(
+			int64(1024*1024*app.Memory),
+			resource.BinarySI,
+		),
+
+		// Volume size, in bytes (e,g. 5Gi = 5GiB = 5 * 1024 * 1024 * 1024)
+		// apiV1.ResourceStorage: resource.MustParse(d.Storage),
+
+		// Local ephemeral storage, in bytes. (500Gi = 500GiB = 500 * 1024 * 1024 * 1024)
+		// The resource name for ResourceEphemeralStorage is alpha and it can change
+		// across releases.
+		// apiV1.ResourceEphemeralStorage: resource.MustParse(d.EphemeralStorage),
+	}
+	if app.Hugepages > 0 {
+		// Hugepages, in bytes, backed by 2MiB pages
+		limits[hugepages2MiResource] = *resource.NewQuantity(
+			int64(1024*1024*app.Hugepages),
+			resource.BinarySI,
+		)
+	}
+
+	if app.RestartPolicy != "" && app.RestartPolicy != "always" {
+		return errors.Errorf("restart policy %s is not supported by kubernetes deployments", app.RestartPolicy)
+	}
+	livenessProbe, err := toK8SProbe(app.LivenessProbe)
+	if err != nil {
+		return errors.Wrap(err, "liveness probe error")
+	}
+	readinessProbe, err := toK8SProbe(app.ReadinessProbe)
+	if err != nil {
+		return errors.Wrap(err, "readiness probe error")
+	}
+
+	volumes, mounts, err := ks.createVolumes(namespace, nodeID, app)
+	if err != nil {
+		return err
+	}
+	pullSecrets, err := ks.applyPullSecret(namespace, app.PullSecret)
+	if err != nil {
+		return err
+	}
+	networksAnnotation, err := ks.applyNetworks(namespace, app.Networks)
+	if err != nil {
+		return err
+	}
+	var annotations map[string]string
+	if networksAnnotation != "" {
+		annotations = map[string]string{multusNetworksAnnotation: networksAnnotation}
+	}
+
+	var env []apiV1.EnvVar
+	for _, e := range app.Env {
+		env = append(env, apiV1.EnvVar{Name: e.Name, Value: e.Value})
+	}
+
+	// a deployment left behind by a deploy that failed after creating it,
+	// e.g. when retried, is kept rather than duplicated under a new name
+	deploymentsClient := ks.clientSet.AppsV1().Deployments(namespace)
+	existing, err := deploymentsClient.List(metaV1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s,%s=%s", appIDLabelKey, app.ID, nodeIDLabelKey, nodeID),
+	})
+	if err != nil {
+		return errors.Wrap(err, "error getting list of deployments")
+	}
+	if len(existing.Items) != 0 {
+		return ks.applyService(namespace, nodeID, app.ID, ports)
 	}
 
-	// deployment client
-	deploymentsClient := ks.clientSet.AppsV1().Deployments(apiV1.NamespaceDefault)
-	_, err := deploymentsClient.Create(&appsV1.Deployment{
+	_, err = deploymentsClient.Create(&appsV1.Deployment{
 		ObjectMeta: metaV1.ObjectMeta{
 			GenerateName: "app",
 			Labels: map[string]string{
@@ -209,37 +420,24 @@ func (ks *Client) deploy(nodeID string, app App) error {
 						appIDLabelKey:  app.ID,
 						nodeIDLabelKey: nodeID,
 					},
+					Annotations: annotations,
 				},
 				Spec: apiV1.PodSpec{
 					Containers: []apiV1.Container{
 						{
 							Resources: apiV1.ResourceRequirements{
-								Limits: apiV1.ResourceList{
-									// CPU, in cores. (500m = .5 cores)
-									apiV1.ResourceCPU: *resource.NewQuantity(
-										int64(app.Cores),
-										resource.DecimalSI,
-									),
-
-									// Memory, in bytes. (500Gi = 500GiB = 500 * 1024 * 1024 * 1024)
-									apiV1.ResourceMemory: *resource.NewQuantity(
-										int64(1024*1024*app.Memory),
-										resource.BinarySI,
-									),
-
-									// Volume size, in bytes (e,g. 5Gi = 5GiB = 5 * 1024 * 1024 * 1024)
-									// apiV1.ResourceStorage: resource.MustParse(d.Storage),
-
-									// Local ephemeral storage, in bytes. (500Gi = 500GiB = 500 * 1024 * 1024 * 1024)
-									// The resource name for ResourceEphemeralStorage is alpha and it can change
-									// across releases.
-									// apiV1.ResourceEphemeralStorage: resource.MustParse(d.EphemeralStorage),
-								},
+								Limits: limits,
 							},
 							Name:            uuid.New(),
-							Image:           app.ID,
+							Image:           app.Image,
+							Command:         app.Command,
+							Args:            app.Args,
+							Env:             env,
 							Ports:           ports,
-							ImagePullPolicy: ks.ImagePullPolicy,
+							VolumeMounts:    mounts,
+							LivenessProbe:   livenessProbe,
+							ReadinessProbe:  readinessProbe,
+							ImagePullPolicy: ks.pullPolicy(app),
 							SecurityContext: &apiV1.SecurityContext{
 								Capabilities: &apiV1.Capabilities{
 									Add: []apiV1.Capability{"NET_ADMIN"},
@@ -247,6 +445,8 @@ func (ks *Client) deploy(nodeID string, app App) error {
 							},
 						},
 					},
+					Volumes:          volumes,
+					ImagePullSecrets: pullSecrets,
 					NodeSelector: map[string]string{
 						nodeIDLabelKey: nodeID,
 					},
@@ -257,60 +457,341 @@ func (ks *Client) deploy(nodeID string, app App) error {
 	if err != nil {
 		return errors.Wrap(err, "create kubernetes deployment error")
 	}
-	return nil
+
+	return ks.applyService(namespace, nodeID, app.ID, ports)
 }
 
-// delete a kubernetes deployment
-func (ks *Client) undeploy(nodeID, appID string) error {
-	deploymentName, err := ks.getDeploymentName(nodeID, appID)
+// convert the ports of the app to container ports
+func toContainerPorts(appPorts []*PortProto) ([]apiV1.ContainerPort, error) {
+	protoConverter := map[string]apiV1.Protocol{
+		"tcp":  apiV1.ProtocolTCP,
+		"udp":  apiV1.ProtocolUDP,
+		"sctp": apiV1.ProtocolSCTP,
+	}
+
+	var ports []apiV1.ContainerPort
+	for _, portProt := range appPorts {
+		proto, ok := protoConverter[portProt.Protocol]
+		if !ok {
+			return nil, errors.New("unsupported protocol for kubernetes error")
+		}
+		ports = append(ports, apiV1.ContainerPort{
+			ContainerPort: portProt.Port,
+			Protocol:      proto,
+		})
+	}
+	return ports, nil
+}
+
+// convert a probe of the app to a container probe
+func toK8SProbe(probe *Probe) (*apiV1.Probe, error) {
+	if probe == nil {
+		return nil, nil
+	}
+
+	k8sProbe := &apiV1.Probe{
+		InitialDelaySeconds: probe.InitialDelaySeconds,
+		PeriodSeconds:       probe.PeriodSeconds,
+		TimeoutSeconds:      probe.TimeoutSeconds,
+		FailureThreshold:    probe.FailureThreshold,
+	}
+	switch probe.Type {
+	case "http":
+		k8sProbe.HTTPGet = &apiV1.HTTPGetAction{
+			Path: probe.Path,
+			Port: intstr.FromInt(int(probe.Port)),
+		}
+	case "tcp":
+		k8sProbe.TCPSocket = &apiV1.TCPSocketAction{
+			Port: intstr.FromInt(int(probe.Port)),
+		}
+	case "exec":
+		k8sProbe.Exec = &apiV1.ExecAction{
+			Command: probe.Command,
+		}
+	default:
+		return nil, errors.Errorf("unsupported probe type %s", probe.Type)
+	}
+
+	return k8sProbe, nil
+}
+
+// name of the ConfigMap holding the config files of an app on a node
+func configMapName(nodeID, appID string) string {
+	return fmt.Sprintf("config-%s.%s", nodeID, appID)
+}
+
+// name of the Service of an app on a node. The IDs are hashed to keep the name
+// within the 63 characters of a DNS label.
+func serviceName(nodeID, appID string) string {
+	return fmt.Sprintf("app-%x", sha256.Sum256([]byte(nodeID+"."+appID)))[:36]
+}
+
+// applyService creates the Service exposing the ports of an app on a node, or
+// updates it if it already exists. Apps without ports get no Service.
+func (ks *Client) applyService(namespace, nodeID, appID string, ports []apiV1.ContainerPort) error {
+	if len(ports) == 0 {
+		return nil
+	}
+
+	var svcPorts []apiV1.ServicePort
+	for _, port := range ports {
+		svcPorts = append(svcPorts, apiV1.ServicePort{
+			Name:       fmt.Sprintf("%s-%d", strings.ToLower(string(port.Protocol)), port.ContainerPort),
+			Protocol:   port.Protocol,
+			Port:       port.ContainerPort,
+			TargetPort: intstr.FromInt(int(port.ContainerPort)),
+		})
+	}
+
+	servicesClient := ks.clientSet.CoreV1().Services(namespace)
+	service, err := servicesClient.Get(serviceName(nodeID, appID), metaV1.GetOptions{})
+	if k8sErrors.IsNotFound(err) {
+		_, err = servicesClient.Create(&apiV1.Service{
+			ObjectMeta: metaV1.ObjectMeta{
+				Name: serviceName(nodeID, appID),
+				Labels: map[string]string{
+					appIDLabelKey:  appID,
+					nodeIDLabelKey: nodeID,
+				},
+			},
+			Spec: apiV1.ServiceSpec{
+				Type: ks.ServiceType,
+				Selector: map[string]string{
+					appIDLabelKey:  appID,
+					nodeIDLabelKey: nodeID,
+				},
+				Ports: svcPorts,
+			},
+		})
+		return errors.Wrap(err, "create kubernetes service error")
+	}
 	if err != nil {
-		return errors.Wrap(err, "start: error getting deployment name by ID")
+		return errors.Wrap(err, "get kubernetes service error")
+	}
+
+	// keep the node ports already allocated to the ports of the service
+	for i := range svcPorts {
+		for _, old := range service.Spec.Ports {
+			if old.Port == svcPorts[i].Port && old.Protocol == svcPorts[i].Protocol {
+				svcPorts[i].NodePort = old.NodePort
+			}
+		}
+	}
+	if ks.ServiceType != apiV1.ServiceTypeNodePort {
+		for i := range svcPorts {
+			svcPorts[i].NodePort = 0
+		}
+	}
+	service.Spec.Type = ks.ServiceType
+	service.Spec.Ports = svcPorts
+	_, err = servicesClient.Update(service)
+	return errors.Wrap(err, "update kubernetes service error")
+}
+
+// ServiceAddress returns the address of the Service of an app on a node, or
+// nil if the app has no Service because it exposes no ports.
+func (ks *Client) ServiceAddress(ctx context.Context, tenant, nodeID, appID string) (*ServiceAddress, error) {
+	ks.connectOnce.Do(ks.init)
+	if ks.err != nil {
+		return nil, ks.err
+	}
+	namespace := tenantNamespace(tenant)
+
+	service, err := ks.clientSet.CoreV1().Services(namespace).Get(
+		serviceName(nodeID, appID), metaV1.GetOptions{})
+	if k8sErrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "get kubernetes service error")
+	}
+
+	addr := &ServiceAddress{
+		Name:      service.Name,
+		Type:      string(service.Spec.Type),
+		ClusterIP: service.Spec.ClusterIP,
+	}
+	for _, port := range service.Spec.Ports {
+		addr.Ports = append(addr.Ports, ServicePort{
+			Port:     port.Port,
+			NodePort: port.NodePort,
+			Protocol: strings.ToLower(string(port.Protocol)),
+		})
+	}
+	return addr, nil
+}
+
+// createVolumes returns the pod volumes and container mounts of the app's
+// volumes and config files. The config files are stored in a ConfigMap that
+// is created first.
+func (ks *Client) createVolumes(namespace, nodeID string, app App) ([]apiV1.Volume, []apiV1.VolumeMount, error) {
+	var (
+		volumes []apiV1.Volume
+		mounts  []apiV1.VolumeMount
+	)
+	for _, v := range app.Volumes {
+		volumes = append(volumes, apiV1.Volume{
+			Name: v.Name,
+			VolumeSource: apiV1.VolumeSource{
+				HostPath: &apiV1.HostPathVolumeSource{
+					Path: v.HostPath,
+				},
+			},
+		})
+		mounts = append(mounts, apiV1.VolumeMount{
+			Name:      v.Name,
+			MountPath: v.MountPath,
+			ReadOnly:  v.ReadOnly,
+		})
+	}
+
+	if len(app.ConfigFiles) == 0 {
+		return volumes, mounts, nil
 	}
 
-	deploymentsClient := ks.clientSet.AppsV1().Deployments(apiV1.NamespaceDefault)
-	foreground := metaV1.DeletePropagationForeground
-	err = deploymentsClient.Delete(deploymentName, &metaV1.DeleteOptions{
-		PropagationPolicy: &foreground,
+	configMap := &apiV1.ConfigMap{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name: configMapName(nodeID, app.ID),
+			Labels: map[string]string{
+				appIDLabelKey:  app.ID,
+				nodeIDLabelKey: nodeID,
+			},
+		},
+		Data: make(map[string]string),
+	}
+	for i, f := range app.ConfigFiles {
+		// each file is mounted on its own from a key of the ConfigMap
+		key := fmt.Sprintf("file-%d", i)
+		configMap.Data[key] = f.Content
+		mounts = append(mounts, apiV1.VolumeMount{
+			Name:      configFilesVolumeName,
+			MountPath: f.Path,
+			SubPath:   key,
+			ReadOnly:  true,
+		})
+	}
+	configMapsClient := ks.clientSet.CoreV1().ConfigMaps(namespace)
+	_, err := configMapsClient.Create(configMap)
+	if k8sErrors.IsAlreadyExists(err) {
+		// left behind by a deployment that failed before, e.g. when retried
+		_, err = configMapsClient.Update(configMap)
+	}
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "create kubernetes config map error")
+	}
+	volumes = append(volumes, apiV1.Volume{
+		Name: configFilesVolumeName,
+		VolumeSource: apiV1.VolumeSource{
+			ConfigMap: &apiV1.ConfigMapVolumeSource{
+				LocalObjectReference: apiV1.LocalObjectReference{
+					Name: configMap.Name,
+				},
+			},
+		},
 	})
-	return errors.Wrap(err, "create kubernetes deployment error")
+
+	return volumes, mounts, nil
+}
+
+// delete a kubernetes deployment
+func (ks *Client) undeploy(namespace, nodeID, appID string) error {
+	vm, err := ks.getVM(namespace, nodeID, appID)
+	if err != nil {
+		return err
+	}
+	if vm != nil {
+		if err = ks.deleteVM(namespace, vm); err != nil {
+			return err
+		}
+	} else {
+		deploymentName, err := ks.getDeploymentName(namespace, nodeID, appID)
+		if err != nil {
+			return errors.Wrap(err, "start: error getting deployment name by ID")
+		}
+
+		deploymentsClient := ks.clientSet.AppsV1().Deployments(namespace)
+		foreground := metaV1.DeletePropagationForeground
+		err = deploymentsClient.Delete(deploymentName, &metaV1.DeleteOptions{
+			PropagationPolicy: &foreground,
+		})
+		if err != nil {
+			return errors.Wrap(err, "create kubernetes deployment error")
+		}
+	}
+
+	// delete the config files of the app, if it has any
+	err = ks.clientSet.CoreV1().ConfigMaps(namespace).Delete(
+		configMapName(nodeID, appID), &metaV1.DeleteOptions{})
+	if err != nil && !k8sErrors.IsNotFound(err) {
+		return errors.Wrap(err, "delete kubernetes config map error")
+	}
+
+	// delete the service of the app, if it has one
+	err = ks.clientSet.CoreV1().Services(namespace).Delete(
+		serviceName(nodeID, appID), &metaV1.DeleteOptions{})
+	if err != nil && !k8sErrors.IsNotFound(err) {
+		return errors.Wrap(err, "delete kubernetes service error")
+	}
+	return nil
 }
 
 func int32Ptr(i int32) *int32 { return &i }
 
-// Start scales up the number of replicas of kubernetes deployment to 1.
-func (ks *Client) Start(ctx context.Context, nodeID, appID string) error {
-	deploymentName, err := ks.getDeploymentName(nodeID, appID)
+// Start scales up the number of replicas of kubernetes deployment to 1, or
+// starts the virtual machine of a VM app.
+func (ks *Client) Start(ctx context.Context, tenant, nodeID, appID string) error {
+	namespace := tenantNamespace(tenant)
+	vm, err := ks.getVM(namespace, nodeID, appID)
+	if err != nil {
+		return errors.Wrap(err, "start: error getting virtual machine by ID")
+	}
+	if vm != nil {
+		return errors.Wrap(ks.setVMRunning(namespace, vm, true), "start: error updating virtual machine")
+	}
+
+	deploymentName, err := ks.getDeploymentName(namespace, nodeID, appID)
 	if err != nil {
 		return errors.Wrap(err, "start: error getting deployment name by ID")
 	}
 
-	deploymentsClient := ks.clientSet.AppsV1().Deployments(apiV1.NamespaceDefault)
+	deploymentsClient := ks.clientSet.AppsV1().Deployments(namespace)
 	_, err = deploymentsClient.UpdateScale(
 		deploymentName,
 		&autoscalingV1.Scale{
 			ObjectMeta: metaV1.ObjectMeta{
 				Name:      deploymentName,
-				Namespace: apiV1.NamespaceDefault,
+				Namespace: namespace,
 			},
 			Spec: autoscalingV1.ScaleSpec{Replicas: 1},
 		})
 	return errors.Wrap(err, "start: error scaling deployment to 1 replica")
 }
 
-// Stop scales down the number of replicas of kubernetes deployment to 0.
-func (ks *Client) Stop(ctx context.Context, nodeID, appID string) error {
-	deploymentName, err := ks.getDeploymentName(nodeID, appID)
+// Stop scales down the number of replicas of kubernetes deployment to 0, or
+// stops the virtual machine of a VM app.
+func (ks *Client) Stop(ctx context.Context, tenant, nodeID, appID string) error {
+	namespace := tenantNamespace(tenant)
+	vm, err := ks.getVM(namespace, nodeID, appID)
+	if err != nil {
+		return errors.Wrap(err, "stop: error getting virtual machine by ID")
+	}
+	if vm != nil {
+		return errors.Wrap(ks.setVMRunning(namespace, vm, false), "stop: error updating virtual machine")
+	}
+
+	deploymentName, err := ks.getDeploymentName(namespace, nodeID, appID)
 	if err != nil {
 		return errors.Wrap(err, "stop: error getting deployment name by ID")
 	}
 
-	deploymentsClient := ks.clientSet.AppsV1().Deployments(apiV1.NamespaceDefault)
+	deploymentsClient := ks.clientSet.AppsV1().Deployments(namespace)
 	_, err = deploymentsClient.UpdateScale(
 		deploymentName,
 		&autoscalingV1.Scale{
 			ObjectMeta: metaV1.ObjectMeta{
 				Name:      deploymentName,
-				Namespace: apiV1.NamespaceDefault,
+				Namespace: namespace,
 			},
 			Spec: autoscalingV1.ScaleSpec{Replicas: 0},
 		})
@@ -318,13 +799,23 @@ func (ks *Client) Stop(ctx context.Context, nodeID, appID string) error {
 }
 
 // Restart scales down the number of replicas of kubernetes deployment to 0 and then scale up to 1.
-func (ks *Client) Restart(ctx context.Context, nodeID, appID string) error {
-	deploymentName, err := ks.getDeploymentName(nodeID, appID)
+// The virtual machine of a VM app is restarted instead.
+func (ks *Client) Restart(ctx context.Context, tenant, nodeID, appID string) error {
+	namespace := tenantNamespace(tenant)
+	vm, err := ks.getVM(namespace, nodeID, appID)
+	if err != nil {
+		return errors.Wrap(err, "restart: error getting virtual machine by ID")
+	}
+	if vm != nil {
+		return errors.Wrap(ks.restartVM(namespace, vm), "restart: error updating virtual machine")
+	}
+
+	deploymentName, err := ks.getDeploymentName(namespace, nodeID, appID)
 	if err != nil {
 		return errors.Wrap(err, "restart: error getting deployment name by ID")
 	}
 
-	deploymentsClient := ks.clientSet.AppsV1().Deployments(apiV1.NamespaceDefault)
+	deploymentsClient := ks.clientSet.AppsV1().Deployments(namespace)
 
 	// Scale down to 0
 	_, err = deploymentsClient.UpdateScale(
@@ -332,7 +823,7 @@ func (ks *Client) Restart(ctx context.Context, nodeID, appID string) error {
 		&autoscalingV1.Scale{
 			ObjectMeta: metaV1.ObjectMeta{
 				Name:      deploymentName,
-				Namespace: apiV1.NamespaceDefault,
+				Namespace: namespace,
 			},
 			Spec: autoscalingV1.ScaleSpec{Replicas: 0},
 		},
@@ -347,7 +838,7 @@ func (ks *Client) Restart(ctx context.Context, nodeID, appID string) error {
 		&autoscalingV1.Scale{
 			ObjectMeta: metaV1.ObjectMeta{
 				Name:      deploymentName,
-				Namespace: apiV1.NamespaceDefault,
+				Namespace: namespace,
 			},
 			Spec: autoscalingV1.ScaleSpec{Replicas: 1},
 		},
@@ -355,9 +846,63 @@ func (ks *Client) Restart(ctx context.Context, nodeID, appID string) error {
 	return errors.Wrap(err, "restart: error scaling deployment to 1 replica")
 }
 
+// UpdateImage replaces the image of a deployed app with the image of the
+// given app, together with its pull policy and registry credential. The
+// deployment's pod rolls over to the new image, attached to the app's
+// networks, and a running virtual machine is restarted from it.
+func (ks *Client) UpdateImage(ctx context.Context, nodeID string, app App) error {
+	ks.connectOnce.Do(ks.init)
+	if ks.err != nil {
+		return ks.err
+	}
+	namespace := tenantNamespace(app.Tenant)
+
+	pullSecrets, err := ks.applyPullSecret(namespace, app.PullSecret)
+	if err != nil {
+		return errors.Wrap(err, "update image")
+	}
+
+	vm, err := ks.getVM(namespace, nodeID, app.ID)
+	if err != nil {
+		return errors.Wrap(err, "update image: error getting virtual machine by ID")
+	}
+	if vm != nil {
+		disk := vmContainerDisk{Image: app.Image, ImagePullPolicy: ks.pullPolicy(app)}
+		if len(pullSecrets) != 0 {
+			disk.ImagePullSecret = pullSecrets[0].Name
+		}
+		return errors.Wrap(ks.setVMImage(namespace, vm, disk), "update image: error updating virtual machine")
+	}
+
+	deployment, err := ks.getDeployment(namespace, nodeID, app.ID)
+	if err != nil {
+		return errors.Wrap(err, "update image: error getting deployment by ID")
+	}
+	for i := range deployment.Spec.Template.Spec.Containers {
+		deployment.Spec.Template.Spec.Containers[i].Image = app.Image
+		deployment.Spec.Template.Spec.Containers[i].ImagePullPolicy = ks.pullPolicy(app)
+	}
+	deployment.Spec.Template.Spec.ImagePullSecrets = pullSecrets
+
+	networksAnnotation, err := ks.applyNetworks(namespace, app.Networks)
+	if err != nil {
+		return errors.Wrap(err, "update image")
+	}
+	delete(deployment.Spec.Template.Annotations, multusNetworksAnnotation)
+	if networksAnnotation != "" {
+		if deployment.Spec.Template.Annotations == nil {
+			deployment.Spec.Template.Annotations = make(map[string]string)
+		}
+		deployment.Spec.Template.Annotations[multusNetworksAnnotation] = networksAnnotation
+	}
+
+	_, err = ks.clientSet.AppsV1().Deployments(namespace).Update(deployment)
+	return errors.Wrap(err, "update image: error updating deployment")
+}
+
 // get unique generated deployment name by controller deployment ID
-func (ks *Client) getDeploymentName(nodeID, appID string) (string, error) {
-	deployment, err := ks.getDeployment(nodeID, appID)
+func (ks *Client) getDeploymentName(namespace, nodeID, appID string) (string, error) {
+	deployment, err := ks.getDeployment(namespace, nodeID, appID)
 	if err != nil {
 		return "", err
 	}
@@ -365,8 +910,8 @@ func (ks *Client) getDeploymentName(nodeID, appID string) (string, error) {
 }
 
 // get deployment info by controller deployment ID
-func (ks *Client) getDeployment(nodeID, appID string) (*appsV1.Deployment, error) {
-	deployments, err := ks.clientSet.AppsV1().Deployments(apiV1.NamespaceDefault).
+func (ks *Client) getDeployment(namespace, nodeID, appID string) (*appsV1.Deployment, error) {
+	deployments, err := ks.clientSet.AppsV1().Deployments(namespace).
 		List(metaV1.ListOptions{
 			LabelSelector: fmt.Sprintf("%s=%s,%s=%s", appIDLabelKey, appID, nodeIDLabelKey, nodeID),
 		})
@@ -400,6 +945,11 @@ func getPodStatus(pod apiV1.Pod) LifecycleStatus {
 			if strings.Contains(cStatus.State.Waiting.Reason, "ContainerCreating") {
 				return Starting
 			}
+
+			// Restarted over and over, e.g. because of a failing liveness probe
+			if cStatus.State.Waiting.Reason == "CrashLoopBackOff" {
+				return Unhealthy
+			}
 		}
 	}
 
@@ -407,63 +957,70 @@ func getPodStatus(pod apiV1.Pod) LifecycleStatus {
 	case apiV1.PodPending:
 		return Pending
 	case apiV1.PodRunning:
+		// A running container that is not ready fails its readiness probe
+		for _, cStatus := range pod.Status.ContainerStatuses {
+			if cStatus.State.Running != nil && !cStatus.Ready {
+				return Unhealthy
+			}
+		}
 		return Running
 	}
 
 	return Unknown
 }
 
-// Status gets the status of kubernetes app
-func (ks *Client) Status(ctx context.Context, nodeID, appID string) (LifecycleStatus, error) {
-	// Check if deployment actually exists
-	_, err := ks.getDeployment(nodeID, appID)
-	if err != nil {
-		return Error, err
-	}
-
-	podsClient := ks.clientSet.CoreV1().Pods(apiV1.NamespaceDefault)
+// Status gets the status of kubernetes app, either from the pods of its
+// deployment or from its virtual machine
+func (ks *Client) Status(ctx context.Context, tenant, nodeID, appID string) (LifecycleStatus, error) {
+	status, err := ks.AppStatus(ctx, tenant, nodeID, appID)
+	return status.Status, err
+}
 
-	listOptions := metaV1.ListOptions{
-		LabelSelector: fmt.Sprintf("node-id=%s,app-id=%s", nodeID, appID),
+// AppStatus gets the status of kubernetes app and its reason. While
+// WatchStatus runs, the status is served from its cache only; the API server
+// is not queried.
+func (ks *Client) AppStatus(ctx context.Context, tenant, nodeID, appID string) (AppStatus, error) {
+	namespace := tenantNamespace(tenant)
+	if sc := ks.getStatusCache(); sc != nil {
+		return sc.status(appKey{namespace, nodeID, appID})
 	}
 
-	pods, err := podsClient.List(listOptions)
+	vm, err := ks.getVM(namespace, nodeID, appID)
 	if err != nil {
-		return Unknown, err
+		return AppStatus{Status: Error}, err
 	}
-
-	if len(pods.Items) == 0 {
-		// Deployment exists, but no pod is running
-		return Deployed, nil
+	if vm != nil {
+		return AppStatus{Status: getVMStatus(vm)}, nil
 	}
 
-	if len(pods.Items) == 1 {
-		// Just one pod for deployment
-		return getPodStatus(pods.Items[0]), nil
+	// Check if deployment actually exists
+	_, err = ks.getDeployment(namespace, nodeID, appID)
+	if err != nil {
+		return AppStatus{Status: Error}, err
 	}
 
-	// Many pods exist
-	state := Unknown
-	isAnyTerminating := false
-	for _, pod := range pods.Items {
-		if getPodStatus(pod) == Terminating {
-			isAnyTerminating = true
-			continue
-		}
+	podsClient := ks.clientSet.CoreV1().Pods(namespace)
 
-		state = getPodStatus(pod)
+	listOptions := metaV1.ListOptions{
+		LabelSelector: fmt.Sprintf("node-id=%s,app-id=%s", nodeID, appID),
 	}
 
-	if state == Unknown && isAnyTerminating {
-		return Terminating, nil
+	pods, err := podsClient.List(listOptions)
+	if err != nil {
+		return AppStatus{Status: Unknown}, err
 	}
 
-	return state, nil
+	var podRefs []*apiV1.Pod
+	for i := range pods.Items {
+		podRefs = append(podRefs, &pods.Items[i])
+	}
+	return getAppStatus(podRefs), nil
 }
 
-// GetAppIDByIP gets the ID of an application running on a node by its pod IP address
+// GetAppIDByIP gets the ID of an application running on a node by its pod IP
+// address. The pods of all tenants are searched.
 func (ks *Client) GetAppIDByIP(ctx context.Context, nodeID, ipAddr string) (string, error) {
-	pods, err := ks.clientSet.CoreV1().Pods(apiV1.NamespaceDefault).List(
+	pods, err := ks.clientSet.CoreV1().Pods(metaV1.NamespaceAll).List(
 		metaV1.ListOptions{
 			LabelSelector: fmt.Sprintf("%s=%s", nodeIDLabelKey, nodeID),
 		},
@@ -487,12 +1044,17 @@ func (ks *Client) GetAppIDByIP(ctx context.Context, nodeID, ipAddr string) (stri
 
 // ApplyNetworkPolicy applies network policy for app on specified node
 func (ks *Client) ApplyNetworkPolicy(ctx context.Context,
-	nodeID, appID string, policy *networkingV1.NetworkPolicy) error {
+	tenant, nodeID, appID string, policy *networkingV1.NetworkPolicy) error {
+	namespace := tenantNamespace(tenant)
 
 	networkingClient := ks.clientSet.NetworkingV1().RESTClient()
 
 	// Currently only 1 NetworkPolicy per app so we can just concatenate node and app
 	policy.ObjectMeta.Name = fmt.Sprintf("np-%s.%s", nodeID, appID)
+	policy.ObjectMeta.Labels = map[string]string{
+		appIDLabelKey:  appID,
+		nodeIDLabelKey: nodeID,
+	}
 
 	policy.Spec.PodSelector = metaV1.LabelSelector{
 		MatchLabels: map[string]string{
@@ -503,7 +1065,7 @@ func (ks *Client) ApplyNetworkPolicy(ctx context.Context,
 
 	err := networkingClient.Post().
 		Context(ctx).
-		Namespace(apiV1.NamespaceDefault).
+		Namespace(namespace).
 		Resource("networkpolicies").
 		Body(policy).
 		Do().Error()
@@ -516,7 +1078,8 @@ func (ks *Client) ApplyNetworkPolicy(ctx context.Context,
 }
 
 // DeleteNetworkPolicy deletes network policy for app on specified node
-func (ks *Client) DeleteNetworkPolicy(ctx context.Context, nodeID, appID string) error {
+func (ks *Client) DeleteNetworkPolicy(ctx context.Context, tenant, nodeID, appID string) error {
+	namespace := tenantNamespace(tenant)
 	networkingClient := ks.clientSet.NetworkingV1().RESTClient()
 
 	propagation := metaV1.DeletePropagationBackground
@@ -529,7 +1092,7 @@ func (ks *Client) DeleteNetworkPolicy(ctx context.Context, nodeID, appID string)
 
 	err := networkingClient.Delete().
 		Context(ctx).
-		Namespace(apiV1.NamespaceDefault).
+		Namespace(namespace).
 		Resource("networkpolicies").
 		Name(name).
 		Body(deleteOptions).
@@ -543,8 +1106,9 @@ func (ks *Client) DeleteNetworkPolicy(ctx context.Context, nodeID, appID string)
 }
 
 // GetNetworkPolicy returns network policy for app on specified node
-func (ks *Client) GetNetworkPolicy(ctx context.Context, nodeID, appID string) (*networkingV1.NetworkPolicy, error) {
-	networkingClient := ks.clientSet.NetworkingV1().NetworkPolicies(apiV1.NamespaceDefault)
+func (ks *Client) GetNetworkPolicy(ctx context.Context, tenant, nodeID, appID string) (*networkingV1.NetworkPolicy, error) {
+	namespace := tenantNamespace(tenant)
+	networkingClient := ks.clientSet.NetworkingV1().NetworkPolicies(namespace)
 
 	name := fmt.Sprintf("np-%s.%s", nodeID, appID)
 
@@ -6,6 +6,7 @@ package k8s_test
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
 	"log"
 	"os/exec"
 	"os/user"
@@ -136,7 +137,7 @@ var _ = Describe("K8S", func() {
 
 			Eventually(func() k8s.LifecycleStatus {
 				var status k8s.LifecycleStatus
-				status, err = client.Status(ctx, nodeID, appID)
+				status, err = client.Status(ctx, "", nodeID, appID)
 				if err != nil {
 					log.Printf("error checking status: %v", err)
 					return k8s.Unknown
@@ -146,35 +147,528 @@ var _ = Describe("K8S", func() {
 
 			ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
 			defer cancel()
-			Expect(client.Start(ctx, nodeID, appID)).To(Succeed())
+			Expect(client.Start(ctx, "", nodeID, appID)).To(Succeed())
 
 			ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
 			defer cancel()
-			Expect(client.ApplyNetworkPolicy(ctx, nodeID, appID, trafficPolicy.ToK8s())).To(Succeed())
+			Expect(client.ApplyNetworkPolicy(ctx, "", nodeID, appID, trafficPolicy.ToK8s())).To(Succeed())
 
 			ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
 			defer cancel()
-			Expect(client.DeleteNetworkPolicy(ctx, nodeID, appID)).To(Succeed())
+			Expect(client.DeleteNetworkPolicy(ctx, "", nodeID, appID)).To(Succeed())
 
 			ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
 			defer cancel()
-			Expect(client.Stop(ctx, nodeID, appID)).To(Succeed())
+			Expect(client.Stop(ctx, "", nodeID, appID)).To(Succeed())
 
 			ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
 			defer cancel()
-			Expect(client.Restart(ctx, nodeID, appID)).To(Succeed())
+			Expect(client.Restart(ctx, "", nodeID, appID)).To(Succeed())
 
 			ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
 			defer cancel()
-			Expect(client.Undeploy(ctx, nodeID, appID)).To(Succeed())
+			app.Image = "nginx:1.13"
+			Expect(client.UpdateImage(ctx, nodeID, app)).To(Succeed())
+
+			cmd := exec.Command("kubectl", "get", "deployments",
+				"-l", fmt.Sprintf("app-id=%s", appID),
+				"-o", "jsonpath={.items[0].spec.template.spec.containers[0].image}")
+			image, err := cmd.Output()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(image)).To(Equal("nginx:1.13"))
+
+			ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			Expect(client.Undeploy(ctx, "", nodeID, appID)).To(Succeed())
 
 			Eventually(func() error {
 				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 				defer cancel()
 
-				_, err = client.Status(ctx, nodeID, appID)
+				_, err = client.Status(ctx, "", nodeID, appID)
 				return err
 			}, 40*time.Second, 1*time.Second).Should(HaveOccurred())
 		})
+
+		It("Should connect with the context of a kubeconfig", func() {
+			kubeConfig := path.Join(homeDir, ".kube", "config")
+			client := k8s.Client{KubeConfig: kubeConfig}
+			Expect(client.Ping()).To(Succeed())
+
+			client = k8s.Client{KubeConfig: kubeConfig, KubeContext: "no-such-context"}
+			Expect(client.Ping()).To(HaveOccurred())
+		})
+
+		It("Should report the allocatable resources of the node", func() {
+			kubeConfig := path.Join(homeDir, ".kube", "config")
+			config, err := clientcmd.BuildConfigFromFlags("", kubeConfig)
+			Expect(err).NotTo(HaveOccurred())
+			client := k8s.Client{
+				Username: config.Username,
+				Host:     config.Host,
+				APIPath:  config.APIPath,
+				CertFile: config.TLSClientConfig.CertFile,
+				KeyFile:  config.TLSClientConfig.KeyFile,
+				CAFile:   config.TLSClientConfig.CAFile,
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			resources, err := client.NodeAllocatable(ctx, nodeID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resources.Cores).To(BeNumerically(">", 0))
+			Expect(resources.Memory).To(BeNumerically(">", 0))
+		})
+
+		It("Should deploy and undeploy an app with env, volumes, config files and probes", func() {
+			kubeConfig := path.Join(homeDir, ".kube", "config")
+			config, err := clientcmd.BuildConfigFromFlags("", kubeConfig)
+			Expect(err).NotTo(HaveOccurred())
+			client := k8s.Client{
+				Username: config.Username,
+				Host:     config.Host,
+				APIPath:  config.APIPath,
+				CertFile: config.TLSClientConfig.CertFile,
+				KeyFile:  config.TLSClientConfig.KeyFile,
+				CAFile:   config.TLSClientConfig.CAFile,
+			}
+
+			configAppID := "0c4bc8a6-3d0c-4b4c-9e2f-0f2f3a1f4e5d"
+			app := k8s.App{
+				ID:      configAppID,
+				Image:   "nginx:1.12",
+				Cores:   1,
+				Memory:  100,
+				Env:     []k8s.EnvVar{{Name: "LOG_LEVEL", Value: "debug"}},
+				Command: []string{"nginx"},
+				Args:    []string{"-g", "daemon off;"},
+				Volumes: []k8s.Volume{
+					{Name: "data", MountPath: "/usr/share/nginx/html", HostPath: "/tmp", ReadOnly: true},
+				},
+				ConfigFiles: []k8s.ConfigFile{
+					{Path: "/etc/nginx/conf.d/app.conf", Content: "server { listen 8080; }"},
+				},
+				LivenessProbe:  &k8s.Probe{Type: "tcp", Port: 8080, PeriodSeconds: 5},
+				ReadinessProbe: &k8s.Probe{Type: "http", Path: "/", Port: 8080},
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			Expect(client.Deploy(ctx, nodeID, app)).To(Succeed())
+
+			configMap := fmt.Sprintf("configmap/config-%s.%s", nodeID, configAppID)
+			cmd := exec.Command("kubectl", "get", configMap)
+			Expect(cmd.Run()).To(Succeed())
+
+			ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			Expect(client.Undeploy(ctx, "", nodeID, configAppID)).To(Succeed())
+
+			cmd = exec.Command("kubectl", "get", configMap)
+			Expect(cmd.Run()).NotTo(Succeed())
+
+			By("Rejecting a restart policy other than always")
+			app.RestartPolicy = "never"
+			ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			Expect(client.Deploy(ctx, nodeID, app)).NotTo(Succeed())
+		})
+
+		It("Should create and delete a service exposing the app's ports", func() {
+			kubeConfig := path.Join(homeDir, ".kube", "config")
+			config, err := clientcmd.BuildConfigFromFlags("", kubeConfig)
+			Expect(err).NotTo(HaveOccurred())
+			client := k8s.Client{
+				Username:    config.Username,
+				Host:        config.Host,
+				APIPath:     config.APIPath,
+				CertFile:    config.TLSClientConfig.CertFile,
+				KeyFile:     config.TLSClientConfig.KeyFile,
+				CAFile:      config.TLSClientConfig.CAFile,
+				ServiceType: "NodePort",
+			}
+
+			svcAppID := "5e0f7a2c-8b1d-4c3e-9f6a-2d4b6c8e0a1f"
+			app := k8s.App{
+				ID:     svcAppID,
+				Image:  "nginx:1.12",
+				Cores:  1,
+				Memory: 100,
+				Ports:  []*k8s.PortProto{{Port: 80, Protocol: "tcp"}},
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			Expect(client.Deploy(ctx, nodeID, app)).To(Succeed())
+
+			ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			addr, err := client.ServiceAddress(ctx, "", nodeID, svcAppID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(addr).NotTo(BeNil())
+			Expect(addr.Type).To(Equal("NodePort"))
+			Expect(addr.ClusterIP).NotTo(BeEmpty())
+			Expect(addr.Ports).To(HaveLen(1))
+			Expect(addr.Ports[0].Port).To(Equal(int32(80)))
+			Expect(addr.Ports[0].NodePort).NotTo(BeZero())
+
+			cmd := exec.Command("kubectl", "get", "service", addr.Name)
+			Expect(cmd.Run()).To(Succeed())
+
+			ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			Expect(client.Undeploy(ctx, "", nodeID, svcAppID)).To(Succeed())
+
+			cmd = exec.Command("kubectl", "get", "service", addr.Name)
+			Expect(cmd.Run()).NotTo(Succeed())
+
+			ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			addr, err = client.ServiceAddress(ctx, "", nodeID, svcAppID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(addr).To(BeNil())
+		})
+
+		It("Should keep the objects of an app that is deployed again", func() {
+			kubeConfig := path.Join(homeDir, ".kube", "config")
+			config, err := clientcmd.BuildConfigFromFlags("", kubeConfig)
+			Expect(err).NotTo(HaveOccurred())
+			client := k8s.Client{
+				Username: config.Username,
+				Host:     config.Host,
+				APIPath:  config.APIPath,
+				CertFile: config.TLSClientConfig.CertFile,
+				KeyFile:  config.TLSClientConfig.KeyFile,
+				CAFile:   config.TLSClientConfig.CAFile,
+			}
+
+			retryAppID := "0c4e8a2f-6b1d-4f3a-8e5c-7d9b1a3c5e2f"
+			app := k8s.App{
+				ID:     retryAppID,
+				Image:  "nginx:1.12",
+				Cores:  1,
+				Memory: 100,
+				Ports:  []*k8s.PortProto{{Port: 80, Protocol: "tcp"}},
+			}
+			for i := 0; i < 2; i++ {
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+				Expect(client.Deploy(ctx, nodeID, app)).To(Succeed())
+			}
+
+			By("Finding a single deployment of the app")
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			Expect(client.Start(ctx, "", nodeID, retryAppID)).To(Succeed())
+
+			ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			Expect(client.Undeploy(ctx, "", nodeID, retryAppID)).To(Succeed())
+		})
+
+		It("Should deploy an app of a tenant to the tenant's namespace", func() {
+			kubeConfig := path.Join(homeDir, ".kube", "config")
+			config, err := clientcmd.BuildConfigFromFlags("", kubeConfig)
+			Expect(err).NotTo(HaveOccurred())
+			client := k8s.Client{
+				Username:    config.Username,
+				Host:        config.Host,
+				APIPath:     config.APIPath,
+				CertFile:    config.TLSClientConfig.CertFile,
+				KeyFile:     config.TLSClientConfig.KeyFile,
+				CAFile:      config.TLSClientConfig.CAFile,
+				TenantQuota: k8s.Resources{Cores: 4, Memory: 1024},
+			}
+
+			tenantAppID := "8c2d4e6f-1a3b-4c5d-8e7f-9a0b1c2d3e4f"
+			app := k8s.App{
+				ID:     tenantAppID,
+				Tenant: "operator-a",
+				Image:  "nginx:1.12",
+				Cores:  1,
+				Memory: 100,
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			Expect(client.Deploy(ctx, nodeID, app)).To(Succeed())
+
+			for _, object := range []string{"resourcequota/tenant-quota", "networkpolicy/default-deny"} {
+				cmd := exec.Command("kubectl", "get", "-n", "tenant-operator-a", object)
+				Expect(cmd.Run()).To(Succeed())
+			}
+			cmd := exec.Command("kubectl", "get", "deployments", "-n", "tenant-operator-a",
+				"-l", fmt.Sprintf("app-id=%s", tenantAppID), "-o", "name")
+			out, err := cmd.Output()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(out)).NotTo(BeEmpty())
+
+			By("Not finding the app outside of the tenant's namespace")
+			ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			_, err = client.Status(ctx, "", nodeID, tenantAppID)
+			Expect(err).To(HaveOccurred())
+
+			ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			_, err = client.Status(ctx, "operator-a", nodeID, tenantAppID)
+			Expect(err).NotTo(HaveOccurred())
+
+			ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			Expect(client.Undeploy(ctx, "operator-a", nodeID, tenantAppID)).To(Succeed())
+		})
+
+		It("Should deploy, start, stop and undeploy a VM app with KubeVirt", func() {
+			if exec.Command("kubectl", "get", "crd", "virtualmachines.kubevirt.io").Run() != nil {
+				Skip("KubeVirt is not installed")
+			}
+
+			kubeConfig := path.Join(homeDir, ".kube", "config")
+			config, err := clientcmd.BuildConfigFromFlags("", kubeConfig)
+			Expect(err).NotTo(HaveOccurred())
+			client := k8s.Client{
+				Username:        config.Username,
+				Host:            config.Host,
+				APIPath:         config.APIPath,
+				CertFile:        config.TLSClientConfig.CertFile,
+				KeyFile:         config.TLSClientConfig.KeyFile,
+				CAFile:          config.TLSClientConfig.CAFile,
+				ImagePullPolicy: "IfNotPresent",
+			}
+
+			vmAppID := "2f4a6c8e-0b1d-4e3f-a5b7-c9d1e3f5a7b9"
+			app := k8s.App{
+				ID:     vmAppID,
+				VM:     true,
+				Image:  "kubevirt/cirros-container-disk-demo",
+				Cores:  1,
+				Memory: 128,
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			Expect(client.Deploy(ctx, nodeID, app)).To(Succeed())
+
+			cmd := exec.Command("kubectl", "get", "virtualmachines",
+				"-l", fmt.Sprintf("app-id=%s", vmAppID),
+				"-o", "jsonpath={.items[0].spec.template.spec.domain.cpu.cores}")
+			cores, err := cmd.Output()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(cores)).To(Equal("1"))
+
+			ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			status, err := client.Status(ctx, "", nodeID, vmAppID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(k8s.Deployed))
+
+			ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			Expect(client.Start(ctx, "", nodeID, vmAppID)).To(Succeed())
+
+			Eventually(func() k8s.LifecycleStatus {
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+
+				status, _ := client.Status(ctx, "", nodeID, vmAppID)
+				return status
+			}, 120*time.Second, 2*time.Second).Should(Equal(k8s.Running))
+
+			ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			Expect(client.Stop(ctx, "", nodeID, vmAppID)).To(Succeed())
+
+			ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			Expect(client.Undeploy(ctx, "", nodeID, vmAppID)).To(Succeed())
+
+			Eventually(func() error {
+				return exec.Command("kubectl", "get", "virtualmachines",
+					"-l", fmt.Sprintf("app-id=%s", vmAppID),
+					"-o", "jsonpath={.items[0].metadata.name}").Run()
+			}, 40*time.Second, 1*time.Second).Should(HaveOccurred())
+		})
+
+		It("Should cache the status of an app and record its transitions", func() {
+			kubeConfig := path.Join(homeDir, ".kube", "config")
+			config, err := clientcmd.BuildConfigFromFlags("", kubeConfig)
+			Expect(err).NotTo(HaveOccurred())
+			client := k8s.Client{
+				Username: config.Username,
+				Host:     config.Host,
+				APIPath:  config.APIPath,
+				CertFile: config.TLSClientConfig.CertFile,
+				KeyFile:  config.TLSClientConfig.KeyFile,
+				CAFile:   config.TLSClientConfig.CAFile,
+			}
+
+			watchCtx, stopWatching := context.WithCancel(context.Background())
+			defer stopWatching()
+			go func() {
+				defer GinkgoRecover()
+				Expect(client.WatchStatus(watchCtx, time.Minute)).To(MatchError(context.Canceled))
+			}()
+
+			watchedAppID := "7b3e9c1d-2f4a-4e6b-8c0d-1a2b3c4d5e6f"
+			app := k8s.App{
+				ID:              watchedAppID,
+				Image:           "registry.invalid/nginx:1.12", // cannot be pulled
+				ImagePullPolicy: "Always",
+				Cores:           1,
+				Memory:          100,
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			Expect(client.Deploy(ctx, nodeID, app)).To(Succeed())
+
+			ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			Expect(client.Start(ctx, "", nodeID, watchedAppID)).To(Succeed())
+
+			Eventually(func() string {
+				status, _ := client.AppStatus(context.Background(), "", nodeID, watchedAppID)
+				return status.Reason
+			}, 60*time.Second, 1*time.Second).Should(Or(Equal("ErrImagePull"), Equal("ImagePullBackOff")))
+
+			events := client.StatusEvents(context.Background(), "", nodeID, watchedAppID)
+			Expect(len(events)).To(BeNumerically(">=", 2))
+			Expect(events[0].Status).To(Equal(k8s.Deployed))
+
+			ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			Expect(client.Undeploy(ctx, "", nodeID, watchedAppID)).To(Succeed())
+
+			Eventually(func() []k8s.StatusEvent {
+				return client.StatusEvents(context.Background(), "", nodeID, watchedAppID)
+			}, 40*time.Second, 1*time.Second).Should(BeEmpty())
+
+			By("Verifying the status of an undeployed app is served from the cache")
+			_, err = client.AppStatus(context.Background(), "", nodeID, watchedAppID)
+			Expect(err).To(MatchError("deployment not found"))
+		})
+
+		It("Should list the deployment of an app as an object of the app", func() {
+			kubeConfig := path.Join(homeDir, ".kube", "config")
+			config, err := clientcmd.BuildConfigFromFlags("", kubeConfig)
+			Expect(err).NotTo(HaveOccurred())
+			client := k8s.Client{
+				Username: config.Username,
+				Host:     config.Host,
+				APIPath:  config.APIPath,
+				CertFile: config.TLSClientConfig.CertFile,
+				KeyFile:  config.TLSClientConfig.KeyFile,
+				CAFile:   config.TLSClientConfig.CAFile,
+			}
+
+			listedAppID := "3c5d7e9f-1a2b-4c3d-8e4f-5a6b7c8d9e0f"
+			app := k8s.App{
+				ID:              listedAppID,
+				Image:           "nginx:1.12",
+				ImagePullPolicy: "IfNotPresent",
+				Cores:           1,
+				Memory:          100,
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			Expect(client.Deploy(ctx, nodeID, app)).To(Succeed())
+
+			ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			objects, err := client.ListAppObjects(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			var listed []k8s.AppObject
+			for _, object := range objects {
+				if object.AppID == listedAppID {
+					object.Name, object.Created = "", time.Time{}
+					listed = append(listed, object)
+				}
+			}
+			Expect(listed).To(Equal([]k8s.AppObject{{
+				Kind:      k8s.KindDeployment,
+				Namespace: "default",
+				NodeID:    nodeID,
+				AppID:     listedAppID,
+			}}))
+
+			ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			Expect(client.Undeploy(ctx, "", nodeID, listedAppID)).To(Succeed())
+		})
+
+		It("Should not list labelled objects outside the namespaces of apps", func() {
+			kubeConfig := path.Join(homeDir, ".kube", "config")
+			config, err := clientcmd.BuildConfigFromFlags("", kubeConfig)
+			Expect(err).NotTo(HaveOccurred())
+			client := k8s.Client{
+				Username: config.Username,
+				Host:     config.Host,
+				APIPath:  config.APIPath,
+				CertFile: config.TLSClientConfig.CertFile,
+				KeyFile:  config.TLSClientConfig.KeyFile,
+				CAFile:   config.TLSClientConfig.CAFile,
+			}
+
+			foreignAppID := "8d2f4a6c-0e1b-4d3f-9a5c-7e9b1d3f5a7c"
+			cmd := exec.Command("kubectl", "create", "deployment", "foreign",
+				"--image=nginx:1.12", "-n", "kube-system")
+			Expect(cmd.Run()).To(Succeed())
+			defer func() {
+				_ = exec.Command("kubectl", "delete", "deployment", "foreign", "-n", "kube-system").Run()
+			}()
+			cmd = exec.Command("kubectl", "label", "deployment", "foreign", "-n", "kube-system",
+				"app-id="+foreignAppID, "node-id="+nodeID)
+			Expect(cmd.Run()).To(Succeed())
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			objects, err := client.ListAppObjects(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			for _, object := range objects {
+				Expect(object.AppID).NotTo(Equal(foreignAppID))
+			}
+		})
+
+		It("Should stream the logs of an app", func() {
+			kubeConfig := path.Join(homeDir, ".kube", "config")
+			config, err := clientcmd.BuildConfigFromFlags("", kubeConfig)
+			Expect(err).NotTo(HaveOccurred())
+			client := k8s.Client{
+				Username: config.Username,
+				Host:     config.Host,
+				APIPath:  config.APIPath,
+				CertFile: config.TLSClientConfig.CertFile,
+				KeyFile:  config.TLSClientConfig.KeyFile,
+				CAFile:   config.TLSClientConfig.CAFile,
+			}
+
+			loggingAppID := "7d1e2f3a-4b5c-4d6e-8f7a-9b0c1d2e3f4a"
+			app := k8s.App{
+				ID:              loggingAppID,
+				Image:           "busybox:1.31",
+				ImagePullPolicy: "IfNotPresent",
+				Cores:           1,
+				Memory:          100,
+				Command:         []string{"sh", "-c", "echo first; echo second; sleep 3600"},
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			Expect(client.Deploy(ctx, nodeID, app)).To(Succeed())
+
+			Eventually(func() string {
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+				logs, err := client.Logs(ctx, "", nodeID, loggingAppID, k8s.LogOptions{Tail: 1})
+				if err != nil {
+					return err.Error()
+				}
+				defer logs.Close()
+				b, err := ioutil.ReadAll(logs)
+				if err != nil {
+					return err.Error()
+				}
+				return string(b)
+			}, 60*time.Second, 2*time.Second).Should(Equal("second\n"))
+
+			ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			Expect(client.Undeploy(ctx, "", nodeID, loggingAppID)).To(Succeed())
+		})
 	})
 })
@@ -0,0 +1,507 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package k8s
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/pkg/errors"
+	apiV1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	// API version of the KubeVirt objects
+	kubevirtAPIVersion = "kubevirt.io/v1alpha3"
+	// Path of the KubeVirt subresources API, e.g. to restart a VM
+	kubevirtSubresourcesPath = "/apis/subresources.kubevirt.io/v1alpha3"
+
+	// Name of the VM's disk holding the app's image
+	vmRootDiskName = "rootfs"
+	// Name of the VM's interface connected to the pod network
+	vmNetworkName = "default"
+)
+
+// virtualMachine is a KubeVirt VirtualMachine. Only the fields set or read by
+// the client are declared.
+type virtualMachine struct {
+	metaV1.TypeMeta   `json:",inline"`
+	metaV1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   vmSpec   `json:"spec"`
+	Status vmStatus `json:"status,omitempty"`
+}
+
+type vmSpec struct {
+	Running  bool       `json:"running"`
+	Template vmTemplate `json:"template"`
+}
+
+type vmTemplate struct {
+	metaV1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec vmiSpec `json:"spec"`
+}
+
+type vmiSpec struct {
+	Domain         vmDomain          `json:"domain"`
+	NodeSelector   map[string]string `json:"nodeSelector,omitempty"`
+	Networks       []vmNetwork       `json:"networks"`
+	Volumes        []vmVolume        `json:"volumes"`
+	LivenessProbe  *apiV1.Probe      `json:"livenessProbe,omitempty"`
+	ReadinessProbe *apiV1.Probe      `json:"readinessProbe,omitempty"`
+}
+
+type vmDomain struct {
+	CPU       vmCPU       `json:"cpu"`
+	Memory    *vmMemory   `json:"memory,omitempty"`
+	Devices   vmDevices   `json:"devices"`
+	Resources vmResources `json:"resources"`
+}
+
+type vmCPU struct {
+	Cores uint32 `json:"cores"`
+}
+
+type vmMemory struct {
+	Hugepages vmHugepages `json:"hugepages"`
+}
+
+type vmHugepages struct {
+	PageSize string `json:"pageSize"`
+}
+
+type vmDevices struct {
+	Disks      []vmDisk      `json:"disks"`
+	Interfaces []vmInterface `json:"interfaces"`
+}
+
+type vmDisk struct {
+	Name string     `json:"name"`
+	Disk vmDiskSpec `json:"disk"`
+}
+
+type vmDiskSpec struct {
+	Bus string `json:"bus"`
+}
+
+type vmInterface struct {
+	Name   string    `json:"name"`
+	Bridge *struct{} `json:"bridge,omitempty"`
+}
+
+type vmResources struct {
+	Requests apiV1.ResourceList `json:"requests,omitempty"`
+	Limits   apiV1.ResourceList `json:"limits,omitempty"`
+}
+
+type vmNetwork struct {
+	Name   string          `json:"name"`
+	Pod    *struct{}       `json:"pod,omitempty"`
+	Multus *vmMultusTarget `json:"multus,omitempty"`
+}
+
+type vmMultusTarget struct {
+	NetworkName string `json:"networkName"`
+}
+
+type vmVolume struct {
+	Name          string           `json:"name"`
+	ContainerDisk *vmContainerDisk `json:"containerDisk,omitempty"`
+}
+
+type vmContainerDisk struct {
+	Image           string           `json:"image"`
+	ImagePullPolicy apiV1.PullPolicy `json:"imagePullPolicy,omitempty"`
+	ImagePullSecret string           `json:"imagePullSecret,omitempty"`
+}
+
+type vmStatus struct {
+	Created bool `json:"created,omitempty"`
+	Ready   bool `json:"ready,omitempty"`
+}
+
+// virtualMachineList is a list of KubeVirt VirtualMachines.
+type virtualMachineList struct {
+	metaV1.TypeMeta `json:",inline"`
+	metaV1.ListMeta `json:"metadata,omitempty"`
+
+	Items []virtualMachine `json:"items"`
+}
+
+// DeepCopyObject implements runtime.Object so VirtualMachines can be cached
+// by an informer. All fields are serialized, so a JSON round trip copies them.
+func (vm *virtualMachine) DeepCopyObject() runtime.Object {
+	out := &virtualMachine{}
+	deepCopyJSON(vm, out)
+	return out
+}
+
+// DeepCopyObject implements runtime.Object.
+func (l *virtualMachineList) DeepCopyObject() runtime.Object {
+	out := &virtualMachineList{}
+	deepCopyJSON(l, out)
+	return out
+}
+
+func deepCopyJSON(in, out interface{}) {
+	raw, err := json.Marshal(in)
+	if err != nil {
+		panic(err)
+	}
+	if err = json.Unmarshal(raw, out); err != nil {
+		panic(err)
+	}
+}
+
+// name of the VirtualMachine of an app on a node. The IDs are hashed to keep
+// the name within the 63 characters of a DNS label.
+func vmName(nodeID, appID string) string {
+	return fmt.Sprintf("vm-%x", sha256.Sum256([]byte(nodeID+"."+appID)))[:35]
+}
+
+// path of the VirtualMachines of a namespace, or of a single one if a name is
+// given
+func vmPath(namespace string, name ...string) string {
+	path := fmt.Sprintf("/apis/%s/namespaces/%s/virtualmachines", kubevirtAPIVersion, namespace)
+	for _, n := range name {
+		path += "/" + n
+	}
+	return path
+}
+
+// deployVM creates a stopped KubeVirt VirtualMachine booting from the app's
+// image, which the node provides as a container disk. A VirtualMachine that
+// already exists is kept.
+func (ks *Client) deployVM(namespace, nodeID string, app App) error {
+	if len(app.Env) != 0 || len(app.Command) != 0 || len(app.Volumes) != 0 || len(app.ConfigFiles) != 0 {
+		return errors.New("env, command, volumes and config files are not supported by virtual machines")
+	}
+	if app.RestartPolicy != "" && app.RestartPolicy != "always" {
+		return errors.Errorf("restart policy %s is not supported by virtual machines", app.RestartPolicy)
+	}
+	for _, probe := range []*Probe{app.LivenessProbe, app.ReadinessProbe} {
+		if probe != nil && probe.Type == "exec" {
+			return errors.New("exec probes are not supported by virtual machines")
+		}
+	}
+	for _, network := range app.Networks {
+		if network.InterfaceName != "" || len(network.Args) != 0 {
+			return errors.New("interface names and args of networks are not supported by virtual machines")
+		}
+	}
+	livenessProbe, err := toK8SProbe(app.LivenessProbe)
+	if err != nil {
+		return errors.Wrap(err, "liveness probe error")
+	}
+	readinessProbe, err := toK8SProbe(app.ReadinessProbe)
+	if err != nil {
+		return errors.Wrap(err, "readiness probe error")
+	}
+	ports, err := toContainerPorts(app.Ports)
+	if err != nil {
+		return err
+	}
+	pullSecrets, err := ks.applyPullSecret(namespace, app.PullSecret)
+	if err != nil {
+		return err
+	}
+	disk := &vmContainerDisk{
+		Image:           app.Image,
+		ImagePullPolicy: ks.pullPolicy(app),
+	}
+	if len(pullSecrets) != 0 {
+		disk.ImagePullSecret = pullSecrets[0].Name
+	}
+	if _, err = ks.applyNetworks(namespace, app.Networks); err != nil {
+		return err
+	}
+	networks := []vmNetwork{{Name: vmNetworkName, Pod: &struct{}{}}}
+	interfaces := []vmInterface{{Name: vmNetworkName, Bridge: &struct{}{}}}
+	for _, network := range app.Networks {
+		networks = append(networks, vmNetwork{
+			Name:   network.Name,
+			Multus: &vmMultusTarget{NetworkName: network.Name},
+		})
+		interfaces = append(interfaces, vmInterface{Name: network.Name, Bridge: &struct{}{}})
+	}
+
+	memory := *resource.NewQuantity(int64(1024*1024*app.Memory), resource.BinarySI)
+	labels := map[string]string{
+		appIDLabelKey:  app.ID,
+		nodeIDLabelKey: nodeID,
+	}
+	vm := &virtualMachine{
+		TypeMeta: metaV1.TypeMeta{
+			APIVersion: kubevirtAPIVersion,
+			Kind:       "VirtualMachine",
+		},
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:   vmName(nodeID, app.ID),
+			Labels: labels,
+		},
+		Spec: vmSpec{
+			// only creates the VM, to be consistent with docker native deploy
+			Running: false,
+			Template: vmTemplate{
+				ObjectMeta: metaV1.ObjectMeta{Labels: labels},
+				Spec: vmiSpec{
+					Domain: vmDomain{
+						CPU: vmCPU{Cores: uint32(app.Cores)},
+						Devices: vmDevices{
+							Disks: []vmDisk{
+								{Name: vmRootDiskName, Disk: vmDiskSpec{Bus: "virtio"}},
+							},
+							Interfaces: interfaces,
+						},
+						Resources: vmResources{
+							Requests: apiV1.ResourceList{apiV1.ResourceMemory: memory},
+						},
+					},
+					NodeSelector: map[string]string{
+						nodeIDLabelKey: nodeID,
+					},
+					Networks: networks,
+					Volumes: []vmVolume{
+						{Name: vmRootDiskName, ContainerDisk: disk},
+					},
+					LivenessProbe:  livenessProbe,
+					ReadinessProbe: readinessProbe,
+				},
+			},
+		},
+	}
+	if app.Hugepages > 0 {
+		// the guest memory is backed by 2MiB hugepages
+		vm.Spec.Template.Spec.Domain.Memory = &vmMemory{Hugepages: vmHugepages{PageSize: "2Mi"}}
+	}
+
+	body, err := json.Marshal(vm)
+	if err != nil {
+		return errors.Wrap(err, "marshal kubevirt virtual machine error")
+	}
+	err = ks.clientSet.CoreV1().RESTClient().Post().
+		AbsPath(vmPath(namespace)).
+		SetHeader("Content-Type", "application/json").
+		Body(body).
+		Do().Error()
+	if err != nil && !k8sErrors.IsAlreadyExists(err) {
+		return errors.Wrap(err, "create kubevirt virtual machine error")
+	}
+
+	return ks.applyService(namespace, nodeID, app.ID, ports)
+}
+
+// getVM returns the VirtualMachine of an app on a node, or nil if the app is
+// not a VM.
+func (ks *Client) getVM(namespace, nodeID, appID string) (*virtualMachine, error) {
+	raw, err := ks.clientSet.CoreV1().RESTClient().Get().
+		AbsPath(vmPath(namespace, vmName(nodeID, appID))).
+		Do().Raw()
+	if k8sErrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "get kubevirt virtual machine error")
+	}
+
+	vm := &virtualMachine{}
+	if err = json.Unmarshal(raw, vm); err != nil {
+		return nil, errors.Wrap(err, "unmarshal kubevirt virtual machine error")
+	}
+	return vm, nil
+}
+
+// kubevirtInstalled checks if the API server serves KubeVirt VirtualMachines.
+func (ks *Client) kubevirtInstalled() (bool, error) {
+	_, err := ks.clientSet.CoreV1().RESTClient().Get().
+		AbsPath("/apis/"+kubevirtAPIVersion+"/virtualmachines").
+		Param("limit", "1").
+		Do().Raw()
+	if k8sErrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Wrap(err, "error listing kubevirt virtual machines")
+	}
+	return true, nil
+}
+
+// vmListWatch lists and watches the VirtualMachines of apps in all
+// namespaces for an informer.
+func (ks *Client) vmListWatch() *cache.ListWatch {
+	request := func(options metaV1.ListOptions) *rest.Request {
+		req := ks.clientSet.CoreV1().RESTClient().Get().
+			AbsPath("/apis/"+kubevirtAPIVersion+"/virtualmachines").
+			Param("labelSelector", appIDLabelKey+","+nodeIDLabelKey)
+		if options.ResourceVersion != "" {
+			req = req.Param("resourceVersion", options.ResourceVersion)
+		}
+		if options.TimeoutSeconds != nil {
+			req = req.Param("timeoutSeconds", strconv.FormatInt(*options.TimeoutSeconds, 10))
+		}
+		return req
+	}
+
+	return &cache.ListWatch{
+		ListFunc: func(options metaV1.ListOptions) (runtime.Object, error) {
+			raw, err := request(options).Do().Raw()
+			if err != nil {
+				return nil, errors.Wrap(err, "error listing kubevirt virtual machines")
+			}
+			vms := &virtualMachineList{}
+			if err = json.Unmarshal(raw, vms); err != nil {
+				return nil, errors.Wrap(err, "unmarshal kubevirt virtual machines error")
+			}
+			return vms, nil
+		},
+		WatchFunc: func(options metaV1.ListOptions) (watch.Interface, error) {
+			stream, err := request(options).Param("watch", "true").Stream()
+			if err != nil {
+				return nil, errors.Wrap(err, "error watching kubevirt virtual machines")
+			}
+			return watch.NewStreamWatcher(
+				&vmEventDecoder{stream: stream, decoder: json.NewDecoder(stream)},
+				vmEventReporter{}), nil
+		},
+	}
+}
+
+// vmEventDecoder decodes the watch events of VirtualMachines.
+type vmEventDecoder struct {
+	stream  io.ReadCloser
+	decoder *json.Decoder
+}
+
+func (d *vmEventDecoder) Decode() (watch.EventType, runtime.Object, error) {
+	var event struct {
+		Type   watch.EventType `json:"type"`
+		Object json.RawMessage `json:"object"`
+	}
+	if err := d.decoder.Decode(&event); err != nil {
+		return "", nil, err
+	}
+
+	var obj runtime.Object = &virtualMachine{}
+	if event.Type == watch.Error {
+		obj = &metaV1.Status{}
+	}
+	if err := json.Unmarshal(event.Object, obj); err != nil {
+		return "", nil, errors.Wrap(err, "unmarshal kubevirt virtual machine event error")
+	}
+	return event.Type, obj, nil
+}
+
+func (d *vmEventDecoder) Close() {
+	d.stream.Close()
+}
+
+// vmEventReporter reports errors decoding VirtualMachine events as a status.
+type vmEventReporter struct{}
+
+func (vmEventReporter) AsObject(err error) runtime.Object {
+	status := k8sErrors.NewInternalError(err).ErrStatus
+	return &status
+}
+
+// patchVM merges a patch into a VirtualMachine. Fields the patch leaves out,
+// including those defaulted by KubeVirt, are kept.
+func (ks *Client) patchVM(namespace, name string, patch interface{}) error {
+	body, err := json.Marshal(patch)
+	if err != nil {
+		return errors.Wrap(err, "marshal kubevirt virtual machine patch error")
+	}
+	err = ks.clientSet.CoreV1().RESTClient().Patch(types.MergePatchType).
+		AbsPath(vmPath(namespace, name)).
+		Body(body).
+		Do().Error()
+	return errors.Wrap(err, "patch kubevirt virtual machine error")
+}
+
+// setVMRunning starts or stops a VirtualMachine.
+func (ks *Client) setVMRunning(namespace string, vm *virtualMachine, running bool) error {
+	if vm.Spec.Running == running {
+		return nil
+	}
+	return ks.patchVM(namespace, vm.Name, map[string]interface{}{
+		"spec": map[string]interface{}{"running": running},
+	})
+}
+
+// setVMImage replaces the container disk of a VirtualMachine and restarts it,
+// if it is running, to boot from the new image.
+func (ks *Client) setVMImage(namespace string, vm *virtualMachine, disk vmContainerDisk) error {
+	volumes := vm.Spec.Template.Spec.Volumes
+	for i := range volumes {
+		if volumes[i].ContainerDisk != nil {
+			*volumes[i].ContainerDisk = disk
+		}
+	}
+	err := ks.patchVM(namespace, vm.Name, map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{"volumes": volumes},
+			},
+		},
+	})
+	if err != nil || !vm.Spec.Running {
+		return err
+	}
+	return ks.restartVM(namespace, vm)
+}
+
+// restartVM restarts a running VirtualMachine, or starts a stopped one.
+func (ks *Client) restartVM(namespace string, vm *virtualMachine) error {
+	if !vm.Spec.Running {
+		return ks.setVMRunning(namespace, vm, true)
+	}
+	err := ks.clientSet.CoreV1().RESTClient().Put().
+		AbsPath(fmt.Sprintf("%s/namespaces/%s/virtualmachines/%s/restart",
+			kubevirtSubresourcesPath, namespace, vm.Name)).
+		Do().Error()
+	return errors.Wrap(err, "restart kubevirt virtual machine error")
+}
+
+// deleteVM deletes a VirtualMachine together with its running instance.
+func (ks *Client) deleteVM(namespace string, vm *virtualMachine) error {
+	foreground := metaV1.DeletePropagationForeground
+	err := ks.clientSet.CoreV1().RESTClient().Delete().
+		AbsPath(vmPath(namespace, vm.Name)).
+		Body(&metaV1.DeleteOptions{PropagationPolicy: &foreground}).
+		Do().Error()
+	if err != nil && !k8sErrors.IsNotFound(err) {
+		return errors.Wrap(err, "delete kubevirt virtual machine error")
+	}
+	return nil
+}
+
+// getVMStatus maps the state of a VirtualMachine to the lifecycle status of
+// its app.
+func getVMStatus(vm *virtualMachine) LifecycleStatus {
+	switch {
+	case vm.DeletionTimestamp != nil:
+		return Terminating
+	case !vm.Spec.Running && !vm.Status.Created:
+		return Deployed
+	case !vm.Spec.Running:
+		return Terminating
+	case !vm.Status.Created:
+		return Pending
+	case !vm.Status.Ready:
+		return Starting
+	default:
+		return Running
+	}
+}
@@ -0,0 +1,64 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+	apiV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// LogOptions selects the logs of an app. Tail is the number of lines from the
+// end to return and Since the time of the oldest line; zero values return
+// all lines. Follow keeps streaming lines as they are written.
+type LogOptions struct {
+	Follow bool
+	Tail   int64
+	Since  time.Time
+}
+
+// Logs streams the logs of the container of an app from the pod API. The
+// logs of a VM app are those of its virt-launcher pod. The stream ends when
+// the context is canceled.
+func (ks *Client) Logs(ctx context.Context, tenant, nodeID, appID string, opts LogOptions) (io.ReadCloser, error) {
+	namespace := tenantNamespace(tenant)
+	pods, err := ks.clientSet.CoreV1().Pods(namespace).List(metaV1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s,%s=%s", nodeIDLabelKey, nodeID, appIDLabelKey, appID),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "logs: error getting list of pods")
+	}
+
+	// During a rollout the newest pod runs the current version of the app
+	var pod *apiV1.Pod
+	for i := range pods.Items {
+		if pod == nil || pods.Items[i].CreationTimestamp.After(pod.CreationTimestamp.Time) {
+			pod = &pods.Items[i]
+		}
+	}
+	if pod == nil || len(pod.Spec.Containers) == 0 {
+		return nil, errors.New("logs: pod not found")
+	}
+
+	logOptions := &apiV1.PodLogOptions{
+		// The app runs in the first container, e.g. compute of virt-launcher
+		Container: pod.Spec.Containers[0].Name,
+		Follow:    opts.Follow,
+	}
+	if opts.Tail > 0 {
+		logOptions.TailLines = &opts.Tail
+	}
+	if !opts.Since.IsZero() {
+		since := metaV1.NewTime(opts.Since)
+		logOptions.SinceTime = &since
+	}
+
+	stream, err := ks.clientSet.CoreV1().Pods(namespace).GetLogs(pod.Name, logOptions).Context(ctx).Stream()
+	return stream, errors.Wrap(err, "logs: error streaming pod logs")
+}
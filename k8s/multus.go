@@ -0,0 +1,116 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package k8s
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// API version of the Multus NetworkAttachmentDefinitions
+	multusAPIVersion = "k8s.cni.cncf.io/v1"
+	// Annotation of a pod selecting the Multus networks it is attached to
+	multusNetworksAnnotation = "k8s.v1.cni.cncf.io/networks"
+)
+
+// Network is a CNI network an app is attached to through Multus, in
+// addition to the pod network.
+type Network struct {
+	Name          string
+	Config        string // CNI configuration in JSON
+	InterfaceName string
+	Args          map[string]string
+}
+
+// networkAttachmentDefinition is a Multus NetworkAttachmentDefinition
+type networkAttachmentDefinition struct {
+	metaV1.TypeMeta   `json:",inline"`
+	metaV1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec nadSpec `json:"spec"`
+}
+
+type nadSpec struct {
+	Config string `json:"config"`
+}
+
+// networkSelection is an element of the networks annotation of a pod
+type networkSelection struct {
+	Name      string            `json:"name"`
+	Interface string            `json:"interface,omitempty"`
+	CNIArgs   map[string]string `json:"cni-args,omitempty"`
+}
+
+// path of the NetworkAttachmentDefinitions of a namespace, or of a single one
+// if a name is given
+func nadPath(namespace string, name ...string) string {
+	path := fmt.Sprintf("/apis/%s/namespaces/%s/network-attachment-definitions", multusAPIVersion, namespace)
+	for _, n := range name {
+		path += "/" + n
+	}
+	return path
+}
+
+// applyNetworks creates or updates the NetworkAttachmentDefinition of each
+// network in the namespace and returns the networks annotation selecting them,
+// or an empty string if there are no networks.
+func (ks *Client) applyNetworks(namespace string, networks []Network) (string, error) {
+	if len(networks) == 0 {
+		return "", nil
+	}
+
+	var selections []networkSelection
+	for _, network := range networks {
+		nad := &networkAttachmentDefinition{
+			TypeMeta: metaV1.TypeMeta{
+				APIVersion: multusAPIVersion,
+				Kind:       "NetworkAttachmentDefinition",
+			},
+			ObjectMeta: metaV1.ObjectMeta{Name: network.Name},
+			Spec:       nadSpec{Config: network.Config},
+		}
+		body, err := json.Marshal(nad)
+		if err != nil {
+			return "", errors.Wrap(err, "marshal network attachment definition error")
+		}
+
+		err = ks.clientSet.CoreV1().RESTClient().Post().
+			AbsPath(nadPath(namespace)).
+			SetHeader("Content-Type", "application/json").
+			Body(body).
+			Do().Error()
+		if k8sErrors.IsAlreadyExists(err) {
+			// the network may have been updated since it was last applied
+			body, err = json.Marshal(map[string]interface{}{"spec": nad.Spec})
+			if err != nil {
+				return "", errors.Wrap(err, "marshal network attachment definition patch error")
+			}
+			err = ks.clientSet.CoreV1().RESTClient().Patch(types.MergePatchType).
+				AbsPath(nadPath(namespace, network.Name)).
+				Body(body).
+				Do().Error()
+		}
+		if err != nil {
+			return "", errors.Wrapf(err, "apply network attachment definition %s error", network.Name)
+		}
+
+		selections = append(selections, networkSelection{
+			Name:      network.Name,
+			Interface: network.InterfaceName,
+			CNIArgs:   network.Args,
+		})
+	}
+
+	annotation, err := json.Marshal(selections)
+	if err != nil {
+		return "", errors.Wrap(err, "marshal networks annotation error")
+	}
+	return string(annotation), nil
+}
@@ -0,0 +1,134 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/open-ness/edgecontroller/uuid"
+	"github.com/pkg/errors"
+	apiV1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Kinds of the objects created for an app on a node.
+const (
+	KindDeployment     = "Deployment"
+	KindVirtualMachine = "VirtualMachine"
+	KindNetworkPolicy  = "NetworkPolicy"
+)
+
+// AppObject is an object created for an app on a node, which the client
+// finds by the app-id and node-id labels. The Deployment or VirtualMachine
+// of an app carries the app; the other objects of the app, e.g. its Service,
+// are removed with it.
+type AppObject struct {
+	Kind      string
+	Tenant    string
+	Namespace string
+	Name      string
+	NodeID    string
+	AppID     string
+	Created   time.Time
+}
+
+// ListAppObjects lists the Deployments, VirtualMachines and NetworkPolicies
+// of the apps of all tenants. VirtualMachines are skipped if KubeVirt is not
+// installed.
+func (ks *Client) ListAppObjects(ctx context.Context) ([]AppObject, error) {
+	ks.connectOnce.Do(ks.init)
+	if ks.err != nil {
+		return nil, ks.err
+	}
+
+	listOptions := metaV1.ListOptions{LabelSelector: appIDLabelKey + "," + nodeIDLabelKey}
+	var objects []AppObject
+
+	deployments, err := ks.clientSet.AppsV1().Deployments(metaV1.NamespaceAll).List(listOptions)
+	if err != nil {
+		return nil, errors.Wrap(err, "error listing deployments")
+	}
+	for i := range deployments.Items {
+		if object, ok := newAppObject(KindDeployment, &deployments.Items[i].ObjectMeta); ok {
+			objects = append(objects, object)
+		}
+	}
+
+	raw, err := ks.clientSet.CoreV1().RESTClient().Get().
+		AbsPath("/apis/"+kubevirtAPIVersion+"/virtualmachines").
+		Param("labelSelector", listOptions.LabelSelector).
+		Do().Raw()
+	if err != nil && !k8sErrors.IsNotFound(err) {
+		return nil, errors.Wrap(err, "error listing kubevirt virtual machines")
+	}
+	if err == nil {
+		var vms virtualMachineList
+		if err = json.Unmarshal(raw, &vms); err != nil {
+			return nil, errors.Wrap(err, "unmarshal kubevirt virtual machines error")
+		}
+		for i := range vms.Items {
+			if object, ok := newAppObject(KindVirtualMachine, &vms.Items[i].ObjectMeta); ok {
+				objects = append(objects, object)
+			}
+		}
+	}
+
+	// network policies applied before they were labelled are found by name
+	policies, err := ks.clientSet.NetworkingV1().NetworkPolicies(metaV1.NamespaceAll).List(metaV1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "error listing network policies")
+	}
+	for i := range policies.Items {
+		meta := &policies.Items[i].ObjectMeta
+		if _, ok := meta.Labels[appIDLabelKey]; !ok {
+			nodeApp := strings.SplitN(strings.TrimPrefix(meta.Name, "np-"), ".", 2)
+			if !strings.HasPrefix(meta.Name, "np-") || len(nodeApp) != 2 {
+				continue
+			}
+			meta = meta.DeepCopy()
+			meta.Labels = map[string]string{nodeIDLabelKey: nodeApp[0], appIDLabelKey: nodeApp[1]}
+		}
+		if object, ok := newAppObject(KindNetworkPolicy, meta); ok {
+			objects = append(objects, object)
+		}
+	}
+
+	return objects, nil
+}
+
+// newAppObject returns the object of an app and true, or false if the object
+// is not labelled with the IDs of an app and a node or is not in the default
+// namespace or the namespace of a tenant.
+func newAppObject(kind string, meta *metaV1.ObjectMeta) (AppObject, bool) {
+	tenant, ok := namespaceTenant(meta.Namespace)
+	if !ok {
+		return AppObject{}, false
+	}
+	object := AppObject{
+		Kind:      kind,
+		Tenant:    tenant,
+		Namespace: meta.Namespace,
+		Name:      meta.Name,
+		NodeID:    meta.Labels[nodeIDLabelKey],
+		AppID:     meta.Labels[appIDLabelKey],
+		Created:   meta.CreationTimestamp.Time,
+	}
+	return object, uuid.IsValid(object.NodeID) && uuid.IsValid(object.AppID)
+}
+
+// namespaceTenant returns the tenant of a namespace and true, or false if the
+// namespace is neither the default namespace nor the namespace of a tenant.
+func namespaceTenant(namespace string) (string, bool) {
+	if namespace == apiV1.NamespaceDefault {
+		return "", true
+	}
+	if !strings.HasPrefix(namespace, tenantNamespacePrefix) || namespace == tenantNamespacePrefix {
+		return "", false
+	}
+	return strings.TrimPrefix(namespace, tenantNamespacePrefix), true
+}
@@ -0,0 +1,92 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package k8s
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+	apiV1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RegistryCredential is the login to the registry of an app's image.
+type RegistryCredential struct {
+	Server   string // host[:port] of the registry
+	Username string
+	Password string
+}
+
+// dockerConfig is the content of a kubernetes.io/dockerconfigjson secret
+type dockerConfig struct {
+	Auths map[string]dockerAuth `json:"auths"`
+}
+
+type dockerAuth struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Auth     string `json:"auth"`
+}
+
+// name of the pull secret of a registry. The host is hashed as it may contain
+// a port, which is not allowed in the name of a secret.
+func pullSecretName(server string) string {
+	return fmt.Sprintf("registry-%x", sha256.Sum256([]byte(server)))[:41]
+}
+
+// pullPolicy returns the pull policy of the app's image, which is the policy
+// of the client unless the app sets its own.
+func (ks *Client) pullPolicy(app App) apiV1.PullPolicy {
+	if app.ImagePullPolicy != "" {
+		return app.ImagePullPolicy
+	}
+	return ks.ImagePullPolicy
+}
+
+// applyPullSecret creates or updates the pull secret of the app's registry in
+// the namespace and returns the reference to it, or nil if the app has no
+// registry credential. The secret is refreshed on each deployment, so changed
+// credentials apply to the apps deployed or upgraded after the change.
+func (ks *Client) applyPullSecret(namespace string, cred *RegistryCredential) ([]apiV1.LocalObjectReference, error) {
+	if cred == nil {
+		return nil, nil
+	}
+
+	config, err := json.Marshal(dockerConfig{
+		Auths: map[string]dockerAuth{
+			cred.Server: {
+				Username: cred.Username,
+				Password: cred.Password,
+				Auth:     base64.StdEncoding.EncodeToString([]byte(cred.Username + ":" + cred.Password)),
+			},
+		},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal registry credential error")
+	}
+	secret := &apiV1.Secret{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name: pullSecretName(cred.Server),
+		},
+		Type: apiV1.SecretTypeDockerConfigJson,
+		Data: map[string][]byte{
+			apiV1.DockerConfigJsonKey: config,
+		},
+	}
+
+	secretsClient := ks.clientSet.CoreV1().Secrets(namespace)
+	_, err = secretsClient.Create(secret)
+	if k8sErrors.IsAlreadyExists(err) {
+		_, err = secretsClient.Update(secret)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "apply registry pull secret error")
+	}
+
+	return []apiV1.LocalObjectReference{{Name: secret.Name}}, nil
+}
@@ -0,0 +1,93 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package k8s
+
+import (
+	"github.com/pkg/errors"
+	apiV1 "k8s.io/api/core/v1"
+	networkingV1 "k8s.io/api/networking/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// Prefix of the namespace of a tenant
+	tenantNamespacePrefix = "tenant-"
+	// Key for the label attached to a tenant's namespace containing the tenant
+	tenantLabelKey = "tenant"
+
+	// Name of the ResourceQuota of a tenant's namespace
+	tenantQuotaName = "tenant-quota"
+	// Name of the NetworkPolicy denying ingress to the pods of a tenant
+	tenantDenyPolicyName = "default-deny"
+)
+
+// tenantNamespace returns the namespace of a tenant. Apps without a tenant
+// are deployed to the default namespace.
+func tenantNamespace(tenant string) string {
+	if tenant == "" {
+		return apiV1.NamespaceDefault
+	}
+	return tenantNamespacePrefix + tenant
+}
+
+// ensureTenant creates the namespace of a tenant with its ResourceQuota and a
+// NetworkPolicy that denies all ingress to the tenant's pods unless an app's
+// traffic policy allows it. Objects that already exist are left untouched.
+func (ks *Client) ensureTenant(namespace, tenant string) error {
+	if tenant == "" {
+		return nil
+	}
+
+	_, err := ks.clientSet.CoreV1().Namespaces().Create(&apiV1.Namespace{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:   namespace,
+			Labels: map[string]string{tenantLabelKey: tenant},
+		},
+	})
+	if err != nil && !k8sErrors.IsAlreadyExists(err) {
+		return errors.Wrap(err, "create kubernetes namespace error")
+	}
+
+	hard := apiV1.ResourceList{}
+	if ks.TenantQuota.Cores > 0 {
+		hard[apiV1.ResourceLimitsCPU] = *resource.NewQuantity(
+			int64(ks.TenantQuota.Cores),
+			resource.DecimalSI,
+		)
+	}
+	if ks.TenantQuota.Memory > 0 {
+		hard[apiV1.ResourceLimitsMemory] = *resource.NewQuantity(
+			int64(1024*1024*ks.TenantQuota.Memory),
+			resource.BinarySI,
+		)
+	}
+	if ks.TenantQuota.Hugepages > 0 {
+		hard[apiV1.ResourceName(apiV1.ResourceRequestsHugePagesPrefix+"2Mi")] = *resource.NewQuantity(
+			int64(1024*1024*ks.TenantQuota.Hugepages),
+			resource.BinarySI,
+		)
+	}
+	_, err = ks.clientSet.CoreV1().ResourceQuotas(namespace).Create(&apiV1.ResourceQuota{
+		ObjectMeta: metaV1.ObjectMeta{Name: tenantQuotaName},
+		Spec:       apiV1.ResourceQuotaSpec{Hard: hard},
+	})
+	if err != nil && !k8sErrors.IsAlreadyExists(err) {
+		return errors.Wrap(err, "create kubernetes resource quota error")
+	}
+
+	_, err = ks.clientSet.NetworkingV1().NetworkPolicies(namespace).Create(&networkingV1.NetworkPolicy{
+		ObjectMeta: metaV1.ObjectMeta{Name: tenantDenyPolicyName},
+		Spec: networkingV1.NetworkPolicySpec{
+			PodSelector: metaV1.LabelSelector{},
+			PolicyTypes: []networkingV1.PolicyType{networkingV1.PolicyTypeIngress},
+		},
+	})
+	if err != nil && !k8sErrors.IsAlreadyExists(err) {
+		return errors.Wrap(err, "create kubernetes network policy error")
+	}
+
+	return nil
+}
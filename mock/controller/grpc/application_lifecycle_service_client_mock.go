@@ -5,6 +5,7 @@ package grpc
 
 import (
 	"context"
+	"io"
 
 	"github.com/golang/protobuf/ptypes/empty"
 	gmock "github.com/open-ness/edgecontroller/mock/node/grpc"
@@ -52,3 +53,76 @@ func (c *MockPBApplicationLifecycleServiceClient) GetStatus(
 ) (*evapb.LifecycleStatus, error) {
 	return c.MockNode.AppLifeSvc.GetStatus(ctx, in)
 }
+
+// GetLogs delegates to a MockNode. The MockNode runs in a goroutine that
+// hands the chunks of the logs over to the returned stream.
+func (c *MockPBApplicationLifecycleServiceClient) GetLogs(
+	ctx context.Context,
+	in *evapb.LogRequest,
+	opts ...grpc.CallOption,
+) (evapb.ApplicationLifecycleService_GetLogsClient, error) {
+	stream := &mockGetLogsClient{
+		ctx:    ctx,
+		chunks: make(chan *evapb.LogChunk),
+		done:   make(chan error, 1),
+	}
+
+	go func() {
+		stream.done <- c.MockNode.AppLifeSvc.GetLogs(in, &mockGetLogsServer{client: stream})
+	}()
+
+	return stream, nil
+}
+
+// mockGetLogsClient receives the chunks sent by mockGetLogsServer.
+type mockGetLogsClient struct {
+	grpc.ClientStream
+
+	ctx    context.Context
+	chunks chan *evapb.LogChunk
+	done   chan error
+	err    error
+}
+
+func (s *mockGetLogsClient) Context() context.Context {
+	return s.ctx
+}
+
+func (s *mockGetLogsClient) Recv() (*evapb.LogChunk, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	select {
+	case chunk := <-s.chunks:
+		return chunk, nil
+	case err := <-s.done:
+		s.err = err
+		if s.err == nil {
+			s.err = io.EOF
+		}
+	case <-s.ctx.Done():
+		s.err = s.ctx.Err()
+	}
+	return nil, s.err
+}
+
+// mockGetLogsServer sends chunks to a mockGetLogsClient.
+type mockGetLogsServer struct {
+	grpc.ServerStream
+
+	client *mockGetLogsClient
+}
+
+func (s *mockGetLogsServer) Context() context.Context {
+	return s.client.ctx
+}
+
+func (s *mockGetLogsServer) Send(chunk *evapb.LogChunk) error {
+	select {
+	case s.client.chunks <- chunk:
+		return nil
+	case <-s.client.ctx.Done():
+		return s.client.ctx.Err()
+	}
+}
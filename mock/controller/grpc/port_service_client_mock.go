@@ -0,0 +1,45 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package grpc
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	gmock "github.com/open-ness/edgecontroller/mock/node/grpc"
+	ifsvcpb "github.com/open-ness/edgecontroller/pb/interfaceservice"
+	"google.golang.org/grpc"
+)
+
+// MockPBPortServiceClient delegates to a MockNode.
+type MockPBPortServiceClient struct {
+	MockNode *gmock.MockNode
+}
+
+// Get delegates to a MockNode.
+func (c *MockPBPortServiceClient) Get(
+	ctx context.Context,
+	in *empty.Empty,
+	opts ...grpc.CallOption,
+) (*ifsvcpb.Ports, error) {
+	return c.MockNode.PortSvc.Get(ctx, in)
+}
+
+// Attach delegates to a MockNode.
+func (c *MockPBPortServiceClient) Attach(
+	ctx context.Context,
+	in *ifsvcpb.Ports,
+	opts ...grpc.CallOption,
+) (*empty.Empty, error) {
+	return c.MockNode.PortSvc.Attach(ctx, in)
+}
+
+// Detach delegates to a MockNode.
+func (c *MockPBPortServiceClient) Detach(
+	ctx context.Context,
+	in *ifsvcpb.Ports,
+	opts ...grpc.CallOption,
+) (*empty.Empty, error) {
+	return c.MockNode.PortSvc.Detach(ctx, in)
+}
@@ -5,6 +5,8 @@ package grpc
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/golang/protobuf/ptypes/empty"
 	evapb "github.com/open-ness/edgecontroller/pb/eva"
@@ -17,6 +19,9 @@ type appDeployLifeService struct {
 	containerApps map[string]*evapb.Application
 	vmApps        map[string]*evapb.Application
 
+	// map of application ID to the lines logged by the application
+	logs map[string][]logLine
+
 	// reference to policy server
 	appPolicyService *appPolicyService
 }
@@ -25,12 +30,28 @@ func newAppDeployLifeService() *appDeployLifeService {
 	return &appDeployLifeService{
 		containerApps: make(map[string]*evapb.Application),
 		vmApps:        make(map[string]*evapb.Application),
+		logs:          make(map[string][]logLine),
 	}
 }
 
+// logLine is a line logged by an application.
+type logLine struct {
+	time time.Time
+	text string
+}
+
+// log records a line in the logs of an application.
+func (s *appDeployLifeService) log(id, format string, args ...interface{}) {
+	s.logs[id] = append(s.logs[id], logLine{
+		time: time.Now(),
+		text: fmt.Sprintf(format, args...) + "\n",
+	})
+}
+
 func (s *appDeployLifeService) reset() {
 	s.containerApps = make(map[string]*evapb.Application)
 	s.vmApps = make(map[string]*evapb.Application)
+	s.logs = make(map[string][]logLine)
 }
 
 func (s *appDeployLifeService) DeployContainer(
@@ -39,6 +60,7 @@ func (s *appDeployLifeService) DeployContainer(
 ) (*empty.Empty, error) {
 	s.containerApps[containerApp.Id] = containerApp
 	containerApp.Status = evapb.LifecycleStatus_READY
+	s.log(containerApp.Id, "Application %s deployed", containerApp.Id)
 
 	return &empty.Empty{}, nil
 }
@@ -49,6 +71,7 @@ func (s *appDeployLifeService) DeployVM(
 ) (*empty.Empty, error) {
 	s.vmApps[vmApp.Id] = vmApp
 	vmApp.Status = evapb.LifecycleStatus_READY
+	s.log(vmApp.Id, "Application %s deployed", vmApp.Id)
 
 	return &empty.Empty{}, nil
 }
@@ -104,11 +127,13 @@ func (s *appDeployLifeService) Undeploy(
 
 	if _, ok := s.containerApps[id.Id]; ok {
 		delete(s.containerApps, id.Id)
+		delete(s.logs, id.Id)
 		return &empty.Empty{}, nil
 	}
 
 	if _, ok := s.vmApps[id.Id]; ok {
 		delete(s.vmApps, id.Id)
+		delete(s.logs, id.Id)
 		return &empty.Empty{}, nil
 	}
 
@@ -137,6 +162,7 @@ func (s *appDeployLifeService) Start(
 		}
 
 		app.Status = evapb.LifecycleStatus_RUNNING
+		s.log(cmd.Id, "Application %s started", cmd.Id)
 		return &empty.Empty{}, nil
 	}
 
@@ -157,6 +183,7 @@ func (s *appDeployLifeService) Stop(
 		}
 
 		app.Status = evapb.LifecycleStatus_STOPPED
+		s.log(cmd.Id, "Application %s stopped", cmd.Id)
 		return &empty.Empty{}, nil
 	}
 
@@ -176,6 +203,7 @@ func (s *appDeployLifeService) Restart(
 				codes.FailedPrecondition, "Application %s not running", cmd.Id)
 		}
 
+		s.log(cmd.Id, "Application %s restarted", cmd.Id)
 		return &empty.Empty{}, nil
 	}
 
@@ -183,6 +211,37 @@ func (s *appDeployLifeService) Restart(
 		codes.NotFound, "Application %s not found", cmd.Id)
 }
 
+func (s *appDeployLifeService) GetLogs(
+	req *evapb.LogRequest,
+	stream evapb.ApplicationLifecycleService_GetLogsServer,
+) error {
+	if s.find(req.Id) == nil {
+		return status.Errorf(codes.NotFound, "Application %s not found", req.Id)
+	}
+
+	var lines []logLine
+	for _, line := range s.logs[req.Id] {
+		if req.Since == 0 || line.time.Unix() >= req.Since {
+			lines = append(lines, line)
+		}
+	}
+	if req.Tail > 0 && int64(len(lines)) > req.Tail {
+		lines = lines[int64(len(lines))-req.Tail:]
+	}
+
+	for _, line := range lines {
+		if err := stream.Send(&evapb.LogChunk{Data: []byte(line.text)}); err != nil {
+			return err
+		}
+	}
+
+	// The mock logs nothing more, so a follow only waits for the client
+	if req.Follow {
+		<-stream.Context().Done()
+	}
+	return nil
+}
+
 func (s *appDeployLifeService) find(id string) *evapb.Application {
 	if containerApp, ok := s.containerApps[id]; ok {
 		return containerApp
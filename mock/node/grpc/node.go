@@ -6,6 +6,7 @@ package grpc
 import (
 	elapb "github.com/open-ness/edgecontroller/pb/ela"
 	evapb "github.com/open-ness/edgecontroller/pb/eva"
+	ifsvcpb "github.com/open-ness/edgecontroller/pb/interfaceservice"
 )
 
 // MockNode provides a mock node gRPC server.
@@ -17,6 +18,7 @@ type MockNode struct {
 	InterfaceSvc elapb.InterfaceServiceServer
 	IfPolicySvc  elapb.InterfacePolicyServiceServer
 	ZoneSvc      elapb.ZoneServiceServer
+	PortSvc      ifsvcpb.InterfaceServiceServer
 }
 
 // NewMockNode creates a new MockNode with node services initialized.
@@ -29,6 +31,7 @@ func NewMockNode() *MockNode {
 		interfaceSvc     = newInterfaceService()
 		ifPolicySvc      = newInterfacePolicyService(interfaceSvc)
 		zoneSvc          = &zoneService{}
+		portSvc          = newPortService()
 	)
 
 	appDeployLifeSvc.appPolicyService = appPolicySvc
@@ -40,6 +43,7 @@ func NewMockNode() *MockNode {
 		InterfaceSvc: interfaceSvc,
 		IfPolicySvc:  ifPolicySvc,
 		ZoneSvc:      zoneSvc,
+		PortSvc:      portSvc,
 		DNSSvc:       dnsSvc,
 	}
 }
@@ -51,5 +55,6 @@ func (mn *MockNode) Reset() {
 	mn.InterfaceSvc.(*interfaceService).reset()
 	mn.IfPolicySvc.(*interfacePolicyService).reset()
 	mn.ZoneSvc.(*zoneService).reset()
+	mn.PortSvc.(*portService).reset()
 	mn.DNSSvc.(*dnsService).reset()
 }
@@ -0,0 +1,101 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package grpc
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	ifsvcpb "github.com/open-ness/edgecontroller/pb/interfaceservice"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type portService struct {
+	ports []*ifsvcpb.Port
+}
+
+func ports() []*ifsvcpb.Port {
+	return []*ifsvcpb.Port{
+		{
+			Pci:        "0000:00:00.0",
+			Driver:     ifsvcpb.Port_NONE,
+			MacAddress: "mac0",
+		},
+		{
+			Pci:        "0000:00:00.1",
+			Driver:     ifsvcpb.Port_NONE,
+			MacAddress: "mac1",
+		},
+	}
+}
+
+func newPortService() *portService {
+	return &portService{
+		ports: ports(),
+	}
+}
+
+func (s *portService) reset() {
+	s.ports = ports()
+}
+
+func (s *portService) Get(
+	context.Context,
+	*empty.Empty,
+) (*ifsvcpb.Ports, error) {
+	return &ifsvcpb.Ports{
+		Ports: s.ports,
+	}, nil
+}
+
+func (s *portService) Attach(
+	ctx context.Context,
+	ports *ifsvcpb.Ports,
+) (*empty.Empty, error) {
+	for _, port := range ports.Ports {
+		if s.find(port.Pci) == nil {
+			return nil, status.Errorf(
+				codes.NotFound, "Port %s not found", port.Pci)
+		}
+	}
+
+	for _, port := range ports.Ports {
+		p := s.find(port.Pci)
+		p.Bridge = port.Bridge
+		p.Driver = port.Driver
+	}
+
+	return &empty.Empty{}, nil
+}
+
+func (s *portService) Detach(
+	ctx context.Context,
+	ports *ifsvcpb.Ports,
+) (*empty.Empty, error) {
+	for _, port := range ports.Ports {
+		if s.find(port.Pci) == nil {
+			return nil, status.Errorf(
+				codes.NotFound, "Port %s not found", port.Pci)
+		}
+	}
+
+	for _, port := range ports.Ports {
+		p := s.find(port.Pci)
+		p.Bridge = ""
+		p.Driver = ifsvcpb.Port_NONE
+	}
+
+	return &empty.Empty{}, nil
+}
+
+func (s *portService) find(pci string) *ifsvcpb.Port {
+	for _, port := range s.ports {
+		if port.Pci == pci {
+			return port
+		}
+	}
+
+	return nil
+}
@@ -39,6 +39,50 @@ CREATE TABLE nodes_nfd_features (
     UNIQUE KEY (node_id, nfd_id)
 );
 
+-- drift is only meaningful while the node exists, so we specify ON DELETE CASCADE
+CREATE TABLE node_drifts (
+    id VARCHAR(36) GENERATED ALWAYS AS (entity->>'$.id') STORED UNIQUE KEY,
+    node_id VARCHAR(36) GENERATED ALWAYS AS (entity->>'$.node_id') STORED,
+    entity JSON,
+    FOREIGN KEY (node_id) REFERENCES nodes(id) ON DELETE CASCADE,
+    UNIQUE KEY (node_id)
+);
+
+-- zones are only meaningful while the node exists, so we specify ON DELETE CASCADE
+CREATE TABLE nodes_zones (
+    id VARCHAR(36) GENERATED ALWAYS AS (entity->>'$.id') STORED UNIQUE KEY,
+    node_id VARCHAR(36) GENERATED ALWAYS AS (entity->>'$.node_id') STORED,
+    zone_id VARCHAR(36) GENERATED ALWAYS AS (entity->>'$.zone_id') STORED,
+    entity JSON,
+    FOREIGN KEY (node_id) REFERENCES nodes(id) ON DELETE CASCADE,
+    UNIQUE KEY (node_id, zone_id)
+);
+
+-- maintenance windows are only meaningful while the node exists, so we specify
+-- ON DELETE CASCADE
+CREATE TABLE nodes_maintenance_windows (
+    id VARCHAR(36) GENERATED ALWAYS AS (entity->>'$.id') STORED UNIQUE KEY,
+    node_id VARCHAR(36) GENERATED ALWAYS AS (entity->>'$.node_id') STORED,
+    entity JSON,
+    FOREIGN KEY (node_id) REFERENCES nodes(id) ON DELETE CASCADE
+);
+
+CREATE TABLE nodes_ports (
+    id VARCHAR(36) GENERATED ALWAYS AS (entity->>'$.id') STORED UNIQUE KEY,
+    node_id VARCHAR(36) GENERATED ALWAYS AS (entity->>'$.node_id') STORED,
+    pci VARCHAR(36) GENERATED ALWAYS AS (entity->>'$.pci') STORED,
+    entity JSON,
+    FOREIGN KEY (node_id) REFERENCES nodes(id) ON DELETE CASCADE,
+    UNIQUE KEY (node_id, pci)
+);
+
+CREATE TABLE nodes_capacities (
+    id VARCHAR(36) GENERATED ALWAYS AS (entity->>'$.id') STORED UNIQUE KEY,
+    node_id VARCHAR(36) GENERATED ALWAYS AS (entity->>'$.node_id') STORED UNIQUE KEY,
+    entity JSON,
+    FOREIGN KEY (node_id) REFERENCES nodes(id) ON DELETE CASCADE
+);
+
 CREATE TABLE apps (
     id VARCHAR(36) GENERATED ALWAYS AS (entity->>'$.id') STORED UNIQUE KEY,
     type VARCHAR(36) GENERATED ALWAYS AS (entity->>'$.type') STORED,
@@ -60,6 +104,51 @@ CREATE TABLE credentials (
     entity JSON
 );
 
+CREATE TABLE networks (
+    id VARCHAR(36) GENERATED ALWAYS AS (entity->>'$.id') STORED UNIQUE KEY,
+    name VARCHAR(63) GENERATED ALWAYS AS (entity->>'$.name') STORED UNIQUE KEY,
+    entity JSON
+);
+
+CREATE TABLE app_bundles (
+    id VARCHAR(36) GENERATED ALWAYS AS (entity->>'$.id') STORED UNIQUE KEY,
+    entity JSON
+);
+
+CREATE TABLE app_templates (
+    id VARCHAR(36) GENERATED ALWAYS AS (entity->>'$.id') STORED UNIQUE KEY,
+    entity JSON
+);
+
+CREATE TABLE registries (
+    id VARCHAR(36) GENERATED ALWAYS AS (entity->>'$.id') STORED UNIQUE KEY,
+    host VARCHAR(255) GENERATED ALWAYS AS (entity->>'$.host') STORED UNIQUE KEY,
+    entity JSON
+);
+
+-- operations are kept after the node or app they refer to is deleted, so no
+-- foreign keys are specified
+CREATE TABLE operations (
+    id VARCHAR(36) GENERATED ALWAYS AS (entity->>'$.id') STORED UNIQUE KEY,
+    node_id VARCHAR(36) GENERATED ALWAYS AS (entity->>'$.node_id') STORED,
+    app_id VARCHAR(36) GENERATED ALWAYS AS (entity->>'$.app_id') STORED,
+    state VARCHAR(36) GENERATED ALWAYS AS (entity->>'$.state') STORED,
+    entity JSON,
+    INDEX (node_id),
+    INDEX (state)
+);
+
+-- upgrades are kept after the app they refer to is deleted, so no foreign keys
+-- are specified
+CREATE TABLE apps_upgrades (
+    id VARCHAR(36) GENERATED ALWAYS AS (entity->>'$.id') STORED UNIQUE KEY,
+    app_id VARCHAR(36) GENERATED ALWAYS AS (entity->>'$.app_id') STORED,
+    state VARCHAR(36) GENERATED ALWAYS AS (entity->>'$.state') STORED,
+    entity JSON,
+    INDEX (app_id),
+    INDEX (state)
+);
+
 -- -------------------
 -- Primary join tables
 -- -------------------
@@ -89,6 +178,17 @@ CREATE TABLE nodes_apps (
     UNIQUE KEY (node_id, app_id)
 );
 
+-- nodes x app_bundles
+CREATE TABLE nodes_app_bundles (
+    id VARCHAR(36) GENERATED ALWAYS AS (entity->>'$.id') STORED UNIQUE KEY,
+    node_id VARCHAR(36) GENERATED ALWAYS AS (entity->>'$.node_id') STORED,
+    bundle_id VARCHAR(36) GENERATED ALWAYS AS (entity->>'$.bundle_id') STORED,
+    entity JSON,
+    FOREIGN KEY (node_id) REFERENCES nodes(id),
+    FOREIGN KEY (bundle_id) REFERENCES app_bundles(id),
+    UNIQUE KEY (node_id, bundle_id)
+);
+
 -- nodes x dns_configs
 CREATE TABLE nodes_dns_configs (
     id VARCHAR(36) GENERATED ALWAYS AS (entity->>'$.id') STORED UNIQUE KEY,
@@ -100,6 +200,17 @@ CREATE TABLE nodes_dns_configs (
     FOREIGN KEY (dns_config_id) REFERENCES dns_configs(id)
 );
 
+-- nodes x apps, scheduled lifecycle commands are only meaningful while the
+-- node and the app exist, so we specify ON DELETE CASCADE
+CREATE TABLE schedules (
+    id VARCHAR(36) GENERATED ALWAYS AS (entity->>'$.id') STORED UNIQUE KEY,
+    node_id VARCHAR(36) GENERATED ALWAYS AS (entity->>'$.node_id') STORED,
+    app_id VARCHAR(36) GENERATED ALWAYS AS (entity->>'$.app_id') STORED,
+    entity JSON,
+    FOREIGN KEY (node_id) REFERENCES nodes(id) ON DELETE CASCADE,
+    FOREIGN KEY (app_id) REFERENCES apps(id) ON DELETE CASCADE
+);
+
 -- nodes (network_interfaces) x traffic_policies
 CREATE TABLE nodes_network_interfaces_traffic_policies (
     id VARCHAR(36) GENERATED ALWAYS AS (entity->>'$.id') STORED UNIQUE KEY,
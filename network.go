@@ -0,0 +1,108 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package cce
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/open-ness/edgecontroller/uuid"
+)
+
+// Network is a CNI network apps are attached to in addition to their default
+// network. The CNI configuration is passed to the node in native mode and is
+// the configuration of a Multus NetworkAttachmentDefinition in Kubernetes
+// mode. Args are CNI_ARGS, i.e. KEY=VALUE pairs separated by semicolons.
+type Network struct {
+	ID            string          `json:"id"`
+	Name          string          `json:"name"`
+	CNIConfig     json.RawMessage `json:"cni_config"`
+	InterfaceName string          `json:"interface_name,omitempty"`
+	Args          string          `json:"args,omitempty"`
+}
+
+var interfaceNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9_.-]{1,15}$`)
+
+// GetTableName returns the name of the persistence table.
+func (*Network) GetTableName() string {
+	return "networks"
+}
+
+// GetID gets the ID.
+func (n *Network) GetID() string {
+	return n.ID
+}
+
+// SetID sets the ID.
+func (n *Network) SetID(id string) {
+	n.ID = id
+}
+
+// Validate validates the model.
+func (n *Network) Validate() error {
+	if !uuid.IsValid(n.ID) {
+		return errors.New("id not a valid uuid")
+	}
+	if !dnsLabelRegexp.MatchString(n.Name) || len(n.Name) > 63 {
+		return errors.New("name must be a lowercase DNS label")
+	}
+	var config map[string]json.RawMessage
+	if err := json.Unmarshal(n.CNIConfig, &config); err != nil {
+		return errors.New("cni_config must be a JSON object")
+	}
+	if config["type"] == nil && config["plugins"] == nil {
+		return errors.New(`cni_config must have a "type" or "plugins"`)
+	}
+	if n.InterfaceName != "" && !interfaceNameRegexp.MatchString(n.InterfaceName) {
+		return fmt.Errorf("interface_name %q must be a network interface name of at most 15 characters",
+			n.InterfaceName)
+	}
+	if _, err := n.ArgsMap(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ArgsMap returns the args as a map of keys to values.
+func (n *Network) ArgsMap() (map[string]string, error) {
+	args := make(map[string]string)
+	if n.Args == "" {
+		return args, nil
+	}
+	for _, pair := range strings.Split(n.Args, ";") {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return nil, fmt.Errorf("args %q must be KEY=VALUE pairs separated by semicolons", n.Args)
+		}
+		args[kv[0]] = kv[1]
+	}
+	return args, nil
+}
+
+// FilterFields returns the filterable fields for this model.
+func (*Network) FilterFields() []string {
+	return []string{
+		"name",
+	}
+}
+
+func (n *Network) String() string {
+	return fmt.Sprintf(strings.TrimSpace(`
+Network[
+    ID: %s
+    Name: %s
+    CNIConfig: %s
+    InterfaceName: %s
+    Args: %s
+]`),
+		n.ID,
+		n.Name,
+		n.CNIConfig,
+		n.InterfaceName,
+		n.Args)
+}
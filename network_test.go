@@ -0,0 +1,125 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package cce_test
+
+import (
+	"encoding/json"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	cce "github.com/open-ness/edgecontroller"
+)
+
+var _ = Describe("Entities: Network", func() {
+	var (
+		network *cce.Network
+	)
+
+	BeforeEach(func() {
+		network = &cce.Network{
+			ID:            "0d4f6a2c-1b3e-4c5d-9e8f-7a6b5c4d3e2f",
+			Name:          "dataplane",
+			CNIConfig:     json.RawMessage(`{"cniVersion":"0.3.1","type":"macvlan","master":"eth1"}`),
+			InterfaceName: "net1",
+			Args:          "IgnoreUnknown=1;mtu=9000",
+		}
+	})
+
+	Describe("GetTableName", func() {
+		It(`Should return "networks"`, func() {
+			Expect(network.GetTableName()).To(Equal("networks"))
+		})
+	})
+
+	Describe("GetID", func() {
+		It("Should return the ID", func() {
+			Expect(network.GetID()).To(Equal("0d4f6a2c-1b3e-4c5d-9e8f-7a6b5c4d3e2f"))
+		})
+	})
+
+	Describe("SetID", func() {
+		It("Should set and return the updated ID", func() {
+			By("Setting the ID")
+			network.SetID("456")
+
+			By("Getting the updated ID")
+			Expect(network.ID).To(Equal("456"))
+		})
+	})
+
+	Describe("Validate", func() {
+		It("Should not return an error for a valid network", func() {
+			Expect(network.Validate()).To(Succeed())
+		})
+
+		It("Should not return an error for a network list", func() {
+			network.CNIConfig = json.RawMessage(`{"cniVersion":"0.3.1","plugins":[{"type":"bridge"}]}`)
+			Expect(network.Validate()).To(Succeed())
+		})
+
+		It("Should return an error if ID is not a UUID", func() {
+			network.ID = "123"
+			Expect(network.Validate()).To(MatchError("id not a valid uuid"))
+		})
+
+		It("Should return an error if Name is not a DNS label", func() {
+			network.Name = "Data Plane"
+			Expect(network.Validate()).To(MatchError("name must be a lowercase DNS label"))
+		})
+
+		It("Should return an error if CNIConfig is not a JSON object", func() {
+			network.CNIConfig = json.RawMessage(`["macvlan"]`)
+			Expect(network.Validate()).To(MatchError("cni_config must be a JSON object"))
+		})
+
+		It("Should return an error if CNIConfig has no type", func() {
+			network.CNIConfig = json.RawMessage(`{"cniVersion":"0.3.1"}`)
+			Expect(network.Validate()).To(MatchError(`cni_config must have a "type" or "plugins"`))
+		})
+
+		It("Should return an error if InterfaceName is too long", func() {
+			network.InterfaceName = "dataplane-interface"
+			Expect(network.Validate()).To(MatchError(
+				`interface_name "dataplane-interface" must be a network interface name of at most 15 characters`))
+		})
+
+		It("Should return an error if Args are not KEY=VALUE pairs", func() {
+			network.Args = "IgnoreUnknown"
+			Expect(network.Validate()).To(MatchError(
+				`args "IgnoreUnknown" must be KEY=VALUE pairs separated by semicolons`))
+		})
+	})
+
+	Describe("ArgsMap", func() {
+		It("Should return the args by key", func() {
+			Expect(network.ArgsMap()).To(Equal(map[string]string{
+				"IgnoreUnknown": "1",
+				"mtu":           "9000",
+			}))
+		})
+	})
+
+	Describe("FilterFields", func() {
+		It("Should return the filterable fields", func() {
+			Expect(network.FilterFields()).To(Equal([]string{
+				"name",
+			}))
+		})
+	})
+
+	Describe("String", func() {
+		It("Should return the string value", func() {
+			Expect(network.String()).To(Equal(strings.TrimSpace(`
+Network[
+    ID: 0d4f6a2c-1b3e-4c5d-9e8f-7a6b5c4d3e2f
+    Name: dataplane
+    CNIConfig: {"cniVersion":"0.3.1","type":"macvlan","master":"eth1"}
+    InterfaceName: net1
+    Args: IgnoreUnknown=1;mtu=9000
+]`,
+			)))
+		})
+	})
+})
@@ -4,18 +4,30 @@
 package cce
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/open-ness/edgecontroller/uuid"
 )
 
-// NodeApp represents an association between a Node and an App.
+// NodeApp represents an association between a Node and an App. An app that
+// was upgraded keeps its AppID on the node and runs the version VersionID.
+// Tenant is the tenant of the app when it was deployed. An app instantiated
+// from a template records the template, its version and the values of its
+// parameters the app was deployed from.
 type NodeApp struct {
-	ID     string `json:"id"`
-	NodeID string `json:"node_id"`
-	AppID  string `json:"app_id"`
+	ID        string `json:"id"`
+	NodeID    string `json:"node_id"`
+	AppID     string `json:"app_id"`
+	VersionID string `json:"version_id,omitempty"`
+	Tenant    string `json:"tenant,omitempty"`
+
+	TemplateID         string                     `json:"template_id,omitempty"`
+	TemplateVersion    int                        `json:"template_version,omitempty"`
+	TemplateParameters map[string]json.RawMessage `json:"template_parameters,omitempty"`
 }
 
 // NodeAppReq is a NodeApp request.
@@ -29,7 +41,18 @@ type NodeAppReq struct {
 // TODO add a String() method and test for this struct.
 type NodeAppResp struct {
 	NodeApp
-	Status string `json:"status"`
+	Status        string `json:"status"`
+	StatusReason  string `json:"status_reason,omitempty"`
+	StatusMessage string `json:"status_message,omitempty"`
+}
+
+// NodeAppLogOptions selects the logs of a node app. Tail is the number of
+// lines from the end to return and Since the time of the oldest line; zero
+// values return all lines. Follow keeps streaming lines as they are written.
+type NodeAppLogOptions struct {
+	Follow bool
+	Tail   int64
+	Since  time.Time
 }
 
 // GetTableName returns the name of the persistence table.
@@ -63,10 +86,32 @@ func (n_a *NodeApp) Validate() error {
 	if !uuid.IsValid(n_a.AppID) {
 		return errors.New("app_id not a valid uuid")
 	}
+	if n_a.VersionID != "" && !uuid.IsValid(n_a.VersionID) {
+		return errors.New("version_id not a valid uuid")
+	}
+	if !ValidTenant(n_a.Tenant) {
+		return fmt.Errorf("tenant must be a lowercase DNS label of at most %d characters", MaxTenantLength)
+	}
+	if n_a.TemplateID != "" {
+		if !uuid.IsValid(n_a.TemplateID) {
+			return errors.New("template_id not a valid uuid")
+		}
+		if n_a.TemplateVersion < 1 {
+			return errors.New("template_version must be positive")
+		}
+	}
 
 	return nil
 }
 
+// RunningAppID returns the ID of the app version running on the node.
+func (n_a *NodeApp) RunningAppID() string {
+	if n_a.VersionID != "" {
+		return n_a.VersionID
+	}
+	return n_a.AppID
+}
+
 // FilterFields returns the filterable fields for this model.
 func (*NodeApp) FilterFields() []string {
 	return []string{
@@ -0,0 +1,117 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package cce
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/open-ness/edgecontroller/uuid"
+)
+
+const (
+	// AppBundleStatusNotDeployed is the status of a member of a bundle that
+	// is not deployed to the node
+	AppBundleStatusNotDeployed = "not_deployed"
+	// AppBundleStatusPartial is the status of a bundle whose members are in
+	// different states, none of them failed
+	AppBundleStatusPartial = "partial"
+)
+
+// NodeAppBundle represents an association between a Node and an AppBundle.
+// The members of the bundle are deployed to the node as node apps.
+type NodeAppBundle struct {
+	ID       string `json:"id"`
+	NodeID   string `json:"node_id"`
+	BundleID string `json:"bundle_id"`
+}
+
+// GetTableName returns the name of the persistence table.
+func (*NodeAppBundle) GetTableName() string {
+	return "nodes_app_bundles"
+}
+
+// GetID gets the ID.
+func (n_b *NodeAppBundle) GetID() string {
+	return n_b.ID
+}
+
+// SetID sets the ID.
+func (n_b *NodeAppBundle) SetID(id string) {
+	n_b.ID = id
+}
+
+// GetNodeID gets the node ID.
+func (n_b *NodeAppBundle) GetNodeID() string {
+	return n_b.NodeID
+}
+
+// Validate validates the model.
+func (n_b *NodeAppBundle) Validate() error {
+	if !uuid.IsValid(n_b.ID) {
+		return errors.New("id not a valid uuid")
+	}
+	if !uuid.IsValid(n_b.NodeID) {
+		return errors.New("node_id not a valid uuid")
+	}
+	if !uuid.IsValid(n_b.BundleID) {
+		return errors.New("bundle_id not a valid uuid")
+	}
+
+	return nil
+}
+
+// FilterFields returns the filterable fields for this model.
+func (*NodeAppBundle) FilterFields() []string {
+	return []string{
+		"node_id",
+		"bundle_id",
+	}
+}
+
+// BundleStatus returns the status of a bundle from the statuses of its
+// members. A bundle whose members share a status has that status. Otherwise
+// it has the status of a failed member, error before unhealthy, or
+// AppBundleStatusPartial.
+func BundleStatus(memberStatuses []string) string {
+	if len(memberStatuses) == 0 {
+		return AppBundleStatusNotDeployed
+	}
+
+	same := true
+	failed := ""
+	for _, status := range memberStatuses {
+		if status != memberStatuses[0] {
+			same = false
+		}
+		switch {
+		case status == Error.String():
+			failed = status
+		case status == Unhealthy.String() && failed == "":
+			failed = status
+		}
+	}
+
+	switch {
+	case same:
+		return memberStatuses[0]
+	case failed != "":
+		return failed
+	default:
+		return AppBundleStatusPartial
+	}
+}
+
+func (n_b *NodeAppBundle) String() string {
+	return fmt.Sprintf(strings.TrimSpace(`
+NodeAppBundle[
+    ID: %s
+    NodeID: %s
+    BundleID: %s
+]`),
+		n_b.ID,
+		n_b.NodeID,
+		n_b.BundleID)
+}
@@ -0,0 +1,110 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package cce_test
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	cce "github.com/open-ness/edgecontroller"
+)
+
+var _ = Describe("Entities: NodeAppBundle", func() {
+	var (
+		nodeBundle *cce.NodeAppBundle
+	)
+
+	BeforeEach(func() {
+		nodeBundle = &cce.NodeAppBundle{
+			ID:       "6a5b4c3d-2e1f-4a0b-9c8d-7e6f5a4b3c2d",
+			NodeID:   "a7c5fa8a-5b0b-4d1e-9d0a-6f5a2d8f3c1b",
+			BundleID: "9f8e7d6c-5b4a-4392-8170-6f5e4d3c2b1a",
+		}
+	})
+
+	Describe("GetTableName", func() {
+		It(`Should return "nodes_app_bundles"`, func() {
+			Expect(nodeBundle.GetTableName()).To(Equal("nodes_app_bundles"))
+		})
+	})
+
+	Describe("GetID", func() {
+		It("Should return the ID", func() {
+			Expect(nodeBundle.GetID()).To(Equal("6a5b4c3d-2e1f-4a0b-9c8d-7e6f5a4b3c2d"))
+		})
+	})
+
+	Describe("SetID", func() {
+		It("Should set and return the updated ID", func() {
+			By("Setting the ID")
+			nodeBundle.SetID("456")
+
+			By("Getting the updated ID")
+			Expect(nodeBundle.ID).To(Equal("456"))
+		})
+	})
+
+	Describe("GetNodeID", func() {
+		It("Should return the node ID", func() {
+			Expect(nodeBundle.GetNodeID()).To(Equal("a7c5fa8a-5b0b-4d1e-9d0a-6f5a2d8f3c1b"))
+		})
+	})
+
+	Describe("Validate", func() {
+		It("Should not return an error for a valid node app bundle", func() {
+			Expect(nodeBundle.Validate()).To(Succeed())
+		})
+
+		It("Should return an error if ID is not a UUID", func() {
+			nodeBundle.ID = "123"
+			Expect(nodeBundle.Validate()).To(MatchError("id not a valid uuid"))
+		})
+
+		It("Should return an error if NodeID is not a UUID", func() {
+			nodeBundle.NodeID = "123"
+			Expect(nodeBundle.Validate()).To(MatchError("node_id not a valid uuid"))
+		})
+
+		It("Should return an error if BundleID is not a UUID", func() {
+			nodeBundle.BundleID = "123"
+			Expect(nodeBundle.Validate()).To(MatchError("bundle_id not a valid uuid"))
+		})
+	})
+
+	Describe("FilterFields", func() {
+		It("Should return the filterable fields", func() {
+			Expect(nodeBundle.FilterFields()).To(Equal([]string{
+				"node_id",
+				"bundle_id",
+			}))
+		})
+	})
+
+	Describe("String", func() {
+		It("Should return the string value", func() {
+			Expect(nodeBundle.String()).To(Equal(strings.TrimSpace(`
+NodeAppBundle[
+    ID: 6a5b4c3d-2e1f-4a0b-9c8d-7e6f5a4b3c2d
+    NodeID: a7c5fa8a-5b0b-4d1e-9d0a-6f5a2d8f3c1b
+    BundleID: 9f8e7d6c-5b4a-4392-8170-6f5e4d3c2b1a
+]`,
+			)))
+		})
+	})
+
+	DescribeTable("BundleStatus",
+		func(statuses []string, expected string) {
+			Expect(cce.BundleStatus(statuses)).To(Equal(expected))
+		},
+		Entry("no members", nil, "not_deployed"),
+		Entry("all running", []string{"running", "running"}, "running"),
+		Entry("all stopped", []string{"stopped", "stopped"}, "stopped"),
+		Entry("some starting", []string{"running", "starting"}, "partial"),
+		Entry("some not deployed", []string{"running", "not_deployed"}, "partial"),
+		Entry("some unhealthy", []string{"running", "unhealthy", "stopped"}, "unhealthy"),
+		Entry("some failed", []string{"unhealthy", "error", "running"}, "error"),
+	)
+})
@@ -70,6 +70,42 @@ var _ = Describe("Join Entities: NodeApp", func() {
 			Expect(na.Validate()).To(MatchError(
 				"app_id not a valid uuid"))
 		})
+
+		It("Should return an error if VersionID is not a UUID", func() {
+			na.VersionID = "123"
+			Expect(na.Validate()).To(MatchError(
+				"version_id not a valid uuid"))
+		})
+
+		It("Should return an error if Tenant is not a DNS label", func() {
+			na.Tenant = "operator-a."
+			Expect(na.Validate()).To(MatchError(
+				"tenant must be a lowercase DNS label of at most 56 characters"))
+		})
+
+		It("Should return an error if TemplateID is not a UUID", func() {
+			na.TemplateID = "123"
+			na.TemplateVersion = 1
+			Expect(na.Validate()).To(MatchError(
+				"template_id not a valid uuid"))
+		})
+
+		It("Should return an error if TemplateVersion is not positive", func() {
+			na.TemplateID = "3e6f1c2a-8b4d-4f7e-9a0c-5d2b1e4f6a8c"
+			Expect(na.Validate()).To(MatchError(
+				"template_version must be positive"))
+		})
+	})
+
+	Describe("RunningAppID", func() {
+		It("Should return the app ID until the app is upgraded", func() {
+			Expect(na.RunningAppID()).To(Equal(
+				"efcece3c-6b58-4993-8d45-bde6239d4baa"))
+
+			na.VersionID = "9d740ea1-6b5c-4d0b-87e4-a8e0a0d7b7a0"
+			Expect(na.RunningAppID()).To(Equal(
+				"9d740ea1-6b5c-4d0b-87e4-a8e0a0d7b7a0"))
+		})
 	})
 
 	Describe("FilterFields", func() {
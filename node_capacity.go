@@ -0,0 +1,87 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package cce
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/open-ness/edgecontroller/uuid"
+)
+
+// NodeCapacity is the amount of resources of a node that apps can request, as
+// set by an administrator. It takes precedence over the resources reported by
+// Kubernetes or NFD.
+type NodeCapacity struct {
+	ID        string `json:"id"`
+	NodeID    string `json:"node_id"`
+	Cores     int    `json:"cores"`
+	Memory    int    `json:"memory"`    // in MB
+	Hugepages int    `json:"hugepages"` // in MB
+}
+
+// GetTableName returns the name of the persistence table.
+func (*NodeCapacity) GetTableName() string {
+	return "nodes_capacities"
+}
+
+// GetID gets the ID.
+func (c *NodeCapacity) GetID() string {
+	return c.ID
+}
+
+// SetID sets the ID.
+func (c *NodeCapacity) SetID(id string) {
+	c.ID = id
+}
+
+// GetNodeID gets the node ID.
+func (c *NodeCapacity) GetNodeID() string {
+	return c.NodeID
+}
+
+// Validate validates the model.
+func (c *NodeCapacity) Validate() error {
+	if !uuid.IsValid(c.ID) {
+		return errors.New("id not a valid uuid")
+	}
+	if !uuid.IsValid(c.NodeID) {
+		return errors.New("node_id not a valid uuid")
+	}
+	if c.Cores < 1 {
+		return errors.New("cores must be at least 1")
+	}
+	if c.Memory < 1 {
+		return errors.New("memory must be at least 1")
+	}
+	if c.Hugepages < 0 {
+		return errors.New("hugepages cannot be negative")
+	}
+
+	return nil
+}
+
+// FilterFields returns the filterable fields for this model.
+func (*NodeCapacity) FilterFields() []string {
+	return []string{
+		"node_id",
+	}
+}
+
+func (c *NodeCapacity) String() string {
+	return fmt.Sprintf(strings.TrimSpace(`
+NodeCapacity[
+    ID: %s
+    NodeID: %s
+    Cores: %d
+    Memory: %d
+    Hugepages: %d
+]`),
+		c.ID,
+		c.NodeID,
+		c.Cores,
+		c.Memory,
+		c.Hugepages)
+}
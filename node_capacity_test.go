@@ -0,0 +1,116 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package cce_test
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	cce "github.com/open-ness/edgecontroller"
+)
+
+var _ = Describe("Entities: NodeCapacity", func() {
+	var (
+		capacity *cce.NodeCapacity
+	)
+
+	BeforeEach(func() {
+		capacity = &cce.NodeCapacity{
+			ID:        "5f2b8f8e-3c1d-4a5b-9e6f-7a8b9c0d1e2f",
+			NodeID:    "48606c73-3905-47e0-864f-14bc7466f5bb",
+			Cores:     16,
+			Memory:    32768,
+			Hugepages: 2048,
+		}
+	})
+
+	Describe("GetTableName", func() {
+		It(`Should return "nodes_capacities"`, func() {
+			Expect(capacity.GetTableName()).To(Equal("nodes_capacities"))
+		})
+	})
+
+	Describe("GetID", func() {
+		It("Should return the ID", func() {
+			Expect(capacity.GetID()).To(Equal(
+				"5f2b8f8e-3c1d-4a5b-9e6f-7a8b9c0d1e2f"))
+		})
+	})
+
+	Describe("SetID", func() {
+		It("Should set and return the updated ID", func() {
+			By("Setting the ID")
+			capacity.SetID("456")
+
+			By("Getting the updated ID")
+			Expect(capacity.ID).To(Equal("456"))
+		})
+	})
+
+	Describe("GetNodeID", func() {
+		It("Should return the node ID", func() {
+			Expect(capacity.GetNodeID()).To(Equal(
+				"48606c73-3905-47e0-864f-14bc7466f5bb"))
+		})
+	})
+
+	Describe("Validate", func() {
+		It("Should not return an error for a valid capacity", func() {
+			Expect(capacity.Validate()).To(Succeed())
+		})
+
+		It("Should not return an error if Hugepages is 0", func() {
+			capacity.Hugepages = 0
+			Expect(capacity.Validate()).To(Succeed())
+		})
+
+		It("Should return an error if ID is not a UUID", func() {
+			capacity.ID = "123"
+			Expect(capacity.Validate()).To(MatchError("id not a valid uuid"))
+		})
+
+		It("Should return an error if NodeID is not a UUID", func() {
+			capacity.NodeID = "123"
+			Expect(capacity.Validate()).To(MatchError("node_id not a valid uuid"))
+		})
+
+		It("Should return an error if Cores is < 1", func() {
+			capacity.Cores = 0
+			Expect(capacity.Validate()).To(MatchError("cores must be at least 1"))
+		})
+
+		It("Should return an error if Memory is < 1", func() {
+			capacity.Memory = 0
+			Expect(capacity.Validate()).To(MatchError("memory must be at least 1"))
+		})
+
+		It("Should return an error if Hugepages is negative", func() {
+			capacity.Hugepages = -1
+			Expect(capacity.Validate()).To(MatchError("hugepages cannot be negative"))
+		})
+	})
+
+	Describe("FilterFields", func() {
+		It("Should return the filterable fields", func() {
+			Expect(capacity.FilterFields()).To(Equal([]string{
+				"node_id",
+			}))
+		})
+	})
+
+	Describe("String", func() {
+		It("Should return the string value", func() {
+			Expect(capacity.String()).To(Equal(strings.TrimSpace(`
+NodeCapacity[
+    ID: 5f2b8f8e-3c1d-4a5b-9e6f-7a8b9c0d1e2f
+    NodeID: 48606c73-3905-47e0-864f-14bc7466f5bb
+    Cores: 16
+    Memory: 32768
+    Hugepages: 2048
+]`,
+			)))
+		})
+	})
+})
@@ -0,0 +1,125 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package cce
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/open-ness/edgecontroller/uuid"
+)
+
+const (
+	// DriftKindNode is a node that could not be reached
+	DriftKindNode = "node"
+	// DriftKindApp is an app deployed to a node
+	DriftKindApp = "app"
+	// DriftKindInterface is a node's network interface configuration
+	DriftKindInterface = "interface"
+	// DriftKindInterfacePolicy is a traffic policy applied to a node's
+	// network interface
+	DriftKindInterfacePolicy = "interface_policy"
+	// DriftKindAppPolicy is a traffic policy applied to an app on a node
+	DriftKindAppPolicy = "app_policy"
+	// DriftKindDNS is a DNS configuration applied to a node
+	DriftKindDNS = "dns"
+)
+
+// NodeDrift is the result of the most recent reconciliation of a node. It
+// holds the differences between the desired state in persistence and the
+// state reported by the node that could not be resolved.
+type NodeDrift struct {
+	ID        string       `json:"id"`
+	NodeID    string       `json:"node_id"`
+	CheckedAt time.Time    `json:"checked_at"`
+	Items     []*DriftItem `json:"items"`
+}
+
+// DriftItem is a single unresolved difference on a node.
+type DriftItem struct {
+	Kind       string `json:"kind"`
+	ResourceID string `json:"resource_id,omitempty"`
+	Expected   string `json:"expected,omitempty"`
+	Actual     string `json:"actual,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// GetTableName returns the name of the persistence table.
+func (*NodeDrift) GetTableName() string {
+	return "node_drifts"
+}
+
+// GetID gets the ID.
+func (d *NodeDrift) GetID() string {
+	return d.ID
+}
+
+// SetID sets the ID.
+func (d *NodeDrift) SetID(id string) {
+	d.ID = id
+}
+
+// GetNodeID gets the node ID.
+func (d *NodeDrift) GetNodeID() string {
+	return d.NodeID
+}
+
+// Validate validates the model.
+func (d *NodeDrift) Validate() error {
+	if !uuid.IsValid(d.ID) {
+		return errors.New("id not a valid uuid")
+	}
+	if !uuid.IsValid(d.NodeID) {
+		return errors.New("node_id not a valid uuid")
+	}
+	for i, item := range d.Items {
+		switch item.Kind {
+		case DriftKindNode, DriftKindApp, DriftKindInterface,
+			DriftKindInterfacePolicy, DriftKindAppPolicy, DriftKindDNS:
+		default:
+			return fmt.Errorf(`items[%d].kind "%s" is invalid`, i, item.Kind)
+		}
+	}
+
+	return nil
+}
+
+// FilterFields returns the filterable fields for this model.
+func (*NodeDrift) FilterFields() []string {
+	return []string{
+		"node_id",
+	}
+}
+
+func (d *NodeDrift) String() string {
+	return fmt.Sprintf(strings.TrimSpace(`
+NodeDrift[
+    ID: %s
+    NodeID: %s
+    CheckedAt: %s
+    Items: %v
+]`),
+		d.ID,
+		d.NodeID,
+		d.CheckedAt.Format(time.RFC3339),
+		d.Items)
+}
+
+func (i *DriftItem) String() string {
+	return fmt.Sprintf(strings.TrimSpace(`
+DriftItem[
+    Kind: %s
+    ResourceID: %s
+    Expected: %s
+    Actual: %s
+    Error: %s
+]`),
+		i.Kind,
+		i.ResourceID,
+		i.Expected,
+		i.Actual,
+		i.Error)
+}
@@ -0,0 +1,119 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package cce_test
+
+import (
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	cce "github.com/open-ness/edgecontroller"
+)
+
+var _ = Describe("Entities: NodeDrift", func() {
+	var (
+		drift *cce.NodeDrift
+	)
+
+	BeforeEach(func() {
+		drift = &cce.NodeDrift{
+			ID:        "2f6e6e7b-6f4e-4b4a-8ad4-0b8e3a2f3e4d",
+			NodeID:    "48606c73-3905-47e0-864f-14bc7466f5bb",
+			CheckedAt: time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+			Items: []*cce.DriftItem{
+				{
+					Kind:       cce.DriftKindApp,
+					ResourceID: "efcece3c-6b58-4993-8d45-bde6239d4baa",
+					Expected:   "deployed",
+					Actual:     "missing",
+					Error:      "boom",
+				},
+			},
+		}
+	})
+
+	Describe("GetTableName", func() {
+		It(`Should return "node_drifts"`, func() {
+			Expect(drift.GetTableName()).To(Equal("node_drifts"))
+		})
+	})
+
+	Describe("GetID", func() {
+		It("Should return the ID", func() {
+			Expect(drift.GetID()).To(Equal(
+				"2f6e6e7b-6f4e-4b4a-8ad4-0b8e3a2f3e4d"))
+		})
+	})
+
+	Describe("SetID", func() {
+		It("Should set and return the updated ID", func() {
+			By("Setting the ID")
+			drift.SetID("456")
+
+			By("Getting the updated ID")
+			Expect(drift.ID).To(Equal("456"))
+		})
+	})
+
+	Describe("GetNodeID", func() {
+		It("Should return the node ID", func() {
+			Expect(drift.GetNodeID()).To(Equal(
+				"48606c73-3905-47e0-864f-14bc7466f5bb"))
+		})
+	})
+
+	Describe("Validate", func() {
+		It("Should not return an error for valid drift", func() {
+			Expect(drift.Validate()).To(Succeed())
+		})
+
+		It("Should not return an error without items", func() {
+			drift.Items = nil
+			Expect(drift.Validate()).To(Succeed())
+		})
+
+		It("Should return an error if ID is not a UUID", func() {
+			drift.ID = "123"
+			Expect(drift.Validate()).To(MatchError("id not a valid uuid"))
+		})
+
+		It("Should return an error if NodeID is not a UUID", func() {
+			drift.NodeID = "123"
+			Expect(drift.Validate()).To(MatchError("node_id not a valid uuid"))
+		})
+
+		It("Should return an error if an item kind is invalid", func() {
+			drift.Items[0].Kind = "weather"
+			Expect(drift.Validate()).To(MatchError(`items[0].kind "weather" is invalid`))
+		})
+	})
+
+	Describe("FilterFields", func() {
+		It("Should return the filterable fields", func() {
+			Expect(drift.FilterFields()).To(Equal([]string{
+				"node_id",
+			}))
+		})
+	})
+
+	Describe("String", func() {
+		It("Should return the string value", func() {
+			Expect(drift.String()).To(Equal(strings.TrimSpace(`
+NodeDrift[
+    ID: 2f6e6e7b-6f4e-4b4a-8ad4-0b8e3a2f3e4d
+    NodeID: 48606c73-3905-47e0-864f-14bc7466f5bb
+    CheckedAt: 2020-01-02T03:04:05Z
+    Items: [DriftItem[
+    Kind: app
+    ResourceID: efcece3c-6b58-4993-8d45-bde6239d4baa
+    Expected: deployed
+    Actual: missing
+    Error: boom
+]]
+]`,
+			)))
+		})
+	})
+})
@@ -39,6 +39,7 @@ func (t *NodeGRPCTarget) GetNodeID() string {
 func (t *NodeGRPCTarget) FilterFields() []string {
 	return []string{
 		"node_id",
+		"grpc_target",
 	}
 }
 
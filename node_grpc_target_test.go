@@ -58,6 +58,7 @@ var _ = Describe("Entities: NodeGRPCTarget", func() {
 		It("Should return the filterable fields", func() {
 			Expect(target.FilterFields()).To(Equal([]string{
 				"node_id",
+				"grpc_target",
 			}))
 		})
 	})
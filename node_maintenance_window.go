@@ -0,0 +1,108 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package cce
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/open-ness/edgecontroller/uuid"
+)
+
+// NodeMaintenanceWindow is a recurring period in which disruptive operations
+// may be executed on a node. The window opens at the times of a cron
+// expression and stays open for Duration minutes. Disruptive operations
+// requested while all windows of a node are closed are deferred until one
+// opens; a node without windows is always open.
+type NodeMaintenanceWindow struct {
+	ID       string `json:"id"`
+	NodeID   string `json:"node_id"`
+	Cron     string `json:"cron"`
+	TimeZone string `json:"time_zone,omitempty"`
+	Duration int    `json:"duration"` // in minutes
+}
+
+// GetTableName returns the name of the persistence table.
+func (*NodeMaintenanceWindow) GetTableName() string {
+	return "nodes_maintenance_windows"
+}
+
+// GetID gets the ID.
+func (w *NodeMaintenanceWindow) GetID() string {
+	return w.ID
+}
+
+// SetID sets the ID.
+func (w *NodeMaintenanceWindow) SetID(id string) {
+	w.ID = id
+}
+
+// GetNodeID gets the node ID.
+func (w *NodeMaintenanceWindow) GetNodeID() string {
+	return w.NodeID
+}
+
+// Validate validates the model.
+func (w *NodeMaintenanceWindow) Validate() error {
+	if !uuid.IsValid(w.ID) {
+		return errors.New("id not a valid uuid")
+	}
+	if !uuid.IsValid(w.NodeID) {
+		return errors.New("node_id not a valid uuid")
+	}
+	if _, err := ParseCron(w.Cron, w.TimeZone); err != nil {
+		return err
+	}
+	if w.Duration < 1 || w.Duration > MaxMaintenanceWindowMinutes {
+		return fmt.Errorf("duration must be in range [1..%d]", MaxMaintenanceWindowMinutes)
+	}
+
+	return nil
+}
+
+// OpenAt returns true if the window is open at t, i.e. if it opened less than
+// Duration minutes before t. A window with an invalid cron expression is
+// never open.
+func (w *NodeMaintenanceWindow) OpenAt(t time.Time) bool {
+	cron, err := ParseCron(w.Cron, w.TimeZone)
+	if err != nil {
+		return false
+	}
+	opened := cron.Next(t.Add(-time.Duration(w.Duration) * time.Minute))
+	return !opened.IsZero() && !opened.After(t)
+}
+
+// NextOpen returns the time the window opens next after t.
+func (w *NodeMaintenanceWindow) NextOpen(t time.Time) time.Time {
+	cron, err := ParseCron(w.Cron, w.TimeZone)
+	if err != nil {
+		return time.Time{}
+	}
+	return cron.Next(t)
+}
+
+// FilterFields returns the filterable fields for this model.
+func (*NodeMaintenanceWindow) FilterFields() []string {
+	return []string{
+		"node_id",
+	}
+}
+
+func (w *NodeMaintenanceWindow) String() string {
+	return fmt.Sprintf(strings.TrimSpace(`
+NodeMaintenanceWindow[
+    ID: %s
+    NodeID: %s
+    Cron: %s
+    TimeZone: %s
+    Duration: %d
+]`),
+		w.ID,
+		w.NodeID,
+		w.Cron,
+		w.TimeZone,
+		w.Duration)
+}
@@ -0,0 +1,122 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package cce_test
+
+import (
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	cce "github.com/open-ness/edgecontroller"
+)
+
+var _ = Describe("Entities: NodeMaintenanceWindow", func() {
+	var (
+		window *cce.NodeMaintenanceWindow
+	)
+
+	BeforeEach(func() {
+		window = &cce.NodeMaintenanceWindow{
+			ID:       "9b8a7c6d-5e4f-4a3b-8c2d-1e0f9a8b7c6d",
+			NodeID:   "a7c5fa8a-5b0b-4d1e-9d0a-6f5a2d8f3c1b",
+			Cron:     "0 2 * * 6",
+			TimeZone: "UTC",
+			Duration: 120,
+		}
+	})
+
+	Describe("GetTableName", func() {
+		It(`Should return "nodes_maintenance_windows"`, func() {
+			Expect(window.GetTableName()).To(Equal("nodes_maintenance_windows"))
+		})
+	})
+
+	Describe("GetID", func() {
+		It("Should return the ID", func() {
+			Expect(window.GetID()).To(Equal("9b8a7c6d-5e4f-4a3b-8c2d-1e0f9a8b7c6d"))
+		})
+	})
+
+	Describe("SetID", func() {
+		It("Should set and return the updated ID", func() {
+			By("Setting the ID")
+			window.SetID("456")
+
+			By("Getting the updated ID")
+			Expect(window.ID).To(Equal("456"))
+		})
+	})
+
+	Describe("GetNodeID", func() {
+		It("Should return the node ID", func() {
+			Expect(window.GetNodeID()).To(Equal("a7c5fa8a-5b0b-4d1e-9d0a-6f5a2d8f3c1b"))
+		})
+	})
+
+	Describe("Validate", func() {
+		It("Should not return an error for a valid window", func() {
+			Expect(window.Validate()).To(Succeed())
+		})
+
+		It("Should return an error if ID is not a UUID", func() {
+			window.ID = "123"
+			Expect(window.Validate()).To(MatchError("id not a valid uuid"))
+		})
+
+		It("Should return an error if NodeID is not a UUID", func() {
+			window.NodeID = "123"
+			Expect(window.Validate()).To(MatchError("node_id not a valid uuid"))
+		})
+
+		It("Should return an error if Cron is invalid", func() {
+			window.Cron = "0 25 * * 6"
+			Expect(window.Validate()).To(MatchError(`cron expression "0 25 * * 6": hour "25" must be within 0-23`))
+		})
+
+		It("Should return an error if Duration is out of range", func() {
+			window.Duration = 0
+			Expect(window.Validate()).To(MatchError("duration must be in range [1..10080]"))
+		})
+	})
+
+	Describe("OpenAt", func() {
+		It("Should be open for the duration after the window opens", func() {
+			// Saturday
+			Expect(window.OpenAt(time.Date(2020, time.March, 7, 1, 59, 0, 0, time.UTC))).To(BeFalse())
+			Expect(window.OpenAt(time.Date(2020, time.March, 7, 2, 0, 0, 0, time.UTC))).To(BeTrue())
+			Expect(window.OpenAt(time.Date(2020, time.March, 7, 3, 59, 59, 0, time.UTC))).To(BeTrue())
+			Expect(window.OpenAt(time.Date(2020, time.March, 7, 4, 0, 0, 0, time.UTC))).To(BeFalse())
+		})
+	})
+
+	Describe("NextOpen", func() {
+		It("Should return the time the window opens next", func() {
+			Expect(window.NextOpen(time.Date(2020, time.March, 7, 3, 0, 0, 0, time.UTC))).To(
+				Equal(time.Date(2020, time.March, 14, 2, 0, 0, 0, time.UTC)))
+		})
+	})
+
+	Describe("FilterFields", func() {
+		It("Should return the filterable fields", func() {
+			Expect(window.FilterFields()).To(Equal([]string{
+				"node_id",
+			}))
+		})
+	})
+
+	Describe("String", func() {
+		It("Should return the string value", func() {
+			Expect(window.String()).To(Equal(strings.TrimSpace(`
+NodeMaintenanceWindow[
+    ID: 9b8a7c6d-5e4f-4a3b-8c2d-1e0f9a8b7c6d
+    NodeID: a7c5fa8a-5b0b-4d1e-9d0a-6f5a2d8f3c1b
+    Cron: 0 2 * * 6
+    TimeZone: UTC
+    Duration: 120
+]`,
+			)))
+		})
+	})
+})
@@ -0,0 +1,92 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package cce
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/open-ness/edgecontroller/uuid"
+)
+
+var pciRegexp = regexp.MustCompile(`^[0-9a-fA-F]{4}:[0-9a-fA-F]{2}:[0-9a-fA-F]{2}\.[0-7]$`)
+
+// NodePort records that a network port of a node, identified by its PCI
+// address, should be attached to an OVS bridge with the given driver.
+type NodePort struct {
+	ID     string `json:"id"`
+	NodeID string `json:"node_id"`
+	PCI    string `json:"pci"`
+	Bridge string `json:"bridge"`
+	Driver string `json:"driver"`
+}
+
+// GetTableName returns the name of the persistence table.
+func (*NodePort) GetTableName() string {
+	return "nodes_ports"
+}
+
+// GetID gets the ID.
+func (p *NodePort) GetID() string {
+	return p.ID
+}
+
+// SetID sets the ID.
+func (p *NodePort) SetID(id string) {
+	p.ID = id
+}
+
+// GetNodeID gets the node ID.
+func (p *NodePort) GetNodeID() string {
+	return p.NodeID
+}
+
+// Validate validates the model.
+func (p *NodePort) Validate() error {
+	if !uuid.IsValid(p.ID) {
+		return errors.New("id not a valid uuid")
+	}
+	if !uuid.IsValid(p.NodeID) {
+		return errors.New("node_id not a valid uuid")
+	}
+	if !pciRegexp.MatchString(p.PCI) {
+		return fmt.Errorf("pci %q is not a valid PCI address", p.PCI)
+	}
+	if p.Bridge == "" {
+		return errors.New("bridge cannot be empty")
+	}
+	switch p.Driver {
+	case "kernel", "userspace":
+	default:
+		return errors.New(`driver must be either "kernel" or "userspace"`)
+	}
+
+	return nil
+}
+
+// FilterFields returns the filterable fields for this model.
+func (*NodePort) FilterFields() []string {
+	return []string{
+		"node_id",
+		"pci",
+	}
+}
+
+func (p *NodePort) String() string {
+	return fmt.Sprintf(strings.TrimSpace(`
+NodePort[
+    ID: %s
+    NodeID: %s
+    PCI: %s
+    Bridge: %s
+    Driver: %s
+]`),
+		p.ID,
+		p.NodeID,
+		p.PCI,
+		p.Bridge,
+		p.Driver)
+}
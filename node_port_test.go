@@ -0,0 +1,117 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package cce_test
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	cce "github.com/open-ness/edgecontroller"
+)
+
+var _ = Describe("Entities: NodePort", func() {
+	var (
+		port *cce.NodePort
+	)
+
+	BeforeEach(func() {
+		port = &cce.NodePort{
+			ID:     "0b4e9a4c-5d7e-4c6f-8a1b-2c3d4e5f6a7b",
+			NodeID: "48606c73-3905-47e0-864f-14bc7466f5bb",
+			PCI:    "0000:86:00.0",
+			Bridge: "br-local",
+			Driver: "kernel",
+		}
+	})
+
+	Describe("GetTableName", func() {
+		It(`Should return "nodes_ports"`, func() {
+			Expect(port.GetTableName()).To(Equal("nodes_ports"))
+		})
+	})
+
+	Describe("GetID", func() {
+		It("Should return the ID", func() {
+			Expect(port.GetID()).To(Equal(
+				"0b4e9a4c-5d7e-4c6f-8a1b-2c3d4e5f6a7b"))
+		})
+	})
+
+	Describe("SetID", func() {
+		It("Should set and return the updated ID", func() {
+			By("Setting the ID")
+			port.SetID("456")
+
+			By("Getting the updated ID")
+			Expect(port.ID).To(Equal("456"))
+		})
+	})
+
+	Describe("GetNodeID", func() {
+		It("Should return the node ID", func() {
+			Expect(port.GetNodeID()).To(Equal(
+				"48606c73-3905-47e0-864f-14bc7466f5bb"))
+		})
+	})
+
+	Describe("Validate", func() {
+		It("Should not return an error for a valid port", func() {
+			Expect(port.Validate()).To(Succeed())
+		})
+
+		It("Should not return an error for a userspace port", func() {
+			port.Driver = "userspace"
+			Expect(port.Validate()).To(Succeed())
+		})
+
+		It("Should return an error if ID is not a UUID", func() {
+			port.ID = "123"
+			Expect(port.Validate()).To(MatchError("id not a valid uuid"))
+		})
+
+		It("Should return an error if NodeID is not a UUID", func() {
+			port.NodeID = "123"
+			Expect(port.Validate()).To(MatchError("node_id not a valid uuid"))
+		})
+
+		It("Should return an error if PCI is not a PCI address", func() {
+			port.PCI = "eth0"
+			Expect(port.Validate()).To(MatchError(`pci "eth0" is not a valid PCI address`))
+		})
+
+		It("Should return an error if Bridge is empty", func() {
+			port.Bridge = ""
+			Expect(port.Validate()).To(MatchError("bridge cannot be empty"))
+		})
+
+		It("Should return an error if Driver is invalid", func() {
+			port.Driver = "dpdk"
+			Expect(port.Validate()).To(MatchError(`driver must be either "kernel" or "userspace"`))
+		})
+	})
+
+	Describe("FilterFields", func() {
+		It("Should return the filterable fields", func() {
+			Expect(port.FilterFields()).To(Equal([]string{
+				"node_id",
+				"pci",
+			}))
+		})
+	})
+
+	Describe("String", func() {
+		It("Should return the string value", func() {
+			Expect(port.String()).To(Equal(strings.TrimSpace(`
+NodePort[
+    ID: 0b4e9a4c-5d7e-4c6f-8a1b-2c3d4e5f6a7b
+    NodeID: 48606c73-3905-47e0-864f-14bc7466f5bb
+    PCI: 0000:86:00.0
+    Bridge: br-local
+    Driver: kernel
+]`,
+			)))
+		})
+	})
+})
@@ -0,0 +1,84 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package cce
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/open-ness/edgecontroller/uuid"
+)
+
+// NodeZone is a network zone defined on a node. Network interfaces of the
+// node can only be assigned to zones that are defined.
+type NodeZone struct {
+	ID          string `json:"id"`
+	NodeID      string `json:"node_id"`
+	ZoneID      string `json:"zone_id"`
+	Description string `json:"description"`
+}
+
+// GetTableName returns the name of the persistence table.
+func (*NodeZone) GetTableName() string {
+	return "nodes_zones"
+}
+
+// GetID gets the ID.
+func (z *NodeZone) GetID() string {
+	return z.ID
+}
+
+// SetID sets the ID.
+func (z *NodeZone) SetID(id string) {
+	z.ID = id
+}
+
+// GetNodeID gets the node ID.
+func (z *NodeZone) GetNodeID() string {
+	return z.NodeID
+}
+
+// Validate validates the model.
+func (z *NodeZone) Validate() error {
+	if !uuid.IsValid(z.ID) {
+		return errors.New("id not a valid uuid")
+	}
+	if !uuid.IsValid(z.NodeID) {
+		return errors.New("node_id not a valid uuid")
+	}
+	if z.ZoneID == "" {
+		return errors.New("zone_id cannot be empty")
+	}
+	if len(z.ZoneID) > 36 {
+		return errors.New("zone_id cannot be longer than 36 characters")
+	}
+	if strings.ContainsAny(z.ZoneID, "/ \t\n") {
+		return errors.New("zone_id cannot contain slashes or whitespace")
+	}
+
+	return nil
+}
+
+// FilterFields returns the filterable fields for this model.
+func (*NodeZone) FilterFields() []string {
+	return []string{
+		"node_id",
+		"zone_id",
+	}
+}
+
+func (z *NodeZone) String() string {
+	return fmt.Sprintf(strings.TrimSpace(`
+NodeZone[
+    ID: %s
+    NodeID: %s
+    ZoneID: %s
+    Description: %s
+]`),
+		z.ID,
+		z.NodeID,
+		z.ZoneID,
+		z.Description)
+}
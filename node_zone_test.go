@@ -0,0 +1,110 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package cce_test
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	cce "github.com/open-ness/edgecontroller"
+)
+
+var _ = Describe("Entities: NodeZone", func() {
+	var (
+		zone *cce.NodeZone
+	)
+
+	BeforeEach(func() {
+		zone = &cce.NodeZone{
+			ID:          "c1b3f4b2-7a0e-4b9e-9d2b-5a3c2f4e8d11",
+			NodeID:      "48606c73-3905-47e0-864f-14bc7466f5bb",
+			ZoneID:      "edge",
+			Description: "edge traffic",
+		}
+	})
+
+	Describe("GetTableName", func() {
+		It(`Should return "nodes_zones"`, func() {
+			Expect(zone.GetTableName()).To(Equal("nodes_zones"))
+		})
+	})
+
+	Describe("GetID", func() {
+		It("Should return the ID", func() {
+			Expect(zone.GetID()).To(Equal(
+				"c1b3f4b2-7a0e-4b9e-9d2b-5a3c2f4e8d11"))
+		})
+	})
+
+	Describe("SetID", func() {
+		It("Should set and return the updated ID", func() {
+			By("Setting the ID")
+			zone.SetID("456")
+
+			By("Getting the updated ID")
+			Expect(zone.ID).To(Equal("456"))
+		})
+	})
+
+	Describe("GetNodeID", func() {
+		It("Should return the node ID", func() {
+			Expect(zone.GetNodeID()).To(Equal(
+				"48606c73-3905-47e0-864f-14bc7466f5bb"))
+		})
+	})
+
+	Describe("Validate", func() {
+		It("Should not return an error for a valid zone", func() {
+			Expect(zone.Validate()).To(Succeed())
+		})
+
+		It("Should return an error if ID is not a UUID", func() {
+			zone.ID = "123"
+			Expect(zone.Validate()).To(MatchError("id not a valid uuid"))
+		})
+
+		It("Should return an error if NodeID is not a UUID", func() {
+			zone.NodeID = "123"
+			Expect(zone.Validate()).To(MatchError("node_id not a valid uuid"))
+		})
+
+		It("Should return an error if ZoneID is empty", func() {
+			zone.ZoneID = ""
+			Expect(zone.Validate()).To(MatchError("zone_id cannot be empty"))
+		})
+
+		It("Should return an error if ZoneID is too long", func() {
+			zone.ZoneID = strings.Repeat("z", 37)
+			Expect(zone.Validate()).To(MatchError("zone_id cannot be longer than 36 characters"))
+		})
+
+		It("Should return an error if ZoneID contains a slash", func() {
+			zone.ZoneID = "edge/core"
+			Expect(zone.Validate()).To(MatchError("zone_id cannot contain slashes or whitespace"))
+		})
+	})
+
+	Describe("FilterFields", func() {
+		It("Should return the filterable fields", func() {
+			Expect(zone.FilterFields()).To(Equal([]string{
+				"node_id",
+				"zone_id",
+			}))
+		})
+	})
+
+	Describe("String", func() {
+		It("Should return the string value", func() {
+			Expect(zone.String()).To(Equal(strings.TrimSpace(`
+NodeZone[
+    ID: c1b3f4b2-7a0e-4b9e-9d2b-5a3c2f4e8d11
+    NodeID: 48606c73-3905-47e0-864f-14bc7466f5bb
+    ZoneID: edge
+    Description: edge traffic
+]`,
+			)))
+		})
+	})
+})
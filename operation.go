@@ -0,0 +1,226 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package cce
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/open-ness/edgecontroller/uuid"
+)
+
+const (
+	// OperationTypeDeploy deploys an app to a node
+	OperationTypeDeploy = "deploy"
+	// OperationTypeUndeploy removes an app from a node
+	OperationTypeUndeploy = "undeploy"
+	// OperationTypeStart starts an app on a node
+	OperationTypeStart = "start"
+	// OperationTypeStop stops an app on a node
+	OperationTypeStop = "stop"
+	// OperationTypeRestart restarts an app on a node
+	OperationTypeRestart = "restart"
+	// OperationTypeSetAppPolicy sets the traffic policy of an app on a node
+	OperationTypeSetAppPolicy = "set_app_policy"
+	// OperationTypeDeleteAppPolicy deletes the traffic policy of an app on a
+	// node
+	OperationTypeDeleteAppPolicy = "delete_app_policy"
+	// OperationTypeSetInterfacePolicy sets the traffic policy of a node's
+	// network interface
+	OperationTypeSetInterfacePolicy = "set_interface_policy"
+	// OperationTypeDeleteInterfacePolicy deletes the traffic policy of a
+	// node's network interface
+	OperationTypeDeleteInterfacePolicy = "delete_interface_policy"
+	// OperationTypeSetDNS replaces the DNS configuration of a node
+	OperationTypeSetDNS = "set_dns"
+	// OperationTypeDeleteDNS deletes the DNS configuration of a node
+	OperationTypeDeleteDNS = "delete_dns"
+	// OperationTypeDecommission removes a node and everything deployed to it
+	OperationTypeDecommission = "decommission"
+	// OperationTypeUpgrade replaces the version of an app running on a node
+	OperationTypeUpgrade = "upgrade"
+	// OperationTypeDeployBundle deploys and starts the apps of a bundle on a
+	// node in dependency order
+	OperationTypeDeployBundle = "deploy_bundle"
+	// OperationTypeUndeployBundle stops and removes the apps of a bundle from
+	// a node in reverse dependency order
+	OperationTypeUndeployBundle = "undeploy_bundle"
+	// OperationTypeStartBundle starts the apps of a bundle on a node in
+	// dependency order
+	OperationTypeStartBundle = "start_bundle"
+	// OperationTypeStopBundle stops the apps of a bundle on a node in reverse
+	// dependency order
+	OperationTypeStopBundle = "stop_bundle"
+	// OperationTypeRestartBundle stops and then starts the apps of a bundle
+	// on a node
+	OperationTypeRestartBundle = "restart_bundle"
+)
+
+const (
+	// OperationStatePending is waiting for a worker
+	OperationStatePending = "pending"
+	// OperationStateRunning is being executed by a worker
+	OperationStateRunning = "running"
+	// OperationStateSucceeded has completed successfully
+	OperationStateSucceeded = "succeeded"
+	// OperationStateFailed has exhausted its attempts
+	OperationStateFailed = "failed"
+	// OperationStateQueued is waiting for an offline node to reconnect
+	OperationStateQueued = "queued"
+	// OperationStateCanceled was canceled while it was queued or deferred
+	OperationStateCanceled = "canceled"
+	// OperationStateDeferred is waiting for a maintenance window of the node
+	OperationStateDeferred = "deferred"
+)
+
+// Operation is a long-running call against a node that is executed
+// asynchronously by the controller's worker pool.
+type Operation struct {
+	ID          string                `json:"id"`
+	Type        string                `json:"type"`
+	NodeID      string                `json:"node_id"`
+	AppID       string                `json:"app_id,omitempty"`
+	State       string                `json:"state"`
+	Attempts    int                   `json:"attempts"`
+	Error       string                `json:"error,omitempty"`
+	Payload     json.RawMessage       `json:"payload,omitempty"`
+	Transitions []OperationTransition `json:"transitions,omitempty"`
+	CreatedAt   time.Time             `json:"created_at"`
+	UpdatedAt   time.Time             `json:"updated_at"`
+}
+
+// OperationTransition records an operation entering a new state.
+type OperationTransition struct {
+	State   string    `json:"state"`
+	Message string    `json:"message,omitempty"`
+	Time    time.Time `json:"time"`
+}
+
+// GetTableName returns the name of the persistence table.
+func (*Operation) GetTableName() string {
+	return "operations"
+}
+
+// GetID gets the ID.
+func (op *Operation) GetID() string {
+	return op.ID
+}
+
+// SetID sets the ID.
+func (op *Operation) SetID(id string) {
+	op.ID = id
+}
+
+// GetNodeID gets the node ID.
+func (op *Operation) GetNodeID() string {
+	return op.NodeID
+}
+
+// Validate validates the model.
+func (op *Operation) Validate() error {
+	if !uuid.IsValid(op.ID) {
+		return errors.New("id not a valid uuid")
+	}
+	switch op.Type {
+	case OperationTypeDeploy, OperationTypeUndeploy,
+		OperationTypeStart, OperationTypeStop, OperationTypeRestart,
+		OperationTypeSetAppPolicy, OperationTypeDeleteAppPolicy,
+		OperationTypeSetInterfacePolicy, OperationTypeDeleteInterfacePolicy,
+		OperationTypeSetDNS, OperationTypeDeleteDNS,
+		OperationTypeDecommission, OperationTypeUpgrade,
+		OperationTypeDeployBundle, OperationTypeUndeployBundle,
+		OperationTypeStartBundle, OperationTypeStopBundle, OperationTypeRestartBundle:
+	default:
+		return fmt.Errorf(`type "%s" is invalid`, op.Type)
+	}
+	if !uuid.IsValid(op.NodeID) {
+		return errors.New("node_id not a valid uuid")
+	}
+	if op.AppID != "" && !uuid.IsValid(op.AppID) {
+		return errors.New("app_id not a valid uuid")
+	}
+	switch op.State {
+	case OperationStatePending, OperationStateRunning,
+		OperationStateSucceeded, OperationStateFailed,
+		OperationStateQueued, OperationStateCanceled,
+		OperationStateDeferred:
+	default:
+		return fmt.Errorf(`state "%s" is invalid`, op.State)
+	}
+
+	return nil
+}
+
+// FilterFields returns the filterable fields for this model.
+func (*Operation) FilterFields() []string {
+	return []string{
+		"node_id",
+		"app_id",
+		"state",
+	}
+}
+
+// Transition moves the operation to a new state and records the change.
+func (op *Operation) Transition(state, message string) {
+	now := time.Now().UTC()
+	op.State = state
+	op.UpdatedAt = now
+	op.Transitions = append(op.Transitions, OperationTransition{
+		State:   state,
+		Message: message,
+		Time:    now,
+	})
+}
+
+// Disruptive returns true if the operation interrupts a running app, and so
+// is deferred to a maintenance window of the node.
+func (op *Operation) Disruptive() bool {
+	switch op.Type {
+	case OperationTypeUndeploy, OperationTypeStop, OperationTypeRestart,
+		OperationTypeUpgrade, OperationTypeDecommission,
+		OperationTypeUndeployBundle, OperationTypeStopBundle, OperationTypeRestartBundle:
+		return true
+	}
+	return false
+}
+
+// Progress returns the message of the most recent transition.
+func (op *Operation) Progress() string {
+	if len(op.Transitions) == 0 {
+		return ""
+	}
+	return op.Transitions[len(op.Transitions)-1].Message
+}
+
+// Done returns true if the operation reached a terminal state.
+func (op *Operation) Done() bool {
+	switch op.State {
+	case OperationStateSucceeded, OperationStateFailed, OperationStateCanceled:
+		return true
+	}
+	return false
+}
+
+func (op *Operation) String() string {
+	return fmt.Sprintf(strings.TrimSpace(`
+Operation[
+    ID: %s
+    Type: %s
+    NodeID: %s
+    AppID: %s
+    State: %s
+    Attempts: %d
+    Error: %s
+]`),
+		op.ID,
+		op.Type,
+		op.NodeID,
+		op.AppID,
+		op.State,
+		op.Attempts,
+		op.Error)
+}
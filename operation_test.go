@@ -0,0 +1,191 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package cce_test
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	cce "github.com/open-ness/edgecontroller"
+)
+
+var _ = Describe("Entities: Operation", func() {
+	var (
+		op *cce.Operation
+	)
+
+	BeforeEach(func() {
+		op = &cce.Operation{
+			ID:     "9d740ea1-6b5c-4d0b-87e4-a8e0a0d7b7a0",
+			Type:   cce.OperationTypeDeploy,
+			NodeID: "48606c73-3905-47e0-864f-14bc7466f5bb",
+			AppID:  "efcece3c-6b58-4993-8d45-bde6239d4baa",
+			State:  cce.OperationStatePending,
+		}
+	})
+
+	Describe("GetTableName", func() {
+		It(`Should return "operations"`, func() {
+			Expect(op.GetTableName()).To(Equal("operations"))
+		})
+	})
+
+	Describe("GetID", func() {
+		It("Should return the ID", func() {
+			Expect(op.GetID()).To(Equal(
+				"9d740ea1-6b5c-4d0b-87e4-a8e0a0d7b7a0"))
+		})
+	})
+
+	Describe("SetID", func() {
+		It("Should set and return the updated ID", func() {
+			By("Setting the ID")
+			op.SetID("456")
+
+			By("Getting the updated ID")
+			Expect(op.ID).To(Equal("456"))
+		})
+	})
+
+	Describe("GetNodeID", func() {
+		It("Should return the node ID", func() {
+			Expect(op.GetNodeID()).To(Equal(
+				"48606c73-3905-47e0-864f-14bc7466f5bb"))
+		})
+	})
+
+	Describe("Validate", func() {
+		It("Should not return an error for a valid operation", func() {
+			Expect(op.Validate()).To(Succeed())
+		})
+
+		It("Should not return an error if AppID is empty", func() {
+			op.AppID = ""
+			Expect(op.Validate()).To(Succeed())
+		})
+
+		It("Should return an error if ID is not a UUID", func() {
+			op.ID = "123"
+			Expect(op.Validate()).To(MatchError("id not a valid uuid"))
+		})
+
+		It("Should return an error if Type is invalid", func() {
+			op.Type = "explode"
+			Expect(op.Validate()).To(MatchError(`type "explode" is invalid`))
+		})
+
+		It("Should return an error if NodeID is not a UUID", func() {
+			op.NodeID = "123"
+			Expect(op.Validate()).To(MatchError("node_id not a valid uuid"))
+		})
+
+		It("Should return an error if AppID is not a UUID", func() {
+			op.AppID = "123"
+			Expect(op.Validate()).To(MatchError("app_id not a valid uuid"))
+		})
+
+		It("Should not return an error for a queued policy operation", func() {
+			op.Type = cce.OperationTypeSetAppPolicy
+			op.State = cce.OperationStateQueued
+			op.Payload = []byte(`{"id":"b1f8bc8e-2d43-4d5d-9d1b-0d9f1e0f8f1c"}`)
+			Expect(op.Validate()).To(Succeed())
+		})
+
+		It("Should not return an error for a decommission operation", func() {
+			op.Type = cce.OperationTypeDecommission
+			op.AppID = ""
+			Expect(op.Validate()).To(Succeed())
+		})
+
+		It("Should not return an error for a deferred operation", func() {
+			op.Type = cce.OperationTypeRestart
+			op.State = cce.OperationStateDeferred
+			Expect(op.Validate()).To(Succeed())
+		})
+
+		It("Should return an error if State is invalid", func() {
+			op.State = "sleeping"
+			Expect(op.Validate()).To(MatchError(`state "sleeping" is invalid`))
+		})
+	})
+
+	Describe("FilterFields", func() {
+		It("Should return the filterable fields", func() {
+			Expect(op.FilterFields()).To(Equal([]string{
+				"node_id",
+				"app_id",
+				"state",
+			}))
+		})
+	})
+
+	Describe("Transition", func() {
+		It("Should update the state and record the transition", func() {
+			op.Transition(cce.OperationStateRunning, "attempt 1 of 3")
+			op.Transition(cce.OperationStateSucceeded, "operation completed")
+
+			Expect(op.State).To(Equal(cce.OperationStateSucceeded))
+			Expect(op.Transitions).To(HaveLen(2))
+			Expect(op.Transitions[0].State).To(Equal(cce.OperationStateRunning))
+			Expect(op.UpdatedAt).To(Equal(op.Transitions[1].Time))
+			Expect(op.Progress()).To(Equal("operation completed"))
+			Expect(op.Done()).To(BeTrue())
+		})
+
+		It("Should report a canceled operation as done", func() {
+			op.Transition(cce.OperationStateQueued, "node is unreachable")
+			Expect(op.Done()).To(BeFalse())
+
+			op.Transition(cce.OperationStateCanceled, "canceled")
+			Expect(op.Done()).To(BeTrue())
+		})
+
+		It("Should report an empty progress without transitions", func() {
+			Expect(op.Progress()).To(BeEmpty())
+			Expect(op.Done()).To(BeFalse())
+		})
+	})
+
+	Describe("Disruptive", func() {
+		It("Should report operations interrupting an app as disruptive", func() {
+			for _, opType := range []string{
+				cce.OperationTypeUndeploy, cce.OperationTypeStop, cce.OperationTypeRestart,
+				cce.OperationTypeUpgrade, cce.OperationTypeDecommission,
+				cce.OperationTypeUndeployBundle, cce.OperationTypeStopBundle, cce.OperationTypeRestartBundle,
+			} {
+				op.Type = opType
+				Expect(op.Disruptive()).To(BeTrue(), opType)
+			}
+		})
+
+		It("Should not report other operations as disruptive", func() {
+			for _, opType := range []string{
+				cce.OperationTypeDeploy, cce.OperationTypeStart, cce.OperationTypeSetAppPolicy,
+				cce.OperationTypeSetDNS, cce.OperationTypeDeployBundle, cce.OperationTypeStartBundle,
+			} {
+				op.Type = opType
+				Expect(op.Disruptive()).To(BeFalse(), opType)
+			}
+		})
+	})
+
+	Describe("String", func() {
+		It("Should return the string value", func() {
+			op.Attempts = 2
+			op.Error = "boom"
+			Expect(op.String()).To(Equal(strings.TrimSpace(`
+Operation[
+    ID: 9d740ea1-6b5c-4d0b-87e4-a8e0a0d7b7a0
+    Type: deploy
+    NodeID: 48606c73-3905-47e0-864f-14bc7466f5bb
+    AppID: efcece3c-6b58-4993-8d45-bde6239d4baa
+    State: pending
+    Attempts: 2
+    Error: boom
+]`,
+			)))
+		})
+	})
+})
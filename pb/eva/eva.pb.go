@@ -64,6 +64,7 @@ const (
 	LifecycleStatus_STOPPING  LifecycleStatus_Status = 5
 	LifecycleStatus_STOPPED   LifecycleStatus_Status = 6
 	LifecycleStatus_ERROR     LifecycleStatus_Status = 7
+	LifecycleStatus_UNHEALTHY LifecycleStatus_Status = 8
 )
 
 var LifecycleStatus_Status_name = map[int32]string{
@@ -75,6 +76,7 @@ var LifecycleStatus_Status_name = map[int32]string{
 	5: "STOPPING",
 	6: "STOPPED",
 	7: "ERROR",
+	8: "UNHEALTHY",
 }
 
 var LifecycleStatus_Status_value = map[string]int32{
@@ -86,6 +88,7 @@ var LifecycleStatus_Status_value = map[string]int32{
 	"STOPPING":  5,
 	"STOPPED":   6,
 	"ERROR":     7,
+	"UNHEALTHY": 8,
 }
 
 func (x LifecycleStatus_Status) String() string {
@@ -139,10 +142,27 @@ type Application struct {
 	// an array of string key-value pairs. Specific keys are defined by their respective features.
 	EACJsonBlob string `protobuf:"bytes,11,opt,name=EACJsonBlob,proto3" json:"EACJsonBlob,omitempty"`
 	// CNI configuration for the application
-	CniConf              *CNIConfiguration `protobuf:"bytes,12,opt,name=cniConf,proto3" json:"cniConf,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
-	XXX_unrecognized     []byte            `json:"-"`
-	XXX_sizecache        int32             `json:"-"`
+	CniConf *CNIConfiguration `protobuf:"bytes,12,opt,name=cniConf,proto3" json:"cniConf,omitempty"`
+	// Environment variables set in the application's container or VM
+	Env []*EnvVar `protobuf:"bytes,13,rep,name=env,proto3" json:"env,omitempty"`
+	// Entrypoint and its arguments, overriding the ones of the image
+	Command []string `protobuf:"bytes,14,rep,name=command,proto3" json:"command,omitempty"`
+	Args    []string `protobuf:"bytes,15,rep,name=args,proto3" json:"args,omitempty"`
+	// Host directories mounted in the application's container or VM
+	Volumes []*VolumeMount `protobuf:"bytes,16,rep,name=volumes,proto3" json:"volumes,omitempty"`
+	// Files written in the application's container or VM before it starts
+	ConfigFiles []*ConfigFile `protobuf:"bytes,17,rep,name=configFiles,proto3" json:"configFiles,omitempty"`
+	// Probes of the application's health
+	LivenessProbe  *Probe `protobuf:"bytes,18,opt,name=livenessProbe,proto3" json:"livenessProbe,omitempty"`
+	ReadinessProbe *Probe `protobuf:"bytes,19,opt,name=readinessProbe,proto3" json:"readinessProbe,omitempty"`
+	// When the application is restarted: always, on-failure or never
+	RestartPolicy string `protobuf:"bytes,20,opt,name=restartPolicy,proto3" json:"restartPolicy,omitempty"`
+	// Expected SHA-256 digest of the image, in hex. The image is refused
+	// when its digest does not match.
+	Digest               string   `protobuf:"bytes,21,opt,name=digest,proto3" json:"digest,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *Application) Reset()         { *m = Application{} }
@@ -271,6 +291,69 @@ func (m *Application) GetCniConf() *CNIConfiguration {
 	return nil
 }
 
+func (m *Application) GetEnv() []*EnvVar {
+	if m != nil {
+		return m.Env
+	}
+	return nil
+}
+
+func (m *Application) GetCommand() []string {
+	if m != nil {
+		return m.Command
+	}
+	return nil
+}
+
+func (m *Application) GetArgs() []string {
+	if m != nil {
+		return m.Args
+	}
+	return nil
+}
+
+func (m *Application) GetVolumes() []*VolumeMount {
+	if m != nil {
+		return m.Volumes
+	}
+	return nil
+}
+
+func (m *Application) GetConfigFiles() []*ConfigFile {
+	if m != nil {
+		return m.ConfigFiles
+	}
+	return nil
+}
+
+func (m *Application) GetLivenessProbe() *Probe {
+	if m != nil {
+		return m.LivenessProbe
+	}
+	return nil
+}
+
+func (m *Application) GetReadinessProbe() *Probe {
+	if m != nil {
+		return m.ReadinessProbe
+	}
+	return nil
+}
+
+func (m *Application) GetRestartPolicy() string {
+	if m != nil {
+		return m.RestartPolicy
+	}
+	return ""
+}
+
+func (m *Application) GetDigest() string {
+	if m != nil {
+		return m.Digest
+	}
+	return ""
+}
+
 // XXX_OneofWrappers is for the internal use of the proto package.
 func (*Application) XXX_OneofWrappers() []interface{} {
 	return []interface{}{
@@ -677,6 +760,370 @@ func (m *ContainerInfo) GetId() string {
 	return ""
 }
 
+// EnvVar is an environment variable of an application
+type EnvVar struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Value                string   `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *EnvVar) Reset()         { *m = EnvVar{} }
+func (m *EnvVar) String() string { return proto.CompactTextString(m) }
+func (*EnvVar) ProtoMessage()    {}
+func (*EnvVar) Descriptor() ([]byte, []int) {
+	return fileDescriptor_78739cf76c9af146, []int{9}
+}
+
+func (m *EnvVar) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EnvVar.Unmarshal(m, b)
+}
+func (m *EnvVar) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EnvVar.Marshal(b, m, deterministic)
+}
+func (m *EnvVar) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EnvVar.Merge(m, src)
+}
+func (m *EnvVar) XXX_Size() int {
+	return xxx_messageInfo_EnvVar.Size(m)
+}
+func (m *EnvVar) XXX_DiscardUnknown() {
+	xxx_messageInfo_EnvVar.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EnvVar proto.InternalMessageInfo
+
+func (m *EnvVar) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *EnvVar) GetValue() string {
+	if m != nil {
+		return m.Value
+	}
+	return ""
+}
+
+// VolumeMount mounts a host directory in an application
+type VolumeMount struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	MountPath            string   `protobuf:"bytes,2,opt,name=mountPath,proto3" json:"mountPath,omitempty"`
+	HostPath             string   `protobuf:"bytes,3,opt,name=hostPath,proto3" json:"hostPath,omitempty"`
+	ReadOnly             bool     `protobuf:"varint,4,opt,name=readOnly,proto3" json:"readOnly,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *VolumeMount) Reset()         { *m = VolumeMount{} }
+func (m *VolumeMount) String() string { return proto.CompactTextString(m) }
+func (*VolumeMount) ProtoMessage()    {}
+func (*VolumeMount) Descriptor() ([]byte, []int) {
+	return fileDescriptor_78739cf76c9af146, []int{10}
+}
+
+func (m *VolumeMount) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_VolumeMount.Unmarshal(m, b)
+}
+func (m *VolumeMount) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_VolumeMount.Marshal(b, m, deterministic)
+}
+func (m *VolumeMount) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VolumeMount.Merge(m, src)
+}
+func (m *VolumeMount) XXX_Size() int {
+	return xxx_messageInfo_VolumeMount.Size(m)
+}
+func (m *VolumeMount) XXX_DiscardUnknown() {
+	xxx_messageInfo_VolumeMount.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_VolumeMount proto.InternalMessageInfo
+
+func (m *VolumeMount) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *VolumeMount) GetMountPath() string {
+	if m != nil {
+		return m.MountPath
+	}
+	return ""
+}
+
+func (m *VolumeMount) GetHostPath() string {
+	if m != nil {
+		return m.HostPath
+	}
+	return ""
+}
+
+func (m *VolumeMount) GetReadOnly() bool {
+	if m != nil {
+		return m.ReadOnly
+	}
+	return false
+}
+
+// ConfigFile is a file written in an application before it starts
+type ConfigFile struct {
+	Path                 string   `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Content              string   `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ConfigFile) Reset()         { *m = ConfigFile{} }
+func (m *ConfigFile) String() string { return proto.CompactTextString(m) }
+func (*ConfigFile) ProtoMessage()    {}
+func (*ConfigFile) Descriptor() ([]byte, []int) {
+	return fileDescriptor_78739cf76c9af146, []int{11}
+}
+
+func (m *ConfigFile) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ConfigFile.Unmarshal(m, b)
+}
+func (m *ConfigFile) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ConfigFile.Marshal(b, m, deterministic)
+}
+func (m *ConfigFile) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ConfigFile.Merge(m, src)
+}
+func (m *ConfigFile) XXX_Size() int {
+	return xxx_messageInfo_ConfigFile.Size(m)
+}
+func (m *ConfigFile) XXX_DiscardUnknown() {
+	xxx_messageInfo_ConfigFile.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ConfigFile proto.InternalMessageInfo
+
+func (m *ConfigFile) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *ConfigFile) GetContent() string {
+	if m != nil {
+		return m.Content
+	}
+	return ""
+}
+
+// Probe checks the health of an application with an HTTP GET, a TCP
+// connection or a command run in it
+type Probe struct {
+	Type                 string   `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Path                 string   `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	Port                 uint32   `protobuf:"varint,3,opt,name=port,proto3" json:"port,omitempty"`
+	Command              []string `protobuf:"bytes,4,rep,name=command,proto3" json:"command,omitempty"`
+	InitialDelaySeconds  int32    `protobuf:"varint,5,opt,name=initialDelaySeconds,proto3" json:"initialDelaySeconds,omitempty"`
+	PeriodSeconds        int32    `protobuf:"varint,6,opt,name=periodSeconds,proto3" json:"periodSeconds,omitempty"`
+	TimeoutSeconds       int32    `protobuf:"varint,7,opt,name=timeoutSeconds,proto3" json:"timeoutSeconds,omitempty"`
+	FailureThreshold     int32    `protobuf:"varint,8,opt,name=failureThreshold,proto3" json:"failureThreshold,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Probe) Reset()         { *m = Probe{} }
+func (m *Probe) String() string { return proto.CompactTextString(m) }
+func (*Probe) ProtoMessage()    {}
+func (*Probe) Descriptor() ([]byte, []int) {
+	return fileDescriptor_78739cf76c9af146, []int{12}
+}
+
+func (m *Probe) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Probe.Unmarshal(m, b)
+}
+func (m *Probe) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Probe.Marshal(b, m, deterministic)
+}
+func (m *Probe) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Probe.Merge(m, src)
+}
+func (m *Probe) XXX_Size() int {
+	return xxx_messageInfo_Probe.Size(m)
+}
+func (m *Probe) XXX_DiscardUnknown() {
+	xxx_messageInfo_Probe.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Probe proto.InternalMessageInfo
+
+func (m *Probe) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *Probe) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *Probe) GetPort() uint32 {
+	if m != nil {
+		return m.Port
+	}
+	return 0
+}
+
+func (m *Probe) GetCommand() []string {
+	if m != nil {
+		return m.Command
+	}
+	return nil
+}
+
+func (m *Probe) GetInitialDelaySeconds() int32 {
+	if m != nil {
+		return m.InitialDelaySeconds
+	}
+	return 0
+}
+
+func (m *Probe) GetPeriodSeconds() int32 {
+	if m != nil {
+		return m.PeriodSeconds
+	}
+	return 0
+}
+
+func (m *Probe) GetTimeoutSeconds() int32 {
+	if m != nil {
+		return m.TimeoutSeconds
+	}
+	return 0
+}
+
+func (m *Probe) GetFailureThreshold() int32 {
+	if m != nil {
+		return m.FailureThreshold
+	}
+	return 0
+}
+
+// LogRequest selects the logs of an application. Tail is the number of
+// lines from the end to return and since the Unix time in seconds of the
+// oldest line, all lines if zero. Follow streams new lines until the
+// request is canceled.
+type LogRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Follow               bool     `protobuf:"varint,2,opt,name=follow,proto3" json:"follow,omitempty"`
+	Tail                 int64    `protobuf:"varint,3,opt,name=tail,proto3" json:"tail,omitempty"`
+	Since                int64    `protobuf:"varint,4,opt,name=since,proto3" json:"since,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LogRequest) Reset()         { *m = LogRequest{} }
+func (m *LogRequest) String() string { return proto.CompactTextString(m) }
+func (*LogRequest) ProtoMessage()    {}
+func (*LogRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_78739cf76c9af146, []int{13}
+}
+
+func (m *LogRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LogRequest.Unmarshal(m, b)
+}
+func (m *LogRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LogRequest.Marshal(b, m, deterministic)
+}
+func (m *LogRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LogRequest.Merge(m, src)
+}
+func (m *LogRequest) XXX_Size() int {
+	return xxx_messageInfo_LogRequest.Size(m)
+}
+func (m *LogRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_LogRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LogRequest proto.InternalMessageInfo
+
+func (m *LogRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *LogRequest) GetFollow() bool {
+	if m != nil {
+		return m.Follow
+	}
+	return false
+}
+
+func (m *LogRequest) GetTail() int64 {
+	if m != nil {
+		return m.Tail
+	}
+	return 0
+}
+
+func (m *LogRequest) GetSince() int64 {
+	if m != nil {
+		return m.Since
+	}
+	return 0
+}
+
+// LogChunk is a part of the logs of an application
+type LogChunk struct {
+	Data                 []byte   `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LogChunk) Reset()         { *m = LogChunk{} }
+func (m *LogChunk) String() string { return proto.CompactTextString(m) }
+func (*LogChunk) ProtoMessage()    {}
+func (*LogChunk) Descriptor() ([]byte, []int) {
+	return fileDescriptor_78739cf76c9af146, []int{14}
+}
+
+func (m *LogChunk) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LogChunk.Unmarshal(m, b)
+}
+func (m *LogChunk) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LogChunk.Marshal(b, m, deterministic)
+}
+func (m *LogChunk) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LogChunk.Merge(m, src)
+}
+func (m *LogChunk) XXX_Size() int {
+	return xxx_messageInfo_LogChunk.Size(m)
+}
+func (m *LogChunk) XXX_DiscardUnknown() {
+	xxx_messageInfo_LogChunk.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LogChunk proto.InternalMessageInfo
+
+func (m *LogChunk) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterEnum("openness.eva.LifecycleCommand_Command", LifecycleCommand_Command_name, LifecycleCommand_Command_value)
 	proto.RegisterEnum("openness.eva.LifecycleStatus_Status", LifecycleStatus_Status_name, LifecycleStatus_Status_value)
@@ -690,66 +1137,99 @@ func init() {
 	proto.RegisterType((*LifecycleStatus)(nil), "openness.eva.LifecycleStatus")
 	proto.RegisterType((*ContainerIP)(nil), "openness.eva.ContainerIP")
 	proto.RegisterType((*ContainerInfo)(nil), "openness.eva.ContainerInfo")
+	proto.RegisterType((*EnvVar)(nil), "openness.eva.EnvVar")
+	proto.RegisterType((*VolumeMount)(nil), "openness.eva.VolumeMount")
+	proto.RegisterType((*ConfigFile)(nil), "openness.eva.ConfigFile")
+	proto.RegisterType((*Probe)(nil), "openness.eva.Probe")
+	proto.RegisterType((*LogRequest)(nil), "openness.eva.LogRequest")
+	proto.RegisterType((*LogChunk)(nil), "openness.eva.LogChunk")
 }
 
 func init() { proto.RegisterFile("eva.proto", fileDescriptor_78739cf76c9af146) }
 
 var fileDescriptor_78739cf76c9af146 = []byte{
-	// 855 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x54, 0xdd, 0x6e, 0xe2, 0x46,
-	0x14, 0xc6, 0xfc, 0x19, 0x0e, 0x24, 0x6b, 0x8d, 0xaa, 0x5d, 0x87, 0x6c, 0xb6, 0xc8, 0x5a, 0xb5,
-	0x48, 0x55, 0x1d, 0x89, 0xde, 0xac, 0xd4, 0x56, 0x2d, 0x01, 0x9a, 0xa5, 0xcd, 0x82, 0x35, 0x90,
-	0xad, 0xb6, 0x37, 0x95, 0x63, 0x06, 0x32, 0x92, 0x3d, 0x63, 0x8d, 0x07, 0x24, 0x7a, 0xd1, 0xfb,
-	0x3e, 0x4b, 0xef, 0xfa, 0x06, 0x7d, 0xa5, 0x3e, 0x41, 0x35, 0x63, 0x03, 0x0e, 0x15, 0x91, 0xba,
-	0xb9, 0xf2, 0x9c, 0xef, 0x9c, 0xf3, 0x9d, 0x9f, 0xf9, 0x3c, 0x50, 0x27, 0x6b, 0xdf, 0x8d, 0x05,
-	0x97, 0x1c, 0x35, 0x79, 0x4c, 0x18, 0x23, 0x49, 0xe2, 0x92, 0xb5, 0xdf, 0x3a, 0x5f, 0x72, 0xbe,
-	0x0c, 0xc9, 0xa5, 0xf6, 0xdd, 0xad, 0x16, 0x97, 0x24, 0x8a, 0xe5, 0x26, 0x0d, 0x75, 0xfe, 0x29,
-	0x41, 0xa3, 0x17, 0xc7, 0x21, 0x0d, 0x7c, 0x49, 0x39, 0x43, 0xa7, 0x50, 0xa4, 0x73, 0xdb, 0x68,
-	0x1b, 0x9d, 0x3a, 0x2e, 0xd2, 0x39, 0x42, 0x50, 0x66, 0x7e, 0x44, 0xec, 0xa2, 0x46, 0xf4, 0x19,
-	0xd9, 0x60, 0xae, 0x89, 0x48, 0x28, 0x67, 0x76, 0x49, 0xc3, 0x5b, 0x13, 0x3d, 0x87, 0xea, 0x9a,
-	0xb0, 0x39, 0x17, 0x76, 0x59, 0x3b, 0x32, 0x0b, 0xb5, 0xa1, 0x31, 0x27, 0x49, 0x20, 0x68, 0xac,
-	0x8a, 0xd8, 0x15, 0xed, 0xcc, 0x43, 0xe8, 0x13, 0xa8, 0x04, 0x5c, 0x90, 0xc4, 0xae, 0xb6, 0x8d,
-	0x4e, 0x05, 0xa7, 0x86, 0xe2, 0x8b, 0x48, 0xc4, 0xc5, 0xc6, 0x36, 0x35, 0x9c, 0x59, 0xe8, 0x4b,
-	0xa8, 0xc4, 0x5c, 0xc8, 0xc4, 0xae, 0xb5, 0x4b, 0x9d, 0x46, 0xf7, 0x85, 0x9b, 0x1f, 0xd8, 0xf5,
-	0xb8, 0x90, 0x9e, 0x9a, 0x0e, 0xa7, 0x51, 0xe8, 0x1b, 0xa8, 0x26, 0xd2, 0x97, 0xab, 0xc4, 0xae,
-	0xb7, 0x8d, 0xce, 0x69, 0xf7, 0xf5, 0xc3, 0xf8, 0x1b, 0xba, 0x20, 0xc1, 0x26, 0x08, 0xc9, 0x54,
-	0x07, 0xb9, 0xe9, 0x07, 0x67, 0x39, 0xa8, 0x07, 0xb5, 0x7b, 0x29, 0xe3, 0x5f, 0x57, 0x82, 0xda,
-	0xd0, 0x36, 0x3a, 0x8d, 0xc3, 0xfc, 0xdc, 0xfe, 0xdc, 0xb7, 0xb3, 0x99, 0x37, 0xe5, 0x2b, 0x11,
-	0x90, 0xb7, 0x05, 0x6c, 0xaa, 0xbc, 0x5b, 0x41, 0xd5, 0xfc, 0xc3, 0x5e, 0xff, 0xc7, 0x84, 0xb3,
-	0xab, 0x90, 0xdf, 0xd9, 0x8d, 0x74, 0xfe, 0x1c, 0x84, 0xde, 0x80, 0x19, 0x30, 0xda, 0xe7, 0x6c,
-	0x61, 0x37, 0x75, 0x8d, 0x57, 0x0f, 0x6b, 0xf4, 0xc7, 0x23, 0xe5, 0xa4, 0xcb, 0x95, 0xd0, 0x85,
-	0xf0, 0x36, 0xbc, 0xf5, 0x39, 0xc0, 0xbe, 0x28, 0x3a, 0xcb, 0x35, 0x9b, 0xde, 0xe2, 0xb6, 0x89,
-	0xab, 0x1a, 0x54, 0x13, 0x1d, 0xe4, 0xfc, 0x0e, 0xd6, 0x21, 0x1f, 0x7a, 0x09, 0xf5, 0x8c, 0x91,
-	0x2e, 0xb3, 0xcc, 0x3d, 0x80, 0x5e, 0xc3, 0x09, 0x65, 0x92, 0x88, 0x85, 0x1f, 0x90, 0xf1, 0x5e,
-	0x0f, 0x0f, 0x41, 0x25, 0x96, 0xd8, 0x97, 0xf7, 0x99, 0x2a, 0xf4, 0x59, 0x61, 0xbe, 0x58, 0x26,
-	0x99, 0x20, 0xf4, 0xd9, 0xf9, 0x14, 0x4e, 0x72, 0x3b, 0x1b, 0x0d, 0x0e, 0x55, 0xe7, 0xbc, 0x83,
-	0x66, 0x2e, 0x20, 0x41, 0xdf, 0x42, 0xd3, 0xcf, 0xd9, 0xb6, 0xa1, 0xaf, 0xfd, 0xec, 0xe8, 0x35,
-	0xe0, 0x07, 0xe1, 0xce, 0xd7, 0x50, 0xdf, 0x69, 0x42, 0x37, 0xc9, 0x85, 0xd4, 0xd5, 0x4e, 0xb0,
-	0x3e, 0xa3, 0x16, 0xd4, 0xf4, 0xef, 0x10, 0xf0, 0x30, 0x9b, 0x6c, 0x67, 0x3b, 0x7f, 0x18, 0x60,
-	0xed, 0x14, 0xd2, 0xe7, 0x51, 0xe4, 0xb3, 0xf9, 0x7f, 0x7e, 0x93, 0x37, 0x50, 0x0a, 0xa2, 0xb9,
-	0xce, 0x3d, 0xed, 0x7e, 0x76, 0x44, 0x5e, 0x59, 0xb2, 0x9b, 0x7d, 0xb1, 0x4a, 0x71, 0xbe, 0x00,
-	0x73, 0x4b, 0x5a, 0x87, 0xca, 0x74, 0xd6, 0xc3, 0x33, 0xab, 0x80, 0x6a, 0x50, 0x9e, 0xce, 0x26,
-	0x9e, 0x65, 0xa0, 0x06, 0x98, 0x78, 0x98, 0xc2, 0x45, 0xe7, 0x6f, 0x03, 0x9e, 0x1d, 0xa8, 0x35,
-	0x27, 0x6e, 0xe3, 0xff, 0x8b, 0xdb, 0x89, 0xa1, 0x9a, 0xf1, 0x34, 0xc0, 0xbc, 0x1d, 0xff, 0x34,
-	0x9e, 0xfc, 0x3c, 0xb6, 0x0a, 0xe8, 0x04, 0xea, 0x83, 0xa1, 0x77, 0x33, 0xf9, 0x30, 0x1a, 0x5f,
-	0x5b, 0x86, 0xea, 0x0c, 0x0f, 0x7b, 0x83, 0x0f, 0x56, 0x11, 0x35, 0xa1, 0xa6, 0xbb, 0x51, 0x8e,
-	0x92, 0xee, 0xee, 0x76, 0x3c, 0x56, 0x46, 0x39, 0x75, 0x4d, 0x3c, 0x4f, 0x59, 0x15, 0xe5, 0xd2,
-	0xd6, 0x70, 0x60, 0x55, 0x15, 0xc1, 0x10, 0xe3, 0x09, 0xb6, 0x4c, 0xe7, 0x02, 0x1a, 0x7d, 0xce,
-	0xa4, 0x4f, 0x19, 0x11, 0x23, 0x4f, 0x6f, 0x32, 0xde, 0x6d, 0x32, 0x56, 0xda, 0xd8, 0xbb, 0xd9,
-	0x82, 0x1f, 0xae, 0xba, 0xfb, 0x67, 0x11, 0x5e, 0xe6, 0xae, 0x7a, 0x40, 0xe2, 0x90, 0x6f, 0x22,
-	0xc2, 0xe4, 0x94, 0x88, 0x35, 0x0d, 0x08, 0xfa, 0x01, 0x9e, 0xa5, 0xe0, 0x8e, 0x07, 0x1d, 0x57,
-	0x4a, 0xeb, 0xb9, 0x9b, 0x3e, 0x8f, 0xee, 0xf6, 0x79, 0x74, 0x87, 0xea, 0x79, 0x74, 0x0a, 0xe8,
-	0x3b, 0xa8, 0xa5, 0x3c, 0xef, 0xdf, 0x7d, 0x34, 0x01, 0x26, 0x73, 0x4d, 0xf1, 0x71, 0x04, 0x3d,
-	0xa8, 0xdd, 0xb2, 0x8c, 0xe0, 0xfc, 0x28, 0xc1, 0x68, 0x70, 0x9c, 0xa2, 0xfb, 0x57, 0x11, 0xce,
-	0x73, 0xb1, 0x7b, 0x35, 0x64, 0xcb, 0xea, 0x41, 0x65, 0x2a, 0x7d, 0x21, 0xd1, 0xab, 0xc7, 0x45,
-	0xfb, 0x48, 0x97, 0xdf, 0x43, 0x79, 0x2a, 0x79, 0xfc, 0x04, 0x86, 0x3e, 0x98, 0x98, 0x24, 0x4f,
-	0x6c, 0x63, 0x04, 0xf5, 0x6b, 0x22, 0x33, 0x31, 0x3f, 0xba, 0xad, 0x8b, 0x47, 0xff, 0x10, 0xa7,
-	0xd0, 0x8d, 0xe0, 0x42, 0x69, 0x47, 0xf0, 0x30, 0x24, 0xe2, 0x3d, 0x15, 0x72, 0xe5, 0x87, 0xf4,
-	0x37, 0x9d, 0xde, 0x5b, 0x12, 0x26, 0xd1, 0x0d, 0x58, 0xd7, 0x44, 0xee, 0xf4, 0x75, 0xb5, 0x19,
-	0x79, 0x87, 0x37, 0x9c, 0xd3, 0x78, 0xeb, 0xfc, 0x98, 0x8b, 0x2d, 0xb8, 0x53, 0xb8, 0x3a, 0xfb,
-	0xe5, 0xc5, 0x92, 0xca, 0xfb, 0xd5, 0x9d, 0x1b, 0xf0, 0xe8, 0x92, 0xcb, 0x20, 0xb9, 0xf7, 0x05,
-	0xb9, 0x24, 0x6b, 0xff, 0xae, 0xaa, 0xc7, 0xfc, 0xea, 0xdf, 0x00, 0x00, 0x00, 0xff, 0xff, 0xbe,
-	0x65, 0x8e, 0x12, 0xdd, 0x07, 0x00, 0x00,
+	// 1292 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x56, 0xdd, 0x6e, 0xdb, 0xb6,
+	0x17, 0x8f, 0xfc, 0xed, 0xe3, 0x24, 0xf5, 0x9f, 0xcd, 0x3f, 0x55, 0x93, 0xb6, 0x33, 0x84, 0xa2,
+	0x0b, 0x36, 0xcc, 0x29, 0xdc, 0x9b, 0xae, 0xdd, 0xb0, 0x39, 0xb6, 0x9b, 0x78, 0x73, 0x1d, 0x83,
+	0x76, 0x32, 0x74, 0x17, 0x1b, 0x14, 0x99, 0xb6, 0x89, 0x49, 0xa4, 0x46, 0x51, 0x2e, 0xbc, 0x8b,
+	0xed, 0xba, 0xcf, 0xb2, 0x97, 0xd9, 0x9b, 0xec, 0x62, 0x2f, 0x30, 0x90, 0x92, 0x65, 0xd9, 0x69,
+	0x02, 0xac, 0xbd, 0x12, 0xcf, 0x27, 0xc9, 0x73, 0x7e, 0xe7, 0x47, 0x41, 0x99, 0xcc, 0xed, 0xba,
+	0x2f, 0xb8, 0xe4, 0x68, 0x9b, 0xfb, 0x84, 0x31, 0x12, 0x04, 0x75, 0x32, 0xb7, 0x0f, 0x0e, 0xa7,
+	0x9c, 0x4f, 0x5d, 0x72, 0xac, 0x6d, 0x57, 0xe1, 0xe4, 0x98, 0x78, 0xbe, 0x5c, 0x44, 0xae, 0xd6,
+	0xdf, 0x05, 0xa8, 0x34, 0x7d, 0xdf, 0xa5, 0x8e, 0x2d, 0x29, 0x67, 0x68, 0x17, 0x32, 0x74, 0x6c,
+	0x1a, 0x35, 0xe3, 0xa8, 0x8c, 0x33, 0x74, 0x8c, 0x10, 0xe4, 0x98, 0xed, 0x11, 0x33, 0xa3, 0x35,
+	0x7a, 0x8d, 0x4c, 0x28, 0xce, 0x89, 0x08, 0x28, 0x67, 0x66, 0x56, 0xab, 0x97, 0x22, 0xda, 0x87,
+	0xc2, 0x9c, 0xb0, 0x31, 0x17, 0x66, 0x4e, 0x1b, 0x62, 0x09, 0xd5, 0xa0, 0x32, 0x26, 0x81, 0x23,
+	0xa8, 0xaf, 0x36, 0x31, 0xf3, 0xda, 0x98, 0x56, 0xa1, 0x3d, 0xc8, 0x3b, 0x5c, 0x90, 0xc0, 0x2c,
+	0xd4, 0x8c, 0xa3, 0x3c, 0x8e, 0x04, 0x95, 0xcf, 0x23, 0x1e, 0x17, 0x0b, 0xb3, 0xa8, 0xd5, 0xb1,
+	0x84, 0xbe, 0x80, 0xbc, 0xcf, 0x85, 0x0c, 0xcc, 0x52, 0x2d, 0x7b, 0x54, 0x69, 0xdc, 0xab, 0xa7,
+	0x2f, 0x5c, 0x1f, 0x70, 0x21, 0x07, 0xea, 0x76, 0x38, 0xf2, 0x42, 0x5f, 0x41, 0x21, 0x90, 0xb6,
+	0x0c, 0x03, 0xb3, 0x5c, 0x33, 0x8e, 0x76, 0x1b, 0x8f, 0xd7, 0xfd, 0x7b, 0x74, 0x42, 0x9c, 0x85,
+	0xe3, 0x92, 0xa1, 0x76, 0xaa, 0x47, 0x1f, 0x1c, 0xc7, 0xa0, 0x26, 0x94, 0x66, 0x52, 0xfa, 0x3f,
+	0x87, 0x82, 0x9a, 0x50, 0x33, 0x8e, 0x2a, 0x9b, 0xf1, 0xa9, 0xfa, 0xd5, 0xcf, 0x46, 0xa3, 0xc1,
+	0x90, 0x87, 0xc2, 0x21, 0x67, 0x5b, 0xb8, 0xa8, 0xe2, 0x2e, 0x04, 0x55, 0xf7, 0xef, 0x34, 0x5b,
+	0xdf, 0x05, 0x9c, 0x9d, 0xb8, 0xfc, 0xca, 0xac, 0x44, 0xf7, 0x4f, 0xa9, 0xd0, 0x73, 0x28, 0x3a,
+	0x8c, 0xb6, 0x38, 0x9b, 0x98, 0xdb, 0x7a, 0x8f, 0x47, 0xeb, 0x7b, 0xb4, 0xfa, 0x5d, 0x65, 0xa4,
+	0xd3, 0x50, 0xe8, 0x8d, 0xf0, 0xd2, 0x1d, 0x3d, 0x81, 0x2c, 0x61, 0x73, 0x73, 0x47, 0x57, 0x62,
+	0x6f, 0x3d, 0xaa, 0xc3, 0xe6, 0x97, 0xb6, 0xc0, 0xca, 0x41, 0x75, 0xcd, 0xe1, 0x9e, 0x67, 0xb3,
+	0xb1, 0xb9, 0x5b, 0xcb, 0xaa, 0xae, 0xc5, 0xa2, 0xea, 0xb1, 0x2d, 0xa6, 0x81, 0x79, 0x47, 0xab,
+	0xf5, 0x1a, 0x3d, 0x83, 0xe2, 0x9c, 0xbb, 0xa1, 0x47, 0x02, 0xb3, 0xaa, 0x33, 0xdf, 0x5f, 0xcf,
+	0x7c, 0xa9, 0x8d, 0xaf, 0x79, 0xc8, 0x24, 0x5e, 0x7a, 0xa2, 0x17, 0x50, 0x71, 0xf4, 0x21, 0x5f,
+	0x51, 0x97, 0x04, 0xe6, 0xff, 0x74, 0xa0, 0xb9, 0x71, 0x91, 0xc4, 0x01, 0xa7, 0x9d, 0xd1, 0x97,
+	0xb0, 0xe3, 0xd2, 0x39, 0x51, 0x7e, 0x03, 0xc1, 0xaf, 0x88, 0x89, 0x74, 0x19, 0xee, 0x6e, 0xb4,
+	0x56, 0x99, 0xf0, 0xba, 0x27, 0x7a, 0x09, 0xbb, 0x82, 0xd8, 0x63, 0xba, 0x8a, 0xbd, 0x7b, 0x73,
+	0xec, 0x86, 0x2b, 0x7a, 0x0c, 0x3b, 0x82, 0x04, 0xd2, 0x16, 0x72, 0xc0, 0x5d, 0xea, 0x2c, 0xcc,
+	0x3d, 0xdd, 0x9c, 0x75, 0xa5, 0x02, 0xe2, 0x98, 0x4e, 0x49, 0x20, 0xcd, 0xff, 0x47, 0xc0, 0x8e,
+	0xa4, 0x83, 0x4f, 0x01, 0x56, 0x1d, 0x47, 0xf7, 0x53, 0x48, 0x89, 0x46, 0x68, 0x89, 0x80, 0x93,
+	0x12, 0x14, 0x02, 0xed, 0x64, 0xfd, 0x0e, 0xd5, 0xcd, 0x66, 0xa2, 0x07, 0x50, 0x8e, 0xdb, 0x49,
+	0xa7, 0x71, 0xe4, 0x4a, 0xa1, 0x8e, 0x48, 0x99, 0x24, 0x62, 0x62, 0x3b, 0xa4, 0xbf, 0x1a, 0xc6,
+	0x75, 0xa5, 0xea, 0xa2, 0x6f, 0xcb, 0x59, 0x3c, 0x92, 0x7a, 0x9d, 0x74, 0x36, 0x9a, 0x46, 0xbd,
+	0xb6, 0x3e, 0x81, 0x9d, 0x14, 0x60, 0xbb, 0xed, 0xcd, 0x91, 0xb7, 0x5e, 0xc3, 0x76, 0xca, 0x21,
+	0x40, 0x5f, 0xc3, 0xb6, 0x9d, 0x92, 0x4d, 0xe3, 0x7d, 0x78, 0x48, 0x45, 0xe0, 0x35, 0x77, 0xeb,
+	0x25, 0x94, 0x93, 0x81, 0xd4, 0x87, 0xe4, 0x42, 0xea, 0xdd, 0x76, 0xb0, 0x5e, 0xa3, 0x03, 0x28,
+	0x69, 0x2e, 0x72, 0xb8, 0x1b, 0xdf, 0x2c, 0x91, 0xad, 0x77, 0x06, 0x54, 0x93, 0xf1, 0x6c, 0xc5,
+	0x78, 0xdd, 0xe4, 0xa8, 0xe7, 0x90, 0x75, 0xbc, 0xb1, 0x8e, 0xdd, 0x6d, 0x3c, 0xb9, 0x61, 0xb6,
+	0xe3, 0xe0, 0x7a, 0xfc, 0xc5, 0x2a, 0xc4, 0xfa, 0x1c, 0x8a, 0xcb, 0xa4, 0x65, 0xc8, 0x0f, 0x47,
+	0x4d, 0x3c, 0xaa, 0x6e, 0xa1, 0x12, 0xe4, 0x86, 0xa3, 0xf3, 0x41, 0xd5, 0x40, 0x15, 0x28, 0xe2,
+	0x4e, 0xa4, 0xce, 0x58, 0x7f, 0x19, 0x70, 0x67, 0x83, 0x2a, 0x52, 0xcc, 0x62, 0xfc, 0x77, 0x66,
+	0xb1, 0xfe, 0x80, 0x42, 0x9c, 0xa7, 0x02, 0xc5, 0x8b, 0xfe, 0xf7, 0xfd, 0xf3, 0x1f, 0xfa, 0xd5,
+	0x2d, 0xb4, 0x03, 0xe5, 0x76, 0x67, 0xd0, 0x3b, 0x7f, 0xd3, 0xed, 0x9f, 0x56, 0x0d, 0x75, 0x32,
+	0xdc, 0x69, 0xb6, 0xdf, 0x54, 0x33, 0x68, 0x1b, 0x4a, 0xfa, 0x34, 0xca, 0x90, 0xd5, 0xa7, 0xbb,
+	0xe8, 0xf7, 0x95, 0x90, 0x8b, 0x4c, 0xe7, 0x83, 0x81, 0x92, 0xf2, 0xca, 0xa4, 0xa5, 0x4e, 0xbb,
+	0x5a, 0x50, 0x09, 0x3a, 0x18, 0x9f, 0xe3, 0x6a, 0x51, 0xa5, 0xbe, 0xe8, 0x9f, 0x75, 0x9a, 0xbd,
+	0xd1, 0xd9, 0x9b, 0x6a, 0xc9, 0x7a, 0x08, 0x95, 0x16, 0x67, 0xd2, 0xa6, 0x8c, 0x88, 0xee, 0x40,
+	0x17, 0xd6, 0x4f, 0x0a, 0xeb, 0x2b, 0xa8, 0xac, 0xcc, 0x6c, 0xc2, 0xaf, 0x41, 0xa5, 0x01, 0x85,
+	0x88, 0x62, 0x92, 0x77, 0xc2, 0x48, 0xbd, 0x13, 0x7b, 0x90, 0x9f, 0xdb, 0x6e, 0xb8, 0xc4, 0x6b,
+	0x24, 0x58, 0x6f, 0xa1, 0x92, 0x22, 0x8f, 0xf7, 0x06, 0x3e, 0x80, 0xb2, 0xa7, 0x8c, 0x03, 0x85,
+	0xe7, 0x28, 0x78, 0xa5, 0x50, 0x78, 0x99, 0xf1, 0x40, 0x0e, 0x56, 0x60, 0x4f, 0x64, 0x65, 0x53,
+	0xf3, 0x7d, 0xce, 0xdc, 0x85, 0x06, 0x7d, 0x09, 0x27, 0xb2, 0xf5, 0x02, 0x60, 0x45, 0x3e, 0xc9,
+	0xb8, 0x18, 0xa9, 0x71, 0xd1, 0x14, 0xc9, 0x24, 0x61, 0x32, 0xde, 0x75, 0x29, 0x5a, 0xef, 0x32,
+	0x90, 0x8f, 0xf8, 0x02, 0x41, 0x4e, 0x2e, 0xfc, 0xe4, 0xbc, 0x6a, 0x9d, 0xe4, 0xca, 0xac, 0x8f,
+	0x9e, 0x46, 0x7a, 0x36, 0x85, 0xf4, 0x14, 0x05, 0xe7, 0xd6, 0x29, 0xf8, 0x29, 0xdc, 0xa5, 0x8c,
+	0x4a, 0x6a, 0xbb, 0x6d, 0xe2, 0xda, 0x8b, 0x21, 0x71, 0x38, 0x1b, 0x07, 0xfa, 0xa1, 0xcc, 0xe3,
+	0xf7, 0x99, 0x14, 0x29, 0xf8, 0x44, 0x50, 0x3e, 0x5e, 0xfa, 0x46, 0x0f, 0xe7, 0xba, 0x12, 0x3d,
+	0x81, 0x5d, 0x49, 0x3d, 0xc2, 0x43, 0xb9, 0x74, 0x8b, 0x1e, 0xd2, 0x0d, 0x2d, 0xfa, 0x0c, 0xaa,
+	0x13, 0x9b, 0xba, 0xa1, 0x20, 0xa3, 0x99, 0x20, 0xc1, 0x8c, 0xbb, 0x63, 0xb3, 0xa4, 0x3d, 0xaf,
+	0xe9, 0xad, 0x9f, 0x00, 0x7a, 0x7c, 0x8a, 0xc9, 0xaf, 0x21, 0x09, 0xe4, 0xb5, 0x61, 0xdc, 0x87,
+	0xc2, 0x84, 0xbb, 0x2e, 0x7f, 0xab, 0xab, 0x51, 0xc2, 0xb1, 0xa4, 0xeb, 0x66, 0x53, 0x57, 0xd7,
+	0x23, 0x8b, 0xf5, 0x5a, 0x01, 0x24, 0xa0, 0xcc, 0x21, 0xba, 0x55, 0x59, 0x1c, 0x09, 0xd6, 0x23,
+	0x28, 0xf5, 0xf8, 0xb4, 0x35, 0x0b, 0xd9, 0x2f, 0x2a, 0x6a, 0x6c, 0x4b, 0x5b, 0xe7, 0xdf, 0xc6,
+	0x7a, 0xdd, 0xf8, 0x33, 0x03, 0x0f, 0x52, 0x74, 0xd3, 0x26, 0xbe, 0xcb, 0x17, 0x1e, 0x61, 0x72,
+	0x48, 0xc4, 0x9c, 0x3a, 0x04, 0xbd, 0x82, 0x3b, 0x91, 0x32, 0x01, 0x2f, 0xba, 0x99, 0xad, 0x0e,
+	0xf6, 0xeb, 0xd1, 0xff, 0x51, 0x7d, 0xf9, 0x7f, 0x54, 0xef, 0xa8, 0xff, 0x23, 0x6b, 0x0b, 0x7d,
+	0x03, 0xa5, 0x28, 0xcf, 0xe5, 0xeb, 0x0f, 0x4e, 0x80, 0xc9, 0x58, 0xa7, 0xf8, 0xb0, 0x04, 0x4d,
+	0x28, 0x5d, 0xb0, 0x38, 0xc1, 0xe1, 0x8d, 0x09, 0xba, 0xed, 0x9b, 0x53, 0x34, 0xfe, 0xc9, 0xc0,
+	0x61, 0xca, 0x77, 0xc5, 0x48, 0x71, 0xb1, 0x9a, 0x90, 0x1f, 0xaa, 0x87, 0x0e, 0x3d, 0xba, 0x9d,
+	0x38, 0x6f, 0x39, 0xe5, 0xb7, 0x90, 0x1b, 0x4a, 0xee, 0x7f, 0x44, 0x86, 0x16, 0x14, 0x71, 0xf4,
+	0xde, 0x7e, 0x44, 0x92, 0x2e, 0x94, 0x4f, 0x89, 0x8c, 0x09, 0xf5, 0xd6, 0x6a, 0x3d, 0xbc, 0x95,
+	0xa5, 0x75, 0xe3, 0x8a, 0xa7, 0x44, 0xf6, 0xf8, 0x34, 0x40, 0x1b, 0xbf, 0x2f, 0x2b, 0xe4, 0x1f,
+	0xec, 0x5f, 0xb3, 0x68, 0xcc, 0x5a, 0x5b, 0x4f, 0x8d, 0x86, 0x07, 0x0f, 0x15, 0xf8, 0x04, 0x77,
+	0x5d, 0x22, 0x2e, 0xa9, 0x90, 0xa1, 0xed, 0xd2, 0xdf, 0xf4, 0xfe, 0xcd, 0x29, 0x61, 0x12, 0xf5,
+	0xa0, 0x7a, 0x4a, 0x64, 0x02, 0xd0, 0x93, 0x45, 0x77, 0xb0, 0x09, 0x91, 0x14, 0x33, 0x1f, 0x1c,
+	0xde, 0x64, 0x62, 0x13, 0x6e, 0x6d, 0x9d, 0xdc, 0xff, 0xf1, 0xde, 0x94, 0xca, 0x59, 0x78, 0x55,
+	0x77, 0xb8, 0x77, 0xcc, 0xa5, 0x13, 0xcc, 0x6c, 0x41, 0x8e, 0xc9, 0xdc, 0xbe, 0x2a, 0xe8, 0x3a,
+	0x3d, 0xfb, 0x77, 0x00, 0xbc, 0x1a, 0x64, 0x48, 0x1f, 0x0c, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -948,6 +1428,7 @@ type ApplicationLifecycleServiceClient interface {
 	Stop(ctx context.Context, in *LifecycleCommand, opts ...grpc.CallOption) (*empty.Empty, error)
 	Restart(ctx context.Context, in *LifecycleCommand, opts ...grpc.CallOption) (*empty.Empty, error)
 	GetStatus(ctx context.Context, in *ApplicationID, opts ...grpc.CallOption) (*LifecycleStatus, error)
+	GetLogs(ctx context.Context, in *LogRequest, opts ...grpc.CallOption) (ApplicationLifecycleService_GetLogsClient, error)
 }
 
 type applicationLifecycleServiceClient struct {
@@ -994,12 +1475,45 @@ func (c *applicationLifecycleServiceClient) GetStatus(ctx context.Context, in *A
 	return out, nil
 }
 
+func (c *applicationLifecycleServiceClient) GetLogs(ctx context.Context, in *LogRequest, opts ...grpc.CallOption) (ApplicationLifecycleService_GetLogsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_ApplicationLifecycleService_serviceDesc.Streams[0], "/openness.eva.ApplicationLifecycleService/GetLogs", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &applicationLifecycleServiceGetLogsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ApplicationLifecycleService_GetLogsClient interface {
+	Recv() (*LogChunk, error)
+	grpc.ClientStream
+}
+
+type applicationLifecycleServiceGetLogsClient struct {
+	grpc.ClientStream
+}
+
+func (x *applicationLifecycleServiceGetLogsClient) Recv() (*LogChunk, error) {
+	m := new(LogChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // ApplicationLifecycleServiceServer is the server API for ApplicationLifecycleService service.
 type ApplicationLifecycleServiceServer interface {
 	Start(context.Context, *LifecycleCommand) (*empty.Empty, error)
 	Stop(context.Context, *LifecycleCommand) (*empty.Empty, error)
 	Restart(context.Context, *LifecycleCommand) (*empty.Empty, error)
 	GetStatus(context.Context, *ApplicationID) (*LifecycleStatus, error)
+	GetLogs(*LogRequest, ApplicationLifecycleService_GetLogsServer) error
 }
 
 // UnimplementedApplicationLifecycleServiceServer can be embedded to have forward compatible implementations.
@@ -1018,6 +1532,9 @@ func (*UnimplementedApplicationLifecycleServiceServer) Restart(ctx context.Conte
 func (*UnimplementedApplicationLifecycleServiceServer) GetStatus(ctx context.Context, req *ApplicationID) (*LifecycleStatus, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetStatus not implemented")
 }
+func (*UnimplementedApplicationLifecycleServiceServer) GetLogs(req *LogRequest, srv ApplicationLifecycleService_GetLogsServer) error {
+	return status.Errorf(codes.Unimplemented, "method GetLogs not implemented")
+}
 
 func RegisterApplicationLifecycleServiceServer(s *grpc.Server, srv ApplicationLifecycleServiceServer) {
 	s.RegisterService(&_ApplicationLifecycleService_serviceDesc, srv)
@@ -1095,6 +1612,27 @@ func _ApplicationLifecycleService_GetStatus_Handler(srv interface{}, ctx context
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ApplicationLifecycleService_GetLogs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(LogRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ApplicationLifecycleServiceServer).GetLogs(m, &applicationLifecycleServiceGetLogsServer{stream})
+}
+
+type ApplicationLifecycleService_GetLogsServer interface {
+	Send(*LogChunk) error
+	grpc.ServerStream
+}
+
+type applicationLifecycleServiceGetLogsServer struct {
+	grpc.ServerStream
+}
+
+func (x *applicationLifecycleServiceGetLogsServer) Send(m *LogChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _ApplicationLifecycleService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "openness.eva.ApplicationLifecycleService",
 	HandlerType: (*ApplicationLifecycleServiceServer)(nil),
@@ -1116,7 +1654,13 @@ var _ApplicationLifecycleService_serviceDesc = grpc.ServiceDesc{
 			Handler:    _ApplicationLifecycleService_GetStatus_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "GetLogs",
+			Handler:       _ApplicationLifecycleService_GetLogs_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "eva.proto",
 }
 
@@ -0,0 +1,73 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package cce
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/open-ness/edgecontroller/uuid"
+)
+
+// Registry holds the credentials of an image registry. Apps with a registry
+// image source pull their images with the credentials of the registry host.
+type Registry struct {
+	ID       string `json:"id"`
+	Host     string `json:"host"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// GetTableName returns the name of the persistence table.
+func (*Registry) GetTableName() string {
+	return "registries"
+}
+
+// GetID gets the ID.
+func (reg *Registry) GetID() string {
+	return reg.ID
+}
+
+// SetID sets the ID.
+func (reg *Registry) SetID(id string) {
+	reg.ID = id
+}
+
+// Validate validates the model.
+func (reg *Registry) Validate() error {
+	if !uuid.IsValid(reg.ID) {
+		return errors.New("id not a valid uuid")
+	}
+	if !registryHostRegexp.MatchString(reg.Host) {
+		return fmt.Errorf("host %q must be a registry host name with an optional port", reg.Host)
+	}
+	if reg.Username == "" {
+		return errors.New("username cannot be empty")
+	}
+	if reg.Password == "" {
+		return errors.New("password cannot be empty")
+	}
+
+	return nil
+}
+
+// FilterFields returns the filterable fields for this model.
+func (*Registry) FilterFields() []string {
+	return []string{
+		"host",
+	}
+}
+
+func (reg *Registry) String() string {
+	return fmt.Sprintf(strings.TrimSpace(`
+Registry[
+    ID: %s
+    Host: %s
+    Username: %s
+]`),
+		reg.ID,
+		reg.Host,
+		reg.Username)
+}
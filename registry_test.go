@@ -0,0 +1,96 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package cce_test
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	cce "github.com/open-ness/edgecontroller"
+)
+
+var _ = Describe("Entities: Registry", func() {
+	var (
+		reg *cce.Registry
+	)
+
+	BeforeEach(func() {
+		reg = &cce.Registry{
+			ID:       "6a1c3e5f-7b9d-4f2a-8c4e-0a2b4c6d8e0f",
+			Host:     "registry.example.com:5000",
+			Username: "edge",
+			Password: "secret",
+		}
+	})
+
+	Describe("GetTableName", func() {
+		It(`Should return "registries"`, func() {
+			Expect(reg.GetTableName()).To(Equal("registries"))
+		})
+	})
+
+	Describe("GetID", func() {
+		It("Should return the ID", func() {
+			Expect(reg.GetID()).To(Equal("6a1c3e5f-7b9d-4f2a-8c4e-0a2b4c6d8e0f"))
+		})
+	})
+
+	Describe("SetID", func() {
+		It("Should set and return the updated ID", func() {
+			By("Setting the ID")
+			reg.SetID("456")
+
+			By("Getting the updated ID")
+			Expect(reg.ID).To(Equal("456"))
+		})
+	})
+
+	Describe("Validate", func() {
+		It("Should not return an error for valid credentials", func() {
+			Expect(reg.Validate()).To(Succeed())
+		})
+
+		It("Should return an error if ID is not a UUID", func() {
+			reg.ID = "123"
+			Expect(reg.Validate()).To(MatchError("id not a valid uuid"))
+		})
+
+		It("Should return an error if Host is not a host name", func() {
+			reg.Host = "https://registry.example.com"
+			Expect(reg.Validate()).To(MatchError(
+				`host "https://registry.example.com" must be a registry host name with an optional port`))
+		})
+
+		It("Should return an error if Username is empty", func() {
+			reg.Username = ""
+			Expect(reg.Validate()).To(MatchError("username cannot be empty"))
+		})
+
+		It("Should return an error if Password is empty", func() {
+			reg.Password = ""
+			Expect(reg.Validate()).To(MatchError("password cannot be empty"))
+		})
+	})
+
+	Describe("FilterFields", func() {
+		It("Should return the filterable fields", func() {
+			Expect(reg.FilterFields()).To(Equal([]string{
+				"host",
+			}))
+		})
+	})
+
+	Describe("String", func() {
+		It("Should return the string value without the password", func() {
+			Expect(reg.String()).To(Equal(strings.TrimSpace(`
+Registry[
+    ID: 6a1c3e5f-7b9d-4f2a-8c4e-0a2b4c6d8e0f
+    Host: registry.example.com:5000
+    Username: edge
+]`,
+			)))
+		})
+	})
+})
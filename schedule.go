@@ -0,0 +1,105 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package cce
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/open-ness/edgecontroller/uuid"
+)
+
+// Schedule issues a lifecycle command to an app on a node at the times of a
+// cron expression, e.g. to run an app during business hours only. The
+// command is sent as an operation like a PATCH of the node app. LastRunAt and
+// LastOperationID record the most recent command that was issued.
+type Schedule struct {
+	ID              string     `json:"id"`
+	NodeID          string     `json:"node_id"`
+	AppID           string     `json:"app_id"`
+	Cmd             string     `json:"cmd"`
+	Cron            string     `json:"cron"`
+	TimeZone        string     `json:"time_zone,omitempty"`
+	LastRunAt       *time.Time `json:"last_run_at,omitempty"`
+	LastOperationID string     `json:"last_operation_id,omitempty"`
+}
+
+// GetTableName returns the name of the persistence table.
+func (*Schedule) GetTableName() string {
+	return "schedules"
+}
+
+// GetID gets the ID.
+func (s *Schedule) GetID() string {
+	return s.ID
+}
+
+// SetID sets the ID.
+func (s *Schedule) SetID(id string) {
+	s.ID = id
+}
+
+// GetNodeID gets the node ID.
+func (s *Schedule) GetNodeID() string {
+	return s.NodeID
+}
+
+// Validate validates the model.
+func (s *Schedule) Validate() error {
+	if !uuid.IsValid(s.ID) {
+		return errors.New("id not a valid uuid")
+	}
+	if !uuid.IsValid(s.NodeID) {
+		return errors.New("node_id not a valid uuid")
+	}
+	if !uuid.IsValid(s.AppID) {
+		return errors.New("app_id not a valid uuid")
+	}
+	switch s.Cmd {
+	case OperationTypeStart, OperationTypeStop, OperationTypeRestart:
+	default:
+		return fmt.Errorf(`cmd "%s" is invalid`, s.Cmd)
+	}
+	if _, err := ParseCron(s.Cron, s.TimeZone); err != nil {
+		return err
+	}
+	if s.LastOperationID != "" && !uuid.IsValid(s.LastOperationID) {
+		return errors.New("last_operation_id not a valid uuid")
+	}
+
+	return nil
+}
+
+// CronSchedule returns the parsed cron expression of the schedule.
+func (s *Schedule) CronSchedule() (*CronSchedule, error) {
+	return ParseCron(s.Cron, s.TimeZone)
+}
+
+// FilterFields returns the filterable fields for this model.
+func (*Schedule) FilterFields() []string {
+	return []string{
+		"node_id",
+		"app_id",
+	}
+}
+
+func (s *Schedule) String() string {
+	return fmt.Sprintf(strings.TrimSpace(`
+Schedule[
+    ID: %s
+    NodeID: %s
+    AppID: %s
+    Cmd: %s
+    Cron: %s
+    TimeZone: %s
+]`),
+		s.ID,
+		s.NodeID,
+		s.AppID,
+		s.Cmd,
+		s.Cron,
+		s.TimeZone)
+}
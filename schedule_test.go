@@ -0,0 +1,127 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package cce_test
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	cce "github.com/open-ness/edgecontroller"
+)
+
+var _ = Describe("Entities: Schedule", func() {
+	var (
+		schedule *cce.Schedule
+	)
+
+	BeforeEach(func() {
+		schedule = &cce.Schedule{
+			ID:       "5f1e2d3c-4b5a-4968-8776-a5b4c3d2e1f0",
+			NodeID:   "a7c5fa8a-5b0b-4d1e-9d0a-6f5a2d8f3c1b",
+			AppID:    "c8d4b0e2-6f1a-4b3c-9e5d-7a8b9c0d1e2f",
+			Cmd:      "stop",
+			Cron:     "0 18 * * 1-5",
+			TimeZone: "Europe/Dublin",
+		}
+	})
+
+	Describe("GetTableName", func() {
+		It(`Should return "schedules"`, func() {
+			Expect(schedule.GetTableName()).To(Equal("schedules"))
+		})
+	})
+
+	Describe("GetID", func() {
+		It("Should return the ID", func() {
+			Expect(schedule.GetID()).To(Equal("5f1e2d3c-4b5a-4968-8776-a5b4c3d2e1f0"))
+		})
+	})
+
+	Describe("SetID", func() {
+		It("Should set and return the updated ID", func() {
+			By("Setting the ID")
+			schedule.SetID("456")
+
+			By("Getting the updated ID")
+			Expect(schedule.ID).To(Equal("456"))
+		})
+	})
+
+	Describe("GetNodeID", func() {
+		It("Should return the node ID", func() {
+			Expect(schedule.GetNodeID()).To(Equal("a7c5fa8a-5b0b-4d1e-9d0a-6f5a2d8f3c1b"))
+		})
+	})
+
+	Describe("Validate", func() {
+		It("Should not return an error for a valid schedule", func() {
+			Expect(schedule.Validate()).To(Succeed())
+		})
+
+		It("Should not return an error without a time zone", func() {
+			schedule.TimeZone = ""
+			Expect(schedule.Validate()).To(Succeed())
+		})
+
+		It("Should return an error if ID is not a UUID", func() {
+			schedule.ID = "123"
+			Expect(schedule.Validate()).To(MatchError("id not a valid uuid"))
+		})
+
+		It("Should return an error if NodeID is not a UUID", func() {
+			schedule.NodeID = "123"
+			Expect(schedule.Validate()).To(MatchError("node_id not a valid uuid"))
+		})
+
+		It("Should return an error if AppID is not a UUID", func() {
+			schedule.AppID = "123"
+			Expect(schedule.Validate()).To(MatchError("app_id not a valid uuid"))
+		})
+
+		It("Should return an error if Cmd is not a lifecycle command", func() {
+			schedule.Cmd = "undeploy"
+			Expect(schedule.Validate()).To(MatchError(`cmd "undeploy" is invalid`))
+		})
+
+		It("Should return an error if Cron is invalid", func() {
+			schedule.Cron = "0 18 * *"
+			Expect(schedule.Validate()).To(MatchError(`cron expression "0 18 * *" must have 5 fields`))
+		})
+
+		It("Should return an error if TimeZone is unknown", func() {
+			schedule.TimeZone = "Europe/Atlantis"
+			Expect(schedule.Validate()).To(MatchError(`time zone "Europe/Atlantis" is unknown`))
+		})
+
+		It("Should return an error if LastOperationID is not a UUID", func() {
+			schedule.LastOperationID = "123"
+			Expect(schedule.Validate()).To(MatchError("last_operation_id not a valid uuid"))
+		})
+	})
+
+	Describe("FilterFields", func() {
+		It("Should return the filterable fields", func() {
+			Expect(schedule.FilterFields()).To(Equal([]string{
+				"node_id",
+				"app_id",
+			}))
+		})
+	})
+
+	Describe("String", func() {
+		It("Should return the string value", func() {
+			Expect(schedule.String()).To(Equal(strings.TrimSpace(`
+Schedule[
+    ID: 5f1e2d3c-4b5a-4968-8776-a5b4c3d2e1f0
+    NodeID: a7c5fa8a-5b0b-4d1e-9d0a-6f5a2d8f3c1b
+    AppID: c8d4b0e2-6f1a-4b3c-9e5d-7a8b9c0d1e2f
+    Cmd: stop
+    Cron: 0 18 * * 1-5
+    TimeZone: Europe/Dublin
+]`,
+			)))
+		})
+	})
+})
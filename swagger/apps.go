@@ -4,6 +4,8 @@
 package swagger
 
 import (
+	"time"
+
 	cce "github.com/open-ness/edgecontroller"
 )
 
@@ -13,6 +15,8 @@ type AppSummary struct {
 	Type        string `json:"type"`
 	Name        string `json:"name"`
 	Version     string `json:"version"`
+	VersionOf   string `json:"version_of,omitempty"`
+	Tenant      string `json:"tenant,omitempty"`
 	Vendor      string `json:"vendor"`
 	Description string `json:"description"`
 }
@@ -22,12 +26,52 @@ type AppDetail struct {
 	AppSummary
 	Cores       int              `json:"cores"`
 	Memory      int              `json:"memory"`
+	Hugepages   int              `json:"hugepages,omitempty"`
 	Ports       []cce.PortProto  `json:"ports"`
 	Source      string           `json:"source"`
+	SHA256      string           `json:"sha256,omitempty"`
+	Signature   string           `json:"signature,omitempty"`
 	EPAFeatures []cce.EPAFeature `json:"epafeatures,omitempty"`
+	Env         []cce.EnvVar     `json:"env,omitempty"`
+	Command     []string         `json:"command,omitempty"`
+	Args        []string         `json:"args,omitempty"`
+	Volumes     []cce.Volume     `json:"volumes,omitempty"`
+	ConfigFiles []cce.ConfigFile `json:"config_files,omitempty"`
+	Networks    []string         `json:"networks,omitempty"`
+
+	LivenessProbe  *cce.Probe `json:"liveness_probe,omitempty"`
+	ReadinessProbe *cce.Probe `json:"readiness_probe,omitempty"`
+	RestartPolicy  string     `json:"restart_policy,omitempty"`
+	PullPolicy     string     `json:"pull_policy,omitempty"`
 }
 
 // AppList is a list representation of apps.
 type AppList struct {
 	Apps []AppSummary `json:"apps"`
 }
+
+// AppUpgradeRequest is a request to upgrade the nodes running an app to one of
+// its versions.
+type AppUpgradeRequest struct {
+	Version   string `json:"version"`
+	BatchSize int    `json:"batch_size,omitempty"`
+}
+
+// AppUpgradeSummary is a summary representation of an app upgrade.
+type AppUpgradeSummary struct {
+	ID          string `json:"id"`
+	AppID       string `json:"app_id"`
+	TargetAppID string `json:"target_app_id"`
+	State       string `json:"state"`
+	URL         string `json:"url"`
+}
+
+// AppUpgradeDetail is a detailed representation of an app upgrade.
+type AppUpgradeDetail struct {
+	AppUpgradeSummary
+	BatchSize int                   `json:"batch_size"`
+	Error     string                `json:"error,omitempty"`
+	Nodes     []*cce.AppUpgradeNode `json:"nodes"`
+	CreatedAt time.Time             `json:"created_at"`
+	UpdatedAt time.Time             `json:"updated_at"`
+}
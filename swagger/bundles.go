@@ -0,0 +1,57 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package swagger
+
+// AppBundleSummary is a summary representation of an app bundle.
+type AppBundleSummary struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// AppBundleDetail is a detailed representation of an app bundle.
+type AppBundleDetail struct {
+	AppBundleSummary
+	Members []AppBundleMember `json:"members"`
+}
+
+// AppBundleMember is an app of a bundle and the apps of the bundle it depends
+// on.
+type AppBundleMember struct {
+	AppID     string   `json:"app_id"`
+	DependsOn []string `json:"depends_on,omitempty"`
+}
+
+// AppBundleList is a list representation of app bundles.
+type AppBundleList struct {
+	AppBundles []AppBundleSummary `json:"app_bundles"`
+}
+
+// NodeAppBundleSummary is a summary representation of an app bundle deployed
+// to a node.
+type NodeAppBundleSummary struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+}
+
+// NodeAppBundleDetail is a detailed representation of an app bundle deployed
+// to a node. Members are listed in the order they are started. Command is
+// used to start, stop or restart the bundle.
+type NodeAppBundleDetail struct {
+	NodeAppBundleSummary
+	Members []NodeAppBundleMember `json:"members,omitempty"`
+	Command string                `json:"command,omitempty"`
+}
+
+// NodeAppBundleMember is the status of a member of an app bundle on a node.
+type NodeAppBundleMember struct {
+	AppID  string `json:"app_id"`
+	Status string `json:"status"`
+}
+
+// NodeAppBundleList is a list representation of the app bundles deployed to a
+// node.
+type NodeAppBundleList struct {
+	NodeAppBundles []NodeAppBundleSummary `json:"app_bundles"`
+}
@@ -0,0 +1,29 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package swagger
+
+import "time"
+
+// MaintenanceWindowSummary is a summary representation of a maintenance
+// window of a node. Duration is in minutes.
+type MaintenanceWindowSummary struct {
+	ID       string `json:"id"`
+	Cron     string `json:"cron"`
+	TimeZone string `json:"time_zone,omitempty"`
+	Duration int    `json:"duration"`
+	Open     bool   `json:"open"`
+}
+
+// MaintenanceWindowDetail is a detailed representation of a maintenance window
+// of a node.
+type MaintenanceWindowDetail struct {
+	MaintenanceWindowSummary
+	NextOpenAt *time.Time `json:"next_open_at,omitempty"`
+}
+
+// MaintenanceWindowList is a list representation of the maintenance windows
+// of a node.
+type MaintenanceWindowList struct {
+	MaintenanceWindows []MaintenanceWindowSummary `json:"maintenance_windows"`
+}
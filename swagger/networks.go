@@ -0,0 +1,25 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package swagger
+
+import "encoding/json"
+
+// NetworkSummary is a summary representation of a CNI network.
+type NetworkSummary struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// NetworkDetail is a detailed representation of a CNI network.
+type NetworkDetail struct {
+	NetworkSummary
+	CNIConfig     json.RawMessage `json:"cni_config"`
+	InterfaceName string          `json:"interface_name,omitempty"`
+	Args          string          `json:"args,omitempty"`
+}
+
+// NetworkList is a list representation of CNI networks.
+type NetworkList struct {
+	Networks []NetworkSummary `json:"networks"`
+}
@@ -0,0 +1,24 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package swagger
+
+// NfdFeatureValue is a value of an NFD feature and the number of nodes that
+// report it.
+type NfdFeatureValue struct {
+	Value string `json:"value"`
+	Nodes int    `json:"nodes"`
+}
+
+// NfdFeature is an NFD feature key, the number of nodes that report it and
+// the values reported across the fleet.
+type NfdFeature struct {
+	ID     string            `json:"id"`
+	Nodes  int               `json:"nodes"`
+	Values []NfdFeatureValue `json:"values"`
+}
+
+// NfdFeatureList is a list of all NFD features reported across the fleet.
+type NfdFeatureList struct {
+	Features []NfdFeature `json:"features"`
+}
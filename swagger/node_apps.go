@@ -3,6 +3,11 @@
 
 package swagger
 
+import (
+	"encoding/json"
+	"time"
+)
+
 // NodeAppSummary is a summary representation of the node app.
 type NodeAppSummary struct {
 	ID string `json:"id"`
@@ -11,8 +16,49 @@ type NodeAppSummary struct {
 // NodeAppDetail is a detailed representation of the node app.
 type NodeAppDetail struct {
 	NodeAppSummary
-	Status  string `json:"status"`
-	Command string `json:"command"`
+	Status        string          `json:"status"`
+	StatusReason  string          `json:"status_reason,omitempty"`
+	StatusMessage string          `json:"status_message,omitempty"`
+	Command       string          `json:"command"`
+	VersionID     string          `json:"version_id,omitempty"`
+	Tenant        string          `json:"tenant,omitempty"`
+	Service       *NodeAppService `json:"service,omitempty"`
+
+	TemplateID         string                     `json:"template_id,omitempty"`
+	TemplateVersion    int                        `json:"template_version,omitempty"`
+	TemplateParameters map[string]json.RawMessage `json:"template_parameters,omitempty"`
+}
+
+// NodeAppService is the stable address of the node app in Kubernetes mode.
+type NodeAppService struct {
+	Name      string           `json:"name"`
+	Type      string           `json:"type"`
+	ClusterIP string           `json:"cluster_ip"`
+	Ports     []NodeAppSvcPort `json:"ports"`
+	DNSName   string           `json:"dns_name,omitempty"`
+}
+
+// NodeAppSvcPort is a port of the node app's service. NodePort is set for
+// NodePort services only.
+type NodeAppSvcPort struct {
+	Port     int32  `json:"port"`
+	NodePort int32  `json:"node_port,omitempty"`
+	Protocol string `json:"protocol"`
+}
+
+// NodeAppEvent is a transition of the status of the node app in Kubernetes
+// mode.
+type NodeAppEvent struct {
+	Time    time.Time `json:"time"`
+	Status  string    `json:"status"`
+	Reason  string    `json:"reason,omitempty"`
+	Message string    `json:"message,omitempty"`
+}
+
+// NodeAppEventList is a list of the status transitions of the node app,
+// oldest first.
+type NodeAppEventList struct {
+	Events []NodeAppEvent `json:"events"`
 }
 
 // NodeAppList is a list representation of node apps.
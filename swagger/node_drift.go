@@ -0,0 +1,18 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package swagger
+
+import (
+	"time"
+
+	cce "github.com/open-ness/edgecontroller"
+)
+
+// NodeDriftDetail is a detailed representation of a node's unresolved drift.
+type NodeDriftDetail struct {
+	NodeID    string           `json:"node_id"`
+	CheckedAt *time.Time       `json:"checked_at,omitempty"`
+	InSync    bool             `json:"in_sync"`
+	Items     []*cce.DriftItem `json:"items"`
+}
@@ -14,6 +14,24 @@ type NodeSummary struct {
 // NodeDetail is a detailed representation of the node.
 type NodeDetail struct {
 	NodeSummary
+	Utilization *NodeUtilization `json:"utilization,omitempty"`
+}
+
+// NodeResources is an amount of a node's resources. Memory and hugepages are
+// in MB.
+type NodeResources struct {
+	Cores     int `json:"cores"`
+	Memory    int `json:"memory"`
+	Hugepages int `json:"hugepages"`
+}
+
+// NodeUtilization is the resources of a node that apps can request and the
+// resources requested by the apps deployed to it. Allocatable is omitted if
+// the node's resources are not known.
+type NodeUtilization struct {
+	Allocatable       *NodeResources `json:"allocatable,omitempty"`
+	AllocatableSource string         `json:"allocatable_source,omitempty"`
+	Requested         NodeResources  `json:"requested"`
 }
 
 // NodeList is a list representation of nodes.
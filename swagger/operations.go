@@ -0,0 +1,36 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package swagger
+
+import (
+	"time"
+
+	cce "github.com/open-ness/edgecontroller"
+)
+
+// OperationSummary is a summary representation of an asynchronous operation.
+type OperationSummary struct {
+	ID     string `json:"id"`
+	Type   string `json:"type"`
+	NodeID string `json:"node_id"`
+	AppID  string `json:"app_id,omitempty"`
+	State  string `json:"state"`
+	URL    string `json:"url"`
+}
+
+// OperationDetail is a detailed representation of an asynchronous operation.
+type OperationDetail struct {
+	OperationSummary
+	Attempts    int                       `json:"attempts"`
+	Progress    string                    `json:"progress"`
+	Error       string                    `json:"error,omitempty"`
+	Transitions []cce.OperationTransition `json:"transitions"`
+	CreatedAt   time.Time                 `json:"created_at"`
+	UpdatedAt   time.Time                 `json:"updated_at"`
+}
+
+// OperationList is a list representation of asynchronous operations.
+type OperationList struct {
+	Operations []OperationSummary `json:"operations"`
+}
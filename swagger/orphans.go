@@ -0,0 +1,29 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package swagger
+
+import "time"
+
+// OrphanReport is the result of the most recent reconciliation of the
+// Kubernetes objects of apps with persistence.
+type OrphanReport struct {
+	CheckedAt time.Time    `json:"checked_at"`
+	Policy    string       `json:"policy"`
+	Items     []OrphanItem `json:"items"`
+}
+
+// OrphanItem is a Kubernetes object that is not in persistence ("orphaned")
+// or that is missing for an app in persistence ("missing"), and the action
+// taken on it.
+type OrphanItem struct {
+	Kind      string `json:"kind"`
+	Tenant    string `json:"tenant,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name,omitempty"`
+	NodeID    string `json:"node_id"`
+	AppID     string `json:"app_id"`
+	Problem   string `json:"problem"`
+	Action    string `json:"action"`
+	Error     string `json:"error,omitempty"`
+}
@@ -0,0 +1,37 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package swagger
+
+// PlacementRequest asks the controller to choose nodes for an app. Each entry
+// of the node selector is an NFD feature condition in the form used by the
+// GET /nodes query, without the "nfd." prefix, e.g. "kernel-version.major>=5".
+type PlacementRequest struct {
+	Replicas     int      `json:"replicas"`
+	NodeSelector []string `json:"node_selector,omitempty"`
+	Strategy     string   `json:"strategy,omitempty"`
+}
+
+// PlacedNode is a node chosen for an app and the operation deploying the app
+// to it.
+type PlacedNode struct {
+	NodeID    string           `json:"node_id"`
+	Operation OperationSummary `json:"operation"`
+}
+
+// RejectedNode is a node that does not qualify for an app and the reasons why.
+type RejectedNode struct {
+	NodeID  string   `json:"node_id"`
+	Reasons []string `json:"reasons"`
+}
+
+// PlacementResult is the outcome of scheduling an app. Error is set if the
+// operation of a replica could not be submitted, in which case the operations
+// of the replicas placed before it are canceled where possible.
+type PlacementResult struct {
+	AppID    string         `json:"app_id"`
+	Strategy string         `json:"strategy"`
+	Placed   []PlacedNode   `json:"placed"`
+	Rejected []RejectedNode `json:"rejected"`
+	Error    string         `json:"error,omitempty"`
+}
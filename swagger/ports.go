@@ -0,0 +1,17 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package swagger
+
+// PortSummary is a summary representation of a network port of a node.
+type PortSummary struct {
+	PCI        string `json:"pci"`
+	Driver     string `json:"driver"`
+	Bridge     string `json:"bridge"`
+	MACAddress string `json:"mac_address"`
+}
+
+// PortList is a list representation of network ports.
+type PortList struct {
+	Ports []PortSummary `json:"ports"`
+}
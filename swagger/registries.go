@@ -0,0 +1,23 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package swagger
+
+// RegistrySummary is a summary representation of an image registry.
+type RegistrySummary struct {
+	ID   string `json:"id"`
+	Host string `json:"host"`
+}
+
+// RegistryDetail is a detailed representation of an image registry. The
+// password is only accepted, it is never returned.
+type RegistryDetail struct {
+	RegistrySummary
+	Username string `json:"username"`
+	Password string `json:"password,omitempty"`
+}
+
+// RegistryList is a list representation of image registries.
+type RegistryList struct {
+	Registries []RegistrySummary `json:"registries"`
+}
@@ -0,0 +1,31 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package swagger
+
+import "time"
+
+// ScheduleSummary is a summary representation of a schedule of lifecycle
+// commands of an app on a node.
+type ScheduleSummary struct {
+	ID     string `json:"id"`
+	NodeID string `json:"node_id"`
+	AppID  string `json:"app_id"`
+	Cmd    string `json:"cmd"`
+	Cron   string `json:"cron"`
+}
+
+// ScheduleDetail is a detailed representation of a schedule of lifecycle
+// commands of an app on a node.
+type ScheduleDetail struct {
+	ScheduleSummary
+	TimeZone        string     `json:"time_zone,omitempty"`
+	NextRunAt       *time.Time `json:"next_run_at,omitempty"`
+	LastRunAt       *time.Time `json:"last_run_at,omitempty"`
+	LastOperationID string     `json:"last_operation_id,omitempty"`
+}
+
+// ScheduleList is a list representation of schedules.
+type ScheduleList struct {
+	Schedules []ScheduleSummary `json:"schedules"`
+}
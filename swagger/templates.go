@@ -0,0 +1,41 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package swagger
+
+import "encoding/json"
+
+// AppTemplateSummary is a summary representation of an app template.
+type AppTemplateSummary struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Version int    `json:"version"`
+}
+
+// AppTemplateDetail is a detailed representation of an app template.
+type AppTemplateDetail struct {
+	AppTemplateSummary
+	Description string                 `json:"description,omitempty"`
+	Parameters  []AppTemplateParameter `json:"parameters,omitempty"`
+	Spec        json.RawMessage        `json:"spec"`
+}
+
+// AppTemplateParameter is a typed parameter of an app template.
+type AppTemplateParameter struct {
+	Name        string          `json:"name"`
+	Type        string          `json:"type"`
+	Default     json.RawMessage `json:"default,omitempty"`
+	Description string          `json:"description,omitempty"`
+}
+
+// AppTemplateList is a list representation of app templates.
+type AppTemplateList struct {
+	AppTemplates []AppTemplateSummary `json:"app_templates"`
+}
+
+// NodeAppTemplateReq deploys an app instantiated from a template, with the
+// given values of its parameters, to a node.
+type NodeAppTemplateReq struct {
+	TemplateID string                     `json:"template_id"`
+	Parameters map[string]json.RawMessage `json:"parameters,omitempty"`
+}
@@ -0,0 +1,20 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package swagger
+
+// ZoneSummary is a summary representation of a network zone.
+type ZoneSummary struct {
+	ID          string `json:"id"`
+	Description string `json:"description"`
+}
+
+// ZoneDetail is a detailed representation of a network zone.
+type ZoneDetail struct {
+	ZoneSummary
+}
+
+// ZoneList is a list representation of network zones.
+type ZoneList struct {
+	Zones []ZoneSummary `json:"zones"`
+}
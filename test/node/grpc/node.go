@@ -27,6 +27,7 @@ import (
 	nodegmock "github.com/open-ness/edgecontroller/mock/node/grpc"
 	elapb "github.com/open-ness/edgecontroller/pb/ela"
 	evapb "github.com/open-ness/edgecontroller/pb/eva"
+	ifsvcpb "github.com/open-ness/edgecontroller/pb/interfaceservice"
 	"github.com/open-ness/edgecontroller/pki"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
@@ -64,6 +65,7 @@ func main() {
 	elapb.RegisterInterfacePolicyServiceServer(elaServer, mockNode.IfPolicySvc)
 	elapb.RegisterZoneServiceServer(elaServer, mockNode.ZoneSvc)
 	elapb.RegisterDNSServiceServer(elaServer, mockNode.DNSSvc)
+	ifsvcpb.RegisterInterfaceServiceServer(elaServer, mockNode.PortSvc)
 	evaServer := grpc.NewServer(grpc.Creds(tlsConf))
 	evapb.RegisterApplicationDeploymentServiceServer(evaServer, mockNode.AppDeploySvc)
 	evapb.RegisterApplicationLifecycleServiceServer(evaServer, mockNode.AppLifeSvc)
@@ -0,0 +1,115 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package cce
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+)
+
+// TrustStore holds the public keys that app signatures are verified with.
+// A signature is valid if any of the keys verifies it. RSA (PKCS #1 v1.5)
+// and ECDSA keys verify signatures of the SHA-256 digest of the image, as
+// made by e.g. openssl dgst -sha256 -sign.
+type TrustStore struct {
+	keys []crypto.PublicKey
+}
+
+// LoadTrustStore reads a trust store from a file of PEM-encoded public keys.
+func LoadTrustStore(path string) (*TrustStore, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseTrustStore(b)
+}
+
+// ParseTrustStore parses a trust store from PEM-encoded public keys.
+func ParseTrustStore(pemBytes []byte) (*TrustStore, error) {
+	ts := &TrustStore{}
+	for {
+		var block *pem.Block
+		if block, pemBytes = pem.Decode(pemBytes); block == nil {
+			break
+		}
+		if block.Type != "PUBLIC KEY" {
+			continue
+		}
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse public key: %v", err)
+		}
+		switch key.(type) {
+		case *rsa.PublicKey, *ecdsa.PublicKey:
+		default:
+			return nil, fmt.Errorf("unsupported public key type %T", key)
+		}
+		ts.keys = append(ts.keys, key)
+	}
+	if len(ts.keys) == 0 {
+		return nil, errors.New("no public keys found")
+	}
+
+	return ts, nil
+}
+
+// VerifyApp verifies the signature of an app. Without a trust store, i.e. if
+// ts is nil, unsigned apps are accepted and signed apps rejected as their
+// signature cannot be verified. With a trust store, all apps must be signed.
+func (ts *TrustStore) VerifyApp(app *App) error {
+	if ts == nil {
+		if app.Signature != "" {
+			return fmt.Errorf("signature of app %s cannot be verified without a trust store", app.ID)
+		}
+		return nil
+	}
+	if app.Signature == "" {
+		return fmt.Errorf("app %s is not signed", app.ID)
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(app.Signature)
+	if err != nil {
+		return fmt.Errorf("signature of app %s is not base64-encoded", app.ID)
+	}
+	digest, err := hex.DecodeString(app.ImageDigest())
+	if err != nil || len(digest) != 32 {
+		return fmt.Errorf("app %s has no sha256 digest to verify its signature with", app.ID)
+	}
+	for _, key := range ts.keys {
+		if verifyDigest(key, digest, sig) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("signature of app %s does not match any key of the trust store", app.ID)
+}
+
+func verifyDigest(key crypto.PublicKey, digest, sig []byte) bool {
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(key, crypto.SHA256, digest, sig) == nil
+	case *ecdsa.PublicKey:
+		// ECDSA signatures are the ASN.1 DER-encoded sequence of R and S
+		var ecSig struct {
+			R, S *big.Int
+		}
+		rest, err := asn1.Unmarshal(sig, &ecSig)
+		if err != nil || len(rest) != 0 {
+			return false
+		}
+		return ecdsa.Verify(key, digest, ecSig.R, ecSig.S)
+	default:
+		return false
+	}
+}
@@ -0,0 +1,125 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package cce_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"math/big"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	cce "github.com/open-ness/edgecontroller"
+)
+
+var _ = Describe("Trust store", func() {
+	var (
+		app    *cce.App
+		digest []byte
+		rsaKey *rsa.PrivateKey
+		ecKey  *ecdsa.PrivateKey
+	)
+
+	signECDSA := func(digest []byte) []byte {
+		r, s, err := ecdsa.Sign(rand.Reader, ecKey, digest)
+		Expect(err).ToNot(HaveOccurred())
+		sig, err := asn1.Marshal(struct{ R, S *big.Int }{r, s})
+		Expect(err).ToNot(HaveOccurred())
+		return sig
+	}
+
+	encodePublicKey := func(key crypto.PublicKey) []byte {
+		der, err := x509.MarshalPKIXPublicKey(key)
+		Expect(err).ToNot(HaveOccurred())
+		return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+	}
+
+	BeforeEach(func() {
+		var err error
+		rsaKey, err = rsa.GenerateKey(rand.Reader, 2048)
+		Expect(err).ToNot(HaveOccurred())
+		ecKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		Expect(err).ToNot(HaveOccurred())
+
+		app = &cce.App{
+			ID:     "efcece3c-6b58-4993-8d45-bde6239d4baa",
+			Source: "https://path/to/file.zip",
+			SHA256: "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+		}
+		digest, err = hex.DecodeString(app.SHA256)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	Describe("ParseTrustStore", func() {
+		It("Should return an error if there are no public keys", func() {
+			_, err := cce.ParseTrustStore([]byte("not a key"))
+			Expect(err).To(MatchError("no public keys found"))
+		})
+
+		It("Should return an error if a public key is invalid", func() {
+			_, err := cce.ParseTrustStore(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: []byte("key")}))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("VerifyApp", func() {
+		It("Should verify signatures of any key of the trust store", func() {
+			ts, err := cce.ParseTrustStore(append(
+				encodePublicKey(rsaKey.Public()),
+				encodePublicKey(ecKey.Public())...))
+			Expect(err).ToNot(HaveOccurred())
+
+			sig, err := rsa.SignPKCS1v15(rand.Reader, rsaKey, crypto.SHA256, digest)
+			Expect(err).ToNot(HaveOccurred())
+			app.Signature = base64.StdEncoding.EncodeToString(sig)
+			Expect(ts.VerifyApp(app)).To(Succeed())
+
+			app.Signature = base64.StdEncoding.EncodeToString(signECDSA(digest))
+			Expect(ts.VerifyApp(app)).To(Succeed())
+		})
+
+		It("Should return an error if the signature does not match the digest", func() {
+			ts, err := cce.ParseTrustStore(encodePublicKey(ecKey.Public()))
+			Expect(err).ToNot(HaveOccurred())
+
+			app.Signature = base64.StdEncoding.EncodeToString(signECDSA(digest))
+			app.SHA256 = "60303ae22b998861bce3b28f33eec1be758a213c86c93c076dbe9f558c11c752"
+			Expect(ts.VerifyApp(app)).To(MatchError(
+				"signature of app efcece3c-6b58-4993-8d45-bde6239d4baa does not match any key of the trust store"))
+		})
+
+		It("Should return an error if the signing key is not trusted", func() {
+			ts, err := cce.ParseTrustStore(encodePublicKey(rsaKey.Public()))
+			Expect(err).ToNot(HaveOccurred())
+
+			app.Signature = base64.StdEncoding.EncodeToString(signECDSA(digest))
+			Expect(ts.VerifyApp(app)).To(MatchError(
+				"signature of app efcece3c-6b58-4993-8d45-bde6239d4baa does not match any key of the trust store"))
+		})
+
+		It("Should return an error if the app is not signed", func() {
+			ts, err := cce.ParseTrustStore(encodePublicKey(rsaKey.Public()))
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(ts.VerifyApp(app)).To(MatchError("app efcece3c-6b58-4993-8d45-bde6239d4baa is not signed"))
+		})
+
+		It("Should only accept unsigned apps without a trust store", func() {
+			var ts *cce.TrustStore
+			Expect(ts.VerifyApp(app)).To(Succeed())
+
+			app.Signature = base64.StdEncoding.EncodeToString(signECDSA(digest))
+			Expect(ts.VerifyApp(app)).To(MatchError("signature of app efcece3c-6b58-4993-8d45-bde6239d4baa " +
+				"cannot be verified without a trust store"))
+		})
+	})
+})